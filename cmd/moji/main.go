@@ -17,27 +17,38 @@ import (
 
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gorilla/websocket"
+	"github.com/leothevan2444/moji/internal/bandwidth"
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/config"
 	"github.com/leothevan2444/moji/internal/controller/api"
 	"github.com/leothevan2444/moji/internal/discovery"
+	"github.com/leothevan2444/moji/internal/feedpoll"
 	"github.com/leothevan2444/moji/internal/graphqlapi"
 	"github.com/leothevan2444/moji/internal/graphqlapi/generated"
 	"github.com/leothevan2444/moji/internal/imagecache"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
+	"github.com/leothevan2444/moji/internal/libraryindex"
 	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/mediaprobe"
 	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/notify"
 	"github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/stashboxcache"
 	"github.com/leothevan2444/moji/internal/stashsync"
 	"github.com/leothevan2444/moji/internal/stats"
 	"github.com/leothevan2444/moji/internal/subscription"
+	"github.com/leothevan2444/moji/internal/subtitle"
 	"github.com/leothevan2444/moji/internal/taskflow"
+	"github.com/leothevan2444/moji/internal/taskhooks"
 	"github.com/leothevan2444/moji/internal/taskruntime"
 	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/internal/watchlist"
+	"github.com/leothevan2444/moji/internal/webhook"
 	"github.com/leothevan2444/moji/internal/webui"
 	"github.com/leothevan2444/moji/pkg/jackett"
 	"github.com/leothevan2444/moji/pkg/qbittorrent"
 	"github.com/leothevan2444/moji/pkg/stash"
-	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
 )
 
 var runtimeCacheSequence atomic.Uint64
@@ -93,6 +104,10 @@ func main() {
 	// Dependencies
 	runtime := newHTTPRuntime(cfg, "dev", configStore)
 	defer runtime.stashBoxCacheService.Close()
+	defer runtime.inspectionCacheService.Close()
+	defer runtime.libraryIndexService.Close()
+	defer runtime.webhookService.Close()
+	defer runtime.watchlistService.Close()
 
 	server := &http.Server{
 		Addr:              *addr,
@@ -112,15 +127,32 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	startTaskReconciliation(ctx, runtime.taskRuntimeService)
 	startTaskSyncWorker(ctx, runtime.taskRuntimeService, runtime.stashService, configureProgressSyncIntervalProvider(configStore, cfg))
-	startSubscriptionWorker(ctx, runtime.subscriptionService, configureSubscriptionPollIntervalProvider(configStore, cfg))
+	startStashJobWatcher(ctx, runtime.taskRuntimeService, runtime.stashService)
+	startSubscriptionWorker(ctx, runtime.subscriptionService, runtime.studioSubscriptionService, configureSubscriptionPollIntervalProvider(configStore, cfg))
 	if runtime.stashBoxCacheService != nil {
 		runtime.stashBoxCacheService.StartCleanup(ctx)
 	}
+	if runtime.inspectionCacheService != nil {
+		runtime.inspectionCacheService.StartCleanup(ctx)
+	}
+	if runtime.libraryIndexService != nil {
+		runtime.libraryIndexService.StartSync(ctx)
+	}
+	runtime.webhookService.Start(ctx)
+	runtime.watchlistService.Start(ctx)
+	runtime.notifier.Start(ctx)
+	runtime.bandwidthScheduler.Start(ctx)
+	runtime.feedPoller.Start(ctx)
+	runtime.upcomingSourcing.StartUpcomingSourcing(ctx)
 	go runtime.statsCollector.Run(ctx)
 
 	go func() {
 		<-ctx.Done()
+		if service, ok := runtime.taskRuntimeService.(*taskruntime.Service); ok {
+			service.Close()
+		}
 		runtime.taskEventBus.Close()
 		runtime.serviceStatusEventBus.Close()
 		runtime.performerSubscriptionEventBus.Close()
@@ -149,11 +181,20 @@ type httpRuntime struct {
 	taskRuntimeService            graphqlapi.TaskRuntimeService
 	stashService                  graphqlapi.StashService
 	subscriptionService           graphqlapi.SubscriptionService
+	studioSubscriptionService     graphqlapi.StudioSubscriptionService
 	statsCollector                *stats.Collector
 	taskEventBus                  *taskruntime.TaskEventBus
 	serviceStatusEventBus         *stats.ServiceStatusEventBus
 	performerSubscriptionEventBus *subscription.PerformerSubscriptionEventBus
 	stashBoxCacheService          *stashboxcache.Service
+	inspectionCacheService        *inspectioncache.Service
+	libraryIndexService           *libraryindex.Service
+	webhookService                *webhook.Service
+	watchlistService              *watchlist.Service
+	notifier                      *notify.Service
+	bandwidthScheduler            *bandwidth.Scheduler
+	feedPoller                    *feedpoll.Poller
+	upcomingSourcing              *subscription.Service
 }
 
 func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Store) *httpRuntime {
@@ -182,6 +223,20 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 	if err != nil {
 		logging.Fatalf("configure StashBox data cache: %v", err)
 	}
+	inspectionCachePath := fmt.Sprintf("file:moji-inspection-cache-%d?mode=memory&cache=shared", runtimeCacheSequence.Add(1))
+	if configStore != nil {
+		inspectionCachePath = inspectionCacheDatabasePath()
+	}
+	inspectionCacheService, err := inspectioncache.New(inspectionCachePath, func() inspectioncache.Config {
+		current := cfg.System.TorrentInspectionCache.Normalize()
+		if configStore != nil {
+			current = configStore.Config().System.TorrentInspectionCache.Normalize()
+		}
+		return inspectioncache.Config{TTL: time.Duration(current.TTLHours) * time.Hour, MaxEntries: current.MaxEntries}
+	})
+	if err != nil {
+		logging.Fatalf("configure torrent inspection cache: %v", err)
+	}
 	jackettTracker := tracker.NewJackettService(configureJackettConfigProvider(configStore, cfg))
 	{
 		current := storeJackett(cfg, configStore)
@@ -190,21 +245,27 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 			logging.Warn("runtime: jackett.password is empty; the home service card will report Jackett as 运行异常 because /api/v2.0/indexers requires a session cookie. Set jackett.password in config.yaml to enable it.")
 		}
 	}
-	apiHandler := api.NewHandler(jackettTracker, api.WithLogFilePath(cfg.EffectiveLogFilePath()))
+	apiOptions := []api.Option{api.WithLogFilePath(cfg.EffectiveLogFilePath())}
 	qbittorrentClient, torrentClient := configureQBittorrent(cfg, configStore)
 	stashClient := configureStashClient(cfg, configStore)
 	taskEventBus := taskruntime.NewTaskEventBus(32)
-	taskRuntimeService := configureTaskRuntime(cfg, configStore, jackettTracker, torrentClient, stashClient, taskEventBus)
-	taskFlowService := configureTaskFlow(taskRuntimeService)
-	stashService := configureStashService(cfg, configStore, stashClient)
 	metadataService := configureMetadata(stashClient)
 	if metadataService != nil {
 		metadataService.SetCache(stashBoxCacheService)
 	}
+	libraryIndexService := configureLibraryIndex(stashClient, configStore != nil)
+	taskRuntimeService := configureTaskRuntime(cfg, configStore, jackettTracker, torrentClient, qbittorrentClient, stashClient, metadataService, taskEventBus, inspectionCacheService, libraryIndexService)
+	taskFlowService := configureTaskFlow(taskRuntimeService)
+	stashService := configureStashService(cfg, configStore, stashClient, metadataService)
 	performerSubscriptionEventBus := subscription.NewPerformerSubscriptionEventBus(16)
 	subscriptionService := configureSubscription(cfg, configStore, stashClient, metadataService, taskFlowService, imageService)
 	if subscriptionService != nil {
 		subscriptionService.SetEventPublisher(performerSubscriptionEventBus)
+		if libraryIndexService != nil {
+			subscriptionService.SetLibraryIndex(libraryIndexService)
+		}
+		subscriptionService.SetReleaseSearcher(jackettTracker)
+		subscriptionService.SetUpcomingReleases(configureUpcomingReleasesProvider(configStore, cfg))
 	}
 	applyAutomationSettings(cfg, subscriptionService, metadataService)
 	if taskFlowService != nil && metadataService != nil {
@@ -220,6 +281,46 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 	)
 	serviceStatusEventBus := stats.NewServiceStatusEventBus(8)
 	statsCollector.SetEventPublisher(serviceStatusEventBus)
+	webhookPath := fmt.Sprintf("file:moji-webhooks-%d?mode=memory&cache=shared", runtimeCacheSequence.Add(1))
+	if configStore != nil {
+		webhookPath = webhookDatabasePath()
+	}
+	webhookService, err := webhook.New(webhookPath, configureWebhooksProvider(configStore, cfg),
+		webhook.WithTaskEvents(taskEventBus),
+		webhook.WithPerformerSubscriptionEvents(performerSubscriptionEventBus),
+		webhook.WithServiceStatusEvents(serviceStatusEventBus, statsCollector),
+	)
+	if err != nil {
+		logging.Fatalf("configure webhooks: %v", err)
+	}
+	notifier := notify.New(configureNotifyProvider(configStore, cfg),
+		notify.WithTaskEvents(taskEventBus),
+		notify.WithServiceStatusEvents(serviceStatusEventBus, statsCollector),
+	)
+	watchlistPath := fmt.Sprintf("file:moji-watchlists-%d?mode=memory&cache=shared", runtimeCacheSequence.Add(1))
+	if configStore != nil {
+		watchlistPath = watchlistDatabasePath()
+	}
+	watchlistOptions := []watchlist.Option{watchlist.WithNotifier(notifier)}
+	if taskRuntimeService != nil {
+		watchlistOptions = append(watchlistOptions, watchlist.WithTaskCreator(taskRuntimeService))
+	}
+	watchlistService, err := watchlist.New(watchlistPath, jackettTracker, watchlistOptions...)
+	if err != nil {
+		logging.Fatalf("configure watchlists: %v", err)
+	}
+	var feedPoller *feedpoll.Poller
+	if sourcer, ok := taskRuntimeService.(feedpoll.TaskSourcer); ok {
+		var feedOptions []feedpoll.Option
+		if subscriptionService != nil {
+			feedOptions = append(feedOptions, feedpoll.WithPendingReleases(subscriptionService))
+		}
+		feedPoller = feedpoll.New(jackettTracker, sourcer, configureFeedPollingProvider(configStore, cfg), feedOptions...)
+	}
+	var bandwidthScheduler *bandwidth.Scheduler
+	if qbittorrentClient != nil {
+		bandwidthScheduler = bandwidth.New(qbittorrentClient, configureBandwidthProvider(configStore, cfg))
+	}
 	resolver := graphqlapi.NewResolver(jackettTracker, torrentClient, taskRuntimeService, stashService, version)
 	resolver.TaskFlow = taskFlowService
 	if metadataService != nil {
@@ -249,10 +350,23 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 		if err != nil {
 			logging.Fatalf("configure performer: %v", err)
 		}
+		if libraryIndexService != nil {
+			performerService.SetLibraryIndex(libraryIndexService)
+		}
+		if subscriptionService != nil {
+			performerService.SetSceneClassifier(subscriptionService)
+		}
 		resolver.Performer = performerService
+		apiOptions = append(apiOptions, api.WithMissingScenesReporter(performerService))
 		resolver.Discovery = discovery.NewService(metadataService, taskFlowService, stashBoxImage)
 	}
 	resolver.PerformerSubscription = subscriptionService
+	var studioSubscriptionService graphqlapi.StudioSubscriptionService
+	if subscriptionService != nil {
+		studioSubscriptionService = subscriptionService
+		resolver.StudioSubscription = subscriptionService
+		resolver.ReleaseReview = subscriptionService
+	}
 	resolver.TaskEventSource = taskEventBus
 	resolver.ServiceStatusEventSource = serviceStatusEventBus
 	resolver.PerformerSubscriptionEventSource = performerSubscriptionEventBus
@@ -264,13 +378,22 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 	resolver.Stats = statsCollector
 	resolver.ImageCache = imageService
 	resolver.StashBoxDataCache = stashBoxCacheService
+	resolver.TorrentInspectionCache = inspectionCacheService
+	if libraryIndexService != nil {
+		resolver.LibraryIndex = libraryIndexService
+	}
+	resolver.Webhooks = webhookService
+	resolver.Watchlist = watchlistService
+	if bandwidthScheduler != nil {
+		resolver.Bandwidth = bandwidthScheduler
+	}
 	if configStore != nil {
-		resolver.SettingsEditor = newRuntimeSettingsEditor(configStore, version, taskRuntimeService != nil, stashService != nil, stashClient, subscriptionService, metadataService)
+		resolver.SettingsEditor = newRuntimeSettingsEditor(configStore, version, taskRuntimeService != nil, stashService != nil, stashClient, subscriptionService, metadataService, notifier)
 	}
 	graphqlHandler := graphqlapi.NewGraphQLServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
 
 	apiMux := http.NewServeMux()
-	apiHandler.Register(apiMux)
+	api.NewHandler(jackettTracker, apiOptions...).Register(apiMux)
 	imageService.RegisterHandler(apiMux)
 	webHandler := webui.NewHandler("web/dist")
 
@@ -294,12 +417,39 @@ func newHTTPRuntime(cfg *config.Config, version string, configStore *config.Stor
 		taskRuntimeService:            taskRuntimeService,
 		stashService:                  stashService,
 		subscriptionService:           subscriptionService,
+		studioSubscriptionService:     studioSubscriptionService,
 		statsCollector:                statsCollector,
 		taskEventBus:                  taskEventBus,
 		serviceStatusEventBus:         serviceStatusEventBus,
 		performerSubscriptionEventBus: performerSubscriptionEventBus,
 		stashBoxCacheService:          stashBoxCacheService,
+		inspectionCacheService:        inspectionCacheService,
+		libraryIndexService:           libraryIndexService,
+		webhookService:                webhookService,
+		watchlistService:              watchlistService,
+		notifier:                      notifier,
+		bandwidthScheduler:            bandwidthScheduler,
+		feedPoller:                    feedPoller,
+		upcomingSourcing:              subscriptionService,
+	}
+}
+
+// configureLibraryIndex opens the local index of the Stash library. Like the
+// other caches it lives in memory unless Moji runs with a config store.
+func configureLibraryIndex(stashClient *stash.Client, persistent bool) *libraryindex.Service {
+	if stashClient == nil {
+		logging.Infof("runtime: library index disabled because stash client is not available")
+		return nil
+	}
+	path := fmt.Sprintf("file:moji-library-index-%d?mode=memory&cache=shared", runtimeCacheSequence.Add(1))
+	if persistent {
+		path = libraryIndexDatabasePath()
+	}
+	service, err := libraryindex.New(path, stashClient)
+	if err != nil {
+		logging.Fatalf("configure library index: %v", err)
 	}
+	return service
 }
 
 func configureTaskFlow(taskRuntimeService graphqlapi.TaskRuntimeService) *taskflow.Service {
@@ -387,7 +537,7 @@ func jackettClientOf(tr tracker.Tracker) *jackett.Client {
 	return nil
 }
 
-func configureTaskRuntime(cfg *config.Config, configStore *config.Store, tr tracker.Tracker, torrent graphqlapi.TorrentClient, stashClient *stash.Client, taskEvents *taskruntime.TaskEventBus) graphqlapi.TaskRuntimeService {
+func configureTaskRuntime(cfg *config.Config, configStore *config.Store, tr tracker.Tracker, torrent graphqlapi.TorrentClient, contents taskruntime.TorrentContentLister, stashClient *stash.Client, metadataService *metadata.Service, taskEvents *taskruntime.TaskEventBus, inspectionCache taskruntime.TorrentInspectionCache, libraryIndex *libraryindex.Service) graphqlapi.TaskRuntimeService {
 	if torrent == nil {
 		logging.Infof("runtime: task runtime disabled because qBittorrent client is not available")
 		return nil
@@ -397,18 +547,35 @@ func configureTaskRuntime(cfg *config.Config, configStore *config.Store, tr trac
 	if err != nil {
 		logging.Fatalf("configure task store: %v", err)
 	}
-	eventingStore, err := taskruntime.NewEventingTaskStore(store, taskEvents)
+	eventingStore, err := taskruntime.NewEventingTaskStore(store, taskEvents, taskruntime.WithStageTransitionHook(taskhooks.New(configureTaskHooksProvider(configStore, cfg))))
 	if err != nil {
 		logging.Fatalf("configure task event store: %v", err)
 	}
-	service, err := taskruntime.NewService(
-		tr,
-		torrent,
-		eventingStore,
+	options := []taskruntime.Option{
 		taskruntime.WithCandidateSelectionProvider(configureTorrentSelectionProvider(configStore, cfg)),
 		taskruntime.WithTaskDeletePolicyProvider(configureTaskDeletePolicyProvider(configStore, cfg)),
-		taskruntime.WithLibraryCodeChecker(stashLibraryCodeChecker{client: stashClient}),
-	)
+		taskruntime.WithTorrentInspectionCache(inspectionCache),
+		taskruntime.WithDiskSpaceProvider(configureDiskSpaceProvider(configStore, cfg)),
+		taskruntime.WithManagedCategory(func() string { return storeQBittorrent(cfg, configStore).Category }),
+		taskruntime.WithTorrentContentLister(contents),
+		taskruntime.WithSubtitler(subtitle.New(configureSubtitleProvider(configStore, cfg))),
+		taskruntime.WithMediaProber(mediaProber{prober: mediaprobe.New(configureMediaProbeProvider(configStore, cfg)), store: configStore, cfg: cfg}),
+	}
+	if metadataService != nil {
+		scenes := stashBoxScenes{metadata: metadataService}
+		options = append(options, taskruntime.WithSceneDurationLookup(scenes), taskruntime.WithSceneAttributesLookup(scenes))
+	}
+	if stashClient != nil {
+		linker, err := stashsync.NewSceneLinker(stashClient)
+		if err != nil {
+			logging.Fatalf("configure stash scene linker: %v", err)
+		}
+		options = append(options, taskruntime.WithSceneLinker(linker))
+	}
+	if libraryIndex != nil {
+		options = append(options, taskruntime.WithLibraryCodeChecker(libraryIndex), taskruntime.WithLibraryIndexSyncer(libraryIndex))
+	}
+	service, err := taskruntime.NewService(tr, torrent, eventingStore, options...)
 	if err != nil {
 		logging.Fatalf("configure task runtime: %v", err)
 	}
@@ -416,6 +583,219 @@ func configureTaskRuntime(cfg *config.Config, configStore *config.Store, tr trac
 	return service
 }
 
+func configureDiskSpaceProvider(store *config.Store, cfg *config.Config) func() taskruntime.DiskSpaceConfig {
+	return func() taskruntime.DiskSpaceConfig {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		guard := current.Ingest.DiskSpace.Normalize()
+		return taskruntime.DiskSpaceConfig{
+			Enabled:         guard.EffectiveEnabled(),
+			Downloads:       stashIntegrationConfig(current).Downloads,
+			DefaultSavePath: strings.TrimSpace(current.Connection.QBittorrent.DefaultSavePath),
+			SaveRoots:       guard.SaveRoots,
+			Policy:          guard.SaveRootPolicy,
+			ReserveBytes:    int64(guard.ReserveMB) << 20,
+		}
+	}
+}
+
+func configureWebhooksProvider(store *config.Store, cfg *config.Config) webhook.ConfigProvider {
+	return func() []webhook.Webhook {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		webhooks := make([]webhook.Webhook, 0, len(current.Notifications.Webhooks))
+		for _, item := range current.Notifications.Normalize().Webhooks {
+			if !item.EffectiveEnabled() {
+				continue
+			}
+			webhooks = append(webhooks, webhook.Webhook{
+				Name:        item.Name,
+				URL:         item.URL,
+				Secret:      item.Secret,
+				Events:      item.Events,
+				MaxAttempts: item.MaxAttempts,
+				Timeout:     time.Duration(item.TimeoutSeconds) * time.Second,
+			})
+		}
+		return webhooks
+	}
+}
+
+func configureNotifyProvider(store *config.Store, cfg *config.Config) notify.ConfigProvider {
+	return func() notify.Settings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		notifications := current.Notifications.Normalize()
+		settings := notify.Settings{Channels: make([]notify.Channel, 0, len(notifications.Channels))}
+		for _, item := range notifications.Channels {
+			if !item.Enabled || item.Validate() != nil {
+				continue
+			}
+			settings.Channels = append(settings.Channels, notifyChannel(item))
+		}
+		for _, item := range notifications.EffectiveTemplates() {
+			settings.Templates = append(settings.Templates, notify.Template{Event: notify.EventType(item.Event), Title: item.Title, Body: item.Body})
+		}
+		return settings
+	}
+}
+
+func notifyChannel(item config.NotificationChannelConfig) notify.Channel {
+	events := make([]notify.EventType, 0, len(item.Events))
+	for _, event := range item.Events {
+		events = append(events, notify.EventType(event))
+	}
+	return notify.Channel{
+		Name:     item.Name,
+		Type:     notify.ChannelType(item.Type),
+		Events:   events,
+		URL:      item.URL,
+		Token:    item.Token,
+		ChatID:   item.ChatID,
+		Topic:    item.Topic,
+		Priority: item.Priority,
+	}
+}
+
+func configureBandwidthProvider(store *config.Store, cfg *config.Config) bandwidth.ConfigProvider {
+	return func() bandwidth.Settings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		schedule := current.Automation.Bandwidth.Normalize()
+		settings := bandwidth.Settings{Enabled: schedule.Enabled, Profiles: make([]bandwidth.Profile, 0, len(schedule.Profiles))}
+		for _, item := range schedule.Profiles {
+			profile := bandwidth.Profile{
+				Name:             item.Name,
+				DownloadLimit:    item.DownloadLimitKiB * 1024,
+				UploadLimit:      item.UploadLimitKiB * 1024,
+				AlternativeSpeed: item.AlternativeSpeed,
+			}
+			for _, window := range item.Windows {
+				start, startErr := config.ParseClockMinutes(window.Start)
+				end, endErr := config.ParseClockMinutes(window.End)
+				if startErr != nil || endErr != nil {
+					continue
+				}
+				days := make([]time.Weekday, 0, len(window.Days))
+				for _, day := range window.Days {
+					if weekday, ok := config.BandwidthWeekday(day); ok {
+						days = append(days, weekday)
+					}
+				}
+				profile.Windows = append(profile.Windows, bandwidth.Window{Days: days, Start: start, End: end})
+			}
+			settings.Profiles = append(settings.Profiles, profile)
+		}
+		return settings
+	}
+}
+
+func configureFeedPollingProvider(store *config.Store, cfg *config.Config) feedpoll.ConfigProvider {
+	return func() feedpoll.Settings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		polling := current.Automation.FeedPolling.Normalize()
+		return feedpoll.Settings{
+			Enabled:    polling.Enabled,
+			Interval:   time.Duration(polling.IntervalMinutes) * time.Minute,
+			Indexers:   polling.Indexers,
+			Categories: polling.Categories,
+		}
+	}
+}
+
+func configureUpcomingReleasesProvider(store *config.Store, cfg *config.Config) subscription.UpcomingConfigProvider {
+	return func() subscription.UpcomingSettings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		upcoming := current.Automation.UpcomingReleases.Normalize()
+		return subscription.UpcomingSettings{
+			Enabled:       *upcoming.Enabled,
+			Delay:         time.Duration(*upcoming.DelayHours) * time.Hour,
+			RetryInterval: time.Duration(upcoming.RetryIntervalHours) * time.Hour,
+			MaxAttempts:   upcoming.MaxAttempts,
+		}
+	}
+}
+
+func configureTaskHooksProvider(store *config.Store, cfg *config.Config) taskhooks.ConfigProvider {
+	return func() []taskhooks.Hook {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		hooks := make([]taskhooks.Hook, 0, len(current.Automation.TaskHooks))
+		for _, hook := range config.NormalizeTaskHooks(current.Automation.TaskHooks) {
+			if !hook.EffectiveEnabled() {
+				continue
+			}
+			stages := make([]taskruntime.TaskStage, 0, len(hook.AfterStages))
+			for _, stage := range hook.AfterStages {
+				stages = append(stages, taskruntime.TaskStage(stage))
+			}
+			hooks = append(hooks, taskhooks.Hook{
+				Name:           hook.Name,
+				Command:        hook.Command,
+				Args:           hook.Args,
+				AfterStages:    stages,
+				Timeout:        time.Duration(hook.TimeoutSeconds) * time.Second,
+				BlockOnFailure: hook.BlockOnFailure,
+			})
+		}
+		return hooks
+	}
+}
+
+func configureSubtitleProvider(store *config.Store, cfg *config.Config) subtitle.ConfigProvider {
+	return func() subtitle.Settings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		subtitles := current.Ingest.Subtitles.Normalize()
+		settings := subtitle.Settings{Enabled: subtitles.Enabled}
+		for _, provider := range subtitles.Providers {
+			if !provider.EffectiveEnabled() {
+				continue
+			}
+			switch provider.Type {
+			case config.SubtitleProviderLibrary:
+				settings.Providers = append(settings.Providers, subtitle.NewLibraryProvider(provider.Name, provider.LibraryDirs, provider.Language))
+			case config.SubtitleProviderCommand:
+				settings.Providers = append(settings.Providers, subtitle.NewCommandProvider(provider.Name, provider.Command, provider.Args, time.Duration(provider.TimeoutSeconds)*time.Second, provider.Language))
+			}
+		}
+		return settings
+	}
+}
+
+func configureMediaProbeProvider(store *config.Store, cfg *config.Config) mediaprobe.ConfigProvider {
+	return func() mediaprobe.Settings {
+		current := cfg
+		if store != nil {
+			current = store.Config()
+		}
+		probe := current.Ingest.Probe.Normalize()
+		return mediaprobe.Settings{
+			Enabled:     probe.Enabled,
+			FFprobePath: probe.FFprobePath,
+			Timeout:     time.Duration(probe.TimeoutSeconds) * time.Second,
+		}
+	}
+}
+
 func configureTorrentSelectionProvider(store *config.Store, cfg *config.Config) func() config.TorrentSelectionConfig {
 	return func() config.TorrentSelectionConfig {
 		current := cfg
@@ -459,33 +839,103 @@ func configureTaskDeletePolicyProvider(store *config.Store, cfg *config.Config)
 	}
 }
 
-type stashLibraryCodeChecker struct {
-	client *stash.Client
+// mediaProber adapts mediaprobe to the task runtime and supplies the
+// configured validation checks.
+type mediaProber struct {
+	prober *mediaprobe.Prober
+	store  *config.Store
+	cfg    *config.Config
 }
 
-func (c stashLibraryCodeChecker) HasCode(ctx context.Context, code string) (bool, error) {
-	if c.client == nil {
-		return false, nil
+func (p mediaProber) Enabled() bool { return p.prober.Enabled() }
+
+func (p mediaProber) Validation() taskruntime.MediaValidation {
+	current := p.cfg
+	if p.store != nil {
+		current = p.store.Config()
 	}
-	code = strings.TrimSpace(code)
-	if code == "" {
-		return false, nil
+	probe := current.Ingest.Probe.Normalize()
+	return taskruntime.MediaValidation{
+		DurationTolerance: float64(probe.DurationTolerancePercent) / 100,
+		MinHeight:         probe.MinHeight,
+		MinBitRate:        int64(probe.MinBitRateKbps) * 1000,
 	}
-	page := 1
-	perPage := 1
-	scenes, err := c.client.FindScenes(ctx, &stashgraphql.SceneFilterType{
-		Code: &stashgraphql.StringCriterionInput{
-			Value:    code,
-			Modifier: stashgraphql.CriterionModifierEquals,
-		},
-	}, &stashgraphql.FindFilterType{
-		Page:    &page,
-		PerPage: &perPage,
+}
+
+func (p mediaProber) Probe(ctx context.Context, path string) (taskruntime.MediaProbe, error) {
+	result, err := p.prober.Probe(ctx, path)
+	return taskruntime.MediaProbe{
+		Path:            result.Path,
+		Container:       result.Container,
+		DurationSeconds: result.DurationSeconds,
+		Width:           result.Width,
+		Height:          result.Height,
+		VideoCodec:      result.VideoCodec,
+		AudioCodec:      result.AudioCodec,
+		BitRate:         result.BitRate,
+	}, err
+}
+
+// stashBoxScenes looks a code up on each StashBox endpoint in order and
+// reports on the first scene whose code matches exactly and has the details
+// asked for.
+type stashBoxScenes struct {
+	metadata *metadata.Service
+}
+
+func (l stashBoxScenes) SceneDuration(ctx context.Context, code string) (int, bool, error) {
+	scene, err := l.findScene(ctx, code, func(scene *stashboxgraphql.SceneFragment) bool {
+		return scene.Duration != nil && *scene.Duration > 0
 	})
-	if err != nil {
-		return false, err
+	if scene == nil {
+		return 0, false, err
+	}
+	return *scene.Duration, true, nil
+}
+
+func (l stashBoxScenes) SceneAttributes(ctx context.Context, code string) (taskruntime.SceneAttributes, bool, error) {
+	scene, err := l.findScene(ctx, code, func(*stashboxgraphql.SceneFragment) bool { return true })
+	if scene == nil {
+		return taskruntime.SceneAttributes{}, false, err
+	}
+	var attrs taskruntime.SceneAttributes
+	if scene.Studio != nil {
+		attrs.Studio = scene.Studio.Name
+	}
+	for _, tag := range scene.Tags {
+		if tag != nil {
+			attrs.Tags = append(attrs.Tags, tag.Name)
+		}
+	}
+	return attrs, true, nil
+}
+
+func (l stashBoxScenes) findScene(ctx context.Context, code string, usable func(*stashboxgraphql.SceneFragment) bool) (*stashboxgraphql.SceneFragment, error) {
+	want := codeparser.Normalize(code)
+	if want == "" {
+		return nil, nil
 	}
-	return len(scenes) > 0, nil
+	var lastErr error
+	for _, box := range l.metadata.Endpoints() {
+		client, ok := l.metadata.Get(box.Endpoint)
+		if !ok || client == nil {
+			continue
+		}
+		scenes, err := client.SearchScene(ctx, want)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, scene := range scenes {
+			if scene == nil || scene.Code == nil || !usable(scene) {
+				continue
+			}
+			if codeparser.Normalize(*scene.Code) == want {
+				return scene, nil
+			}
+		}
+	}
+	return nil, lastErr
 }
 
 // configureQBittorrentConfigProvider returns the latest qBittorrent config
@@ -578,8 +1028,38 @@ func buildSettingsSnapshot(cfg *config.Config, version string) *graphqlapi.Setti
 			TaskDeletePolicy:  string(cfg.System.EffectiveTaskDeletePolicy()),
 			ImageCache:        graphqlapi.ImageCacheSettingsSnapshot{Enabled: cfg.System.ImageCache.EffectiveEnabled(), MaxSizeMB: cfg.System.ImageCache.Normalize().MaxSizeMB, RetentionDays: cfg.System.ImageCache.Normalize().RetentionDays},
 			StashBoxDataCache: graphqlapi.StashBoxDataCacheSettingsSnapshot{TTLHours: cfg.System.StashBoxDataCache.Normalize().TTLHours},
+			TorrentInspectionCache: graphqlapi.TorrentInspectionCacheSettingsSnapshot{
+				TTLHours:   cfg.System.TorrentInspectionCache.Normalize().TTLHours,
+				MaxEntries: cfg.System.TorrentInspectionCache.Normalize().MaxEntries,
+			},
 		},
+		Notifications: notificationSettingsSnapshot(cfg.Notifications.Normalize()),
+	}
+}
+
+func notificationSettingsSnapshot(cfg config.NotificationsConfig) graphqlapi.NotificationSettingsSnapshot {
+	out := graphqlapi.NotificationSettingsSnapshot{Channels: make([]graphqlapi.NotificationChannelSnapshot, 0, len(cfg.Channels))}
+	for _, channel := range cfg.Channels {
+		events := make([]string, 0, len(channel.Events))
+		for _, event := range channel.Events {
+			events = append(events, string(event))
+		}
+		out.Channels = append(out.Channels, graphqlapi.NotificationChannelSnapshot{
+			Name:     channel.Name,
+			Type:     string(channel.Type),
+			Enabled:  channel.Enabled,
+			Events:   events,
+			URL:      channel.URL,
+			Token:    channel.Token,
+			ChatID:   channel.ChatID,
+			Topic:    channel.Topic,
+			Priority: channel.Priority,
+		})
+	}
+	for _, item := range cfg.EffectiveTemplates() {
+		out.Templates = append(out.Templates, graphqlapi.NotificationTemplateSnapshot{Event: string(item.Event), Title: item.Title, Body: item.Body})
 	}
+	return out
 }
 
 func torrentSelectionSnapshot(cfg config.TorrentSelectionConfig) graphqlapi.TorrentSelectionSettingsSnapshot {
@@ -720,6 +1200,42 @@ func loadStashLibraries(stashConfigured bool, stashClient *stash.Client) ([]grap
 	return out, ""
 }
 
+// startTaskReconciliation reports drift between tasks and qBittorrent once at
+// startup. It only logs; fixes are applied on demand through GraphQL.
+func startTaskReconciliation(ctx context.Context, service graphqlapi.TaskRuntimeService) {
+	if service == nil {
+		return
+	}
+	go func() {
+		reconcileCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		report, err := service.Reconcile(reconcileCtx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logging.Warnf("reconcile tasks with qBittorrent: %v", err)
+			}
+			return
+		}
+		if len(report.Issues) == 0 {
+			logging.Infof("runtime: task reconciliation found no drift across %d tasks and %d torrents", report.TaskCount, report.TorrentCount)
+			return
+		}
+		logging.Warnf("runtime: task reconciliation found %d issues across %d tasks and %d torrents", len(report.Issues), report.TaskCount, report.TorrentCount)
+		for _, issue := range report.Issues {
+			logging.Warnf("runtime: reconcile %s task=%s torrent=%s: %s", issue.Kind, issue.TaskID, issue.TorrentHash, issue.Detail)
+		}
+	}()
+}
+
+// startStashJobWatcher follows Stash job updates so scanning tasks complete
+// as soon as their job finishes instead of on the next sync tick.
+func startStashJobWatcher(ctx context.Context, service graphqlapi.TaskRuntimeService, stash graphqlapi.StashService) {
+	if service == nil || stash == nil {
+		return
+	}
+	go service.WatchStashJobs(ctx, stash)
+}
+
 func startTaskSyncWorker(ctx context.Context, service graphqlapi.TaskRuntimeService, stash graphqlapi.StashService, intervalProvider func() time.Duration) {
 	if service == nil || intervalProvider == nil || intervalProvider() <= 0 {
 		if service == nil {
@@ -804,7 +1320,7 @@ func storeStash(cfg *config.Config, store *config.Store) *config.StashConfig {
 	return &cfg.Connection.Stash
 }
 
-func configureStashService(cfg *config.Config, store *config.Store, client *stash.Client) graphqlapi.StashService {
+func configureStashService(cfg *config.Config, store *config.Store, client *stash.Client, metadataService *metadata.Service) graphqlapi.StashService {
 	if client == nil {
 		logging.Infof("runtime: stash sync service disabled because stash client is not available")
 		return nil
@@ -815,7 +1331,13 @@ func configureStashService(cfg *config.Config, store *config.Store, client *stas
 		if store != nil {
 			current = store.Config()
 		}
-		return stashIntegrationConfig(current)
+		integration := stashIntegrationConfig(current)
+		if integration.Identify.Enabled && metadataService != nil {
+			for _, box := range metadataService.Endpoints() {
+				integration.Identify.Sources = append(integration.Identify.Sources, box.Endpoint)
+			}
+		}
+		return integration
 	}
 	service, err := stashsync.NewService(client, configProvider)
 	if err != nil {
@@ -850,6 +1372,52 @@ func stashIntegrationConfig(cfg *config.Config) stashsync.IntegrationConfig {
 		Transfer: stashsync.TransferConfig{
 			Action: stashsync.TransferAction(strings.TrimSpace(cfg.Ingest.Transfer.Action)),
 		},
+		Identify: stashsync.IdentifyConfig{Enabled: cfg.Ingest.Identify.Enabled},
+		Scan:     stashScanConfig(cfg.Ingest.Scan),
+		Routes:   stashLibraryRoutes(cfg.Ingest.Routes),
+	}
+}
+
+func stashLibraryRoutes(routes []config.LibraryRouteConfig) []stashsync.LibraryRoute {
+	out := make([]stashsync.LibraryRoute, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, stashsync.LibraryRoute{
+			Name:          route.Name,
+			CodePrefixes:  route.CodePrefixes,
+			Studios:       route.Studios,
+			Tags:          route.Tags,
+			TitleKeywords: route.TitleKeywords,
+			Sources:       route.Sources,
+			Library: stashsync.LibraryPathConfig{
+				MojiRoot:  route.Library.MojiRoot,
+				StashRoot: route.Library.StashRoot,
+			},
+		})
+	}
+	return out
+}
+
+func stashScanConfig(scan config.ScanIngestConfig) stashsync.ScanConfig {
+	out := stashsync.ScanConfig{Defaults: stashScanOptions(scan.ScanOptionsConfig)}
+	for _, library := range scan.Libraries {
+		out.Libraries = append(out.Libraries, stashsync.LibraryScanOptions{
+			Path:    library.Path,
+			Options: stashScanOptions(library.ScanOptionsConfig),
+		})
+	}
+	return out
+}
+
+func stashScanOptions(options config.ScanOptionsConfig) stashsync.ScanOptions {
+	return stashsync.ScanOptions{
+		Rescan:                    options.Rescan,
+		ScanGenerateCovers:        options.GenerateCovers,
+		ScanGeneratePreviews:      options.GeneratePreviews,
+		ScanGenerateImagePreviews: options.GenerateImagePreviews,
+		ScanGenerateSprites:       options.GenerateSprites,
+		ScanGeneratePhashes:       options.GeneratePhashes,
+		ScanGenerateThumbnails:    options.GenerateThumbnails,
+		ScanGenerateClipPreviews:  options.GenerateClipPreviews,
 	}
 }
 
@@ -957,7 +1525,7 @@ func storeAutomation(cfg *config.Config, store *config.Store) *config.Config {
 	return cfg
 }
 
-func startSubscriptionWorker(ctx context.Context, service graphqlapi.SubscriptionService, intervalProvider func() time.Duration) {
+func startSubscriptionWorker(ctx context.Context, service graphqlapi.SubscriptionService, studios graphqlapi.StudioSubscriptionService, intervalProvider func() time.Duration) {
 	if service == nil || intervalProvider == nil || intervalProvider() <= 0 {
 		if service == nil {
 			logging.Infof("runtime: subscription worker not started because subscription service is unavailable")
@@ -984,6 +1552,13 @@ func startSubscriptionWorker(ctx context.Context, service graphqlapi.Subscriptio
 					logging.Errorf("refresh subscription performers: %v", err)
 				}
 				cancel()
+				if studios != nil {
+					studioCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+					if _, err := studios.RefreshStudios(studioCtx); err != nil && !errors.Is(err, context.Canceled) {
+						logging.Errorf("refresh subscription studios: %v", err)
+					}
+					cancel()
+				}
 
 				// Re-check the interval after each tick so Web UI edits to
 				// automation.subscriptionPollIntervalHours take effect on the
@@ -1009,6 +1584,14 @@ func runtimeDatabasePath() string {
 
 func stashBoxCacheDatabasePath() string { return "cache/stashbox/cache.db" }
 
+func inspectionCacheDatabasePath() string { return "cache/inspection/cache.db" }
+
+func libraryIndexDatabasePath() string { return "cache/library/index.db" }
+
+func webhookDatabasePath() string { return "webhooks.db" }
+
+func watchlistDatabasePath() string { return "watchlists.db" }
+
 func effectiveTaskProgressSyncIntervalSeconds(cfg *config.Config) int {
 	seconds := cfg.Automation.TaskProgressSyncIntervalSeconds
 	if seconds < 0 {
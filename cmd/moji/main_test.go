@@ -550,6 +550,8 @@ func (f *fakeProgressSyncService) TriggerStashScans(context.Context, taskruntime
 	return nil, nil
 }
 
+func (f *fakeProgressSyncService) WatchStashJobs(context.Context, taskruntime.StashJobWatcher) {}
+
 func (f *fakeProgressSyncService) RetryTasks(context.Context, []string, taskruntime.StashScanner) (taskruntime.TaskBatchPayload, error) {
 	return taskruntime.TaskBatchPayload{}, nil
 }
@@ -562,12 +564,32 @@ func (f *fakeProgressSyncService) DeleteTasks(context.Context, []string) (taskru
 	return taskruntime.TaskBatchPayload{}, nil
 }
 
+func (f *fakeProgressSyncService) Reconcile(context.Context) (taskruntime.ReconcileReport, error) {
+	return taskruntime.ReconcileReport{}, nil
+}
+
+func (f *fakeProgressSyncService) ApplyReconcileFix(context.Context, taskruntime.ReconcileFixRequest) (*taskruntime.Task, error) {
+	return nil, nil
+}
+
+func (f *fakeProgressSyncService) PreviewTorrentAdoption(context.Context, taskruntime.TorrentAdoptionFilter) ([]taskruntime.TorrentAdoptionCandidate, error) {
+	return nil, nil
+}
+
+func (f *fakeProgressSyncService) AdoptTorrents(context.Context, taskruntime.TorrentAdoptionRequest) (taskruntime.TorrentAdoptionPayload, error) {
+	return taskruntime.TorrentAdoptionPayload{}, nil
+}
+
 type fakeConfiguredStashService struct{}
 
 func (fakeConfiguredStashService) MetadataScan(context.Context, stashsync.ScanRequest) (string, error) {
 	return "job-test", nil
 }
 
+func (fakeConfiguredStashService) MetadataIdentify(context.Context, stashsync.IdentifyRequest) (string, error) {
+	return "job-identify", nil
+}
+
 func (fakeConfiguredStashService) FindJob(context.Context, string) (*stashsync.Job, error) {
 	return nil, nil
 }
@@ -575,3 +597,7 @@ func (fakeConfiguredStashService) FindJob(context.Context, string) (*stashsync.J
 func (fakeConfiguredStashService) CurrentConfig() stashsync.IntegrationConfig {
 	return stashsync.IntegrationConfig{}
 }
+
+func (fakeConfiguredStashService) WatchJobs(context.Context, func(), func(stashsync.Job)) error {
+	return stashsync.ErrJobSubscriptionUnsupported
+}
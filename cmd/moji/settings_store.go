@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"strings"
 
 	"github.com/leothevan2444/moji/internal/config"
 	"github.com/leothevan2444/moji/internal/graphqlapi"
 	"github.com/leothevan2444/moji/internal/logging"
 	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/notify"
 	"github.com/leothevan2444/moji/internal/subscription"
 	"github.com/leothevan2444/moji/pkg/stash"
 )
@@ -19,9 +22,10 @@ type runtimeSettingsEditor struct {
 	stashClient         *stash.Client
 	subscriptionService *subscription.Service
 	metadataService     *metadata.Service
+	notifier            *notify.Service
 }
 
-func newRuntimeSettingsEditor(store *config.Store, version string, downloaderEnabled bool, stashEnabled bool, stashClient *stash.Client, subscriptionService *subscription.Service, metadataService *metadata.Service) *runtimeSettingsEditor {
+func newRuntimeSettingsEditor(store *config.Store, version string, downloaderEnabled bool, stashEnabled bool, stashClient *stash.Client, subscriptionService *subscription.Service, metadataService *metadata.Service, notifier *notify.Service) *runtimeSettingsEditor {
 	return &runtimeSettingsEditor{
 		store:               store,
 		version:             version,
@@ -30,6 +34,7 @@ func newRuntimeSettingsEditor(store *config.Store, version string, downloaderEna
 		stashClient:         stashClient,
 		subscriptionService: subscriptionService,
 		metadataService:     metadataService,
+		notifier:            notifier,
 	}
 }
 
@@ -213,7 +218,7 @@ func (s *runtimeSettingsEditor) UpdateAutomationSettings(input graphqlapi.Update
 func (s *runtimeSettingsEditor) UpdateSystemSettings(input graphqlapi.UpdateSystemSettingsInput) (*graphqlapi.SettingsSnapshot, error) {
 	policy := config.NormalizeTaskDeletePolicy(input.TaskDeletePolicy)
 	enabled := input.ImageCache.Enabled
-	cfg, err := s.store.UpdateSystemWithDataCache(policy, config.ImageCacheConfig{Enabled: &enabled, MaxSizeMB: input.ImageCache.MaxSizeMB, RetentionDays: input.ImageCache.RetentionDays}, config.StashBoxDataCacheConfig{TTLHours: input.StashBoxDataCache.TTLHours})
+	cfg, err := s.store.UpdateSystemWithDataCache(policy, config.ImageCacheConfig{Enabled: &enabled, MaxSizeMB: input.ImageCache.MaxSizeMB, RetentionDays: input.ImageCache.RetentionDays}, config.StashBoxDataCacheConfig{TTLHours: input.StashBoxDataCache.TTLHours}, config.TorrentInspectionCacheConfig{TTLHours: input.TorrentInspectionCache.TTLHours, MaxEntries: input.TorrentInspectionCache.MaxEntries})
 	if err != nil {
 		logging.Errorf("settings: save system settings failed: %v", err)
 		return nil, err
@@ -221,3 +226,50 @@ func (s *runtimeSettingsEditor) UpdateSystemSettings(input graphqlapi.UpdateSyst
 	logging.Infof("settings: system settings saved task_delete_policy=%s", cfg.System.EffectiveTaskDeletePolicy())
 	return buildSettingsSnapshot(cfg, s.version), nil
 }
+
+func (s *runtimeSettingsEditor) UpdateNotificationSettings(input graphqlapi.NotificationSettingsSnapshot) (*graphqlapi.SettingsSnapshot, error) {
+	channels := make([]config.NotificationChannelConfig, 0, len(input.Channels))
+	for _, channel := range input.Channels {
+		channels = append(channels, notificationChannelConfig(channel))
+	}
+	templates := make([]config.NotificationTemplateConfig, 0, len(input.Templates))
+	for _, item := range input.Templates {
+		templates = append(templates, config.NotificationTemplateConfig{Event: config.NotificationEventType(item.Event), Title: item.Title, Body: item.Body})
+	}
+	cfg, err := s.store.UpdateNotifications(channels, templates)
+	if err != nil {
+		logging.Errorf("settings: save notification settings failed: %v", err)
+		return nil, err
+	}
+	logging.Infof("settings: notification settings saved channels=%d templates=%d", len(cfg.Notifications.Channels), len(cfg.Notifications.Templates))
+	return buildSettingsSnapshot(cfg, s.version), nil
+}
+
+func (s *runtimeSettingsEditor) TestNotificationChannel(ctx context.Context, input graphqlapi.NotificationChannelSnapshot) error {
+	if s.notifier == nil {
+		return errors.New("notifications are not configured")
+	}
+	channel := notificationChannelConfig(input).Normalize()
+	if err := channel.Validate(); err != nil {
+		return err
+	}
+	return s.notifier.Test(ctx, notifyChannel(channel))
+}
+
+func notificationChannelConfig(input graphqlapi.NotificationChannelSnapshot) config.NotificationChannelConfig {
+	events := make([]config.NotificationEventType, 0, len(input.Events))
+	for _, event := range input.Events {
+		events = append(events, config.NotificationEventType(event))
+	}
+	return config.NotificationChannelConfig{
+		Name:     input.Name,
+		Type:     config.NotificationChannelType(input.Type),
+		Enabled:  input.Enabled,
+		Events:   events,
+		URL:      input.URL,
+		Token:    input.Token,
+		ChatID:   input.ChatID,
+		Topic:    input.Topic,
+		Priority: input.Priority,
+	}
+}
@@ -0,0 +1,100 @@
+package bandwidth
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// DefaultProfileName is reported when no scheduled window matches and the
+// limits are lifted.
+const DefaultProfileName = "DEFAULT"
+
+var ErrProfileNotFound = errors.New("bandwidth: profile not found")
+
+// Profile is one set of qBittorrent global limits. Limits are bytes/s and
+// zero means unlimited.
+type Profile struct {
+	Name             string
+	DownloadLimit    int
+	UploadLimit      int
+	AlternativeSpeed bool
+	Windows          []Window
+}
+
+// Window covers [Start, End) minutes after local midnight on each of Days
+// (every day when empty). An End at or before Start wraps past midnight, and
+// Days then names the day the window opens.
+type Window struct {
+	Days  []time.Weekday
+	Start int
+	End   int
+}
+
+// Settings is the live schedule configuration.
+type Settings struct {
+	Enabled  bool
+	Profiles []Profile
+}
+
+type ConfigProvider func() Settings
+
+func defaultProfile() Profile {
+	return Profile{Name: DefaultProfileName}
+}
+
+// Active returns the first profile with a window covering now, or the
+// unlimited default profile.
+func (s Settings) Active(now time.Time) (Profile, bool) {
+	for _, profile := range s.Profiles {
+		for _, window := range profile.Windows {
+			if window.covers(now) {
+				return profile, true
+			}
+		}
+	}
+	return defaultProfile(), false
+}
+
+func (s Settings) profile(name string) (Profile, bool) {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, DefaultProfileName) {
+		return defaultProfile(), true
+	}
+	for _, profile := range s.Profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, true
+		}
+	}
+	return Profile{}, false
+}
+
+func (w Window) covers(now time.Time) bool {
+	minute := now.Hour()*60 + now.Minute()
+	if w.Start < w.End {
+		return w.onDay(now.Weekday()) && minute >= w.Start && minute < w.End
+	}
+	if minute >= w.Start {
+		return w.onDay(now.Weekday())
+	}
+	return minute < w.End && w.onDay(now.AddDate(0, 0, -1).Weekday())
+}
+
+func (w Window) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, candidate := range w.Days {
+		if candidate == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Profile) sameLimits(other Profile) bool {
+	return p.Name == other.Name &&
+		p.DownloadLimit == other.DownloadLimit &&
+		p.UploadLimit == other.UploadLimit &&
+		p.AlternativeSpeed == other.AlternativeSpeed
+}
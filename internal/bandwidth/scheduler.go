@@ -0,0 +1,235 @@
+package bandwidth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+)
+
+const tickInterval = time.Minute
+
+// Client is the subset of the qBittorrent transfer API the scheduler drives.
+type Client interface {
+	SetGlobalDownloadLimit(ctx context.Context, limit int) error
+	SetGlobalUploadLimit(ctx context.Context, limit int) error
+	GetAlternativeSpeedLimitsState(ctx context.Context) (bool, error)
+	ToggleAlternativeSpeedLimits(ctx context.Context) error
+}
+
+type Source string
+
+const (
+	SourceSchedule Source = "SCHEDULE"
+	SourceDefault  Source = "DEFAULT"
+	SourceOverride Source = "OVERRIDE"
+	SourceDisabled Source = "DISABLED"
+)
+
+// Status describes the profile the scheduler wants applied right now and
+// the outcome of the last attempt to apply it.
+type Status struct {
+	Enabled       bool
+	Profile       Profile
+	Source        Source
+	OverrideUntil *time.Time
+	AppliedAt     *time.Time
+	LastError     string
+	Profiles      []Profile
+}
+
+type override struct {
+	profile string
+	until   *time.Time
+}
+
+// Scheduler re-evaluates the schedule every minute and pushes the limits to
+// qBittorrent only when the desired profile changes, so limits the user sets
+// by hand in qBittorrent survive until the next transition.
+type Scheduler struct {
+	client   Client
+	settings ConfigProvider
+	now      func() time.Time
+
+	applyMu   sync.Mutex
+	mu        sync.Mutex
+	override  *override
+	applied   *Profile
+	appliedAt *time.Time
+	lastError string
+}
+
+type Option func(*Scheduler)
+
+func WithClock(now func() time.Time) Option {
+	return func(scheduler *Scheduler) {
+		if now != nil {
+			scheduler.now = now
+		}
+	}
+}
+
+func New(client Client, provider ConfigProvider, options ...Option) *Scheduler {
+	if provider == nil {
+		provider = func() Settings { return Settings{} }
+	}
+	scheduler := &Scheduler{
+		client:   client,
+		settings: provider,
+		now:      time.Now,
+	}
+	for _, option := range options {
+		option(scheduler)
+	}
+	return scheduler
+}
+
+// Start applies the current profile and keeps re-evaluating it until ctx is
+// cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	if s == nil || s.client == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			s.Apply(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Apply pushes the desired profile to qBittorrent if it differs from the
+// last one applied successfully.
+func (s *Scheduler) Apply(ctx context.Context) {
+	if s.client == nil {
+		return
+	}
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+	s.mu.Lock()
+	profile, source := s.desiredLocked(s.settings())
+	if source == SourceDisabled {
+		// Leave qBittorrent alone, and forget what was applied so
+		// re-enabling the schedule pushes its profile again.
+		s.applied = nil
+		s.mu.Unlock()
+		return
+	}
+	if s.applied != nil && s.applied.sameLimits(profile) {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	err := s.push(ctx, profile)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if ctx.Err() == nil {
+			s.lastError = err.Error()
+			logging.Warnf("bandwidth: apply profile %s: %v", profile.Name, err)
+		}
+		return
+	}
+	now := s.now().UTC()
+	s.applied = &profile
+	s.appliedAt = &now
+	s.lastError = ""
+	logging.Infof("bandwidth: applied profile %s source=%s download_limit=%d upload_limit=%d alternative_speed=%t", profile.Name, source, profile.DownloadLimit, profile.UploadLimit, profile.AlternativeSpeed)
+}
+
+func (s *Scheduler) push(ctx context.Context, profile Profile) error {
+	if err := s.client.SetGlobalDownloadLimit(ctx, profile.DownloadLimit); err != nil {
+		return fmt.Errorf("set download limit: %w", err)
+	}
+	if err := s.client.SetGlobalUploadLimit(ctx, profile.UploadLimit); err != nil {
+		return fmt.Errorf("set upload limit: %w", err)
+	}
+	enabled, err := s.client.GetAlternativeSpeedLimitsState(ctx)
+	if err != nil {
+		return fmt.Errorf("read alternative speed state: %w", err)
+	}
+	if enabled != profile.AlternativeSpeed {
+		if err := s.client.ToggleAlternativeSpeedLimits(ctx); err != nil {
+			return fmt.Errorf("toggle alternative speed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) desiredLocked(settings Settings) (Profile, Source) {
+	now := s.now()
+	if s.override != nil && s.override.until != nil && !now.Before(*s.override.until) {
+		s.override = nil
+	}
+	if s.override != nil {
+		if profile, ok := settings.profile(s.override.profile); ok {
+			return profile, SourceOverride
+		}
+		s.override = nil
+	}
+	if !settings.Enabled {
+		return Profile{}, SourceDisabled
+	}
+	if profile, ok := settings.Active(now); ok {
+		return profile, SourceSchedule
+	}
+	return defaultProfile(), SourceDefault
+}
+
+// Override pins a profile regardless of the schedule, until the given
+// duration elapses or indefinitely when it is zero.
+func (s *Scheduler) Override(ctx context.Context, name string, duration time.Duration) (Status, error) {
+	settings := s.settings()
+	profile, ok := settings.profile(name)
+	if !ok {
+		return Status{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	s.mu.Lock()
+	next := &override{profile: profile.Name}
+	if duration > 0 {
+		until := s.now().Add(duration).UTC()
+		next.until = &until
+	}
+	s.override = next
+	s.mu.Unlock()
+	s.Apply(ctx)
+	return s.Status(), nil
+}
+
+// ClearOverride hands control back to the schedule.
+func (s *Scheduler) ClearOverride(ctx context.Context) Status {
+	s.mu.Lock()
+	s.override = nil
+	s.mu.Unlock()
+	s.Apply(ctx)
+	return s.Status()
+}
+
+func (s *Scheduler) Status() Status {
+	settings := s.settings()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, source := s.desiredLocked(settings)
+	status := Status{
+		Enabled:   settings.Enabled,
+		Profile:   profile,
+		Source:    source,
+		AppliedAt: s.appliedAt,
+		LastError: s.lastError,
+		Profiles:  append([]Profile(nil), settings.Profiles...),
+	}
+	if s.override != nil {
+		status.OverrideUntil = s.override.until
+	}
+	return status
+}
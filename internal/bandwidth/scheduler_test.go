@@ -0,0 +1,126 @@
+package bandwidth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	mu          sync.Mutex
+	download    int
+	upload      int
+	alternative bool
+	calls       int
+	err         error
+}
+
+func (f *fakeClient) SetGlobalDownloadLimit(_ context.Context, limit int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	f.download = limit
+	return nil
+}
+
+func (f *fakeClient) SetGlobalUploadLimit(_ context.Context, limit int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.upload = limit
+	return nil
+}
+
+func (f *fakeClient) GetAlternativeSpeedLimitsState(context.Context) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.alternative, nil
+}
+
+func (f *fakeClient) ToggleAlternativeSpeedLimits(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.alternative = !f.alternative
+	return nil
+}
+
+func workHours() Settings {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	return Settings{Enabled: true, Profiles: []Profile{
+		{Name: "work", DownloadLimit: 1 << 20, UploadLimit: 256 << 10, Windows: []Window{{Days: weekdays, Start: 9 * 60, End: 18 * 60}}},
+		{Name: "late", AlternativeSpeed: true, Windows: []Window{{Days: []time.Weekday{time.Friday}, Start: 23 * 60, End: 2 * 60}}},
+		{Name: "quiet", DownloadLimit: 64 << 10},
+	}}
+}
+
+func TestSettingsActiveMatchesWindows(t *testing.T) {
+	settings := workHours()
+	for _, test := range []struct {
+		at   string
+		want string
+	}{
+		{"2026-10-19 10:30", "work"},             // Monday
+		{"2026-10-19 18:00", DefaultProfileName}, // end is exclusive
+		{"2026-10-18 10:30", DefaultProfileName}, // Sunday
+		{"2026-10-23 23:30", "late"},             // Friday night
+		{"2026-10-24 01:15", "late"},             // wraps into Saturday
+		{"2026-10-25 01:15", DefaultProfileName}, // Sunday early is not a Friday window
+	} {
+		now, err := time.ParseInLocation("2006-01-02 15:04", test.at, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := settings.Active(now); got.Name != test.want {
+			t.Errorf("Active(%s) = %s, want %s", test.at, got.Name, test.want)
+		}
+	}
+}
+
+func TestSchedulerAppliesTransitionsAndOverrides(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	client := &fakeClient{alternative: true}
+	scheduler := New(client, workHours, WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	scheduler.Apply(ctx)
+	if client.download != 1<<20 || client.upload != 256<<10 || client.alternative {
+		t.Fatalf("work profile not applied: %+v", client)
+	}
+	scheduler.Apply(ctx)
+	if client.calls != 1 {
+		t.Fatalf("unchanged profile should not be pushed again, calls=%d", client.calls)
+	}
+
+	status, err := scheduler.Override(ctx, "QUIET", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("Override: %v", err)
+	}
+	if status.Source != SourceOverride || status.Profile.Name != "quiet" || status.OverrideUntil == nil || client.download != 64<<10 {
+		t.Fatalf("unexpected override status %+v client %+v", status, client)
+	}
+	if _, err := scheduler.Override(ctx, "missing", 0); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	now = now.Add(31 * time.Minute)
+	scheduler.Apply(ctx)
+	if got := scheduler.Status(); got.Source != SourceSchedule || got.Profile.Name != "work" || client.download != 1<<20 {
+		t.Fatalf("expired override should hand back to schedule, got %+v", got)
+	}
+
+	now = time.Date(2026, 10, 19, 20, 0, 0, 0, time.Local)
+	client.err = errors.New("qb unreachable")
+	scheduler.Apply(ctx)
+	if got := scheduler.Status(); got.Source != SourceDefault || got.LastError == "" {
+		t.Fatalf("expected default profile with apply error, got %+v", got)
+	}
+	client.err = nil
+	scheduler.Apply(ctx)
+	if got := scheduler.Status(); got.LastError != "" || client.download != 0 || client.upload != 0 {
+		t.Fatalf("expected limits lifted after retry, got %+v client %+v", got, client)
+	}
+}
@@ -0,0 +1,108 @@
+package codeparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FamilyFC2          = "fc2"
+	FamilyHeyzo        = "heyzo"
+	FamilyT28          = "t28"
+	Family1Pondo       = "1pondo"
+	FamilyCaribbeancom = "caribbeancom"
+	Family10Musume     = "10musume"
+	FamilyPacopacomama = "pacopacomama"
+	FamilyGeneric      = "generic"
+)
+
+// builtinFamilies lists the code families recognised out of the box, most
+// specific first.
+func builtinFamilies() []Family {
+	return []Family{
+		{
+			Name:    FamilyFC2,
+			Pattern: regexp.MustCompile(`(?i)\bfc2[-_\s]*(?:ppv[-_\s]*)?(\d{5,8})(?:$|[^0-9])`),
+			Normalize: func(match []string) string {
+				return "FC2-PPV-" + match[1]
+			},
+			SearchQueries: func(code string) []string {
+				number := strings.TrimPrefix(code, "FC2-PPV-")
+				return []string{"FC2PPV-" + number, "FC2-" + number}
+			},
+			LibraryCodes: func(code string) []string {
+				number := strings.TrimPrefix(code, "FC2-PPV-")
+				return []string{"FC2PPV-" + number, "FC2-" + number}
+			},
+		},
+		{
+			Name:    FamilyHeyzo,
+			Pattern: regexp.MustCompile(`(?i)\bheyzo[-_\s]*(?:hd[-_\s]*)?(\d{4})(?:$|[^0-9])`),
+			Normalize: func(match []string) string {
+				return "HEYZO-" + match[1]
+			},
+			SearchQueries: func(code string) []string {
+				return []string{"HEYZO " + strings.TrimPrefix(code, "HEYZO-")}
+			},
+		},
+		{
+			Name:    FamilyT28,
+			Pattern: regexp.MustCompile(`(?i)\bt-?28[-_\s]?(\d{3,4})(?:$|[^0-9])`),
+			Normalize: func(match []string) string {
+				return "T28-" + match[1]
+			},
+			SearchQueries: func(code string) []string {
+				number := strings.TrimPrefix(code, "T28-")
+				return []string{"T-28-" + number, "T-28" + number}
+			},
+			LibraryCodes: func(code string) []string {
+				number := strings.TrimPrefix(code, "T28-")
+				return []string{"T-28-" + number, "T-28" + number}
+			},
+		},
+		uncensoredFamily(Family1Pondo, `1pon(?:do)?`, "_", 3, "1pondo", true),
+		uncensoredFamily(FamilyCaribbeancom, `carib(?:bean)?(?:com)?`, "-", 3, "Caribbeancom", true),
+		uncensoredFamily(Family10Musume, `10mu(?:sume)?`, "_", 2, "10musume", true),
+		uncensoredFamily(FamilyPacopacomama, `paco(?:pacomama)?`, "_", 3, "Pacopacomama", false),
+		{
+			Name:    FamilyGeneric,
+			Pattern: regexp.MustCompile(`(?i)\b([a-z]{2,10})[-_\s]?(\d{2,6})\b`),
+			Normalize: func(match []string) string {
+				return strings.ToUpper(match[1]) + "-" + match[2]
+			},
+		},
+	}
+}
+
+// uncensoredFamily builds a date-based studio family such as 1pondo. The
+// canonical code keeps the studio (1PONDO-123456_789) because several studios
+// share the same date format; the bare 123456_789 is only used as a fallback
+// search query. A bare code is read as this family only when bare is set, and
+// only at the start of the value, so dates inside titles are not mistaken for
+// codes.
+func uncensoredFamily(name, prefix, separator string, suffixDigits int, label string, bare bool) Family {
+	start := `\b` + prefix + `[-_\s\])]*`
+	if bare {
+		start = `(?:` + start + `|^)`
+	}
+	pattern := regexp.MustCompile(`(?i)` + start + `(\d{6})` + regexp.QuoteMeta(separator) + `(\d{` + strconv.Itoa(suffixDigits) + `})(?:$|[^0-9])`)
+	canonicalPrefix := strings.ToUpper(label) + "-"
+	return Family{
+		Name:    name,
+		Pattern: pattern,
+		Normalize: func(match []string) string {
+			return canonicalPrefix + match[1] + separator + match[2]
+		},
+		SearchQueries: func(code string) []string {
+			number := strings.TrimPrefix(code, canonicalPrefix)
+			return []string{label + " " + number, number}
+		},
+		LibraryCodes: func(code string) []string {
+			if !bare {
+				return nil
+			}
+			return []string{strings.TrimPrefix(code, canonicalPrefix)}
+		},
+	}
+}
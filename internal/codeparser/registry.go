@@ -0,0 +1,153 @@
+// Package codeparser recognises release codes in free text (scene codes,
+// torrent titles, file names) and normalises them per code family.
+package codeparser
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Code is a release code recognised by one of the registered families.
+type Code struct {
+	Family string
+	Value  string
+}
+
+// Family describes one release code format. Pattern is matched against the
+// raw input; Normalize turns the submatches into the canonical code, and
+// SearchQueries/LibraryCodes expand a canonical code into tracker queries and
+// equivalent spellings used by library lookups.
+type Family struct {
+	Name          string
+	Pattern       *regexp.Regexp
+	Normalize     func(match []string) string
+	SearchQueries func(code string) []string
+	LibraryCodes  func(code string) []string
+}
+
+// Registry holds code families in priority order. The first family whose
+// pattern matches a value wins, so specific formats are registered before
+// the generic LETTERS-DIGITS family.
+type Registry struct {
+	mu       sync.RWMutex
+	families []Family
+}
+
+func NewRegistry(families ...Family) *Registry {
+	r := &Registry{}
+	for _, family := range families {
+		r.Register(family)
+	}
+	return r
+}
+
+// Register appends a family. Families without a pattern or normaliser are
+// ignored.
+func (r *Registry) Register(family Family) {
+	if family.Pattern == nil || family.Normalize == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, family)
+}
+
+// Parse returns the first code found in value.
+func (r *Registry) Parse(value string) (Code, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Code{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, family := range r.families {
+		match := family.Pattern.FindStringSubmatch(value)
+		if match == nil {
+			continue
+		}
+		if code := family.Normalize(match); code != "" {
+			return Code{Family: family.Name, Value: code}, true
+		}
+	}
+	return Code{}, false
+}
+
+// Normalize returns the canonical code found in value, or "".
+func (r *Registry) Normalize(value string) string {
+	code, _ := r.Parse(value)
+	return code.Value
+}
+
+// Extract returns the canonical code of the first value that contains one.
+func (r *Registry) Extract(values ...string) string {
+	for _, value := range values {
+		if code := r.Normalize(value); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// SearchQueries returns the tracker queries to try for code, canonical
+// spelling first.
+func (r *Registry) SearchQueries(code string) []string {
+	return r.expand(code, func(family Family) func(string) []string { return family.SearchQueries })
+}
+
+// LibraryCodes returns the spellings under which code may be stored in a
+// media library, canonical spelling first.
+func (r *Registry) LibraryCodes(code string) []string {
+	return r.expand(code, func(family Family) func(string) []string { return family.LibraryCodes })
+}
+
+func (r *Registry) expand(code string, pick func(Family) func(string) []string) []string {
+	parsed, ok := r.Parse(code)
+	if !ok {
+		if trimmed := strings.TrimSpace(code); trimmed != "" {
+			return []string{trimmed}
+		}
+		return nil
+	}
+	out := []string{parsed.Value}
+	r.mu.RLock()
+	family, found := r.familyLocked(parsed.Family)
+	r.mu.RUnlock()
+	if !found || pick(family) == nil {
+		return out
+	}
+	seen := map[string]struct{}{strings.ToUpper(parsed.Value): {}}
+	for _, item := range pick(family)(parsed.Value) {
+		item = strings.TrimSpace(item)
+		key := strings.ToUpper(item)
+		if item == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+func (r *Registry) familyLocked(name string) (Family, bool) {
+	for _, family := range r.families {
+		if family.Name == name {
+			return family, true
+		}
+	}
+	return Family{}, false
+}
+
+var defaultRegistry = NewRegistry(builtinFamilies()...)
+
+// Default returns the process-wide registry with the built-in families.
+func Default() *Registry { return defaultRegistry }
+
+func Parse(value string) (Code, bool)    { return defaultRegistry.Parse(value) }
+func Normalize(value string) string      { return defaultRegistry.Normalize(value) }
+func Extract(values ...string) string    { return defaultRegistry.Extract(values...) }
+func SearchQueries(code string) []string { return defaultRegistry.SearchQueries(code) }
+func LibraryCodes(code string) []string  { return defaultRegistry.LibraryCodes(code) }
@@ -0,0 +1,129 @@
+package codeparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeByFamily(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		family string
+		want   string
+	}{
+		{name: "generic", input: "abc-123", family: FamilyGeneric, want: "ABC-123"},
+		{name: "generic without separator", input: "ssis00123", family: FamilyGeneric, want: "SSIS-00123"},
+		{name: "generic in title", input: "[FHD] SSIS-001 Some Title", family: FamilyGeneric, want: "SSIS-001"},
+		{name: "generic underscore", input: "ipx_456", family: FamilyGeneric, want: "IPX-456"},
+
+		{name: "fc2 ppv", input: "FC2-PPV-1234567", family: FamilyFC2, want: "FC2-PPV-1234567"},
+		{name: "fc2 compact", input: "fc2ppv 1234567", family: FamilyFC2, want: "FC2-PPV-1234567"},
+		{name: "fc2 without ppv", input: "FC2-1234567.mp4", family: FamilyFC2, want: "FC2-PPV-1234567"},
+		{name: "fc2 underscores", input: "FC2_PPV_987654", family: FamilyFC2, want: "FC2-PPV-987654"},
+
+		{name: "heyzo", input: "HEYZO-1234", family: FamilyHeyzo, want: "HEYZO-1234"},
+		{name: "heyzo hd", input: "heyzo_hd_0987_full.mp4", family: FamilyHeyzo, want: "HEYZO-0987"},
+
+		{name: "1pondo prefixed", input: "1pondo 123456_789", family: Family1Pondo, want: "1PONDO-123456_789"},
+		{name: "1pondo bracketed", input: "[1Pondo] 010124_001 Title", family: Family1Pondo, want: "1PONDO-010124_001"},
+		{name: "1pondo bare", input: "123456_789", family: Family1Pondo, want: "1PONDO-123456_789"},
+
+		{name: "caribbeancom prefixed", input: "Caribbeancom 123456-789", family: FamilyCaribbeancom, want: "CARIBBEANCOM-123456-789"},
+		{name: "carib short", input: "carib-010124-001-FHD", family: FamilyCaribbeancom, want: "CARIBBEANCOM-010124-001"},
+		{name: "caribbeancom bare", input: "123456-789", family: FamilyCaribbeancom, want: "CARIBBEANCOM-123456-789"},
+
+		{name: "10musume prefixed", input: "10musume 123456_01", family: Family10Musume, want: "10MUSUME-123456_01"},
+		{name: "10musume bare", input: "123456_01", family: Family10Musume, want: "10MUSUME-123456_01"},
+
+		{name: "pacopacomama prefixed", input: "pacopacomama-123456_789.mp4", family: FamilyPacopacomama, want: "PACOPACOMAMA-123456_789"},
+
+		{name: "t28", input: "T28-123", family: FamilyT28, want: "T28-123"},
+		{name: "t28 hyphenated", input: "t-28591 title", family: FamilyT28, want: "T28-591"},
+
+		{name: "bare date inside title is not uncensored", input: "[FHD]240101-001", family: "", want: ""},
+		{name: "empty", input: "  ", family: "", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, ok := Parse(test.input)
+			if test.want == "" {
+				if ok {
+					t.Fatalf("Parse(%q) = %+v, want no match", test.input, code)
+				}
+				return
+			}
+			if !ok || code.Value != test.want || code.Family != test.family {
+				t.Fatalf("Parse(%q) = %+v ok=%v, want %s/%s", test.input, code, ok, test.family, test.want)
+			}
+			if got := Normalize(code.Value); got != test.want {
+				t.Fatalf("Normalize(%q) = %q, want idempotent %q", code.Value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueriesByFamily(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{code: "abc-123", want: []string{"ABC-123"}},
+		{code: "FC2-PPV-1234567", want: []string{"FC2-PPV-1234567", "FC2PPV-1234567", "FC2-1234567"}},
+		{code: "HEYZO-1234", want: []string{"HEYZO-1234", "HEYZO 1234"}},
+		{code: "123456_789", want: []string{"1PONDO-123456_789", "1pondo 123456_789", "123456_789"}},
+		{code: "123456-789", want: []string{"CARIBBEANCOM-123456-789", "Caribbeancom 123456-789", "123456-789"}},
+		{code: "123456_01", want: []string{"10MUSUME-123456_01", "10musume 123456_01", "123456_01"}},
+		{code: "PACOPACOMAMA-123456_789", want: []string{"PACOPACOMAMA-123456_789", "Pacopacomama 123456_789", "123456_789"}},
+		{code: "T28-123", want: []string{"T28-123", "T-28-123", "T-28123"}},
+		{code: "unparseable", want: []string{"unparseable"}},
+	}
+	for _, test := range tests {
+		if got := SearchQueries(test.code); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SearchQueries(%q) = %#v, want %#v", test.code, got, test.want)
+		}
+	}
+}
+
+func TestLibraryCodesByFamily(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{code: "ABC-123", want: []string{"ABC-123"}},
+		{code: "fc2 ppv 1234567", want: []string{"FC2-PPV-1234567", "FC2PPV-1234567", "FC2-1234567"}},
+		{code: "HEYZO-1234", want: []string{"HEYZO-1234"}},
+		{code: "1pondo 123456_789", want: []string{"1PONDO-123456_789", "123456_789"}},
+		{code: "PACOPACOMAMA-123456_789", want: []string{"PACOPACOMAMA-123456_789"}},
+		{code: "T28-123", want: []string{"T28-123", "T-28-123", "T-28123"}},
+	}
+	for _, test := range tests {
+		if got := LibraryCodes(test.code); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("LibraryCodes(%q) = %#v, want %#v", test.code, got, test.want)
+		}
+	}
+}
+
+func TestExtractUsesFirstValueWithCode(t *testing.T) {
+	if got := Extract("no code here", "Caribbeancom 010124-001", "ABC-123"); got != "CARIBBEANCOM-010124-001" {
+		t.Fatalf("Extract = %q, want CARIBBEANCOM-010124-001", got)
+	}
+}
+
+func TestUncensoredStudiosKeepDistinctCodes(t *testing.T) {
+	pondo, paco := Normalize("1pondo 123456_789"), Normalize("Pacopacomama 123456_789")
+	if pondo == paco {
+		t.Fatalf("expected different studios to keep different codes, both normalized to %q", pondo)
+	}
+}
+
+func TestRegisterCustomFamilyBeforeGeneric(t *testing.T) {
+	registry := NewRegistry(builtinFamilies()[:1]...)
+	registry.Register(builtinFamilies()[len(builtinFamilies())-1])
+	if got := registry.Normalize("FC2-PPV-1234567"); got != "FC2-PPV-1234567" {
+		t.Fatalf("Normalize = %q", got)
+	}
+	if got := registry.Normalize("1pondo 123456_789"); got != "" {
+		t.Fatalf("expected registry without 1pondo family to ignore code, got %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+// Package command runs local helper programs such as task hooks, subtitle
+// generators and ffprobe with a timeout and a readable failure message.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	maxStderrMessageLength = 1000
+	// waitDelay bounds how long a timed-out command's children may keep its
+	// stderr pipe open.
+	waitDelay = 5 * time.Second
+)
+
+type Options struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Env replaces the process environment when non-nil.
+	Env []string
+}
+
+// Run runs name with args and waits at most timeout. A failed command is
+// reported by its trimmed stderr, falling back to the exit error.
+func Run(ctx context.Context, timeout time.Duration, name string, args []string, options Options) error {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, name, args...)
+	cmd.Stdin = options.Stdin
+	cmd.Stdout = options.Stdout
+	cmd.Env = options.Env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	err := cmd.Run()
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		if len(message) > maxStderrMessageLength {
+			message = message[:maxStderrMessageLength]
+		}
+		return errors.New(message)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunReportsTrimmedStderr(t *testing.T) {
+	long := strings.Repeat("x", 2*maxStderrMessageLength)
+	err := Run(context.Background(), time.Minute, "sh", []string{"-c", "echo '  " + long + "' >&2; exit 3"}, Options{})
+	if err == nil || len(err.Error()) != maxStderrMessageLength || !strings.HasPrefix(err.Error(), "xxx") {
+		t.Fatalf("expected a trimmed stderr message, got %v", err)
+	}
+}
+
+func TestRunPassesStdinAndStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := Run(context.Background(), time.Minute, "cat", nil, Options{Stdin: strings.NewReader("payload"), Stdout: &stdout}); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "payload" {
+		t.Fatalf("unexpected stdout %q", stdout.String())
+	}
+}
+
+func TestRunTimesOut(t *testing.T) {
+	err := Run(context.Background(), 50*time.Millisecond, "sleep", []string{"5"}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -206,9 +210,37 @@ func NormalizeTaskDeletePolicy(value string) TaskDeletePolicy {
 }
 
 type SystemConfig struct {
-	TaskDeletePolicy  TaskDeletePolicy        `yaml:"task_delete_policy"`
-	ImageCache        ImageCacheConfig        `yaml:"image_cache"`
-	StashBoxDataCache StashBoxDataCacheConfig `yaml:"stash_box_data_cache"`
+	TaskDeletePolicy       TaskDeletePolicy             `yaml:"task_delete_policy"`
+	ImageCache             ImageCacheConfig             `yaml:"image_cache"`
+	StashBoxDataCache      StashBoxDataCacheConfig      `yaml:"stash_box_data_cache"`
+	TorrentInspectionCache TorrentInspectionCacheConfig `yaml:"torrent_inspection_cache"`
+}
+
+type TorrentInspectionCacheConfig struct {
+	TTLHours   int `yaml:"ttl_hours"`
+	MaxEntries int `yaml:"max_entries"`
+}
+
+func (c TorrentInspectionCacheConfig) Normalize() TorrentInspectionCacheConfig {
+	if c.TTLHours == 0 {
+		c.TTLHours = 168
+	}
+	if c.TTLHours < 1 {
+		c.TTLHours = 1
+	}
+	if c.TTLHours > 720 {
+		c.TTLHours = 720
+	}
+	if c.MaxEntries == 0 {
+		c.MaxEntries = 20000
+	}
+	if c.MaxEntries < 100 {
+		c.MaxEntries = 100
+	}
+	if c.MaxEntries > 200000 {
+		c.MaxEntries = 200000
+	}
+	return c
 }
 
 type StashBoxDataCacheConfig struct {
@@ -286,6 +318,224 @@ type AutomationConfig struct {
 	StashBoxEndpoints               []string                        `yaml:"selected_stash_box_endpoints"`
 	SubscriptionReleasePolicy       SubscriptionReleasePolicyConfig `yaml:"subscription_release_policy"`
 	TorrentSelection                TorrentSelectionConfig          `yaml:"torrent_selection"`
+	TaskHooks                       []TaskHookConfig                `yaml:"task_hooks,omitempty"`
+	Bandwidth                       BandwidthConfig                 `yaml:"bandwidth,omitempty"`
+	FeedPolling                     FeedPollingConfig               `yaml:"feed_polling,omitempty"`
+	UpcomingReleases                UpcomingReleasesConfig          `yaml:"upcoming_releases,omitempty"`
+}
+
+// UpcomingReleasesConfig defers subscription releases whose StashBox release
+// date is still in the future. Sourcing is first tried DelayHours after the
+// release date and retried every RetryIntervalHours; after MaxAttempts the
+// release waits for a torrent like any other pending download. DelayHours
+// defaults to 12 when unset; 0 sources releases on their release date.
+type UpcomingReleasesConfig struct {
+	Enabled            *bool `yaml:"enabled,omitempty"`
+	DelayHours         *int  `yaml:"delay_hours,omitempty"`
+	RetryIntervalHours int   `yaml:"retry_interval_hours,omitempty"`
+	MaxAttempts        int   `yaml:"max_attempts,omitempty"`
+}
+
+const (
+	DefaultUpcomingReleaseDelayHours         = 12
+	DefaultUpcomingReleaseRetryIntervalHours = 24
+	DefaultUpcomingReleaseMaxAttempts        = 5
+)
+
+func (c UpcomingReleasesConfig) Normalize() UpcomingReleasesConfig {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	delay := DefaultUpcomingReleaseDelayHours
+	if c.DelayHours != nil {
+		delay = max(*c.DelayHours, 0)
+	}
+	c.DelayHours = &delay
+	if c.RetryIntervalHours <= 0 {
+		c.RetryIntervalHours = DefaultUpcomingReleaseRetryIntervalHours
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultUpcomingReleaseMaxAttempts
+	}
+	return c
+}
+
+// FeedPollingConfig polls Jackett's Torznab feeds for releases that pending
+// subscription releases and blocked SOURCING tasks are waiting on. Empty
+// Indexers reads the aggregate feed of every indexer.
+type FeedPollingConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	IntervalMinutes int      `yaml:"interval_minutes,omitempty"`
+	Indexers        []string `yaml:"indexers,omitempty"`
+	Categories      []int    `yaml:"categories,omitempty"`
+}
+
+const (
+	DefaultFeedPollingIntervalMinutes = 5
+	minFeedPollingIntervalMinutes     = 1
+)
+
+func (c FeedPollingConfig) Normalize() FeedPollingConfig {
+	if c.IntervalMinutes <= 0 {
+		c.IntervalMinutes = DefaultFeedPollingIntervalMinutes
+	}
+	c.IntervalMinutes = max(c.IntervalMinutes, minFeedPollingIntervalMinutes)
+	c.Indexers = cleanStrings(c.Indexers)
+	categories := make([]int, 0, len(c.Categories))
+	for _, category := range c.Categories {
+		if category > 0 && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	c.Categories = categories
+	return c
+}
+
+// BandwidthConfig schedules qBittorrent's global speed limits. The first
+// profile with a window covering the current local time is applied; when no
+// window matches, limits are lifted and alternative speed is turned off.
+type BandwidthConfig struct {
+	Enabled  bool                     `yaml:"enabled"`
+	Profiles []BandwidthProfileConfig `yaml:"profiles,omitempty"`
+}
+
+// BandwidthProfileConfig limits are in KiB/s and zero means unlimited. A
+// profile without windows is never scheduled but can still be selected as a
+// manual override.
+type BandwidthProfileConfig struct {
+	Name             string                  `yaml:"name"`
+	DownloadLimitKiB int                     `yaml:"download_limit_kib"`
+	UploadLimitKiB   int                     `yaml:"upload_limit_kib"`
+	AlternativeSpeed bool                    `yaml:"alternative_speed"`
+	Windows          []BandwidthWindowConfig `yaml:"windows,omitempty"`
+}
+
+// BandwidthWindowConfig covers Start (inclusive) to End (exclusive) in 24h
+// "HH:MM" local time on each listed day (MON..SUN, empty for every day). A
+// window whose End is not after Start runs past midnight into the next day.
+type BandwidthWindowConfig struct {
+	Days  []string `yaml:"days,omitempty"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+}
+
+var bandwidthDays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+func (c BandwidthConfig) Normalize() BandwidthConfig {
+	profiles := make([]BandwidthProfileConfig, 0, len(c.Profiles))
+	for _, profile := range c.Profiles {
+		profile.Name = strings.TrimSpace(profile.Name)
+		if profile.Name == "" {
+			continue
+		}
+		profile.DownloadLimitKiB = max(profile.DownloadLimitKiB, 0)
+		profile.UploadLimitKiB = max(profile.UploadLimitKiB, 0)
+		windows := make([]BandwidthWindowConfig, 0, len(profile.Windows))
+		for _, window := range profile.Windows {
+			days := make([]string, 0, len(window.Days))
+			for _, day := range cleanStrings(window.Days) {
+				days = append(days, strings.ToUpper(day))
+			}
+			window.Days = days
+			window.Start = strings.TrimSpace(window.Start)
+			window.End = strings.TrimSpace(window.End)
+			windows = append(windows, window)
+		}
+		profile.Windows = windows
+		profiles = append(profiles, profile)
+	}
+	c.Profiles = profiles
+	return c
+}
+
+func (c BandwidthConfig) Validate() error {
+	names := make(map[string]struct{}, len(c.Profiles))
+	for _, profile := range c.Normalize().Profiles {
+		if _, exists := names[strings.ToLower(profile.Name)]; exists {
+			return fmt.Errorf("bandwidth profile %q is defined more than once", profile.Name)
+		}
+		names[strings.ToLower(profile.Name)] = struct{}{}
+		for _, window := range profile.Windows {
+			for _, day := range window.Days {
+				if !slices.Contains(bandwidthDays, day) {
+					return fmt.Errorf("bandwidth profile %q: unknown day %q", profile.Name, day)
+				}
+			}
+			for _, value := range []string{window.Start, window.End} {
+				if _, err := ParseClockMinutes(value); err != nil {
+					return fmt.Errorf("bandwidth profile %q: %w", profile.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseClockMinutes parses a 24h "HH:MM" time into minutes after midnight.
+func ParseClockMinutes(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// BandwidthWeekday maps a MON..SUN day name to time.Weekday.
+func BandwidthWeekday(day string) (time.Weekday, bool) {
+	index := slices.Index(bandwidthDays, strings.ToUpper(strings.TrimSpace(day)))
+	return time.Weekday(index), index >= 0
+}
+
+// TaskHookConfig describes a local command run when a task leaves one of the
+// listed stages. A BlockOnFailure hook runs before the task moves on and a
+// failure keeps the task blocked in the stage it guards; other hooks run in
+// the background once the task has moved on.
+type TaskHookConfig struct {
+	Name           string   `yaml:"name"`
+	Command        string   `yaml:"command"`
+	Args           []string `yaml:"args,omitempty"`
+	AfterStages    []string `yaml:"after_stages"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+	BlockOnFailure bool     `yaml:"block_on_failure"`
+	Enabled        *bool    `yaml:"enabled,omitempty"`
+}
+
+func (c TaskHookConfig) EffectiveEnabled() bool { return c.Enabled == nil || *c.Enabled }
+
+func (c TaskHookConfig) Normalize() TaskHookConfig {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Command = strings.TrimSpace(c.Command)
+	if c.Name == "" {
+		c.Name = c.Command
+	}
+	stages := make([]string, 0, len(c.AfterStages))
+	for _, stage := range cleanStrings(c.AfterStages) {
+		stages = append(stages, strings.ToUpper(stage))
+	}
+	c.AfterStages = stages
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = 60
+	}
+	if c.TimeoutSeconds < 1 {
+		c.TimeoutSeconds = 1
+	}
+	if c.TimeoutSeconds > 3600 {
+		c.TimeoutSeconds = 3600
+	}
+	return c
+}
+
+func NormalizeTaskHooks(hooks []TaskHookConfig) []TaskHookConfig {
+	out := make([]TaskHookConfig, 0, len(hooks))
+	for _, hook := range hooks {
+		hook = hook.Normalize()
+		if hook.Command == "" {
+			continue
+		}
+		out = append(out, hook)
+	}
+	return out
 }
 
 type SubscriptionReleaseBehavior string
@@ -312,6 +562,109 @@ type SubscriptionReleasePolicyConfig struct {
 	CompilationBehavior    SubscriptionReleaseBehavior  `yaml:"compilation_behavior"`
 	MaxGroupPerformerCount int                          `yaml:"max_group_performer_count"`
 	ReleaseDateRange       SubscriptionReleaseDateRange `yaml:"release_date_range"`
+	// CompilationKeywords replace the built-in keywords that mark a title,
+	// description or tag as a compilation.
+	CompilationKeywords []string                        `yaml:"compilation_keywords,omitempty"`
+	Rules               []SubscriptionReleaseRuleConfig `yaml:"rules,omitempty"`
+}
+
+// SubscriptionReleaseRuleConfig is an ordered release filter rule. Every
+// condition that is set must match; the first matching rule decides the
+// release and the solo, group and compilation behaviors are skipped.
+// TagsInclude, Studios, TitleKeywords and CoPerformers match when any listed
+// value matches; TagsExclude matches when none of its tags are present.
+// Durations are in minutes and performer counts include the subscribed
+// performer. TitleRegex is compiled when the rules are normalized.
+type SubscriptionReleaseRuleConfig struct {
+	Name               string                      `yaml:"name"`
+	Behavior           SubscriptionReleaseBehavior `yaml:"behavior"`
+	TagsInclude        []string                    `yaml:"tags_include,omitempty"`
+	TagsExclude        []string                    `yaml:"tags_exclude,omitempty"`
+	Studios            []string                    `yaml:"studios,omitempty"`
+	MinDurationMinutes int                         `yaml:"min_duration_minutes,omitempty"`
+	MaxDurationMinutes int                         `yaml:"max_duration_minutes,omitempty"`
+	TitleKeywords      []string                    `yaml:"title_keywords,omitempty"`
+	TitleRegex         string                      `yaml:"title_regex,omitempty"`
+	MinPerformers      int                         `yaml:"min_performers,omitempty"`
+	MaxPerformers      int                         `yaml:"max_performers,omitempty"`
+	CoPerformers       []string                    `yaml:"co_performers,omitempty"`
+
+	titlePattern *regexp.Regexp
+}
+
+// TitlePattern returns the compiled TitleRegex of a normalized rule, or nil
+// when it is unset or invalid.
+func (r SubscriptionReleaseRuleConfig) TitlePattern() *regexp.Regexp {
+	return r.titlePattern
+}
+
+func (r SubscriptionReleaseRuleConfig) hasConditions() bool {
+	return len(r.TagsInclude)+len(r.TagsExclude)+len(r.Studios)+len(r.TitleKeywords)+len(r.CoPerformers) > 0 ||
+		r.MinDurationMinutes > 0 || r.MaxDurationMinutes > 0 || r.TitleRegex != "" ||
+		r.MinPerformers > 0 || r.MaxPerformers > 0
+}
+
+func NormalizeSubscriptionReleaseRules(rules []SubscriptionReleaseRuleConfig) []SubscriptionReleaseRuleConfig {
+	if len(rules) == 0 {
+		return nil
+	}
+	out := make([]SubscriptionReleaseRuleConfig, 0, len(rules))
+	for _, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.Behavior = SubscriptionReleaseBehavior(strings.ToUpper(strings.TrimSpace(string(rule.Behavior))))
+		rule.TagsInclude = cleanStrings(rule.TagsInclude)
+		rule.TagsExclude = cleanStrings(rule.TagsExclude)
+		rule.Studios = cleanStrings(rule.Studios)
+		rule.TitleKeywords = cleanStrings(rule.TitleKeywords)
+		rule.TitleRegex = strings.TrimSpace(rule.TitleRegex)
+		rule.CoPerformers = cleanStrings(rule.CoPerformers)
+		// Policies are normalized again on every evaluation, so a pattern
+		// that is already compiled is kept.
+		switch {
+		case rule.TitleRegex == "":
+			rule.titlePattern = nil
+		case rule.titlePattern == nil || rule.titlePattern.String() != rule.TitleRegex:
+			rule.titlePattern, _ = regexp.Compile(rule.TitleRegex)
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+func ValidateSubscriptionReleaseRules(rules []SubscriptionReleaseRuleConfig) error {
+	seen := make(map[string]struct{}, len(rules))
+	for i, rule := range NormalizeSubscriptionReleaseRules(rules) {
+		if rule.Name == "" {
+			return fmt.Errorf("subscription release rule %d: name is required", i+1)
+		}
+		if _, ok := seen[rule.Name]; ok {
+			return fmt.Errorf("subscription release rule %q: duplicate name", rule.Name)
+		}
+		seen[rule.Name] = struct{}{}
+		switch rule.Behavior {
+		case SubscriptionReleaseBehaviorDownload, SubscriptionReleaseBehaviorReview, SubscriptionReleaseBehaviorBlock:
+		default:
+			return fmt.Errorf("subscription release rule %q: unknown behavior %q", rule.Name, rule.Behavior)
+		}
+		if !rule.hasConditions() {
+			return fmt.Errorf("subscription release rule %q: at least one condition is required", rule.Name)
+		}
+		if rule.MinDurationMinutes < 0 || rule.MaxDurationMinutes < 0 || rule.MinPerformers < 0 || rule.MaxPerformers < 0 {
+			return fmt.Errorf("subscription release rule %q: durations and performer counts must not be negative", rule.Name)
+		}
+		if rule.MaxDurationMinutes > 0 && rule.MaxDurationMinutes < rule.MinDurationMinutes {
+			return fmt.Errorf("subscription release rule %q: max_duration_minutes is below min_duration_minutes", rule.Name)
+		}
+		if rule.MaxPerformers > 0 && rule.MaxPerformers < rule.MinPerformers {
+			return fmt.Errorf("subscription release rule %q: max_performers is below min_performers", rule.Name)
+		}
+		if rule.TitleRegex != "" {
+			if _, err := regexp.Compile(rule.TitleRegex); err != nil {
+				return fmt.Errorf("subscription release rule %q: title_regex: %w", rule.Name, err)
+			}
+		}
+	}
+	return nil
 }
 
 type IngestConfig struct {
@@ -319,6 +672,12 @@ type IngestConfig struct {
 	Downloads    DownloadsIngestConfig `yaml:"downloads"`
 	Library      LibraryIngestConfig   `yaml:"library"`
 	Transfer     TransferIngestConfig  `yaml:"transfer"`
+	DiskSpace    DiskSpaceIngestConfig `yaml:"disk_space,omitempty"`
+	Subtitles    SubtitlesIngestConfig `yaml:"subtitles,omitempty"`
+	Probe        ProbeIngestConfig     `yaml:"probe,omitempty"`
+	Identify     IdentifyIngestConfig  `yaml:"identify,omitempty"`
+	Scan         ScanIngestConfig      `yaml:"scan,omitempty"`
+	Routes       []LibraryRouteConfig  `yaml:"routes,omitempty"`
 }
 
 type DownloadsIngestConfig struct {
@@ -326,6 +685,275 @@ type DownloadsIngestConfig struct {
 	MojiRoot string `yaml:"moji_root"`
 }
 
+type SaveRootPolicy string
+
+const (
+	SaveRootPolicyMostFree  SaveRootPolicy = "MOST_FREE"
+	SaveRootPolicyFillFirst SaveRootPolicy = "FILL_FIRST"
+)
+
+// DiskSpaceIngestConfig guards torrent submission against full volumes.
+// SaveRoots are qB-side paths; free space is measured on their Moji-side
+// counterparts through downloads.qb_root/moji_root. With no save roots the
+// requested or default qB save path is checked instead.
+type DiskSpaceIngestConfig struct {
+	Enabled        *bool          `yaml:"enabled,omitempty"`
+	ReserveMB      int            `yaml:"reserve_mb"`
+	SaveRoots      []string       `yaml:"save_roots,omitempty"`
+	SaveRootPolicy SaveRootPolicy `yaml:"save_root_policy,omitempty"`
+}
+
+func (c DiskSpaceIngestConfig) EffectiveEnabled() bool { return c.Enabled == nil || *c.Enabled }
+
+func (c DiskSpaceIngestConfig) Normalize() DiskSpaceIngestConfig {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	if c.ReserveMB == 0 {
+		c.ReserveMB = 2048
+	}
+	if c.ReserveMB < 0 {
+		c.ReserveMB = 0
+	}
+	c.SaveRoots = cleanStrings(c.SaveRoots)
+	switch SaveRootPolicy(strings.ToUpper(strings.TrimSpace(string(c.SaveRootPolicy)))) {
+	case SaveRootPolicyFillFirst:
+		c.SaveRootPolicy = SaveRootPolicyFillFirst
+	default:
+		c.SaveRootPolicy = SaveRootPolicyMostFree
+	}
+	return c
+}
+
+// SubtitlesIngestConfig drives the SUBTITLING stage between delivery and the
+// Stash scan. Providers are tried in order for each video until one yields a
+// subtitle; videos that already have a caption file are left alone.
+type SubtitlesIngestConfig struct {
+	Enabled   bool                     `yaml:"enabled"`
+	Providers []SubtitleProviderConfig `yaml:"providers,omitempty"`
+}
+
+type SubtitleProviderType string
+
+const (
+	// SubtitleProviderLibrary matches existing .srt/.ass files by code.
+	SubtitleProviderLibrary SubtitleProviderType = "LIBRARY"
+	// SubtitleProviderCommand runs a local command such as whisper.cpp.
+	SubtitleProviderCommand SubtitleProviderType = "COMMAND"
+)
+
+// SubtitleProviderConfig configures one provider. Command args may use the
+// {video}, {output} and {code} placeholders; the command is expected to write
+// {output}.srt (or .ass/.vtt). Language is the caption language suffix used
+// when the provider cannot tell, for example "ja" or "zh".
+type SubtitleProviderConfig struct {
+	Name           string               `yaml:"name"`
+	Type           SubtitleProviderType `yaml:"type"`
+	Enabled        *bool                `yaml:"enabled,omitempty"`
+	LibraryDirs    []string             `yaml:"library_dirs,omitempty"`
+	Command        string               `yaml:"command,omitempty"`
+	Args           []string             `yaml:"args,omitempty"`
+	TimeoutSeconds int                  `yaml:"timeout_seconds,omitempty"`
+	Language       string               `yaml:"language,omitempty"`
+}
+
+func (c SubtitleProviderConfig) EffectiveEnabled() bool { return c.Enabled == nil || *c.Enabled }
+
+func (c SubtitlesIngestConfig) Normalize() SubtitlesIngestConfig {
+	providers := make([]SubtitleProviderConfig, 0, len(c.Providers))
+	for _, provider := range c.Providers {
+		provider.Type = SubtitleProviderType(strings.ToUpper(strings.TrimSpace(string(provider.Type))))
+		provider.Name = strings.TrimSpace(provider.Name)
+		if provider.Name == "" {
+			provider.Name = strings.ToLower(string(provider.Type))
+		}
+		provider.LibraryDirs = cleanStrings(provider.LibraryDirs)
+		provider.Command = strings.TrimSpace(provider.Command)
+		provider.Language = strings.ToLower(strings.TrimSpace(provider.Language))
+		if provider.TimeoutSeconds <= 0 {
+			provider.TimeoutSeconds = 3600
+		}
+		if provider.TimeoutSeconds > 6*3600 {
+			provider.TimeoutSeconds = 6 * 3600
+		}
+		providers = append(providers, provider)
+	}
+	c.Providers = providers
+	return c
+}
+
+func (c SubtitlesIngestConfig) Validate() error {
+	for _, provider := range c.Normalize().Providers {
+		switch provider.Type {
+		case SubtitleProviderLibrary:
+			if len(provider.LibraryDirs) == 0 {
+				return fmt.Errorf("subtitle provider %q: library_dirs is required", provider.Name)
+			}
+		case SubtitleProviderCommand:
+			if provider.Command == "" {
+				return fmt.Errorf("subtitle provider %q: command is required", provider.Name)
+			}
+		default:
+			return fmt.Errorf("subtitle provider %q: unknown type %q", provider.Name, provider.Type)
+		}
+	}
+	return nil
+}
+
+// ProbeIngestConfig runs ffprobe on delivered videos and records the result on
+// the task. DurationTolerancePercent is how far the probed duration may stray
+// from the StashBox scene duration before the task is flagged; MinHeight and
+// MinBitRateKbps flag videos below them and are not checked when zero.
+type ProbeIngestConfig struct {
+	Enabled                  bool   `yaml:"enabled"`
+	FFprobePath              string `yaml:"ffprobe_path,omitempty"`
+	TimeoutSeconds           int    `yaml:"timeout_seconds,omitempty"`
+	DurationTolerancePercent int    `yaml:"duration_tolerance_percent,omitempty"`
+	MinHeight                int    `yaml:"min_height,omitempty"`
+	MinBitRateKbps           int    `yaml:"min_bitrate_kbps,omitempty"`
+}
+
+func (c ProbeIngestConfig) Normalize() ProbeIngestConfig {
+	c.FFprobePath = strings.TrimSpace(c.FFprobePath)
+	if c.FFprobePath == "" {
+		c.FFprobePath = "ffprobe"
+	}
+	if c.TimeoutSeconds <= 0 {
+		c.TimeoutSeconds = 30
+	}
+	if c.DurationTolerancePercent <= 0 {
+		c.DurationTolerancePercent = 10
+	}
+	if c.DurationTolerancePercent > 100 {
+		c.DurationTolerancePercent = 100
+	}
+	c.MinHeight = max(c.MinHeight, 0)
+	c.MinBitRateKbps = max(c.MinBitRateKbps, 0)
+	return c
+}
+
+// IdentifyIngestConfig runs Stash's identify task on each scanned path, using
+// the StashBox endpoints configured in Stash as sources in the order selected
+// under automation.selected_stash_box_endpoints.
+type IdentifyIngestConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ScanIngestConfig sets the generation options of the Stash scan run for
+// each ingested task. Phashes and previews are generated unless set to false,
+// so newly ingested scenes can be matched and browsed; other options left
+// unset are up to Stash. Libraries override them for scan paths under a
+// Stash-side library path; the longest matching path wins.
+type ScanIngestConfig struct {
+	ScanOptionsConfig `yaml:",inline"`
+	Libraries         []LibraryScanIngestConfig `yaml:"libraries,omitempty"`
+}
+
+type ScanOptionsConfig struct {
+	Rescan                *bool `yaml:"rescan,omitempty"`
+	GenerateCovers        *bool `yaml:"generate_covers,omitempty"`
+	GeneratePreviews      *bool `yaml:"generate_previews,omitempty"`
+	GenerateImagePreviews *bool `yaml:"generate_image_previews,omitempty"`
+	GenerateSprites       *bool `yaml:"generate_sprites,omitempty"`
+	GeneratePhashes       *bool `yaml:"generate_phashes,omitempty"`
+	GenerateThumbnails    *bool `yaml:"generate_thumbnails,omitempty"`
+	GenerateClipPreviews  *bool `yaml:"generate_clip_previews,omitempty"`
+}
+
+type LibraryScanIngestConfig struct {
+	Path              string `yaml:"path"`
+	ScanOptionsConfig `yaml:",inline"`
+}
+
+func (c ScanIngestConfig) Normalize() ScanIngestConfig {
+	if c.GeneratePhashes == nil {
+		enabled := true
+		c.GeneratePhashes = &enabled
+	}
+	if c.GeneratePreviews == nil {
+		enabled := true
+		c.GeneratePreviews = &enabled
+	}
+	libraries := make([]LibraryScanIngestConfig, 0, len(c.Libraries))
+	for _, library := range c.Libraries {
+		library.Path = strings.TrimRight(strings.TrimSpace(library.Path), "/")
+		if library.Path != "" {
+			libraries = append(libraries, library)
+		}
+	}
+	c.Libraries = libraries
+	return c
+}
+
+// LibraryRouteConfig delivers matching tasks into another library instead of
+// ingest.library. Routes are tried in order and the first match wins. Every
+// condition that is set must match, and a condition matches when any of its
+// values does: code prefixes and title keywords are case-insensitive, studios
+// and tags are compared with the StashBox scene and the task's qBittorrent
+// tags, and sources are task sources such as SUBSCRIPTION.
+type LibraryRouteConfig struct {
+	Name          string              `yaml:"name"`
+	CodePrefixes  []string            `yaml:"code_prefixes,omitempty"`
+	Studios       []string            `yaml:"studios,omitempty"`
+	Tags          []string            `yaml:"tags,omitempty"`
+	TitleKeywords []string            `yaml:"title_keywords,omitempty"`
+	Sources       []string            `yaml:"sources,omitempty"`
+	Library       LibraryIngestConfig `yaml:"library"`
+}
+
+func (c LibraryRouteConfig) hasConditions() bool {
+	return len(c.CodePrefixes)+len(c.Studios)+len(c.Tags)+len(c.TitleKeywords)+len(c.Sources) > 0
+}
+
+func NormalizeLibraryRoutes(routes []LibraryRouteConfig) []LibraryRouteConfig {
+	out := make([]LibraryRouteConfig, 0, len(routes))
+	for _, route := range routes {
+		route.Name = strings.TrimSpace(route.Name)
+		route.CodePrefixes = cleanStrings(route.CodePrefixes)
+		route.Studios = cleanStrings(route.Studios)
+		route.Tags = cleanStrings(route.Tags)
+		route.TitleKeywords = cleanStrings(route.TitleKeywords)
+		route.Sources = cleanStrings(route.Sources)
+		for i := range route.Sources {
+			route.Sources[i] = strings.ToUpper(route.Sources[i])
+		}
+		route.Library.MojiRoot = strings.TrimSpace(route.Library.MojiRoot)
+		route.Library.StashRoot = strings.TrimSpace(route.Library.StashRoot)
+		out = append(out, route)
+	}
+	return out
+}
+
+// libraryRouteSources mirrors taskruntime.TaskSource, which config cannot
+// import.
+var libraryRouteSources = []string{"MANUAL", "SEARCH", "SUBSCRIPTION", "WATCHLIST"}
+
+func ValidateLibraryRoutes(routes []LibraryRouteConfig) error {
+	seen := make(map[string]struct{}, len(routes))
+	for i, route := range NormalizeLibraryRoutes(routes) {
+		if route.Name == "" {
+			return fmt.Errorf("library route %d: name is required", i+1)
+		}
+		if _, ok := seen[route.Name]; ok {
+			return fmt.Errorf("library route %q: duplicate name", route.Name)
+		}
+		seen[route.Name] = struct{}{}
+		if !route.hasConditions() {
+			return fmt.Errorf("library route %q: at least one condition is required", route.Name)
+		}
+		if route.Library.StashRoot == "" {
+			return fmt.Errorf("library route %q: library.stash_root is required", route.Name)
+		}
+		for _, source := range route.Sources {
+			if !slices.Contains(libraryRouteSources, source) {
+				return fmt.Errorf("library route %q: unknown source %q", route.Name, source)
+			}
+		}
+	}
+	return nil
+}
+
 type LibraryIngestConfig struct {
 	MojiRoot  string `yaml:"moji_root"`
 	StashRoot string `yaml:"stash_root"`
@@ -335,6 +963,267 @@ type TransferIngestConfig struct {
 	Action string `yaml:"action"`
 }
 
+type NotificationsConfig struct {
+	Webhooks  []WebhookConfig              `yaml:"webhooks,omitempty"`
+	Channels  []NotificationChannelConfig  `yaml:"channels,omitempty"`
+	Templates []NotificationTemplateConfig `yaml:"templates,omitempty"`
+}
+
+type NotificationChannelType string
+
+const (
+	NotificationChannelTelegram NotificationChannelType = "TELEGRAM"
+	NotificationChannelDiscord  NotificationChannelType = "DISCORD"
+	NotificationChannelNtfy     NotificationChannelType = "NTFY"
+	NotificationChannelGotify   NotificationChannelType = "GOTIFY"
+)
+
+type NotificationEventType string
+
+const (
+	NotificationEventReleaseDownloaded NotificationEventType = "RELEASE_DOWNLOADED"
+	NotificationEventTaskBlocked       NotificationEventType = "TASK_BLOCKED"
+	NotificationEventServiceDown       NotificationEventType = "SERVICE_DOWN"
+	NotificationEventServiceRecovered  NotificationEventType = "SERVICE_RECOVERED"
+	NotificationEventWatchlistMatch    NotificationEventType = "WATCHLIST_MATCH"
+)
+
+func NotificationEventTypes() []NotificationEventType {
+	return []NotificationEventType{
+		NotificationEventReleaseDownloaded,
+		NotificationEventTaskBlocked,
+		NotificationEventServiceDown,
+		NotificationEventServiceRecovered,
+		NotificationEventWatchlistMatch,
+	}
+}
+
+// NotificationChannelConfig is one chat destination. The meaning of URL and
+// Token depends on Type: Discord uses URL as the webhook URL; ntfy and Gotify
+// use it as the server URL; Telegram uses Token as the bot token and ChatID
+// as the destination. An empty Events list subscribes to every event.
+type NotificationChannelConfig struct {
+	Name     string                  `yaml:"name"`
+	Type     NotificationChannelType `yaml:"type"`
+	Enabled  bool                    `yaml:"enabled"`
+	Events   []NotificationEventType `yaml:"events,omitempty"`
+	URL      string                  `yaml:"url,omitempty"`
+	Token    string                  `yaml:"token,omitempty"`
+	ChatID   string                  `yaml:"chat_id,omitempty"`
+	Topic    string                  `yaml:"topic,omitempty"`
+	Priority int                     `yaml:"priority,omitempty"`
+}
+
+// NotificationTemplateConfig overrides the Go text/template used to render
+// the title and body for one event type.
+type NotificationTemplateConfig struct {
+	Event NotificationEventType `yaml:"event"`
+	Title string                `yaml:"title"`
+	Body  string                `yaml:"body"`
+}
+
+func DefaultNotificationTemplates() []NotificationTemplateConfig {
+	return []NotificationTemplateConfig{
+		{Event: NotificationEventReleaseDownloaded, Title: "Moji: {{.Code}} downloaded", Body: "{{.Code}} finished downloading{{if .TorrentName}}: {{.TorrentName}}{{end}}"},
+		{Event: NotificationEventTaskBlocked, Title: "Moji: {{.Code}} blocked", Body: "{{.Code}} is blocked at {{.Stage}}: {{.ErrorMessage}}"},
+		{Event: NotificationEventServiceDown, Title: "Moji: {{.Service}} is down", Body: "{{.Service}} is unreachable: {{.ErrorMessage}}"},
+		{Event: NotificationEventServiceRecovered, Title: "Moji: {{.Service}} recovered", Body: "{{.Service}} is reachable again."},
+		{Event: NotificationEventWatchlistMatch, Title: "Moji: new result for {{.Watchlist}}", Body: "{{.Title}}{{if .Tracker}} on {{.Tracker}}{{end}}{{if .Link}}\n{{.Link}}{{end}}"},
+	}
+}
+
+func NormalizeNotificationChannelType(value NotificationChannelType) NotificationChannelType {
+	switch NotificationChannelType(strings.ToUpper(strings.TrimSpace(string(value)))) {
+	case NotificationChannelTelegram:
+		return NotificationChannelTelegram
+	case NotificationChannelDiscord:
+		return NotificationChannelDiscord
+	case NotificationChannelNtfy:
+		return NotificationChannelNtfy
+	case NotificationChannelGotify:
+		return NotificationChannelGotify
+	default:
+		return ""
+	}
+}
+
+func NormalizeNotificationEventType(value NotificationEventType) NotificationEventType {
+	normalized := NotificationEventType(strings.ToUpper(strings.TrimSpace(string(value))))
+	for _, known := range NotificationEventTypes() {
+		if normalized == known {
+			return normalized
+		}
+	}
+	return ""
+}
+
+func (c NotificationChannelConfig) Normalize() NotificationChannelConfig {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Type = NormalizeNotificationChannelType(c.Type)
+	c.URL = strings.TrimSpace(c.URL)
+	c.Token = strings.TrimSpace(c.Token)
+	c.ChatID = strings.TrimSpace(c.ChatID)
+	c.Topic = strings.TrimSpace(c.Topic)
+	if c.Name == "" {
+		c.Name = strings.ToLower(string(c.Type))
+	}
+	events := make([]NotificationEventType, 0, len(c.Events))
+	for _, event := range c.Events {
+		if event = NormalizeNotificationEventType(event); event != "" && !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	c.Events = events
+	if c.Priority < 0 {
+		c.Priority = 0
+	}
+	if c.Priority > 10 {
+		c.Priority = 10
+	}
+	return c
+}
+
+// Validate reports the fields a channel of its type needs to send anything.
+func (c NotificationChannelConfig) Validate() error {
+	switch c.Type {
+	case NotificationChannelTelegram:
+		if c.Token == "" || c.ChatID == "" {
+			return fmt.Errorf("notification channel %q: telegram requires a bot token and chat id", c.Name)
+		}
+	case NotificationChannelDiscord:
+		if c.URL == "" {
+			return fmt.Errorf("notification channel %q: discord requires a webhook url", c.Name)
+		}
+	case NotificationChannelNtfy:
+		if c.URL == "" || c.Topic == "" {
+			return fmt.Errorf("notification channel %q: ntfy requires a server url and topic", c.Name)
+		}
+	case NotificationChannelGotify:
+		if c.URL == "" || c.Token == "" {
+			return fmt.Errorf("notification channel %q: gotify requires a server url and app token", c.Name)
+		}
+	default:
+		return fmt.Errorf("notification channel %q: unsupported channel type", c.Name)
+	}
+	return nil
+}
+
+// EffectiveTemplates returns one template per event type, with configured
+// overrides replacing the defaults field by field.
+func (c NotificationsConfig) EffectiveTemplates() []NotificationTemplateConfig {
+	templates := DefaultNotificationTemplates()
+	for _, override := range c.Templates {
+		event := NormalizeNotificationEventType(override.Event)
+		for index := range templates {
+			if templates[index].Event != event {
+				continue
+			}
+			if strings.TrimSpace(override.Title) != "" {
+				templates[index].Title = override.Title
+			}
+			if strings.TrimSpace(override.Body) != "" {
+				templates[index].Body = override.Body
+			}
+		}
+	}
+	return templates
+}
+
+func (c NotificationsConfig) Validate() error {
+	names := make(map[string]struct{}, len(c.Channels))
+	for _, channel := range c.Channels {
+		channel = channel.Normalize()
+		if _, exists := names[channel.Name]; exists {
+			return fmt.Errorf("notification channel %q is defined more than once", channel.Name)
+		}
+		names[channel.Name] = struct{}{}
+		if err := channel.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, item := range c.Templates {
+		if NormalizeNotificationEventType(item.Event) == "" {
+			return fmt.Errorf("notification template: unknown event %q", item.Event)
+		}
+		for _, text := range []string{item.Title, item.Body} {
+			if _, err := template.New("notification").Option("missingkey=zero").Parse(text); err != nil {
+				return fmt.Errorf("notification template %s: %w", item.Event, err)
+			}
+		}
+	}
+	return nil
+}
+
+// WebhookConfig is an outbound HTTP receiver. Events filters by event type
+// ("task.updated", or "task.*" for a family); empty means every event except
+// task.progress download ticks, which must be listed by name.
+type WebhookConfig struct {
+	Name           string   `yaml:"name"`
+	URL            string   `yaml:"url"`
+	Secret         string   `yaml:"secret,omitempty"`
+	Events         []string `yaml:"events,omitempty"`
+	MaxAttempts    int      `yaml:"max_attempts"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+	Enabled        *bool    `yaml:"enabled,omitempty"`
+}
+
+func (c WebhookConfig) EffectiveEnabled() bool { return c.Enabled == nil || *c.Enabled }
+
+func (c WebhookConfig) Normalize() WebhookConfig {
+	c.Name = strings.TrimSpace(c.Name)
+	c.URL = strings.TrimSpace(c.URL)
+	if c.Name == "" {
+		c.Name = c.URL
+	}
+	c.Events = cleanStrings(c.Events)
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 5
+	}
+	if c.MaxAttempts < 1 {
+		c.MaxAttempts = 1
+	}
+	if c.MaxAttempts > 20 {
+		c.MaxAttempts = 20
+	}
+	if c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = 10
+	}
+	if c.TimeoutSeconds < 1 {
+		c.TimeoutSeconds = 1
+	}
+	if c.TimeoutSeconds > 120 {
+		c.TimeoutSeconds = 120
+	}
+	return c
+}
+
+func (c NotificationsConfig) Normalize() NotificationsConfig {
+	webhooks := make([]WebhookConfig, 0, len(c.Webhooks))
+	for _, webhook := range c.Webhooks {
+		webhook = webhook.Normalize()
+		if webhook.URL == "" {
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	c.Webhooks = webhooks
+	channels := make([]NotificationChannelConfig, 0, len(c.Channels))
+	for _, channel := range c.Channels {
+		if channel = channel.Normalize(); channel.Type != "" {
+			channels = append(channels, channel)
+		}
+	}
+	c.Channels = channels
+	templates := make([]NotificationTemplateConfig, 0, len(c.Templates))
+	for _, item := range c.Templates {
+		if item.Event = NormalizeNotificationEventType(item.Event); item.Event != "" {
+			templates = append(templates, item)
+		}
+	}
+	c.Templates = templates
+	return c
+}
+
 type LoggingConfig struct {
 	Level            string `yaml:"level"`
 	FilePath         string `yaml:"file_path"`
@@ -344,11 +1233,12 @@ type LoggingConfig struct {
 }
 
 type Config struct {
-	Connection ConnectionConfig `yaml:"connection"`
-	Ingest     IngestConfig     `yaml:"ingest"`
-	Automation AutomationConfig `yaml:"automation"`
-	System     SystemConfig     `yaml:"system"`
-	Logging    LoggingConfig    `yaml:"logging"`
+	Connection    ConnectionConfig    `yaml:"connection"`
+	Ingest        IngestConfig        `yaml:"ingest"`
+	Automation    AutomationConfig    `yaml:"automation"`
+	System        SystemConfig        `yaml:"system"`
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
+	Logging       LoggingConfig       `yaml:"logging"`
 
 	path string
 }
@@ -497,7 +1387,16 @@ func (p SubscriptionReleasePolicyConfig) Effective() SubscriptionReleasePolicyCo
 		CompilationBehavior:    NormalizeSubscriptionReleaseBehavior(p.CompilationBehavior),
 		MaxGroupPerformerCount: NormalizeSubscriptionReleaseMaxGroupPerformerCount(p.MaxGroupPerformerCount),
 		ReleaseDateRange:       NormalizeSubscriptionReleaseDateRange(p.ReleaseDateRange),
+		CompilationKeywords:    cleanReleaseKeywords(p.CompilationKeywords),
+		Rules:                  NormalizeSubscriptionReleaseRules(p.Rules),
+	}
+}
+
+func cleanReleaseKeywords(keywords []string) []string {
+	if len(keywords) == 0 {
+		return nil
 	}
+	return cleanStrings(keywords)
 }
 
 func (r TorrentSelectionRule) normalized() TorrentSelectionRule {
@@ -907,11 +1806,34 @@ func LoadFromPath(path string) (*Config, error) {
 	config.System.TaskDeletePolicy = config.System.EffectiveTaskDeletePolicy()
 	config.System.ImageCache = config.System.ImageCache.Normalize()
 	config.System.StashBoxDataCache = config.System.StashBoxDataCache.Normalize()
+	config.System.TorrentInspectionCache = config.System.TorrentInspectionCache.Normalize()
 	config.Automation.StashBoxEndpoints = cleanStrings(config.Automation.StashBoxEndpoints)
 	config.Automation.SubscriptionReleasePolicy = config.Automation.SubscriptionReleasePolicy.Effective()
+	config.Automation.TaskHooks = NormalizeTaskHooks(config.Automation.TaskHooks)
+	config.Notifications = config.Notifications.Normalize()
+	config.Ingest.DiskSpace = config.Ingest.DiskSpace.Normalize()
 	if err := config.Automation.TorrentSelection.Validate(); err != nil {
 		return nil, err
 	}
+	if err := config.Automation.Bandwidth.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Ingest.Subtitles.Validate(); err != nil {
+		return nil, err
+	}
+	config.Ingest.Subtitles = config.Ingest.Subtitles.Normalize()
+	config.Ingest.Probe = config.Ingest.Probe.Normalize()
+	config.Ingest.Scan = config.Ingest.Scan.Normalize()
+	if err := ValidateSubscriptionReleaseRules(config.Automation.SubscriptionReleasePolicy.Rules); err != nil {
+		return nil, err
+	}
+	if err := ValidateLibraryRoutes(config.Ingest.Routes); err != nil {
+		return nil, err
+	}
+	config.Ingest.Routes = NormalizeLibraryRoutes(config.Ingest.Routes)
+	config.Automation.Bandwidth = config.Automation.Bandwidth.Normalize()
+	config.Automation.FeedPolling = config.Automation.FeedPolling.Normalize()
+	config.Automation.UpcomingReleases = config.Automation.UpcomingReleases.Normalize()
 	config.Automation.TorrentSelection = config.Automation.TorrentSelection.Effective()
 	config.path = path
 
@@ -38,9 +38,31 @@ func OpenStore(path string) (*Store, error) {
 	cfg.System.TaskDeletePolicy = cfg.System.EffectiveTaskDeletePolicy()
 	cfg.Automation.StashBoxEndpoints = cleanStrings(cfg.Automation.StashBoxEndpoints)
 	cfg.Automation.SubscriptionReleasePolicy = cfg.Automation.SubscriptionReleasePolicy.Effective()
+	cfg.Automation.TaskHooks = NormalizeTaskHooks(cfg.Automation.TaskHooks)
+	cfg.Notifications = cfg.Notifications.Normalize()
+	cfg.Ingest.DiskSpace = cfg.Ingest.DiskSpace.Normalize()
 	if err := cfg.Automation.TorrentSelection.Validate(); err != nil {
 		return nil, err
 	}
+	if err := cfg.Automation.Bandwidth.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Ingest.Subtitles.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.Ingest.Subtitles = cfg.Ingest.Subtitles.Normalize()
+	cfg.Ingest.Probe = cfg.Ingest.Probe.Normalize()
+	cfg.Ingest.Scan = cfg.Ingest.Scan.Normalize()
+	if err := ValidateSubscriptionReleaseRules(cfg.Automation.SubscriptionReleasePolicy.Rules); err != nil {
+		return nil, err
+	}
+	if err := ValidateLibraryRoutes(cfg.Ingest.Routes); err != nil {
+		return nil, err
+	}
+	cfg.Ingest.Routes = NormalizeLibraryRoutes(cfg.Ingest.Routes)
+	cfg.Automation.Bandwidth = cfg.Automation.Bandwidth.Normalize()
+	cfg.Automation.FeedPolling = cfg.Automation.FeedPolling.Normalize()
+	cfg.Automation.UpcomingReleases = cfg.Automation.UpcomingReleases.Normalize()
 	cfg.Automation.TorrentSelection = cfg.Automation.TorrentSelection.Effective()
 	cfg.path = path
 
@@ -143,6 +165,17 @@ func (s *Store) UpdateAutomation(taskProgressSyncIntervalSeconds, subscriptionPo
 	if err := torrentSelection.Validate(); err != nil {
 		return nil, err
 	}
+	// Filter rules and compilation keywords are only edited in the config
+	// file, so a settings save without them keeps the configured ones.
+	if subscriptionReleasePolicy.Rules == nil {
+		subscriptionReleasePolicy.Rules = s.cfg.Automation.SubscriptionReleasePolicy.Rules
+	}
+	if subscriptionReleasePolicy.CompilationKeywords == nil {
+		subscriptionReleasePolicy.CompilationKeywords = s.cfg.Automation.SubscriptionReleasePolicy.CompilationKeywords
+	}
+	if err := ValidateSubscriptionReleaseRules(subscriptionReleasePolicy.Rules); err != nil {
+		return nil, err
+	}
 
 	s.cfg.Automation.TaskProgressSyncIntervalSeconds = taskProgressSyncIntervalSeconds
 	s.cfg.Automation.SubscriptionPollIntervalHours = subscriptionPollIntervalHours
@@ -157,12 +190,35 @@ func (s *Store) UpdateAutomation(taskProgressSyncIntervalSeconds, subscriptionPo
 	return &clone, nil
 }
 
+// UpdateNotifications replaces the chat channels and message templates. Only
+// those two keys are rewritten so hand-edited webhooks are left untouched.
+func (s *Store) UpdateNotifications(channels []NotificationChannelConfig, templates []NotificationTemplateConfig) (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := NotificationsConfig{Webhooks: s.cfg.Notifications.Webhooks, Channels: channels, Templates: templates}.Normalize()
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	s.cfg.Notifications = next
+
+	notifications := mapValue(ensureMapValue(documentNode(&s.root)), "notifications")
+	setNodeValue(notifications, "channels", next.Channels)
+	setNodeValue(notifications, "templates", next.Templates)
+	if err := s.updateConfigNode(); err != nil {
+		return nil, err
+	}
+	clone := *s.cfg
+	return &clone, nil
+}
+
 func (s *Store) UpdateSystem(taskDeletePolicy TaskDeletePolicy, imageCache ...ImageCacheConfig) (*Config, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	stashBoxCache := s.cfg.System.StashBoxDataCache.Normalize()
-	return s.updateSystemLocked(taskDeletePolicy, firstImageCache(s.cfg.System.ImageCache, imageCache), stashBoxCache)
+	inspectionCache := s.cfg.System.TorrentInspectionCache.Normalize()
+	return s.updateSystemLocked(taskDeletePolicy, firstImageCache(s.cfg.System.ImageCache, imageCache), stashBoxCache, inspectionCache)
 }
 
 func firstImageCache(current ImageCacheConfig, values []ImageCacheConfig) ImageCacheConfig {
@@ -172,16 +228,17 @@ func firstImageCache(current ImageCacheConfig, values []ImageCacheConfig) ImageC
 	return current
 }
 
-func (s *Store) UpdateSystemWithDataCache(taskDeletePolicy TaskDeletePolicy, imageCache ImageCacheConfig, stashBoxCache StashBoxDataCacheConfig) (*Config, error) {
+func (s *Store) UpdateSystemWithDataCache(taskDeletePolicy TaskDeletePolicy, imageCache ImageCacheConfig, stashBoxCache StashBoxDataCacheConfig, inspectionCache TorrentInspectionCacheConfig) (*Config, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.updateSystemLocked(taskDeletePolicy, imageCache, stashBoxCache)
+	return s.updateSystemLocked(taskDeletePolicy, imageCache, stashBoxCache, inspectionCache)
 }
 
-func (s *Store) updateSystemLocked(taskDeletePolicy TaskDeletePolicy, imageCache ImageCacheConfig, stashBoxCache StashBoxDataCacheConfig) (*Config, error) {
+func (s *Store) updateSystemLocked(taskDeletePolicy TaskDeletePolicy, imageCache ImageCacheConfig, stashBoxCache StashBoxDataCacheConfig, inspectionCache TorrentInspectionCacheConfig) (*Config, error) {
 	s.cfg.System.TaskDeletePolicy = NormalizeTaskDeletePolicy(string(taskDeletePolicy))
 	s.cfg.System.ImageCache = imageCache.Normalize()
 	s.cfg.System.StashBoxDataCache = stashBoxCache.Normalize()
+	s.cfg.System.TorrentInspectionCache = inspectionCache.Normalize()
 
 	if err := s.updateConfigNode(); err != nil {
 		return nil, err
@@ -240,6 +297,9 @@ func (s *Store) updateConfigNode() error {
 	setIntScalar(imageCache, "retention_days", s.cfg.System.ImageCache.Normalize().RetentionDays)
 	stashBoxDataCache := mapValue(system, "stash_box_data_cache")
 	setIntScalar(stashBoxDataCache, "ttl_hours", s.cfg.System.StashBoxDataCache.Normalize().TTLHours)
+	torrentInspectionCache := mapValue(system, "torrent_inspection_cache")
+	setIntScalar(torrentInspectionCache, "ttl_hours", s.cfg.System.TorrentInspectionCache.Normalize().TTLHours)
+	setIntScalar(torrentInspectionCache, "max_entries", s.cfg.System.TorrentInspectionCache.Normalize().MaxEntries)
 	deleteMapKey(top, "stash")
 	deleteMapKey(top, "jackett")
 	deleteMapKey(top, "qbittorrent")
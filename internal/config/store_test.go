@@ -156,6 +156,119 @@ ingest:
 	}
 }
 
+func TestLoadFromPathReadsScanOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := `ingest:
+  scan:
+    generate_previews: false
+    libraries:
+      - path: " /library/vr/ "
+        generate_previews: false
+      - path: ""
+        generate_sprites: true
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	scan := cfg.Ingest.Scan
+	if scan.GeneratePhashes == nil || !*scan.GeneratePhashes || scan.GeneratePreviews == nil || *scan.GeneratePreviews || scan.GenerateCovers != nil {
+		t.Fatalf("expected phashes on by default and previews turned off, got %+v", scan.ScanOptionsConfig)
+	}
+	if len(scan.Libraries) != 1 || scan.Libraries[0].Path != "/library/vr" || scan.Libraries[0].GeneratePreviews == nil || *scan.Libraries[0].GeneratePreviews {
+		t.Fatalf("unexpected library overrides %+v", scan.Libraries)
+	}
+}
+
+func TestValidateLibraryRoutes(t *testing.T) {
+	valid := LibraryRouteConfig{Name: "vr", CodePrefixes: []string{"SIVR"}, Sources: []string{" subscription "}, Library: LibraryIngestConfig{StashRoot: "/vr"}}
+	if err := ValidateLibraryRoutes([]LibraryRouteConfig{valid}); err != nil {
+		t.Fatalf("expected valid route, got %v", err)
+	}
+	if got := NormalizeLibraryRoutes([]LibraryRouteConfig{valid}); got[0].Sources[0] != "SUBSCRIPTION" {
+		t.Fatalf("expected normalized source, got %v", got[0].Sources)
+	}
+	for name, routes := range map[string][]LibraryRouteConfig{
+		"duplicate name": {valid, valid},
+		"no conditions":  {{Name: "all", Library: LibraryIngestConfig{StashRoot: "/all"}}},
+		"no stash root":  {{Name: "vr", CodePrefixes: []string{"SIVR"}}},
+		"unknown source": {{Name: "vr", Sources: []string{"RSS"}, Library: LibraryIngestConfig{StashRoot: "/vr"}}},
+	} {
+		if err := ValidateLibraryRoutes(routes); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestStoreUpdateAutomationKeepsSubscriptionReleaseRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := `automation:
+  subscription_release_policy:
+    solo_behavior: DOWNLOAD
+    compilation_keywords: ["recap"]
+    rules:
+      - name: vr
+        behavior: BLOCK
+        tags_include: ["VR"]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	if _, err := store.UpdateAutomation(60, 1, nil, DefaultSubscriptionReleasePolicyConfig(), NewTorrentSelectionConfig(false, 5, nil)); err != nil {
+		t.Fatalf("update automation: %v", err)
+	}
+	reloaded, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	policy := reloaded.Automation.SubscriptionReleasePolicy
+	if len(policy.Rules) != 1 || policy.Rules[0].Name != "vr" || policy.Rules[0].TagsInclude[0] != "VR" {
+		t.Fatalf("expected the filter rule to survive a settings save, got %+v", policy.Rules)
+	}
+	if len(policy.CompilationKeywords) != 1 || policy.CompilationKeywords[0] != "recap" {
+		t.Fatalf("expected compilation keywords to survive a settings save, got %+v", policy.CompilationKeywords)
+	}
+}
+
+func TestValidateSubscriptionReleaseRules(t *testing.T) {
+	valid := SubscriptionReleaseRuleConfig{Name: "vr", Behavior: " block ", TagsInclude: []string{"VR"}}
+	if err := ValidateSubscriptionReleaseRules([]SubscriptionReleaseRuleConfig{valid}); err != nil {
+		t.Fatalf("expected valid rule, got %v", err)
+	}
+	if got := NormalizeSubscriptionReleaseRules([]SubscriptionReleaseRuleConfig{valid}); got[0].Behavior != SubscriptionReleaseBehaviorBlock {
+		t.Fatalf("expected normalized behavior, got %q", got[0].Behavior)
+	}
+	regex := NormalizeSubscriptionReleaseRules([]SubscriptionReleaseRuleConfig{{Name: "vr", Behavior: SubscriptionReleaseBehaviorBlock, TitleRegex: " (?i)vr$ "}})
+	if pattern := regex[0].TitlePattern(); pattern == nil || !pattern.MatchString("Special VR") {
+		t.Fatalf("expected the title regex to be compiled, got %v", pattern)
+	}
+	if again := NormalizeSubscriptionReleaseRules(regex); again[0].TitlePattern() != regex[0].TitlePattern() {
+		t.Fatal("expected normalizing again to keep the compiled title regex")
+	}
+	for name, rules := range map[string][]SubscriptionReleaseRuleConfig{
+		"duplicate name":   {valid, valid},
+		"no conditions":    {{Name: "all", Behavior: SubscriptionReleaseBehaviorReview}},
+		"unknown behavior": {{Name: "vr", Behavior: "SKIP", TagsInclude: []string{"VR"}}},
+		"bad regex":        {{Name: "vr", Behavior: SubscriptionReleaseBehaviorBlock, TitleRegex: "("}},
+		"inverted range":   {{Name: "long", Behavior: SubscriptionReleaseBehaviorBlock, MinDurationMinutes: 90, MaxDurationMinutes: 60}},
+	} {
+		if err := ValidateSubscriptionReleaseRules(rules); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
 func TestLoadFromPathRejectsDuplicateFastRuleOrderTypes(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "config.yaml")
@@ -225,7 +338,7 @@ func TestStoreUpdateSystemPersistsStashBoxCacheTTL(t *testing.T) {
 		t.Fatal(err)
 	}
 	enabled := true
-	if _, err := store.UpdateSystemWithDataCache(TaskDeletePolicyKeepOnly, ImageCacheConfig{Enabled: &enabled, MaxSizeMB: 1024, RetentionDays: 30}, StashBoxDataCacheConfig{TTLHours: 36}); err != nil {
+	if _, err := store.UpdateSystemWithDataCache(TaskDeletePolicyKeepOnly, ImageCacheConfig{Enabled: &enabled, MaxSizeMB: 1024, RetentionDays: 30}, StashBoxDataCacheConfig{TTLHours: 36}, TorrentInspectionCacheConfig{TTLHours: 48, MaxEntries: 5000}); err != nil {
 		t.Fatal(err)
 	}
 	reloaded, err := LoadFromPath(path)
@@ -235,6 +348,9 @@ func TestStoreUpdateSystemPersistsStashBoxCacheTTL(t *testing.T) {
 	if reloaded.System.StashBoxDataCache.TTLHours != 36 {
 		t.Fatalf("TTL hours = %d, want 36", reloaded.System.StashBoxDataCache.TTLHours)
 	}
+	if got := reloaded.System.TorrentInspectionCache; got.TTLHours != 48 || got.MaxEntries != 5000 {
+		t.Fatalf("torrent inspection cache = %+v, want ttl 48 and max entries 5000", got)
+	}
 }
 
 func TestStashBoxDataCacheConfigNormalizeBounds(t *testing.T) {
@@ -247,3 +363,109 @@ func TestStashBoxDataCacheConfigNormalizeBounds(t *testing.T) {
 		}
 	}
 }
+
+func TestUpcomingReleasesConfigNormalizeDelay(t *testing.T) {
+	zero, negative := 0, -3
+	for _, test := range []struct {
+		input *int
+		want  int
+	}{{nil, DefaultUpcomingReleaseDelayHours}, {&zero, 0}, {&negative, 0}} {
+		if got := (UpcomingReleasesConfig{DelayHours: test.input}).Normalize().DelayHours; got == nil || *got != test.want {
+			t.Errorf("Normalize(%v) = %v, want %d", test.input, got, test.want)
+		}
+	}
+}
+
+func TestTorrentInspectionCacheConfigNormalizeBounds(t *testing.T) {
+	for _, test := range []struct {
+		input TorrentInspectionCacheConfig
+		want  TorrentInspectionCacheConfig
+	}{
+		{TorrentInspectionCacheConfig{}, TorrentInspectionCacheConfig{TTLHours: 168, MaxEntries: 20000}},
+		{TorrentInspectionCacheConfig{TTLHours: -1, MaxEntries: 10}, TorrentInspectionCacheConfig{TTLHours: 1, MaxEntries: 100}},
+		{TorrentInspectionCacheConfig{TTLHours: 721, MaxEntries: 300000}, TorrentInspectionCacheConfig{TTLHours: 720, MaxEntries: 200000}},
+	} {
+		if got := test.input.Normalize(); got != test.want {
+			t.Errorf("Normalize(%+v) = %+v, want %+v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestStoreUpdatePreservesNotificationWebhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `system:
+  task_delete_policy: KEEP_ONLY
+notifications:
+  webhooks:
+    - name: " ops "
+      url: "https://example.test/hook"
+      secret: s3cret
+      events: ["task.*", " "]
+      max_attempts: 50
+    - name: missing-url
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.UpdateSystem(TaskDeletePolicyRemoveTorrent); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks := reloaded.Notifications.Webhooks
+	if len(webhooks) != 1 {
+		t.Fatalf("expected one valid webhook, got %+v", webhooks)
+	}
+	if got := webhooks[0]; got.Name != "ops" || got.Secret != "s3cret" || len(got.Events) != 1 || got.MaxAttempts != 20 || got.TimeoutSeconds != 10 {
+		t.Fatalf("unexpected normalized webhook: %+v", got)
+	}
+}
+
+func TestStoreUpdateNotificationsKeepsWebhooksAndValidatesTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `notifications:
+  webhooks:
+    - name: ops
+      url: "https://example.test/hook"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.UpdateNotifications(nil, []NotificationTemplateConfig{{Event: NotificationEventTaskBlocked, Body: "{{.Code"}}); err == nil {
+		t.Fatal("expected invalid template to be rejected")
+	}
+	if _, err := store.UpdateNotifications([]NotificationChannelConfig{{Type: "discord"}}, nil); err == nil {
+		t.Fatal("expected discord channel without url to be rejected")
+	}
+	channels := []NotificationChannelConfig{{Name: "phone", Type: "ntfy", Enabled: true, URL: "https://ntfy.sh", Topic: "moji", Events: []NotificationEventType{"task_blocked", "bogus"}}}
+	templates := []NotificationTemplateConfig{{Event: NotificationEventTaskBlocked, Title: "Stuck: {{.Code}}"}}
+	if _, err := store.UpdateNotifications(channels, templates); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifications := reloaded.Notifications
+	if len(notifications.Webhooks) != 1 || notifications.Webhooks[0].Name != "ops" {
+		t.Fatalf("webhooks were not preserved: %+v", notifications.Webhooks)
+	}
+	if len(notifications.Channels) != 1 || notifications.Channels[0].Type != NotificationChannelNtfy || len(notifications.Channels[0].Events) != 1 || notifications.Channels[0].Events[0] != NotificationEventTaskBlocked {
+		t.Fatalf("unexpected channels: %+v", notifications.Channels)
+	}
+	for _, item := range notifications.EffectiveTemplates() {
+		if item.Event == NotificationEventTaskBlocked && (item.Title != "Stuck: {{.Code}}" || item.Body == "") {
+			t.Fatalf("expected title override with default body, got %+v", item)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -10,12 +11,18 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/tracker"
 )
 
+type MissingScenesReporter interface {
+	MissingScenesReport(ctx context.Context, query performer.MissingScenesQuery) (performer.MissingScenesReport, error)
+}
+
 type Handler struct {
-	tracker     tracker.Tracker
-	logFilePath string
+	tracker       tracker.Tracker
+	logFilePath   string
+	missingScenes MissingScenesReporter
 }
 
 type Option func(*Handler)
@@ -26,6 +33,12 @@ func WithLogFilePath(path string) Option {
 	}
 }
 
+func WithMissingScenesReporter(reporter MissingScenesReporter) Option {
+	return func(h *Handler) {
+		h.missingScenes = reporter
+	}
+}
+
 func NewHandler(tr tracker.Tracker, options ...Option) *Handler {
 	h := &Handler{tracker: tr}
 	for _, option := range options {
@@ -40,6 +53,7 @@ func (h *Handler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("GET /healthz", h.handleHealthz)
 	mux.HandleFunc("GET /api/tracker/search", h.handleTrackerSearch)
 	mux.HandleFunc("GET /api/logs/current", h.handleCurrentLogFile)
+	mux.HandleFunc("GET /api/reports/missing-scenes", h.handleMissingScenesReport)
 }
 
 func (h *Handler) handleHealthz(w http.ResponseWriter, _ *http.Request) {
@@ -135,6 +149,74 @@ func (h *Handler) handleCurrentLogFile(w http.ResponseWriter, _ *http.Request) {
 	_, _ = io.Copy(w, file)
 }
 
+// handleMissingScenesReport exports a missing-scenes report as a CSV or JSON
+// attachment. Filters mirror the GraphQL missingScenesReport input.
+func (h *Handler) handleMissingScenesReport(w http.ResponseWriter, r *http.Request) {
+	if h.missingScenes == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "missing-scenes report is not configured"})
+		return
+	}
+	params := r.URL.Query()
+	format := strings.ToLower(strings.TrimSpace(params.Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid format: expected csv or json"})
+		return
+	}
+	query := performer.MissingScenesQuery{
+		Subject:         performer.MissingScenesSubject(strings.ToUpper(strings.TrimSpace(params.Get("subject")))),
+		ID:              params.Get("id"),
+		Endpoint:        params.Get("endpoint"),
+		DateFrom:        params.Get("dateFrom"),
+		DateTo:          params.Get("dateTo"),
+		Classifications: splitCSV(params.Get("classification")),
+		Studios:         splitCSV(params.Get("studio")),
+	}
+	report, err := h.missingScenes.MissingScenesReport(r.Context(), query)
+	if err != nil {
+		writeJSON(w, missingScenesErrorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+
+	filename := "missing-scenes-" + strings.ToLower(string(report.Query.Subject)) + "-" + sanitizeFilename(report.Query.ID) + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = performer.WriteMissingScenesJSON(w, report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = performer.WriteMissingScenesCSV(w, report)
+}
+
+func missingScenesErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, performer.ErrInvalidMissingScenesQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, performer.ErrMissingScenesSubjectNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, performer.ErrMissingScenesCatalogUnavailable):
+		return http.StatusBadGateway
+	case errors.Is(err, performer.ErrLibraryIndexUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func sanitizeFilename(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, value)
+}
+
 func markDeprecatedRESTDebugEndpoint(w http.ResponseWriter) {
 	w.Header().Set("Deprecation", "true")
 	w.Header().Set("Link", `</graphql>; rel="successor-version"`)
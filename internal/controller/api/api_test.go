@@ -1,13 +1,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/tracker"
 	"github.com/leothevan2444/moji/pkg/jackett"
 )
@@ -113,6 +118,71 @@ func TestCurrentLogFileEndpointReturnsNotFoundWhenMissing(t *testing.T) {
 	}
 }
 
+func TestMissingScenesReportEndpointExportsCSVAttachment(t *testing.T) {
+	reporter := &fakeMissingScenesReporter{report: performer.MissingScenesReport{
+		Query: performer.MissingScenesQuery{Subject: performer.MissingScenesSubjectStudio, ID: "studio-1"},
+		Items: []performer.MissingScene{{Scene: performer.Scene{Key: "stashbox:box:scene-1", StashBoxSceneID: "scene-1", Code: "ABC-001"}, Classification: "SOLO"}},
+	}}
+	mux := http.NewServeMux()
+	NewHandler(&fakeTracker{}, WithMissingScenesReporter(reporter)).Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reports/missing-scenes?subject=studio&id=studio-1&endpoint=https://box/graphql&dateFrom=2024-01-01&classification=SOLO,SMALL_GROUP&studio=Studio%20A", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="missing-scenes-studio-studio-1.csv"` {
+		t.Fatalf("unexpected Content-Disposition header: %q", got)
+	}
+	if reporter.query.Subject != performer.MissingScenesSubjectStudio || reporter.query.DateFrom != "2024-01-01" || len(reporter.query.Classifications) != 2 || reporter.query.Studios[0] != "Studio A" {
+		t.Fatalf("unexpected report query: %+v", reporter.query)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "stashbox:box:scene-1") || !strings.Contains(body, "SOLO") {
+		t.Fatalf("unexpected csv body: %q", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/reports/missing-scenes?subject=studio&id=studio-1&format=xml", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for unknown format, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestMissingScenesReportEndpointMapsErrorStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{err: fmt.Errorf("%w: subject id is required", performer.ErrInvalidMissingScenesQuery), want: http.StatusBadRequest},
+		{err: fmt.Errorf("%w: performer %q", performer.ErrMissingScenesSubjectNotFound, "1"), want: http.StatusNotFound},
+		{err: fmt.Errorf("%w: timeout", performer.ErrMissingScenesCatalogUnavailable), want: http.StatusBadGateway},
+		{err: performer.ErrLibraryIndexUnavailable, want: http.StatusServiceUnavailable},
+		{err: errors.New("library index: database is locked"), want: http.StatusInternalServerError},
+	} {
+		mux := http.NewServeMux()
+		NewHandler(&fakeTracker{}, WithMissingScenesReporter(&fakeMissingScenesReporter{err: tc.err})).Register(mux)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/reports/missing-scenes?subject=performer&id=1", nil))
+		if rec.Code != tc.want {
+			t.Fatalf("expected status %d for %v, got %d", tc.want, tc.err, rec.Code)
+		}
+	}
+}
+
+type fakeMissingScenesReporter struct {
+	report performer.MissingScenesReport
+	query  performer.MissingScenesQuery
+	err    error
+}
+
+func (f *fakeMissingScenesReporter) MissingScenesReport(_ context.Context, query performer.MissingScenesQuery) (performer.MissingScenesReport, error) {
+	f.query = query
+	return f.report, f.err
+}
+
 type fakeTracker struct {
 	results []jackett.SearchResult
 	query   string
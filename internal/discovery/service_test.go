@@ -45,6 +45,10 @@ func (testStashBoxClient) QueryScenesPage(context.Context, stashboxgraphql.Scene
 	return stashboxpkg.ScenePage{}, nil
 }
 
+func (testStashBoxClient) FindStudioByID(context.Context, string) (*stashboxgraphql.StudioFragment, error) {
+	return nil, nil
+}
+
 type testTaskCreator struct {
 	task *taskruntime.Task
 }
@@ -0,0 +1,298 @@
+// Package feedpoll watches Jackett's Torznab RSS feeds for releases Moji is
+// already waiting on. Feed items are matched by code against blocked
+// SOURCING tasks and pending subscription releases, so a release is grabbed
+// minutes after it is posted instead of at the next subscription refresh.
+package feedpoll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/codeparser"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/subscription"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+const tickInterval = time.Minute
+
+// FeedReader reads the latest items of one indexer's Torznab feed. An empty
+// indexer reads every indexer.
+type FeedReader interface {
+	Feed(indexer string, categories []int) ([]jackett.SearchResult, error)
+}
+
+// TaskSourcer lists tasks and sources blocked ones from feed items.
+type TaskSourcer interface {
+	ListTasks(ctx context.Context) ([]*taskruntime.Task, error)
+	SourceBlockedTask(ctx context.Context, id string, results []jackett.SearchResult) (*taskruntime.Task, error)
+}
+
+// PendingReleases lists and grabs subscription releases waiting for a
+// torrent.
+type PendingReleases interface {
+	PendingDownloads(ctx context.Context) ([]subscription.PendingDownload, error)
+	GrabPendingDownload(ctx context.Context, key string, results []jackett.SearchResult) (*taskruntime.Task, error)
+}
+
+type Settings struct {
+	Enabled    bool
+	Interval   time.Duration
+	Indexers   []string
+	Categories []int
+}
+
+type ConfigProvider func() Settings
+
+// Summary describes one poll.
+type Summary struct {
+	Fetched int
+	Matched int
+	Tasks   []*taskruntime.Task
+}
+
+// Poller reads the configured feeds every Interval. Each feed item is tried
+// at most once per task or release, so an item that fails candidate
+// selection is not re-inspected on every poll.
+type Poller struct {
+	feeds    FeedReader
+	tasks    TaskSourcer
+	releases PendingReleases
+	settings ConfigProvider
+	now      func() time.Time
+
+	pollMu   sync.Mutex
+	lastPoll time.Time
+	// attempted maps a feed item key to the tasks and releases it was
+	// offered to. Items that drop out of the feeds are forgotten.
+	attempted map[string]map[string]struct{}
+}
+
+type Option func(*Poller)
+
+func WithPendingReleases(releases PendingReleases) Option {
+	return func(poller *Poller) {
+		if releases != nil {
+			poller.releases = releases
+		}
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(poller *Poller) {
+		if now != nil {
+			poller.now = now
+		}
+	}
+}
+
+func New(feeds FeedReader, tasks TaskSourcer, provider ConfigProvider, options ...Option) *Poller {
+	if provider == nil {
+		provider = func() Settings { return Settings{} }
+	}
+	poller := &Poller{
+		feeds:     feeds,
+		tasks:     tasks,
+		settings:  provider,
+		now:       time.Now,
+		attempted: make(map[string]map[string]struct{}),
+	}
+	for _, option := range options {
+		option(poller)
+	}
+	return poller
+}
+
+// Start polls the feeds whenever the configured interval has elapsed until
+// ctx is cancelled. Interval changes apply without a restart.
+func (p *Poller) Start(ctx context.Context) {
+	if p == nil || p.feeds == nil || p.tasks == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			settings := p.settings()
+			if settings.Enabled && p.due(settings.Interval) {
+				if _, err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+					logging.Warnf("feedpoll: poll: %v", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Poller) due(interval time.Duration) bool {
+	p.pollMu.Lock()
+	defer p.pollMu.Unlock()
+	return p.lastPoll.IsZero() || !p.now().Before(p.lastPoll.Add(interval))
+}
+
+// Poll reads every configured feed once and acts on the items whose code a
+// blocked task or pending release is waiting for. A failing feed is logged
+// and does not stop the others.
+func (p *Poller) Poll(ctx context.Context) (Summary, error) {
+	p.pollMu.Lock()
+	defer p.pollMu.Unlock()
+	p.lastPoll = p.now()
+	settings := p.settings()
+
+	indexers := settings.Indexers
+	if len(indexers) == 0 {
+		indexers = []string{""}
+	}
+	var items []jackett.SearchResult
+	failed := 0
+	for _, indexer := range indexers {
+		found, err := p.feeds.Feed(indexer, settings.Categories)
+		if err != nil {
+			failed++
+			if ctx.Err() == nil {
+				logging.Warnf("feedpoll: read feed %q: %v", feedName(indexer), err)
+			}
+			continue
+		}
+		items = append(items, found...)
+	}
+	summary := Summary{Fetched: len(items)}
+	if failed == len(indexers) {
+		return summary, fmt.Errorf("feedpoll: every feed failed to load")
+	}
+
+	byCode := make(map[string][]jackett.SearchResult)
+	current := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		key := itemKey(item)
+		if key == "" {
+			continue
+		}
+		current[key] = struct{}{}
+		if code := codeparser.Extract(item.Title); code != "" {
+			byCode[code] = append(byCode[code], item)
+		}
+	}
+	for key := range p.attempted {
+		if _, ok := current[key]; !ok {
+			delete(p.attempted, key)
+		}
+	}
+	if len(byCode) == 0 {
+		return summary, nil
+	}
+
+	tasks, err := p.tasks.ListTasks(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("feedpoll: list tasks: %w", err)
+	}
+	taskCodes := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		code := codeparser.Normalize(task.Code)
+		if code == "" {
+			continue
+		}
+		taskCodes[code] = struct{}{}
+		if task.Stage != taskruntime.TaskStageSourcing || task.StageStatus != taskruntime.TaskStageStatusBlocked {
+			continue
+		}
+		results := p.untried("task:"+task.ID, byCode[code])
+		if len(results) == 0 {
+			continue
+		}
+		summary.Matched++
+		sourced, err := p.tasks.SourceBlockedTask(ctx, task.ID, results)
+		if err != nil {
+			logging.Warnf("feedpoll: source blocked task %s code %q from %d feed items: %v", task.ID, code, len(results), err)
+			continue
+		}
+		logging.Infof("feedpoll: sourced blocked task %s code %q from feed", task.ID, code)
+		summary.Tasks = append(summary.Tasks, sourced)
+	}
+
+	if p.releases == nil {
+		return summary, nil
+	}
+	pending, err := p.releases.PendingDownloads(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("feedpoll: list pending releases: %w", err)
+	}
+	for _, release := range pending {
+		code := codeparser.Extract(release.Code, release.Title)
+		if code == "" {
+			continue
+		}
+		// A task for the code already exists, e.g. the auto-download task
+		// that is blocked in SOURCING; it was handled above.
+		if _, exists := taskCodes[code]; exists {
+			continue
+		}
+		results := p.untried("release:"+release.Key, byCode[code])
+		if len(results) == 0 {
+			continue
+		}
+		summary.Matched++
+		task, err := p.releases.GrabPendingDownload(ctx, release.Key, results)
+		if task != nil {
+			taskCodes[code] = struct{}{}
+		}
+		if err != nil {
+			logging.Warnf("feedpoll: grab pending release %s code %q from %d feed items: %v", release.Key, code, len(results), err)
+			continue
+		}
+		logging.Infof("feedpoll: grabbed pending release %s code %q from feed", release.Key, code)
+		if task != nil {
+			summary.Tasks = append(summary.Tasks, task)
+		}
+	}
+	return summary, nil
+}
+
+// untried returns the items not yet offered to target and marks them as
+// offered.
+func (p *Poller) untried(target string, items []jackett.SearchResult) []jackett.SearchResult {
+	out := make([]jackett.SearchResult, 0, len(items))
+	for _, item := range items {
+		key := itemKey(item)
+		targets := p.attempted[key]
+		if targets == nil {
+			targets = make(map[string]struct{})
+			p.attempted[key] = targets
+		}
+		if _, ok := targets[target]; ok {
+			continue
+		}
+		targets[target] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+// itemKey identifies a feed item by info hash when the indexer reports one,
+// so the same torrent seen through two feeds counts once.
+func itemKey(item jackett.SearchResult) string {
+	if hash := strings.ToLower(strings.TrimSpace(item.InfoHash)); hash != "" {
+		return hash
+	}
+	for _, value := range []string{item.GUID, item.Link, item.MagnetURI} {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func feedName(indexer string) string {
+	if indexer == "" {
+		return "all"
+	}
+	return indexer
+}
@@ -0,0 +1,119 @@
+package feedpoll
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/subscription"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+type fakeFeeds struct {
+	items    map[string][]jackett.SearchResult
+	err      error
+	indexers []string
+}
+
+func (f *fakeFeeds) Feed(indexer string, _ []int) ([]jackett.SearchResult, error) {
+	f.indexers = append(f.indexers, indexer)
+	return f.items[indexer], f.err
+}
+
+type fakeTasks struct {
+	tasks   []*taskruntime.Task
+	sourced map[string][]jackett.SearchResult
+	err     error
+}
+
+func (f *fakeTasks) ListTasks(context.Context) ([]*taskruntime.Task, error) {
+	return f.tasks, nil
+}
+
+func (f *fakeTasks) SourceBlockedTask(_ context.Context, id string, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	f.sourced[id] = append(f.sourced[id], results...)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &taskruntime.Task{ID: id}, nil
+}
+
+type fakeReleases struct {
+	pending []subscription.PendingDownload
+	grabbed map[string][]jackett.SearchResult
+}
+
+func (f *fakeReleases) PendingDownloads(context.Context) ([]subscription.PendingDownload, error) {
+	return f.pending, nil
+}
+
+func (f *fakeReleases) GrabPendingDownload(_ context.Context, key string, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	f.grabbed[key] = append(f.grabbed[key], results...)
+	return &taskruntime.Task{ID: "task-" + key}, nil
+}
+
+func TestPollMatchesBlockedTasksAndPendingReleases(t *testing.T) {
+	feeds := &fakeFeeds{items: map[string][]jackett.SearchResult{
+		"onejav": {
+			{Title: "SONE-786 1080p", InfoHash: "AAA"},
+			{Title: "SONE-786 720p", InfoHash: "BBB"},
+			{Title: "ABF-100 release", InfoHash: "CCC"},
+			{Title: "MIDV-200 release", InfoHash: "DDD"},
+			{Title: "no code here", InfoHash: "EEE"},
+		},
+	}}
+	tasks := &fakeTasks{
+		tasks: []*taskruntime.Task{
+			{ID: "task-blocked", Code: "SONE-786", Stage: taskruntime.TaskStageSourcing, StageStatus: taskruntime.TaskStageStatusBlocked},
+			{ID: "task-running", Code: "MIDV-200", Stage: taskruntime.TaskStageDownloading, StageStatus: taskruntime.TaskStageStatusRunning},
+		},
+		sourced: map[string][]jackett.SearchResult{},
+	}
+	releases := &fakeReleases{
+		pending: []subscription.PendingDownload{
+			{Key: "release-abf", Code: "ABF-100"},
+			{Key: "release-midv", Code: "MIDV-200"},
+			{Key: "release-other", Code: "SSIS-001"},
+		},
+		grabbed: map[string][]jackett.SearchResult{},
+	}
+	poller := New(feeds, tasks, func() Settings {
+		return Settings{Enabled: true, Interval: 5 * time.Minute, Indexers: []string{"onejav"}}
+	}, WithPendingReleases(releases))
+
+	summary, err := poller.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if summary.Fetched != 5 || summary.Matched != 2 || len(summary.Tasks) != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(tasks.sourced["task-blocked"]) != 2 {
+		t.Fatalf("expected both SONE-786 items to be offered to the blocked task, got %+v", tasks.sourced)
+	}
+	if len(releases.grabbed) != 1 || len(releases.grabbed["release-abf"]) != 1 {
+		t.Fatalf("expected only the release without a task to be grabbed, got %+v", releases.grabbed)
+	}
+
+	tasks.err = errors.New("no downloadable torrent candidate found")
+	feeds.items["onejav"] = append(feeds.items["onejav"], jackett.SearchResult{Title: "SONE-786 4K", InfoHash: "FFF"})
+	if _, err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("second Poll: %v", err)
+	}
+	if got := tasks.sourced["task-blocked"]; len(got) != 3 || got[2].InfoHash != "FFF" {
+		t.Fatalf("expected only the new item to be offered again, got %+v", got)
+	}
+}
+
+func TestPollFailsWhenEveryFeedFails(t *testing.T) {
+	feeds := &fakeFeeds{err: errors.New("jackett down")}
+	poller := New(feeds, &fakeTasks{}, func() Settings { return Settings{Enabled: true} })
+	if _, err := poller.Poll(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(feeds.indexers) != 1 || feeds.indexers[0] != "" {
+		t.Fatalf("expected the aggregate feed to be read, got %q", feeds.indexers)
+	}
+}
@@ -0,0 +1,48 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// SetBandwidthOverride is the resolver for the setBandwidthOverride field.
+func (r *mutationResolver) SetBandwidthOverride(ctx context.Context, profile string, durationMinutes *int) (*model.BandwidthStatus, error) {
+	if r.Bandwidth == nil {
+		return nil, errors.New("bandwidth scheduler is not configured")
+	}
+	var duration time.Duration
+	if durationMinutes != nil {
+		if *durationMinutes < 0 {
+			return nil, errors.New("durationMinutes must not be negative")
+		}
+		duration = time.Duration(*durationMinutes) * time.Minute
+	}
+	status, err := r.Bandwidth.Override(ctx, profile, duration)
+	if err != nil {
+		return nil, err
+	}
+	return bandwidthStatusToModel(status), nil
+}
+
+// ClearBandwidthOverride is the resolver for the clearBandwidthOverride field.
+func (r *mutationResolver) ClearBandwidthOverride(ctx context.Context) (*model.BandwidthStatus, error) {
+	if r.Bandwidth == nil {
+		return nil, errors.New("bandwidth scheduler is not configured")
+	}
+	return bandwidthStatusToModel(r.Bandwidth.ClearOverride(ctx)), nil
+}
+
+// BandwidthStatus is the resolver for the bandwidthStatus field.
+func (r *queryResolver) BandwidthStatus(ctx context.Context) (*model.BandwidthStatus, error) {
+	if r.Bandwidth == nil {
+		return &model.BandwidthStatus{Source: model.BandwidthProfileSourceDisabled, Profiles: []*model.BandwidthProfile{}}, nil
+	}
+	return bandwidthStatusToModel(r.Bandwidth.Status()), nil
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/leothevan2444/moji/internal/bandwidth"
 	"github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/subscription"
 	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/webhook"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -42,8 +44,15 @@ const (
 	ErrorTaskBatchTooLarge           = "TASK_BATCH_TOO_LARGE"
 	ErrorPerformerBatchEmpty         = "PERFORMER_BATCH_EMPTY"
 	ErrorPerformerBatchTooLarge      = "PERFORMER_BATCH_TOO_LARGE"
+	ErrorReleaseReviewEmpty          = "RELEASE_REVIEW_EMPTY"
+	ErrorReleaseReviewTooLarge       = "RELEASE_REVIEW_TOO_LARGE"
 	ErrorPerformerSceneBatchEmpty    = "PERFORMER_SCENE_BATCH_EMPTY"
 	ErrorPerformerSceneBatchTooLarge = "PERFORMER_SCENE_BATCH_TOO_LARGE"
+	ErrorWebhookDeliveryNotFound     = "WEBHOOK_DELIVERY_NOT_FOUND"
+	ErrorBandwidthProfileNotFound    = "BANDWIDTH_PROFILE_NOT_FOUND"
+	ErrorInsufficientDiskSpace       = "INSUFFICIENT_DISK_SPACE"
+	ErrorReconcileFixNotApplicable   = "RECONCILE_FIX_NOT_APPLICABLE"
+	ErrorAdoptionFilterRequired      = "TORRENT_ADOPTION_FILTER_REQUIRED"
 )
 
 // ConfigureGraphQLServer installs the production error contract in one place.
@@ -121,10 +130,24 @@ func classifyError(err error) string {
 		return ErrorPerformerBatchEmpty
 	case errors.Is(err, subscription.ErrPerformerBatchTooLarge):
 		return ErrorPerformerBatchTooLarge
+	case errors.Is(err, subscription.ErrReleaseReviewEmpty):
+		return ErrorReleaseReviewEmpty
+	case errors.Is(err, subscription.ErrReleaseReviewTooLarge):
+		return ErrorReleaseReviewTooLarge
 	case errors.Is(err, performer.ErrQueueSceneBatchEmpty):
 		return ErrorPerformerSceneBatchEmpty
 	case errors.Is(err, performer.ErrQueueSceneBatchTooLarge):
 		return ErrorPerformerSceneBatchTooLarge
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return ErrorWebhookDeliveryNotFound
+	case errors.Is(err, bandwidth.ErrProfileNotFound):
+		return ErrorBandwidthProfileNotFound
+	case errors.Is(err, taskruntime.ErrInsufficientDiskSpace):
+		return ErrorInsufficientDiskSpace
+	case errors.Is(err, taskruntime.ErrReconcileFixNotApplicable):
+		return ErrorReconcileFixNotApplicable
+	case errors.Is(err, taskruntime.ErrTorrentAdoptionFilterRequired):
+		return ErrorAdoptionFilterRequired
 	}
 	message := strings.ToLower(err.Error())
 	switch {
@@ -21,6 +21,8 @@ func TestErrorPresenterContract(t *testing.T) {
 		{taskruntime.ErrTaskCodeRequired, ErrorTaskCodeRequired},
 		{subscription.ErrPerformerBatchEmpty, ErrorPerformerBatchEmpty},
 		{subscription.ErrPerformerBatchTooLarge, ErrorPerformerBatchTooLarge},
+		{subscription.ErrReleaseReviewEmpty, ErrorReleaseReviewEmpty},
+		{subscription.ErrReleaseReviewTooLarge, ErrorReleaseReviewTooLarge},
 		{performer.ErrQueueSceneBatchEmpty, ErrorPerformerSceneBatchEmpty},
 		{performer.ErrQueueSceneBatchTooLarge, ErrorPerformerSceneBatchTooLarge},
 		{fmt.Errorf("tracker is not configured"), ErrorTrackerNotConfigured},
@@ -4,9 +4,11 @@ import (
 	"sort"
 	"time"
 
+	"github.com/leothevan2444/moji/internal/bandwidth"
 	"github.com/leothevan2444/moji/internal/graphqlapi/model"
 	"github.com/leothevan2444/moji/internal/stashsync"
 	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/webhook"
 	"github.com/leothevan2444/moji/pkg/jackett"
 	"github.com/leothevan2444/moji/pkg/qbittorrent"
 )
@@ -165,38 +167,44 @@ func taskToModel(task *taskruntime.Task) *model.Task {
 	}
 
 	return &model.Task{
-		ID:                  task.ID,
-		Source:              model.TaskSource(source),
-		Code:                task.Code,
-		Stage:               model.TaskStage(task.Stage),
-		StageStatus:         model.TaskStageStatus(task.StageStatus),
-		StageLabel:          task.StageLabel,
-		StageStatusLabel:    task.StageStatusLabel,
-		StageErrorCode:      nilIfEmpty(task.StageErrorCode),
-		StageErrorMessage:   nilIfEmpty(task.StageErrorMessage),
-		Candidate:           candidateToModel(task.Candidate),
-		TorrentURL:          task.TorrentURL,
-		SavePath:            nilIfEmpty(task.SavePath),
-		Category:            nilIfEmpty(task.Category),
-		Tags:                nilIfEmpty(task.Tags),
-		TorrentHash:         nilIfEmpty(task.TorrentHash),
-		TorrentName:         nilIfEmpty(task.TorrentName),
-		Progress:            task.Progress,
-		QbittorrentState:    nilIfEmpty(task.QBittorrentState),
-		ContentPath:         nilIfEmpty(task.ContentPath),
-		DownloadCompletedAt: formatOptionalTime(task.DownloadCompletedAt),
-		DeliveryMode:        nilIfEmpty(task.DeliveryMode),
-		MojiSourcePath:      nilIfEmpty(task.MojiSourcePath),
-		TransferAction:      nilIfEmpty(task.TransferAction),
-		MojiTransferPath:    nilIfEmpty(task.MojiTransferPath),
-		TransferError:       nilIfEmpty(task.TransferError),
-		StashScanJobID:      nilIfEmpty(task.StashScanJobID),
-		StashScanPath:       nilIfEmpty(task.StashScanPath),
-		StashScanError:      nilIfEmpty(task.StashScanError),
-		StashScanHint:       nilIfEmpty(task.StashScanHint),
-		StashScanStartedAt:  formatOptionalTime(task.StashScanStartedAt),
-		CreatedAt:           formatTime(task.CreatedAt),
-		UpdatedAt:           formatTime(task.UpdatedAt),
+		ID:                     task.ID,
+		Source:                 model.TaskSource(source),
+		Code:                   task.Code,
+		Stage:                  model.TaskStage(task.Stage),
+		StageStatus:            model.TaskStageStatus(task.StageStatus),
+		StageLabel:             task.StageLabel,
+		StageStatusLabel:       task.StageStatusLabel,
+		StageErrorCode:         nilIfEmpty(task.StageErrorCode),
+		StageErrorMessage:      nilIfEmpty(task.StageErrorMessage),
+		Candidate:              candidateToModel(task.Candidate),
+		TorrentURL:             task.TorrentURL,
+		SavePath:               nilIfEmpty(task.SavePath),
+		Category:               nilIfEmpty(task.Category),
+		Tags:                   nilIfEmpty(task.Tags),
+		TorrentHash:            nilIfEmpty(task.TorrentHash),
+		TorrentName:            nilIfEmpty(task.TorrentName),
+		Progress:               task.Progress,
+		QbittorrentState:       nilIfEmpty(task.QBittorrentState),
+		ContentPath:            nilIfEmpty(task.ContentPath),
+		DownloadCompletedAt:    formatOptionalTime(task.DownloadCompletedAt),
+		LibraryRoute:           nilIfEmpty(task.LibraryRoute),
+		DeliveryMode:           nilIfEmpty(task.DeliveryMode),
+		MojiSourcePath:         nilIfEmpty(task.MojiSourcePath),
+		TransferAction:         nilIfEmpty(task.TransferAction),
+		MojiTransferPath:       nilIfEmpty(task.MojiTransferPath),
+		TransferError:          nilIfEmpty(task.TransferError),
+		StashScanJobID:         nilIfEmpty(task.StashScanJobID),
+		StashScanPath:          nilIfEmpty(task.StashScanPath),
+		StashScanError:         nilIfEmpty(task.StashScanError),
+		StashScanHint:          nilIfEmpty(task.StashScanHint),
+		StashScanStartedAt:     formatOptionalTime(task.StashScanStartedAt),
+		StashIdentifyJobID:     nilIfEmpty(task.StashIdentifyJobID),
+		StashSceneID:           nilIfEmpty(task.StashSceneID),
+		OriginStashBoxEndpoint: nilIfEmpty(task.Origin.StashBoxEndpoint),
+		OriginStashBoxSceneID:  nilIfEmpty(task.Origin.StashBoxSceneID),
+		MediaProbe:             mediaProbeToModel(task.MediaProbe),
+		CreatedAt:              formatTime(task.CreatedAt),
+		UpdatedAt:              formatTime(task.UpdatedAt),
 	}
 }
 
@@ -212,16 +220,20 @@ func taskBatchPayloadToModel(payload taskruntime.TaskBatchPayload) *model.TaskBa
 	}
 	return &model.TaskBatchPayload{
 		BatchID: payload.BatchID,
-		Summary: &model.TaskBatchSummary{
-			RequestedCount: payload.Summary.RequestedCount,
-			SucceededCount: payload.Summary.SucceededCount,
-			SkippedCount:   payload.Summary.SkippedCount,
-			FailedCount:    payload.Summary.FailedCount,
-		},
+		Summary: taskBatchSummaryToModel(payload.Summary),
 		Results: results,
 	}
 }
 
+func taskBatchSummaryToModel(summary taskruntime.TaskBatchSummary) *model.TaskBatchSummary {
+	return &model.TaskBatchSummary{
+		RequestedCount: summary.RequestedCount,
+		SucceededCount: summary.SucceededCount,
+		SkippedCount:   summary.SkippedCount,
+		FailedCount:    summary.FailedCount,
+	}
+}
+
 func candidateToModel(candidate taskruntime.Candidate) *model.DownloadCandidate {
 	return &model.DownloadCandidate{
 		Title:     candidate.Title,
@@ -235,6 +247,44 @@ func candidateToModel(candidate taskruntime.Candidate) *model.DownloadCandidate
 	}
 }
 
+func mediaProbeToModel(probe *taskruntime.MediaProbe) *model.TaskMediaProbe {
+	if probe == nil {
+		return nil
+	}
+	return &model.TaskMediaProbe{
+		Path:                    probe.Path,
+		Container:               nilIfEmpty(probe.Container),
+		DurationSeconds:         nilIfNotPositive(probe.DurationSeconds),
+		Width:                   nilIfNotPositive(probe.Width),
+		Height:                  nilIfNotPositive(probe.Height),
+		Resolution:              nilIfEmpty(probe.Resolution()),
+		VideoCodec:              nilIfEmpty(probe.VideoCodec),
+		AudioCodec:              nilIfEmpty(probe.AudioCodec),
+		BitRate:                 nilIfNotPositive(probe.BitRate),
+		ExpectedDurationSeconds: nilIfNotPositive(probe.ExpectedDurationSeconds),
+		DurationMismatch:        probe.DurationMismatch,
+		Warnings:                mediaWarningsToModel(probe.Warnings),
+		Error:                   nilIfEmpty(probe.Error),
+		ProbedAt:                formatTime(probe.ProbedAt),
+	}
+}
+
+func mediaWarningsToModel(warnings []taskruntime.MediaWarning) []model.TaskMediaWarning {
+	out := make([]model.TaskMediaWarning, 0, len(warnings))
+	for _, warning := range warnings {
+		out = append(out, model.TaskMediaWarning(warning))
+	}
+	return out
+}
+
+// nilIfNotPositive treats zero as "not reported" for probe measurements.
+func nilIfNotPositive[T int | int64 | float64](value T) *T {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
+
 func stashJobToModel(job *stashsync.Job) *model.StashJob {
 	if job == nil {
 		return nil
@@ -265,3 +315,171 @@ func formatOptionalTime(t *time.Time) *string {
 func formatTime(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
+
+func webhookDeliveryToModel(delivery webhook.Delivery) *model.WebhookDelivery {
+	out := &model.WebhookDelivery{
+		ID:            delivery.ID,
+		Webhook:       delivery.Webhook,
+		URL:           delivery.URL,
+		Event:         delivery.Event,
+		Status:        model.WebhookDeliveryStatus(delivery.Status),
+		Attempts:      delivery.Attempts,
+		Payload:       string(delivery.Payload),
+		CreatedAt:     formatTime(delivery.CreatedAt),
+		LastAttemptAt: formatOptionalTime(delivery.LastAttemptAt),
+		NextAttemptAt: formatOptionalTime(delivery.NextAttemptAt),
+		DeliveredAt:   formatOptionalTime(delivery.DeliveredAt),
+	}
+	if delivery.ResponseStatus != 0 {
+		status := delivery.ResponseStatus
+		out.ResponseStatus = &status
+	}
+	if delivery.LastError != "" {
+		lastError := delivery.LastError
+		out.LastError = &lastError
+	}
+	if delivery.RedeliveryOf != "" {
+		redeliveryOf := delivery.RedeliveryOf
+		out.RedeliveryOf = &redeliveryOf
+	}
+	return out
+}
+
+func webhookDeliveryFilterFromModel(name *string, status *model.WebhookDeliveryStatus, limit *int) webhook.DeliveryFilter {
+	filter := webhook.DeliveryFilter{Webhook: derefString(name)}
+	if status != nil {
+		filter.Status = webhook.DeliveryStatus(*status)
+	}
+	if limit != nil {
+		filter.Limit = *limit
+	}
+	return filter
+}
+
+func bandwidthStatusToModel(status bandwidth.Status) *model.BandwidthStatus {
+	out := &model.BandwidthStatus{
+		Enabled:       status.Enabled,
+		Source:        model.BandwidthProfileSource(status.Source),
+		OverrideUntil: formatOptionalTime(status.OverrideUntil),
+		AppliedAt:     formatOptionalTime(status.AppliedAt),
+		Profiles:      make([]*model.BandwidthProfile, 0, len(status.Profiles)),
+	}
+	if status.Source != bandwidth.SourceDisabled {
+		out.ActiveProfile = bandwidthProfileToModel(status.Profile)
+	}
+	if status.LastError != "" {
+		lastError := status.LastError
+		out.LastError = &lastError
+	}
+	for _, profile := range status.Profiles {
+		out.Profiles = append(out.Profiles, bandwidthProfileToModel(profile))
+	}
+	return out
+}
+
+func bandwidthProfileToModel(profile bandwidth.Profile) *model.BandwidthProfile {
+	return &model.BandwidthProfile{
+		Name:             profile.Name,
+		DownloadLimitKib: profile.DownloadLimit / 1024,
+		UploadLimitKib:   profile.UploadLimit / 1024,
+		AlternativeSpeed: profile.AlternativeSpeed,
+	}
+}
+
+func taskReconcileReportToModel(report taskruntime.ReconcileReport) *model.TaskReconciliationReport {
+	out := &model.TaskReconciliationReport{
+		GeneratedAt:  formatTime(report.GeneratedAt),
+		TaskCount:    report.TaskCount,
+		TorrentCount: report.TorrentCount,
+		Issues:       make([]*model.TaskReconciliationIssue, 0, len(report.Issues)),
+	}
+	for _, issue := range report.Issues {
+		fixes := make([]model.TaskReconciliationFix, 0, len(issue.ProposedFixes))
+		for _, fix := range issue.ProposedFixes {
+			fixes = append(fixes, model.TaskReconciliationFix(fix))
+		}
+		out.Issues = append(out.Issues, &model.TaskReconciliationIssue{
+			Kind:            model.TaskReconciliationIssueKind(issue.Kind),
+			TaskID:          nilIfEmpty(issue.TaskID),
+			Code:            nilIfEmpty(issue.Code),
+			TorrentHash:     nilIfEmpty(issue.TorrentHash),
+			TorrentName:     nilIfEmpty(issue.TorrentName),
+			TaskSavePath:    nilIfEmpty(issue.TaskSavePath),
+			TorrentSavePath: nilIfEmpty(issue.TorrentSavePath),
+			Detail:          issue.Detail,
+			ProposedFixes:   fixes,
+		})
+	}
+	return out
+}
+
+func taskReconcileFixFromModel(input model.TaskReconciliationFixInput) taskruntime.ReconcileFixRequest {
+	return taskruntime.ReconcileFixRequest{
+		Fix:         taskruntime.ReconcileFix(input.Fix),
+		TaskID:      derefString(input.TaskID),
+		TorrentHash: derefString(input.TorrentHash),
+	}
+}
+
+func torrentAdoptionFilterFromModel(input *model.TorrentAdoptionFilterInput) taskruntime.TorrentAdoptionFilter {
+	if input == nil {
+		return taskruntime.TorrentAdoptionFilter{}
+	}
+	return taskruntime.TorrentAdoptionFilter{
+		Category: derefString(input.Category),
+		Tag:      derefString(input.Tag),
+		SavePath: derefString(input.SavePath),
+		Hashes:   input.Hashes,
+	}
+}
+
+func torrentAdoptionRequestFromModel(input model.AdoptTorrentsInput) taskruntime.TorrentAdoptionRequest {
+	req := taskruntime.TorrentAdoptionRequest{Filter: torrentAdoptionFilterFromModel(input.Filter)}
+	if len(input.Codes) > 0 {
+		req.Codes = make(map[string]string, len(input.Codes))
+		for _, item := range input.Codes {
+			if item != nil {
+				req.Codes[item.Hash] = item.Code
+			}
+		}
+	}
+	return req
+}
+
+func adoptableTorrentToModel(candidate taskruntime.TorrentAdoptionCandidate) *model.AdoptableTorrent {
+	out := &model.AdoptableTorrent{
+		Hash:     candidate.Hash,
+		Name:     candidate.Name,
+		Category: candidate.Category,
+		Tags:     candidate.Tags,
+		SavePath: candidate.SavePath,
+		Progress: candidate.Progress,
+		Code:     nilIfEmpty(candidate.Code),
+		Stage:    model.TaskStage(candidate.Stage),
+		TaskID:   nilIfEmpty(candidate.TaskID),
+	}
+	if candidate.CodeSource != "" {
+		source := model.TorrentCodeSource(candidate.CodeSource)
+		out.CodeSource = &source
+	}
+	return out
+}
+
+func torrentAdoptionPayloadToModel(payload taskruntime.TorrentAdoptionPayload) *model.TorrentAdoptionPayload {
+	results := make([]*model.TorrentAdoptionResult, 0, len(payload.Results))
+	for _, result := range payload.Results {
+		results = append(results, &model.TorrentAdoptionResult{
+			Hash:       result.Hash,
+			Name:       result.Name,
+			Code:       nilIfEmpty(result.Code),
+			Status:     model.TaskBatchStatus(result.Status),
+			ReasonCode: result.ReasonCode,
+			Message:    nilIfEmpty(result.Message),
+			Task:       taskToModel(result.Task),
+		})
+	}
+	return &model.TorrentAdoptionPayload{
+		Summary: taskBatchSummaryToModel(payload.Summary),
+		Results: results,
+	}
+}
@@ -9,6 +9,27 @@ import (
 	"strconv"
 )
 
+type AdoptTorrentsInput struct {
+	Filter *TorrentAdoptionFilterInput `json:"filter"`
+	// Codes to use instead of the ones extracted from torrent and file names
+	Codes []*TorrentCodeOverrideInput `json:"codes,omitempty"`
+}
+
+type AdoptableTorrent struct {
+	Hash       string             `json:"hash"`
+	Name       string             `json:"name"`
+	Category   string             `json:"category"`
+	Tags       string             `json:"tags"`
+	SavePath   string             `json:"savePath"`
+	Progress   float64            `json:"progress"`
+	Code       *string            `json:"code,omitempty"`
+	CodeSource *TorrentCodeSource `json:"codeSource,omitempty"`
+	// Stage the adopted task would start in
+	Stage TaskStage `json:"stage"`
+	// Task that already owns this torrent
+	TaskID *string `json:"taskId,omitempty"`
+}
+
 type AutomationSettings struct {
 	TaskProgressSyncIntervalSeconds int `json:"taskProgressSyncIntervalSeconds"`
 	SubscriptionPollIntervalHours   int `json:"subscriptionPollIntervalHours"`
@@ -25,6 +46,25 @@ type AutomationStatus struct {
 	SubscriptionPollEnabled         bool `json:"subscriptionPollEnabled"`
 }
 
+type BandwidthProfile struct {
+	Name string `json:"name"`
+	// Global download limit in KiB/s; 0 means unlimited
+	DownloadLimitKib int `json:"downloadLimitKib"`
+	// Global upload limit in KiB/s; 0 means unlimited
+	UploadLimitKib   int  `json:"uploadLimitKib"`
+	AlternativeSpeed bool `json:"alternativeSpeed"`
+}
+
+type BandwidthStatus struct {
+	Enabled       bool                   `json:"enabled"`
+	ActiveProfile *BandwidthProfile      `json:"activeProfile,omitempty"`
+	Source        BandwidthProfileSource `json:"source"`
+	OverrideUntil *string                `json:"overrideUntil,omitempty"`
+	AppliedAt     *string                `json:"appliedAt,omitempty"`
+	LastError     *string                `json:"lastError,omitempty"`
+	Profiles      []*BandwidthProfile    `json:"profiles"`
+}
+
 type DashboardStats struct {
 	Total        int `json:"total"`
 	Active       int `json:"active"`
@@ -207,6 +247,21 @@ type JackettStats struct {
 	OkAt *string `json:"okAt,omitempty"`
 }
 
+type LibraryIndexStatus struct {
+	// Whether a full sync finished; until then lookups query Stash directly
+	Ready      bool `json:"ready"`
+	Rebuilding bool `json:"rebuilding"`
+	SceneCount int  `json:"sceneCount"`
+	// Distinct codes from scene codes, file paths and titles
+	CodeCount     int     `json:"codeCount"`
+	StashIDCount  int     `json:"stashIdCount"`
+	OshashCount   int     `json:"oshashCount"`
+	DatabasePath  string  `json:"databasePath"`
+	LastRebuildAt *string `json:"lastRebuildAt,omitempty"`
+	LastSyncAt    *string `json:"lastSyncAt,omitempty"`
+	LastError     *string `json:"lastError,omitempty"`
+}
+
 type LibraryIngestSettings struct {
 	MojiRoot  string `json:"mojiRoot"`
 	StashRoot string `json:"stashRoot"`
@@ -236,9 +291,96 @@ type MatchedStashBox struct {
 	PerformerName string `json:"performerName"`
 }
 
+type MissingScene struct {
+	Scene          *StashPerformerScene `json:"scene"`
+	Classification *string              `json:"classification,omitempty"`
+}
+
+type MissingScenesReport struct {
+	Subject      MissingScenesSubject `json:"subject"`
+	ID           string               `json:"id"`
+	Name         *string              `json:"name,omitempty"`
+	Endpoint     *string              `json:"endpoint,omitempty"`
+	CatalogCount int                  `json:"catalogCount"`
+	// True when StashBox stopped returning pages before the whole catalog loaded
+	CatalogPartial bool `json:"catalogPartial"`
+	// Owned scenes among those matching the filters
+	OwnedCount     int             `json:"ownedCount"`
+	MissingCount   int             `json:"missingCount"`
+	Items          []*MissingScene `json:"items"`
+	CacheUpdatedAt *string         `json:"cacheUpdatedAt,omitempty"`
+	CacheStale     bool            `json:"cacheStale"`
+}
+
+type MissingScenesReportInput struct {
+	Subject MissingScenesSubject `json:"subject"`
+	// Stash performer id for PERFORMER, StashBox studio id for STUDIO
+	ID string `json:"id"`
+	// StashBox endpoint; required for STUDIO
+	Endpoint *string `json:"endpoint,omitempty"`
+	// Inclusive YYYY-MM-DD bounds; undated scenes are excluded when set
+	DateFrom        *string  `json:"dateFrom,omitempty"`
+	DateTo          *string  `json:"dateTo,omitempty"`
+	Classifications []string `json:"classifications,omitempty"`
+	Studios         []string `json:"studios,omitempty"`
+}
+
 type Mutation struct {
 }
 
+// A chat destination. Discord uses url as the webhook URL; ntfy and Gotify use it
+// as the server URL. Telegram uses token as the bot token and chatId as the
+// destination, with url optionally pointing at a self-hosted Bot API server.
+type NotificationChannel struct {
+	Name    string                  `json:"name"`
+	Type    NotificationChannelType `json:"type"`
+	Enabled bool                    `json:"enabled"`
+	// Empty means every event type
+	Events []NotificationEventType `json:"events"`
+	URL    string                  `json:"url"`
+	// Bot, app or access token. Returned in plaintext for the settings UI; never logged.
+	Token    string `json:"token"`
+	ChatID   string `json:"chatId"`
+	Topic    string `json:"topic"`
+	Priority int    `json:"priority"`
+}
+
+type NotificationChannelInput struct {
+	Name     string                  `json:"name"`
+	Type     NotificationChannelType `json:"type"`
+	Enabled  bool                    `json:"enabled"`
+	Events   []NotificationEventType `json:"events,omitempty"`
+	URL      *string                 `json:"url,omitempty"`
+	Token    *string                 `json:"token,omitempty"`
+	ChatID   *string                 `json:"chatId,omitempty"`
+	Topic    *string                 `json:"topic,omitempty"`
+	Priority *int                    `json:"priority,omitempty"`
+}
+
+type NotificationSettings struct {
+	Channels []*NotificationChannel `json:"channels"`
+	// One template per event type; unset overrides fall back to the built-in defaults
+	Templates []*NotificationTemplate `json:"templates"`
+}
+
+// Go text/template sources rendered against the event (.Code, .Stage, .TorrentName, .Service, .ErrorMessage, ...)
+type NotificationTemplate struct {
+	Event NotificationEventType `json:"event"`
+	Title string                `json:"title"`
+	Body  string                `json:"body"`
+}
+
+type NotificationTemplateInput struct {
+	Event NotificationEventType `json:"event"`
+	Title string                `json:"title"`
+	Body  string                `json:"body"`
+}
+
+type NotificationTestResult struct {
+	Ok    bool    `json:"ok"`
+	Error *string `json:"error,omitempty"`
+}
+
 type PerformerBatchPayload struct {
 	BatchID string                  `json:"batchId"`
 	Summary *PerformerBatchSummary  `json:"summary"`
@@ -386,6 +528,11 @@ type QueueDiscoveredSceneInput struct {
 	StashBoxEndpoint string `json:"stashBoxEndpoint"`
 }
 
+type QueueMissingScenesInput struct {
+	Report    *MissingScenesReportInput `json:"report"`
+	SceneKeys []string                  `json:"sceneKeys,omitempty"`
+}
+
 type QueuePerformerSceneResult struct {
 	Key          string                    `json:"key"`
 	Status       QueuePerformerSceneStatus `json:"status"`
@@ -412,6 +559,62 @@ type QueuePerformerScenesSummary struct {
 	FailedCount    int `json:"failedCount"`
 }
 
+type QueuedRelease struct {
+	Release        *SubscriptionRelease `json:"release"`
+	Classification string               `json:"classification"`
+	DecisionReason string               `json:"decisionReason"`
+	// Stash performers whose subscription found the release
+	PerformerIds []string `json:"performerIds"`
+	// StashBox studios whose subscription found the release
+	Studios []*QueuedReleaseStudio `json:"studios"`
+}
+
+type QueuedReleaseStudio struct {
+	Endpoint string `json:"endpoint"`
+	StudioID string `json:"studioId"`
+}
+
+type ReleaseReviewConnection struct {
+	Items       []*QueuedRelease `json:"items"`
+	Page        int              `json:"page"`
+	PageSize    int              `json:"pageSize"`
+	TotalCount  int              `json:"totalCount"`
+	TotalPages  int              `json:"totalPages"`
+	HasPrevPage bool             `json:"hasPrevPage"`
+	HasNextPage bool             `json:"hasNextPage"`
+}
+
+type ReleaseReviewPayload struct {
+	Summary *ReleaseReviewSummary  `json:"summary"`
+	Results []*ReleaseReviewResult `json:"results"`
+}
+
+type ReleaseReviewQueueInput struct {
+	// Decision reasons, e.g. group_behavior_review; empty matches all
+	Reasons []string `json:"reasons,omitempty"`
+	// Release classifications, e.g. SMALL_GROUP; empty matches all
+	Classifications []string `json:"classifications,omitempty"`
+	// Inclusive YYYY-MM-DD bounds; undated releases are excluded when set
+	DateFrom *string `json:"dateFrom,omitempty"`
+	DateTo   *string `json:"dateTo,omitempty"`
+	Page     *int    `json:"page,omitempty"`
+	PageSize *int    `json:"pageSize,omitempty"`
+}
+
+type ReleaseReviewResult struct {
+	Key        string              `json:"key"`
+	Status     ReleaseReviewStatus `json:"status"`
+	ReasonCode string              `json:"reasonCode"`
+	Task       *Task               `json:"task,omitempty"`
+}
+
+type ReleaseReviewSummary struct {
+	RequestedCount int `json:"requestedCount"`
+	SucceededCount int `json:"succeededCount"`
+	SkippedCount   int `json:"skippedCount"`
+	FailedCount    int `json:"failedCount"`
+}
+
 type ResolveBlockedSourcingTaskInput struct {
 	TorrentURL string  `json:"torrentUrl"`
 	Title      *string `json:"title,omitempty"`
@@ -437,25 +640,28 @@ type ServiceStatusEvent struct {
 }
 
 type Settings struct {
-	Stash       *StashSettings       `json:"stash"`
-	Ingest      *IngestSettings      `json:"ingest"`
-	Jackett     *JackettSettings     `json:"jackett"`
-	Qbittorrent *QBittorrentSettings `json:"qbittorrent"`
-	Automation  *AutomationSettings  `json:"automation"`
-	System      *SystemSettings      `json:"system"`
+	Stash         *StashSettings        `json:"stash"`
+	Ingest        *IngestSettings       `json:"ingest"`
+	Jackett       *JackettSettings      `json:"jackett"`
+	Qbittorrent   *QBittorrentSettings  `json:"qbittorrent"`
+	Automation    *AutomationSettings   `json:"automation"`
+	System        *SystemSettings       `json:"system"`
+	Notifications *NotificationSettings `json:"notifications"`
 }
 
 type SettingsStatus struct {
-	Stash                   *ServiceStatus           `json:"stash"`
-	Jackett                 *ServiceStatus           `json:"jackett"`
-	Qbittorrent             *ServiceStatus           `json:"qbittorrent"`
-	Automation              *AutomationStatus        `json:"automation"`
-	StashBox                *StashBoxStatus          `json:"stashBox"`
-	Ingest                  *IngestStatus            `json:"ingest"`
-	ImageCache              *ImageCacheStatus        `json:"imageCache"`
-	StashBoxDataCache       *StashBoxDataCacheStatus `json:"stashBoxDataCache"`
-	StashLibraries          []*StashLibrary          `json:"stashLibraries"`
-	StashLibrariesLoadError *string                  `json:"stashLibrariesLoadError,omitempty"`
+	Stash                   *ServiceStatus                `json:"stash"`
+	Jackett                 *ServiceStatus                `json:"jackett"`
+	Qbittorrent             *ServiceStatus                `json:"qbittorrent"`
+	Automation              *AutomationStatus             `json:"automation"`
+	StashBox                *StashBoxStatus               `json:"stashBox"`
+	Ingest                  *IngestStatus                 `json:"ingest"`
+	ImageCache              *ImageCacheStatus             `json:"imageCache"`
+	StashBoxDataCache       *StashBoxDataCacheStatus      `json:"stashBoxDataCache"`
+	TorrentInspectionCache  *TorrentInspectionCacheStatus `json:"torrentInspectionCache"`
+	LibraryIndex            *LibraryIndexStatus           `json:"libraryIndex"`
+	StashLibraries          []*StashLibrary               `json:"stashLibraries"`
+	StashLibrariesLoadError *string                       `json:"stashLibrariesLoadError,omitempty"`
 	// Runtime stats for the Stash server. Refreshed by the stats collector.
 	StashStats *StashStats `json:"stashStats"`
 	// Runtime stats for the Jackett indexer aggregator. Refreshed by the stats collector.
@@ -642,12 +848,29 @@ type StashStats struct {
 }
 
 type SubscribedPerformer struct {
-	Performer             *StashPerformer        `json:"performer"`
+	Performer     *StashPerformer `json:"performer"`
+	LastCheckedAt *string         `json:"lastCheckedAt,omitempty"`
+	LastError     *string         `json:"lastError,omitempty"`
+	// Release policy override for this performer; null when the global policy applies
+	ReleasePolicy         *SubscriptionReleasePolicy `json:"releasePolicy,omitempty"`
+	PendingReleaseCount   int                        `json:"pendingReleaseCount"`
+	ProcessedReleaseCount int                        `json:"processedReleaseCount"`
+	RecentReleases        []*SubscriptionRelease     `json:"recentReleases"`
+	// Releases dated in the future, soonest sourcing first
+	UpcomingReleases []*UpcomingRelease `json:"upcomingReleases"`
+}
+
+type SubscribedStudio struct {
+	Endpoint              string                 `json:"endpoint"`
+	StudioID              string                 `json:"studioId"`
+	Name                  string                 `json:"name"`
+	SubscribedAt          string                 `json:"subscribedAt"`
 	LastCheckedAt         *string                `json:"lastCheckedAt,omitempty"`
 	LastError             *string                `json:"lastError,omitempty"`
 	PendingReleaseCount   int                    `json:"pendingReleaseCount"`
 	ProcessedReleaseCount int                    `json:"processedReleaseCount"`
 	RecentReleases        []*SubscriptionRelease `json:"recentReleases"`
+	UpcomingReleases      []*UpcomingRelease     `json:"upcomingReleases"`
 }
 
 type Subscription struct {
@@ -683,9 +906,10 @@ type SubscriptionReleasePolicyInput struct {
 }
 
 type SystemSettings struct {
-	TaskDeletePolicy  TaskDeletePolicy           `json:"taskDeletePolicy"`
-	ImageCache        *ImageCacheSettings        `json:"imageCache"`
-	StashBoxDataCache *StashBoxDataCacheSettings `json:"stashBoxDataCache"`
+	TaskDeletePolicy       TaskDeletePolicy                `json:"taskDeletePolicy"`
+	ImageCache             *ImageCacheSettings             `json:"imageCache"`
+	StashBoxDataCache      *StashBoxDataCacheSettings      `json:"stashBoxDataCache"`
+	TorrentInspectionCache *TorrentInspectionCacheSettings `json:"torrentInspectionCache"`
 }
 
 type Task struct {
@@ -709,18 +933,25 @@ type Task struct {
 	QbittorrentState    *string            `json:"qbittorrentState,omitempty"`
 	ContentPath         *string            `json:"contentPath,omitempty"`
 	DownloadCompletedAt *string            `json:"downloadCompletedAt,omitempty"`
-	DeliveryMode        *string            `json:"deliveryMode,omitempty"`
-	MojiSourcePath      *string            `json:"mojiSourcePath,omitempty"`
-	TransferAction      *string            `json:"transferAction,omitempty"`
-	MojiTransferPath    *string            `json:"mojiTransferPath,omitempty"`
-	TransferError       *string            `json:"transferError,omitempty"`
-	StashScanJobID      *string            `json:"stashScanJobId,omitempty"`
-	StashScanPath       *string            `json:"stashScanPath,omitempty"`
-	StashScanError      *string            `json:"stashScanError,omitempty"`
-	StashScanHint       *string            `json:"stashScanHint,omitempty"`
-	StashScanStartedAt  *string            `json:"stashScanStartedAt,omitempty"`
-	CreatedAt           string             `json:"createdAt"`
-	UpdatedAt           string             `json:"updatedAt"`
+	// Name of the library route the task was delivered by; null for the default library.
+	LibraryRoute           *string         `json:"libraryRoute,omitempty"`
+	DeliveryMode           *string         `json:"deliveryMode,omitempty"`
+	MojiSourcePath         *string         `json:"mojiSourcePath,omitempty"`
+	TransferAction         *string         `json:"transferAction,omitempty"`
+	MojiTransferPath       *string         `json:"mojiTransferPath,omitempty"`
+	TransferError          *string         `json:"transferError,omitempty"`
+	StashScanJobID         *string         `json:"stashScanJobId,omitempty"`
+	StashScanPath          *string         `json:"stashScanPath,omitempty"`
+	StashScanError         *string         `json:"stashScanError,omitempty"`
+	StashScanHint          *string         `json:"stashScanHint,omitempty"`
+	StashScanStartedAt     *string         `json:"stashScanStartedAt,omitempty"`
+	StashIdentifyJobID     *string         `json:"stashIdentifyJobId,omitempty"`
+	StashSceneID           *string         `json:"stashSceneId,omitempty"`
+	OriginStashBoxEndpoint *string         `json:"originStashBoxEndpoint,omitempty"`
+	OriginStashBoxSceneID  *string         `json:"originStashBoxSceneId,omitempty"`
+	MediaProbe             *TaskMediaProbe `json:"mediaProbe,omitempty"`
+	CreatedAt              string          `json:"createdAt"`
+	UpdatedAt              string          `json:"updatedAt"`
 }
 
 type TaskBatchPayload struct {
@@ -751,6 +982,51 @@ type TaskEvent struct {
 	DashboardStats *DashboardStats `json:"dashboardStats"`
 }
 
+type TaskMediaProbe struct {
+	Path                    string   `json:"path"`
+	Container               *string  `json:"container,omitempty"`
+	DurationSeconds         *float64 `json:"durationSeconds,omitempty"`
+	Width                   *int     `json:"width,omitempty"`
+	Height                  *int     `json:"height,omitempty"`
+	Resolution              *string  `json:"resolution,omitempty"`
+	VideoCodec              *string  `json:"videoCodec,omitempty"`
+	AudioCodec              *string  `json:"audioCodec,omitempty"`
+	BitRate                 *int64   `json:"bitRate,omitempty"`
+	ExpectedDurationSeconds *int     `json:"expectedDurationSeconds,omitempty"`
+	DurationMismatch        bool     `json:"durationMismatch"`
+	// Validation checks the delivered video failed
+	Warnings []TaskMediaWarning `json:"warnings"`
+	Error    *string            `json:"error,omitempty"`
+	ProbedAt string             `json:"probedAt"`
+}
+
+type TaskReconciliationFixInput struct {
+	Fix TaskReconciliationFix `json:"fix"`
+	// Required for READD_TORRENT, MARK_FAILED and SYNC_SAVE_PATH
+	TaskID *string `json:"taskId,omitempty"`
+	// Required for ADOPT_TORRENT
+	TorrentHash *string `json:"torrentHash,omitempty"`
+}
+
+type TaskReconciliationIssue struct {
+	Kind            TaskReconciliationIssueKind `json:"kind"`
+	TaskID          *string                     `json:"taskId,omitempty"`
+	Code            *string                     `json:"code,omitempty"`
+	TorrentHash     *string                     `json:"torrentHash,omitempty"`
+	TorrentName     *string                     `json:"torrentName,omitempty"`
+	TaskSavePath    *string                     `json:"taskSavePath,omitempty"`
+	TorrentSavePath *string                     `json:"torrentSavePath,omitempty"`
+	Detail          string                      `json:"detail"`
+	ProposedFixes   []TaskReconciliationFix     `json:"proposedFixes"`
+}
+
+type TaskReconciliationReport struct {
+	GeneratedAt  string                     `json:"generatedAt"`
+	TaskCount    int                        `json:"taskCount"`
+	TorrentCount int                        `json:"torrentCount"`
+	Issues       []*TaskReconciliationIssue `json:"issues"`
+}
+
 type TitleMatchClause struct {
 	Pattern     string                `json:"pattern"`
 	PatternMode TitleMatchPatternMode `json:"patternMode"`
@@ -771,6 +1047,34 @@ type TitleMatchRuleInput struct {
 	Clauses []*TitleMatchClauseInput `json:"clauses"`
 }
 
+// At least one field is required. savePath also matches directories below it.
+type TorrentAdoptionFilterInput struct {
+	Category *string  `json:"category,omitempty"`
+	Tag      *string  `json:"tag,omitempty"`
+	SavePath *string  `json:"savePath,omitempty"`
+	Hashes   []string `json:"hashes,omitempty"`
+}
+
+type TorrentAdoptionPayload struct {
+	Summary *TaskBatchSummary        `json:"summary"`
+	Results []*TorrentAdoptionResult `json:"results"`
+}
+
+type TorrentAdoptionResult struct {
+	Hash       string          `json:"hash"`
+	Name       string          `json:"name"`
+	Code       *string         `json:"code,omitempty"`
+	Status     TaskBatchStatus `json:"status"`
+	ReasonCode string          `json:"reasonCode"`
+	Message    *string         `json:"message,omitempty"`
+	Task       *Task           `json:"task,omitempty"`
+}
+
+type TorrentCodeOverrideInput struct {
+	Hash string `json:"hash"`
+	Code string `json:"code"`
+}
+
 type TorrentFileNameMatchClause struct {
 	Pattern     string                 `json:"pattern"`
 	PatternMode TitleMatchPatternMode  `json:"patternMode"`
@@ -791,6 +1095,28 @@ type TorrentFileNameMatchRuleInput struct {
 	Clauses []*TorrentFileNameMatchClauseInput `json:"clauses"`
 }
 
+type TorrentInspectionCacheSettings struct {
+	TTLHours   int `json:"ttlHours"`
+	MaxEntries int `json:"maxEntries"`
+}
+
+type TorrentInspectionCacheSettingsInput struct {
+	TTLHours   int `json:"ttlHours"`
+	MaxEntries int `json:"maxEntries"`
+}
+
+type TorrentInspectionCacheStatus struct {
+	UsedBytes  int64 `json:"usedBytes"`
+	EntryCount int   `json:"entryCount"`
+	// Cache hits since the process started or the cache was last cleared
+	Hits int64 `json:"hits"`
+	// Cache misses since the process started or the cache was last cleared
+	Misses        int64   `json:"misses"`
+	DatabasePath  string  `json:"databasePath"`
+	LastCleanupAt *string `json:"lastCleanupAt,omitempty"`
+	LastError     *string `json:"lastError,omitempty"`
+}
+
 type TorrentSelectionRule struct {
 	Type                 TorrentSelectionRuleType  `json:"type"`
 	Enabled              bool                      `json:"enabled"`
@@ -835,6 +1161,13 @@ type TransferIngestSettingsInput struct {
 	Action string `json:"action"`
 }
 
+type UpcomingRelease struct {
+	Release *SubscriptionRelease `json:"release"`
+	// When the next sourcing attempt runs
+	ScheduledAt    string `json:"scheduledAt"`
+	SourceAttempts int    `json:"sourceAttempts"`
+}
+
 type UpdateAutomationSettingsInput struct {
 	TaskProgressSyncIntervalSeconds int                             `json:"taskProgressSyncIntervalSeconds"`
 	SubscriptionPollIntervalHours   int                             `json:"subscriptionPollIntervalHours"`
@@ -857,6 +1190,11 @@ type UpdateJackettSettingsInput struct {
 	Password string `json:"password"`
 }
 
+type UpdateNotificationSettingsInput struct {
+	Channels  []*NotificationChannelInput  `json:"channels"`
+	Templates []*NotificationTemplateInput `json:"templates"`
+}
+
 type UpdateQBittorrentSettingsInput struct {
 	URL             string  `json:"url"`
 	Username        string  `json:"username"`
@@ -872,9 +1210,140 @@ type UpdateStashSettingsInput struct {
 }
 
 type UpdateSystemSettingsInput struct {
-	TaskDeletePolicy  TaskDeletePolicy                `json:"taskDeletePolicy"`
-	ImageCache        *ImageCacheSettingsInput        `json:"imageCache,omitempty"`
-	StashBoxDataCache *StashBoxDataCacheSettingsInput `json:"stashBoxDataCache,omitempty"`
+	TaskDeletePolicy       TaskDeletePolicy                     `json:"taskDeletePolicy"`
+	ImageCache             *ImageCacheSettingsInput             `json:"imageCache,omitempty"`
+	StashBoxDataCache      *StashBoxDataCacheSettingsInput      `json:"stashBoxDataCache,omitempty"`
+	TorrentInspectionCache *TorrentInspectionCacheSettingsInput `json:"torrentInspectionCache,omitempty"`
+}
+
+type Watchlist struct {
+	ID              string          `json:"id"`
+	Name            string          `json:"name"`
+	Query           string          `json:"query"`
+	Indexers        []string        `json:"indexers"`
+	Categories      []int           `json:"categories"`
+	Exclude         []string        `json:"exclude"`
+	MinSeeders      int             `json:"minSeeders"`
+	Action          WatchlistAction `json:"action"`
+	Enabled         bool            `json:"enabled"`
+	IntervalMinutes int             `json:"intervalMinutes"`
+	CreatedAt       string          `json:"createdAt"`
+	UpdatedAt       string          `json:"updatedAt"`
+	LastRunAt       *string         `json:"lastRunAt,omitempty"`
+	LastSuccessAt   *string         `json:"lastSuccessAt,omitempty"`
+	LastError       *string         `json:"lastError,omitempty"`
+}
+
+type WatchlistInput struct {
+	Name  *string `json:"name,omitempty"`
+	Query string  `json:"query"`
+	// Jackett indexer ids; empty searches every indexer
+	Indexers   []string `json:"indexers,omitempty"`
+	Categories []int    `json:"categories,omitempty"`
+	// Case-insensitive title terms that exclude a result
+	Exclude    []string         `json:"exclude,omitempty"`
+	MinSeeders *int             `json:"minSeeders,omitempty"`
+	Action     *WatchlistAction `json:"action,omitempty"`
+	Enabled    *bool            `json:"enabled,omitempty"`
+	// Minutes between runs; defaults to 60 and is at least 15
+	IntervalMinutes *int `json:"intervalMinutes,omitempty"`
+}
+
+type WatchlistResult struct {
+	Key         string                `json:"key"`
+	Title       string                `json:"title"`
+	Tracker     *string               `json:"tracker,omitempty"`
+	Link        *string               `json:"link,omitempty"`
+	Size        int64                 `json:"size"`
+	Seeders     int                   `json:"seeders"`
+	PublishDate *string               `json:"publishDate,omitempty"`
+	Status      WatchlistResultStatus `json:"status"`
+	TaskID      *string               `json:"taskId,omitempty"`
+	Error       *string               `json:"error,omitempty"`
+	SeenAt      string                `json:"seenAt"`
+}
+
+type WatchlistRun struct {
+	Watchlist    *Watchlist `json:"watchlist"`
+	FetchedCount int        `json:"fetchedCount"`
+	// Results seen for the first time in this run
+	NewResults []*WatchlistResult `json:"newResults"`
+}
+
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	Webhook        string                `json:"webhook"`
+	URL            string                `json:"url"`
+	Event          string                `json:"event"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus *int                  `json:"responseStatus,omitempty"`
+	LastError      *string               `json:"lastError,omitempty"`
+	Payload        string                `json:"payload"`
+	CreatedAt      string                `json:"createdAt"`
+	LastAttemptAt  *string               `json:"lastAttemptAt,omitempty"`
+	NextAttemptAt  *string               `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *string               `json:"deliveredAt,omitempty"`
+	RedeliveryOf   *string               `json:"redeliveryOf,omitempty"`
+}
+
+type BandwidthProfileSource string
+
+const (
+	BandwidthProfileSourceSchedule BandwidthProfileSource = "SCHEDULE"
+	BandwidthProfileSourceDefault  BandwidthProfileSource = "DEFAULT"
+	BandwidthProfileSourceOverride BandwidthProfileSource = "OVERRIDE"
+	BandwidthProfileSourceDisabled BandwidthProfileSource = "DISABLED"
+)
+
+var AllBandwidthProfileSource = []BandwidthProfileSource{
+	BandwidthProfileSourceSchedule,
+	BandwidthProfileSourceDefault,
+	BandwidthProfileSourceOverride,
+	BandwidthProfileSourceDisabled,
+}
+
+func (e BandwidthProfileSource) IsValid() bool {
+	switch e {
+	case BandwidthProfileSourceSchedule, BandwidthProfileSourceDefault, BandwidthProfileSourceOverride, BandwidthProfileSourceDisabled:
+		return true
+	}
+	return false
+}
+
+func (e BandwidthProfileSource) String() string {
+	return string(e)
+}
+
+func (e *BandwidthProfileSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BandwidthProfileSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BandwidthProfileSource", str)
+	}
+	return nil
+}
+
+func (e BandwidthProfileSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BandwidthProfileSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BandwidthProfileSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
 
 type DiscoverSortBy string
@@ -1170,50 +1639,48 @@ func (e LogLevel) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-type PerformerBatchStatus string
+type MissingScenesSubject string
 
 const (
-	PerformerBatchStatusSucceeded PerformerBatchStatus = "SUCCEEDED"
-	PerformerBatchStatusSkipped   PerformerBatchStatus = "SKIPPED"
-	PerformerBatchStatusFailed    PerformerBatchStatus = "FAILED"
+	MissingScenesSubjectPerformer MissingScenesSubject = "PERFORMER"
+	MissingScenesSubjectStudio    MissingScenesSubject = "STUDIO"
 )
 
-var AllPerformerBatchStatus = []PerformerBatchStatus{
-	PerformerBatchStatusSucceeded,
-	PerformerBatchStatusSkipped,
-	PerformerBatchStatusFailed,
+var AllMissingScenesSubject = []MissingScenesSubject{
+	MissingScenesSubjectPerformer,
+	MissingScenesSubjectStudio,
 }
 
-func (e PerformerBatchStatus) IsValid() bool {
+func (e MissingScenesSubject) IsValid() bool {
 	switch e {
-	case PerformerBatchStatusSucceeded, PerformerBatchStatusSkipped, PerformerBatchStatusFailed:
+	case MissingScenesSubjectPerformer, MissingScenesSubjectStudio:
 		return true
 	}
 	return false
 }
 
-func (e PerformerBatchStatus) String() string {
+func (e MissingScenesSubject) String() string {
 	return string(e)
 }
 
-func (e *PerformerBatchStatus) UnmarshalGQL(v any) error {
+func (e *MissingScenesSubject) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PerformerBatchStatus(str)
+	*e = MissingScenesSubject(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PerformerBatchStatus", str)
+		return fmt.Errorf("%s is not a valid MissingScenesSubject", str)
 	}
 	return nil
 }
 
-func (e PerformerBatchStatus) MarshalGQL(w io.Writer) {
+func (e MissingScenesSubject) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PerformerBatchStatus) UnmarshalJSON(b []byte) error {
+func (e *MissingScenesSubject) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1221,56 +1688,58 @@ func (e *PerformerBatchStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PerformerBatchStatus) MarshalJSON() ([]byte, error) {
+func (e MissingScenesSubject) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PerformerSubscriptionEventType string
+type NotificationChannelType string
 
 const (
-	PerformerSubscriptionEventTypeCreated PerformerSubscriptionEventType = "CREATED"
-	PerformerSubscriptionEventTypeUpdated PerformerSubscriptionEventType = "UPDATED"
-	PerformerSubscriptionEventTypeDeleted PerformerSubscriptionEventType = "DELETED"
+	NotificationChannelTypeTelegram NotificationChannelType = "TELEGRAM"
+	NotificationChannelTypeDiscord  NotificationChannelType = "DISCORD"
+	NotificationChannelTypeNtfy     NotificationChannelType = "NTFY"
+	NotificationChannelTypeGotify   NotificationChannelType = "GOTIFY"
 )
 
-var AllPerformerSubscriptionEventType = []PerformerSubscriptionEventType{
-	PerformerSubscriptionEventTypeCreated,
-	PerformerSubscriptionEventTypeUpdated,
-	PerformerSubscriptionEventTypeDeleted,
+var AllNotificationChannelType = []NotificationChannelType{
+	NotificationChannelTypeTelegram,
+	NotificationChannelTypeDiscord,
+	NotificationChannelTypeNtfy,
+	NotificationChannelTypeGotify,
 }
 
-func (e PerformerSubscriptionEventType) IsValid() bool {
+func (e NotificationChannelType) IsValid() bool {
 	switch e {
-	case PerformerSubscriptionEventTypeCreated, PerformerSubscriptionEventTypeUpdated, PerformerSubscriptionEventTypeDeleted:
+	case NotificationChannelTypeTelegram, NotificationChannelTypeDiscord, NotificationChannelTypeNtfy, NotificationChannelTypeGotify:
 		return true
 	}
 	return false
 }
 
-func (e PerformerSubscriptionEventType) String() string {
+func (e NotificationChannelType) String() string {
 	return string(e)
 }
 
-func (e *PerformerSubscriptionEventType) UnmarshalGQL(v any) error {
+func (e *NotificationChannelType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PerformerSubscriptionEventType(str)
+	*e = NotificationChannelType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PerformerSubscriptionEventType", str)
+		return fmt.Errorf("%s is not a valid NotificationChannelType", str)
 	}
 	return nil
 }
 
-func (e PerformerSubscriptionEventType) MarshalGQL(w io.Writer) {
+func (e NotificationChannelType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PerformerSubscriptionEventType) UnmarshalJSON(b []byte) error {
+func (e *NotificationChannelType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1278,56 +1747,62 @@ func (e *PerformerSubscriptionEventType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PerformerSubscriptionEventType) MarshalJSON() ([]byte, error) {
+func (e NotificationChannelType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type QueuePerformerSceneStatus string
+type NotificationEventType string
 
 const (
-	QueuePerformerSceneStatusQueued  QueuePerformerSceneStatus = "QUEUED"
-	QueuePerformerSceneStatusSkipped QueuePerformerSceneStatus = "SKIPPED"
-	QueuePerformerSceneStatusFailed  QueuePerformerSceneStatus = "FAILED"
+	// A subscription task finished downloading
+	NotificationEventTypeReleaseDownloaded NotificationEventType = "RELEASE_DOWNLOADED"
+	NotificationEventTypeTaskBlocked       NotificationEventType = "TASK_BLOCKED"
+	NotificationEventTypeServiceDown       NotificationEventType = "SERVICE_DOWN"
+	NotificationEventTypeServiceRecovered  NotificationEventType = "SERVICE_RECOVERED"
+	// A watchlist found a new matching tracker result
+	NotificationEventTypeWatchlistMatch NotificationEventType = "WATCHLIST_MATCH"
 )
 
-var AllQueuePerformerSceneStatus = []QueuePerformerSceneStatus{
-	QueuePerformerSceneStatusQueued,
-	QueuePerformerSceneStatusSkipped,
-	QueuePerformerSceneStatusFailed,
+var AllNotificationEventType = []NotificationEventType{
+	NotificationEventTypeReleaseDownloaded,
+	NotificationEventTypeTaskBlocked,
+	NotificationEventTypeServiceDown,
+	NotificationEventTypeServiceRecovered,
+	NotificationEventTypeWatchlistMatch,
 }
 
-func (e QueuePerformerSceneStatus) IsValid() bool {
+func (e NotificationEventType) IsValid() bool {
 	switch e {
-	case QueuePerformerSceneStatusQueued, QueuePerformerSceneStatusSkipped, QueuePerformerSceneStatusFailed:
+	case NotificationEventTypeReleaseDownloaded, NotificationEventTypeTaskBlocked, NotificationEventTypeServiceDown, NotificationEventTypeServiceRecovered, NotificationEventTypeWatchlistMatch:
 		return true
 	}
 	return false
 }
 
-func (e QueuePerformerSceneStatus) String() string {
+func (e NotificationEventType) String() string {
 	return string(e)
 }
 
-func (e *QueuePerformerSceneStatus) UnmarshalGQL(v any) error {
+func (e *NotificationEventType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = QueuePerformerSceneStatus(str)
+	*e = NotificationEventType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid QueuePerformerSceneStatus", str)
+		return fmt.Errorf("%s is not a valid NotificationEventType", str)
 	}
 	return nil
 }
 
-func (e QueuePerformerSceneStatus) MarshalGQL(w io.Writer) {
+func (e NotificationEventType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *QueuePerformerSceneStatus) UnmarshalJSON(b []byte) error {
+func (e *NotificationEventType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1335,54 +1810,56 @@ func (e *QueuePerformerSceneStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e QueuePerformerSceneStatus) MarshalJSON() ([]byte, error) {
+func (e NotificationEventType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SceneSource string
+type PerformerBatchStatus string
 
 const (
-	SceneSourceStash    SceneSource = "STASH"
-	SceneSourceStashbox SceneSource = "STASHBOX"
+	PerformerBatchStatusSucceeded PerformerBatchStatus = "SUCCEEDED"
+	PerformerBatchStatusSkipped   PerformerBatchStatus = "SKIPPED"
+	PerformerBatchStatusFailed    PerformerBatchStatus = "FAILED"
 )
 
-var AllSceneSource = []SceneSource{
-	SceneSourceStash,
-	SceneSourceStashbox,
+var AllPerformerBatchStatus = []PerformerBatchStatus{
+	PerformerBatchStatusSucceeded,
+	PerformerBatchStatusSkipped,
+	PerformerBatchStatusFailed,
 }
 
-func (e SceneSource) IsValid() bool {
+func (e PerformerBatchStatus) IsValid() bool {
 	switch e {
-	case SceneSourceStash, SceneSourceStashbox:
+	case PerformerBatchStatusSucceeded, PerformerBatchStatusSkipped, PerformerBatchStatusFailed:
 		return true
 	}
 	return false
 }
 
-func (e SceneSource) String() string {
+func (e PerformerBatchStatus) String() string {
 	return string(e)
 }
 
-func (e *SceneSource) UnmarshalGQL(v any) error {
+func (e *PerformerBatchStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SceneSource(str)
+	*e = PerformerBatchStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SceneSource", str)
+		return fmt.Errorf("%s is not a valid PerformerBatchStatus", str)
 	}
 	return nil
 }
 
-func (e SceneSource) MarshalGQL(w io.Writer) {
+func (e PerformerBatchStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SceneSource) UnmarshalJSON(b []byte) error {
+func (e *PerformerBatchStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1390,56 +1867,56 @@ func (e *SceneSource) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SceneSource) MarshalJSON() ([]byte, error) {
+func (e PerformerBatchStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SceneSourceFilter string
+type PerformerSubscriptionEventType string
 
 const (
-	SceneSourceFilterAll      SceneSourceFilter = "ALL"
-	SceneSourceFilterStash    SceneSourceFilter = "STASH"
-	SceneSourceFilterStashbox SceneSourceFilter = "STASHBOX"
+	PerformerSubscriptionEventTypeCreated PerformerSubscriptionEventType = "CREATED"
+	PerformerSubscriptionEventTypeUpdated PerformerSubscriptionEventType = "UPDATED"
+	PerformerSubscriptionEventTypeDeleted PerformerSubscriptionEventType = "DELETED"
 )
 
-var AllSceneSourceFilter = []SceneSourceFilter{
-	SceneSourceFilterAll,
-	SceneSourceFilterStash,
-	SceneSourceFilterStashbox,
+var AllPerformerSubscriptionEventType = []PerformerSubscriptionEventType{
+	PerformerSubscriptionEventTypeCreated,
+	PerformerSubscriptionEventTypeUpdated,
+	PerformerSubscriptionEventTypeDeleted,
 }
 
-func (e SceneSourceFilter) IsValid() bool {
+func (e PerformerSubscriptionEventType) IsValid() bool {
 	switch e {
-	case SceneSourceFilterAll, SceneSourceFilterStash, SceneSourceFilterStashbox:
+	case PerformerSubscriptionEventTypeCreated, PerformerSubscriptionEventTypeUpdated, PerformerSubscriptionEventTypeDeleted:
 		return true
 	}
 	return false
 }
 
-func (e SceneSourceFilter) String() string {
+func (e PerformerSubscriptionEventType) String() string {
 	return string(e)
 }
 
-func (e *SceneSourceFilter) UnmarshalGQL(v any) error {
+func (e *PerformerSubscriptionEventType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SceneSourceFilter(str)
+	*e = PerformerSubscriptionEventType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SceneSourceFilter", str)
+		return fmt.Errorf("%s is not a valid PerformerSubscriptionEventType", str)
 	}
 	return nil
 }
 
-func (e SceneSourceFilter) MarshalGQL(w io.Writer) {
+func (e PerformerSubscriptionEventType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SceneSourceFilter) UnmarshalJSON(b []byte) error {
+func (e *PerformerSubscriptionEventType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1447,56 +1924,56 @@ func (e *SceneSourceFilter) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SceneSourceFilter) MarshalJSON() ([]byte, error) {
+func (e PerformerSubscriptionEventType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SubscriptionReleaseBehavior string
+type QueuePerformerSceneStatus string
 
 const (
-	SubscriptionReleaseBehaviorDownload SubscriptionReleaseBehavior = "DOWNLOAD"
-	SubscriptionReleaseBehaviorReview   SubscriptionReleaseBehavior = "REVIEW"
-	SubscriptionReleaseBehaviorBlock    SubscriptionReleaseBehavior = "BLOCK"
+	QueuePerformerSceneStatusQueued  QueuePerformerSceneStatus = "QUEUED"
+	QueuePerformerSceneStatusSkipped QueuePerformerSceneStatus = "SKIPPED"
+	QueuePerformerSceneStatusFailed  QueuePerformerSceneStatus = "FAILED"
 )
 
-var AllSubscriptionReleaseBehavior = []SubscriptionReleaseBehavior{
-	SubscriptionReleaseBehaviorDownload,
-	SubscriptionReleaseBehaviorReview,
-	SubscriptionReleaseBehaviorBlock,
+var AllQueuePerformerSceneStatus = []QueuePerformerSceneStatus{
+	QueuePerformerSceneStatusQueued,
+	QueuePerformerSceneStatusSkipped,
+	QueuePerformerSceneStatusFailed,
 }
 
-func (e SubscriptionReleaseBehavior) IsValid() bool {
+func (e QueuePerformerSceneStatus) IsValid() bool {
 	switch e {
-	case SubscriptionReleaseBehaviorDownload, SubscriptionReleaseBehaviorReview, SubscriptionReleaseBehaviorBlock:
+	case QueuePerformerSceneStatusQueued, QueuePerformerSceneStatusSkipped, QueuePerformerSceneStatusFailed:
 		return true
 	}
 	return false
 }
 
-func (e SubscriptionReleaseBehavior) String() string {
+func (e QueuePerformerSceneStatus) String() string {
 	return string(e)
 }
 
-func (e *SubscriptionReleaseBehavior) UnmarshalGQL(v any) error {
+func (e *QueuePerformerSceneStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SubscriptionReleaseBehavior(str)
+	*e = QueuePerformerSceneStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SubscriptionReleaseBehavior", str)
+		return fmt.Errorf("%s is not a valid QueuePerformerSceneStatus", str)
 	}
 	return nil
 }
 
-func (e SubscriptionReleaseBehavior) MarshalGQL(w io.Writer) {
+func (e QueuePerformerSceneStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SubscriptionReleaseBehavior) UnmarshalJSON(b []byte) error {
+func (e *QueuePerformerSceneStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1504,7 +1981,233 @@ func (e *SubscriptionReleaseBehavior) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SubscriptionReleaseBehavior) MarshalJSON() ([]byte, error) {
+func (e QueuePerformerSceneStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReleaseReviewStatus string
+
+const (
+	ReleaseReviewStatusSucceeded ReleaseReviewStatus = "SUCCEEDED"
+	ReleaseReviewStatusSkipped   ReleaseReviewStatus = "SKIPPED"
+	ReleaseReviewStatusFailed    ReleaseReviewStatus = "FAILED"
+)
+
+var AllReleaseReviewStatus = []ReleaseReviewStatus{
+	ReleaseReviewStatusSucceeded,
+	ReleaseReviewStatusSkipped,
+	ReleaseReviewStatusFailed,
+}
+
+func (e ReleaseReviewStatus) IsValid() bool {
+	switch e {
+	case ReleaseReviewStatusSucceeded, ReleaseReviewStatusSkipped, ReleaseReviewStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ReleaseReviewStatus) String() string {
+	return string(e)
+}
+
+func (e *ReleaseReviewStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReleaseReviewStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReleaseReviewStatus", str)
+	}
+	return nil
+}
+
+func (e ReleaseReviewStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReleaseReviewStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReleaseReviewStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SceneSource string
+
+const (
+	SceneSourceStash    SceneSource = "STASH"
+	SceneSourceStashbox SceneSource = "STASHBOX"
+)
+
+var AllSceneSource = []SceneSource{
+	SceneSourceStash,
+	SceneSourceStashbox,
+}
+
+func (e SceneSource) IsValid() bool {
+	switch e {
+	case SceneSourceStash, SceneSourceStashbox:
+		return true
+	}
+	return false
+}
+
+func (e SceneSource) String() string {
+	return string(e)
+}
+
+func (e *SceneSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SceneSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SceneSource", str)
+	}
+	return nil
+}
+
+func (e SceneSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SceneSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SceneSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SceneSourceFilter string
+
+const (
+	SceneSourceFilterAll      SceneSourceFilter = "ALL"
+	SceneSourceFilterStash    SceneSourceFilter = "STASH"
+	SceneSourceFilterStashbox SceneSourceFilter = "STASHBOX"
+)
+
+var AllSceneSourceFilter = []SceneSourceFilter{
+	SceneSourceFilterAll,
+	SceneSourceFilterStash,
+	SceneSourceFilterStashbox,
+}
+
+func (e SceneSourceFilter) IsValid() bool {
+	switch e {
+	case SceneSourceFilterAll, SceneSourceFilterStash, SceneSourceFilterStashbox:
+		return true
+	}
+	return false
+}
+
+func (e SceneSourceFilter) String() string {
+	return string(e)
+}
+
+func (e *SceneSourceFilter) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SceneSourceFilter(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SceneSourceFilter", str)
+	}
+	return nil
+}
+
+func (e SceneSourceFilter) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SceneSourceFilter) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SceneSourceFilter) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SubscriptionReleaseBehavior string
+
+const (
+	SubscriptionReleaseBehaviorDownload SubscriptionReleaseBehavior = "DOWNLOAD"
+	SubscriptionReleaseBehaviorReview   SubscriptionReleaseBehavior = "REVIEW"
+	SubscriptionReleaseBehaviorBlock    SubscriptionReleaseBehavior = "BLOCK"
+)
+
+var AllSubscriptionReleaseBehavior = []SubscriptionReleaseBehavior{
+	SubscriptionReleaseBehaviorDownload,
+	SubscriptionReleaseBehaviorReview,
+	SubscriptionReleaseBehaviorBlock,
+}
+
+func (e SubscriptionReleaseBehavior) IsValid() bool {
+	switch e {
+	case SubscriptionReleaseBehaviorDownload, SubscriptionReleaseBehaviorReview, SubscriptionReleaseBehaviorBlock:
+		return true
+	}
+	return false
+}
+
+func (e SubscriptionReleaseBehavior) String() string {
+	return string(e)
+}
+
+func (e *SubscriptionReleaseBehavior) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SubscriptionReleaseBehavior(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SubscriptionReleaseBehavior", str)
+	}
+	return nil
+}
+
+func (e SubscriptionReleaseBehavior) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SubscriptionReleaseBehavior) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SubscriptionReleaseBehavior) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -1742,23 +2445,205 @@ func (e TaskEventType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type TaskMediaWarning string
+
+const (
+	TaskMediaWarningDurationMismatch TaskMediaWarning = "DURATION_MISMATCH"
+	TaskMediaWarningLowResolution    TaskMediaWarning = "LOW_RESOLUTION"
+	TaskMediaWarningLowBitrate       TaskMediaWarning = "LOW_BITRATE"
+)
+
+var AllTaskMediaWarning = []TaskMediaWarning{
+	TaskMediaWarningDurationMismatch,
+	TaskMediaWarningLowResolution,
+	TaskMediaWarningLowBitrate,
+}
+
+func (e TaskMediaWarning) IsValid() bool {
+	switch e {
+	case TaskMediaWarningDurationMismatch, TaskMediaWarningLowResolution, TaskMediaWarningLowBitrate:
+		return true
+	}
+	return false
+}
+
+func (e TaskMediaWarning) String() string {
+	return string(e)
+}
+
+func (e *TaskMediaWarning) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskMediaWarning(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskMediaWarning", str)
+	}
+	return nil
+}
+
+func (e TaskMediaWarning) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskMediaWarning) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskMediaWarning) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaskReconciliationFix string
+
+const (
+	// Submit the task's stored torrent URL to qBittorrent again
+	TaskReconciliationFixReaddTorrent TaskReconciliationFix = "READD_TORRENT"
+	// Create a task for the orphan torrent
+	TaskReconciliationFixAdoptTorrent TaskReconciliationFix = "ADOPT_TORRENT"
+	// Block the task with TORRENT_MISSING
+	TaskReconciliationFixMarkFailed TaskReconciliationFix = "MARK_FAILED"
+	// Copy qBittorrent's save and content paths onto the task
+	TaskReconciliationFixSyncSavePath TaskReconciliationFix = "SYNC_SAVE_PATH"
+)
+
+var AllTaskReconciliationFix = []TaskReconciliationFix{
+	TaskReconciliationFixReaddTorrent,
+	TaskReconciliationFixAdoptTorrent,
+	TaskReconciliationFixMarkFailed,
+	TaskReconciliationFixSyncSavePath,
+}
+
+func (e TaskReconciliationFix) IsValid() bool {
+	switch e {
+	case TaskReconciliationFixReaddTorrent, TaskReconciliationFixAdoptTorrent, TaskReconciliationFixMarkFailed, TaskReconciliationFixSyncSavePath:
+		return true
+	}
+	return false
+}
+
+func (e TaskReconciliationFix) String() string {
+	return string(e)
+}
+
+func (e *TaskReconciliationFix) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskReconciliationFix(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskReconciliationFix", str)
+	}
+	return nil
+}
+
+func (e TaskReconciliationFix) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskReconciliationFix) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskReconciliationFix) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaskReconciliationIssueKind string
+
+const (
+	// A downloading or pending-ingest task whose torrent is no longer in qBittorrent
+	TaskReconciliationIssueKindTorrentMissing TaskReconciliationIssueKind = "TORRENT_MISSING"
+	// A torrent in Moji's qBittorrent category that no task owns
+	TaskReconciliationIssueKindOrphanTorrent TaskReconciliationIssueKind = "ORPHAN_TORRENT"
+	// A task whose recorded save path differs from the torrent's
+	TaskReconciliationIssueKindSavePathMismatch TaskReconciliationIssueKind = "SAVE_PATH_MISMATCH"
+)
+
+var AllTaskReconciliationIssueKind = []TaskReconciliationIssueKind{
+	TaskReconciliationIssueKindTorrentMissing,
+	TaskReconciliationIssueKindOrphanTorrent,
+	TaskReconciliationIssueKindSavePathMismatch,
+}
+
+func (e TaskReconciliationIssueKind) IsValid() bool {
+	switch e {
+	case TaskReconciliationIssueKindTorrentMissing, TaskReconciliationIssueKindOrphanTorrent, TaskReconciliationIssueKindSavePathMismatch:
+		return true
+	}
+	return false
+}
+
+func (e TaskReconciliationIssueKind) String() string {
+	return string(e)
+}
+
+func (e *TaskReconciliationIssueKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskReconciliationIssueKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskReconciliationIssueKind", str)
+	}
+	return nil
+}
+
+func (e TaskReconciliationIssueKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskReconciliationIssueKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskReconciliationIssueKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type TaskSource string
 
 const (
 	TaskSourceManual       TaskSource = "MANUAL"
 	TaskSourceSearch       TaskSource = "SEARCH"
 	TaskSourceSubscription TaskSource = "SUBSCRIPTION"
+	TaskSourceWatchlist    TaskSource = "WATCHLIST"
 )
 
 var AllTaskSource = []TaskSource{
 	TaskSourceManual,
 	TaskSourceSearch,
 	TaskSourceSubscription,
+	TaskSourceWatchlist,
 }
 
 func (e TaskSource) IsValid() bool {
 	switch e {
-	case TaskSourceManual, TaskSourceSearch, TaskSourceSubscription:
+	case TaskSourceManual, TaskSourceSearch, TaskSourceSubscription, TaskSourceWatchlist:
 		return true
 	}
 	return false
@@ -1806,6 +2691,7 @@ const (
 	TaskStageDownloading   TaskStage = "DOWNLOADING"
 	TaskStagePendingIngest TaskStage = "PENDING_INGEST"
 	TaskStageTransferring  TaskStage = "TRANSFERRING"
+	TaskStageSubtitling    TaskStage = "SUBTITLING"
 	TaskStageScanning      TaskStage = "SCANNING"
 	TaskStageCompleted     TaskStage = "COMPLETED"
 )
@@ -1815,13 +2701,14 @@ var AllTaskStage = []TaskStage{
 	TaskStageDownloading,
 	TaskStagePendingIngest,
 	TaskStageTransferring,
+	TaskStageSubtitling,
 	TaskStageScanning,
 	TaskStageCompleted,
 }
 
 func (e TaskStage) IsValid() bool {
 	switch e {
-	case TaskStageSourcing, TaskStageDownloading, TaskStagePendingIngest, TaskStageTransferring, TaskStageScanning, TaskStageCompleted:
+	case TaskStageSourcing, TaskStageDownloading, TaskStagePendingIngest, TaskStageTransferring, TaskStageSubtitling, TaskStageScanning, TaskStageCompleted:
 		return true
 	}
 	return false
@@ -2031,6 +2918,61 @@ func (e TitleMatchPatternMode) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type TorrentCodeSource string
+
+const (
+	TorrentCodeSourceName  TorrentCodeSource = "NAME"
+	TorrentCodeSourceFiles TorrentCodeSource = "FILES"
+)
+
+var AllTorrentCodeSource = []TorrentCodeSource{
+	TorrentCodeSourceName,
+	TorrentCodeSourceFiles,
+}
+
+func (e TorrentCodeSource) IsValid() bool {
+	switch e {
+	case TorrentCodeSourceName, TorrentCodeSourceFiles:
+		return true
+	}
+	return false
+}
+
+func (e TorrentCodeSource) String() string {
+	return string(e)
+}
+
+func (e *TorrentCodeSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TorrentCodeSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TorrentCodeSource", str)
+	}
+	return nil
+}
+
+func (e TorrentCodeSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TorrentCodeSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TorrentCodeSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type TorrentFileMatchEffect string
 
 const (
@@ -2209,3 +3151,181 @@ func (e TorrentSelectionRuleType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type WatchlistAction string
+
+const (
+	// Send a WATCHLIST_MATCH notification for each new match
+	WatchlistActionNotify WatchlistAction = "NOTIFY"
+	// Create a task for each new match
+	WatchlistActionDownload WatchlistAction = "DOWNLOAD"
+)
+
+var AllWatchlistAction = []WatchlistAction{
+	WatchlistActionNotify,
+	WatchlistActionDownload,
+}
+
+func (e WatchlistAction) IsValid() bool {
+	switch e {
+	case WatchlistActionNotify, WatchlistActionDownload:
+		return true
+	}
+	return false
+}
+
+func (e WatchlistAction) String() string {
+	return string(e)
+}
+
+func (e *WatchlistAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WatchlistAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WatchlistAction", str)
+	}
+	return nil
+}
+
+func (e WatchlistAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WatchlistAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WatchlistAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WatchlistResultStatus string
+
+const (
+	// Present when the watchlist first ran; never acted on
+	WatchlistResultStatusBaseline  WatchlistResultStatus = "BASELINE"
+	WatchlistResultStatusExcluded  WatchlistResultStatus = "EXCLUDED"
+	WatchlistResultStatusNotified  WatchlistResultStatus = "NOTIFIED"
+	WatchlistResultStatusQueued    WatchlistResultStatus = "QUEUED"
+	WatchlistResultStatusDuplicate WatchlistResultStatus = "DUPLICATE"
+	WatchlistResultStatusFailed    WatchlistResultStatus = "FAILED"
+)
+
+var AllWatchlistResultStatus = []WatchlistResultStatus{
+	WatchlistResultStatusBaseline,
+	WatchlistResultStatusExcluded,
+	WatchlistResultStatusNotified,
+	WatchlistResultStatusQueued,
+	WatchlistResultStatusDuplicate,
+	WatchlistResultStatusFailed,
+}
+
+func (e WatchlistResultStatus) IsValid() bool {
+	switch e {
+	case WatchlistResultStatusBaseline, WatchlistResultStatusExcluded, WatchlistResultStatusNotified, WatchlistResultStatusQueued, WatchlistResultStatusDuplicate, WatchlistResultStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WatchlistResultStatus) String() string {
+	return string(e)
+}
+
+func (e *WatchlistResultStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WatchlistResultStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WatchlistResultStatus", str)
+	}
+	return nil
+}
+
+func (e WatchlistResultStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WatchlistResultStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WatchlistResultStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusDelivered,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -62,6 +62,12 @@ func (s *workspacePerformerService) ListPerformerScenes(context.Context, string,
 func (s *workspacePerformerService) RefreshPerformerScenes(context.Context, string, performerdomain.SceneQuery) (performerdomain.ScenePage, error) {
 	return performerdomain.ScenePage{}, nil
 }
+func (s *workspacePerformerService) MissingScenesReport(context.Context, performerdomain.MissingScenesQuery) (performerdomain.MissingScenesReport, error) {
+	return performerdomain.MissingScenesReport{}, nil
+}
+func (s *workspacePerformerService) QueueMissingScenes(context.Context, performerdomain.MissingScenesQuery, []performerdomain.QueueSceneSelection) (performerdomain.QueueScenesResult, error) {
+	return performerdomain.QueueScenesResult{}, nil
+}
 
 type workspaceSubscriptionService struct {
 	batch         subscription.PerformerBatchPayload
@@ -104,3 +110,6 @@ func (s *workspaceSubscriptionService) UnsubscribePerformers(context.Context, []
 func (s *workspaceSubscriptionService) RefreshSubscribedPerformers(context.Context, []string) (subscription.PerformerBatchPayload, error) {
 	return s.batch, nil
 }
+func (s *workspaceSubscriptionService) SetPerformerReleasePolicies(context.Context, []string, *subscription.ReleasePolicyConfig) (subscription.PerformerBatchPayload, error) {
+	return s.batch, nil
+}
@@ -0,0 +1,65 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// ApproveQueuedReleases is the resolver for the approveQueuedReleases field.
+func (r *mutationResolver) ApproveQueuedReleases(ctx context.Context, keys []string) (*model.ReleaseReviewPayload, error) {
+	if r.ReleaseReview == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	payload, err := r.ReleaseReview.ApproveQueuedReleases(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return releaseReviewPayloadToModel(payload), nil
+}
+
+// RejectQueuedReleases is the resolver for the rejectQueuedReleases field.
+func (r *mutationResolver) RejectQueuedReleases(ctx context.Context, keys []string) (*model.ReleaseReviewPayload, error) {
+	if r.ReleaseReview == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	payload, err := r.ReleaseReview.RejectQueuedReleases(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return releaseReviewPayloadToModel(payload), nil
+}
+
+// SnoozeQueuedReleases is the resolver for the snoozeQueuedReleases field.
+func (r *mutationResolver) SnoozeQueuedReleases(ctx context.Context, keys []string, hours int) (*model.ReleaseReviewPayload, error) {
+	if r.ReleaseReview == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	payload, err := r.ReleaseReview.SnoozeQueuedReleases(ctx, keys, time.Duration(hours)*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return releaseReviewPayloadToModel(payload), nil
+}
+
+// ReleaseReviewQueue is the resolver for the releaseReviewQueue field.
+func (r *queryResolver) ReleaseReviewQueue(ctx context.Context, input *model.ReleaseReviewQueueInput) (*model.ReleaseReviewConnection, error) {
+	if r.ReleaseReview == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	page, err := r.ReleaseReview.ListQueuedReleases(ctx, releaseReviewFilterFromModel(input))
+	if err != nil {
+		return nil, err
+	}
+	return releaseReviewPageToModel(page), nil
+}
@@ -0,0 +1,65 @@
+package graphqlapi
+
+import (
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+	"github.com/leothevan2444/moji/internal/subscription"
+)
+
+func releaseReviewFilterFromModel(input *model.ReleaseReviewQueueInput) subscription.ReleaseReviewFilter {
+	if input == nil {
+		return subscription.ReleaseReviewFilter{Page: 1, PageSize: normalizePageSize(nil)}
+	}
+	filter := subscription.ReleaseReviewFilter{
+		Reasons:  input.Reasons,
+		DateFrom: derefString(input.DateFrom),
+		DateTo:   derefString(input.DateTo),
+		Page:     normalizePage(input.Page),
+		PageSize: normalizePageSize(input.PageSize),
+	}
+	for _, classification := range input.Classifications {
+		filter.Classifications = append(filter.Classifications, subscription.ReleaseClassification(classification))
+	}
+	return filter
+}
+
+func releaseReviewPageToModel(page subscription.ReleaseReviewPage) *model.ReleaseReviewConnection {
+	items := make([]*model.QueuedRelease, 0, len(page.Items))
+	for _, item := range page.Items {
+		studios := make([]*model.QueuedReleaseStudio, 0, len(item.Studios))
+		for _, studio := range item.Studios {
+			studios = append(studios, &model.QueuedReleaseStudio{Endpoint: studio.Endpoint, StudioID: studio.StudioID})
+		}
+		items = append(items, &model.QueuedRelease{
+			Release:        subscriptionReleaseToModel(item.Release),
+			Classification: string(item.Release.Classification),
+			DecisionReason: item.Release.DecisionReason,
+			PerformerIds:   append([]string{}, item.PerformerIDs...),
+			Studios:        studios,
+		})
+	}
+	totalPages := 0
+	if page.PageSize > 0 {
+		totalPages = (page.TotalCount + page.PageSize - 1) / page.PageSize
+	}
+	return &model.ReleaseReviewConnection{
+		Items:       items,
+		Page:        page.Page,
+		PageSize:    page.PageSize,
+		TotalCount:  page.TotalCount,
+		TotalPages:  totalPages,
+		HasPrevPage: page.Page > 1,
+		HasNextPage: page.Page < totalPages,
+	}
+}
+
+func releaseReviewPayloadToModel(payload subscription.ReleaseReviewPayload) *model.ReleaseReviewPayload {
+	results := make([]*model.ReleaseReviewResult, 0, len(payload.Results))
+	for _, item := range payload.Results {
+		var task *model.Task
+		if item.Task != nil {
+			task = taskToModel(item.Task)
+		}
+		results = append(results, &model.ReleaseReviewResult{Key: item.Key, Status: model.ReleaseReviewStatus(item.Status), ReasonCode: item.ReasonCode, Task: task})
+	}
+	return &model.ReleaseReviewPayload{Summary: &model.ReleaseReviewSummary{RequestedCount: payload.Summary.RequestedCount, SucceededCount: payload.Summary.SucceededCount, SkippedCount: payload.Summary.SkippedCount, FailedCount: payload.Summary.FailedCount}, Results: results}
+}
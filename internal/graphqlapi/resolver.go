@@ -6,9 +6,13 @@ package graphqlapi
 
 import (
 	"context"
+	"time"
 
+	"github.com/leothevan2444/moji/internal/bandwidth"
 	"github.com/leothevan2444/moji/internal/discovery"
 	"github.com/leothevan2444/moji/internal/imagecache"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
+	"github.com/leothevan2444/moji/internal/libraryindex"
 	"github.com/leothevan2444/moji/internal/logging"
 	"github.com/leothevan2444/moji/internal/metadata"
 	"github.com/leothevan2444/moji/internal/performer"
@@ -19,6 +23,8 @@ import (
 	"github.com/leothevan2444/moji/internal/taskflow"
 	"github.com/leothevan2444/moji/internal/taskruntime"
 	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/internal/watchlist"
+	"github.com/leothevan2444/moji/internal/webhook"
 	"github.com/leothevan2444/moji/pkg/qbittorrent"
 )
 
@@ -30,8 +36,10 @@ type TorrentClient interface {
 
 type StashService interface {
 	MetadataScan(ctx context.Context, req stashsync.ScanRequest) (string, error)
+	MetadataIdentify(ctx context.Context, req stashsync.IdentifyRequest) (string, error)
 	FindJob(ctx context.Context, id string) (*stashsync.Job, error)
 	CurrentConfig() stashsync.IntegrationConfig
+	WatchJobs(ctx context.Context, ready func(), handle func(stashsync.Job)) error
 }
 
 type TaskRuntimeService interface {
@@ -46,9 +54,14 @@ type TaskRuntimeService interface {
 	SyncProgress(ctx context.Context) ([]*taskruntime.Task, error)
 	TriggerTaskStashScan(ctx context.Context, id string, scanner taskruntime.StashScanner) (*taskruntime.Task, error)
 	TriggerStashScans(ctx context.Context, scanner taskruntime.StashScanner) ([]*taskruntime.Task, error)
+	WatchStashJobs(ctx context.Context, watcher taskruntime.StashJobWatcher)
 	RetryTasks(ctx context.Context, ids []string, scanner taskruntime.StashScanner) (taskruntime.TaskBatchPayload, error)
 	ProcessTaskIngest(ctx context.Context, ids []string, scanner taskruntime.StashScanner) (taskruntime.TaskBatchPayload, error)
 	DeleteTasks(ctx context.Context, ids []string) (taskruntime.TaskBatchPayload, error)
+	Reconcile(ctx context.Context) (taskruntime.ReconcileReport, error)
+	ApplyReconcileFix(ctx context.Context, req taskruntime.ReconcileFixRequest) (*taskruntime.Task, error)
+	PreviewTorrentAdoption(ctx context.Context, filter taskruntime.TorrentAdoptionFilter) ([]taskruntime.TorrentAdoptionCandidate, error)
+	AdoptTorrents(ctx context.Context, req taskruntime.TorrentAdoptionRequest) (taskruntime.TorrentAdoptionPayload, error)
 }
 
 // TaskFlowService is the only GraphQL seam allowed to create new tasks. Querying
@@ -68,6 +81,8 @@ type SettingsEditor interface {
 	UpdateQBittorrentSettings(input UpdateQBittorrentSettingsInput) (*SettingsSnapshot, error)
 	UpdateAutomationSettings(input UpdateAutomationSettingsInput) (*SettingsSnapshot, error)
 	UpdateSystemSettings(input UpdateSystemSettingsInput) (*SettingsSnapshot, error)
+	UpdateNotificationSettings(input NotificationSettingsSnapshot) (*SettingsSnapshot, error)
+	TestNotificationChannel(ctx context.Context, channel NotificationChannelSnapshot) error
 }
 
 type ImageCacheService interface {
@@ -82,6 +97,37 @@ type StashBoxDataCacheService interface {
 	Cleanup(context.Context) error
 }
 
+type TorrentInspectionCacheService interface {
+	Status(context.Context) (inspectioncache.Status, error)
+	Clear(context.Context) (inspectioncache.Status, error)
+	Cleanup(context.Context) error
+}
+
+type LibraryIndexService interface {
+	Status(context.Context) (libraryindex.Status, error)
+	Rebuild(context.Context) (libraryindex.Status, error)
+}
+
+type WebhookService interface {
+	Deliveries(ctx context.Context, filter webhook.DeliveryFilter) ([]webhook.Delivery, error)
+	Redeliver(ctx context.Context, id string) (webhook.Delivery, error)
+}
+
+type WatchlistService interface {
+	List(ctx context.Context) ([]watchlist.Watchlist, error)
+	Create(ctx context.Context, input watchlist.Input) (watchlist.Watchlist, error)
+	Update(ctx context.Context, id string, input watchlist.Input) (watchlist.Watchlist, error)
+	Delete(ctx context.Context, id string) error
+	Results(ctx context.Context, id string, limit int) ([]watchlist.Result, error)
+	Run(ctx context.Context, id string) (watchlist.RunSummary, error)
+}
+
+type BandwidthService interface {
+	Status() bandwidth.Status
+	Override(ctx context.Context, profile string, duration time.Duration) (bandwidth.Status, error)
+	ClearOverride(ctx context.Context) bandwidth.Status
+}
+
 type UpdateStashSettingsInput struct {
 	URL    string
 	APIKey string
@@ -118,13 +164,42 @@ type UpdateAutomationSettingsInput struct {
 }
 
 type UpdateSystemSettingsInput struct {
-	TaskDeletePolicy  string
-	ImageCache        ImageCacheSettingsSnapshot
-	StashBoxDataCache StashBoxDataCacheSettingsSnapshot
+	TaskDeletePolicy       string
+	ImageCache             ImageCacheSettingsSnapshot
+	StashBoxDataCache      StashBoxDataCacheSettingsSnapshot
+	TorrentInspectionCache TorrentInspectionCacheSettingsSnapshot
+}
+
+type NotificationSettingsSnapshot struct {
+	Channels  []NotificationChannelSnapshot
+	Templates []NotificationTemplateSnapshot
+}
+
+type NotificationChannelSnapshot struct {
+	Name     string
+	Type     string
+	Enabled  bool
+	Events   []string
+	URL      string
+	Token    string
+	ChatID   string
+	Topic    string
+	Priority int
+}
+
+type NotificationTemplateSnapshot struct {
+	Event string
+	Title string
+	Body  string
 }
 
 type StashBoxDataCacheSettingsSnapshot struct{ TTLHours int }
 
+type TorrentInspectionCacheSettingsSnapshot struct {
+	TTLHours   int
+	MaxEntries int
+}
+
 type ImageCacheSettingsSnapshot struct {
 	Enabled       bool
 	MaxSizeMB     int
@@ -132,12 +207,13 @@ type ImageCacheSettingsSnapshot struct {
 }
 
 type SettingsSnapshot struct {
-	Stash       StashSettingsSnapshot
-	Ingest      IngestSettingsSnapshot
-	Jackett     JackettSettingsSnapshot
-	QBittorrent QBittorrentSettingsSnapshot
-	Automation  AutomationSettingsSnapshot
-	System      SystemSettingsSnapshot
+	Stash         StashSettingsSnapshot
+	Ingest        IngestSettingsSnapshot
+	Jackett       JackettSettingsSnapshot
+	QBittorrent   QBittorrentSettingsSnapshot
+	Automation    AutomationSettingsSnapshot
+	System        SystemSettingsSnapshot
+	Notifications NotificationSettingsSnapshot
 }
 
 type StashSettingsSnapshot struct {
@@ -252,9 +328,10 @@ type SubscriptionReleasePolicySnapshot struct {
 }
 
 type SystemSettingsSnapshot struct {
-	TaskDeletePolicy  string
-	ImageCache        ImageCacheSettingsSnapshot
-	StashBoxDataCache StashBoxDataCacheSettingsSnapshot
+	TaskDeletePolicy       string
+	ImageCache             ImageCacheSettingsSnapshot
+	StashBoxDataCache      StashBoxDataCacheSettingsSnapshot
+	TorrentInspectionCache TorrentInspectionCacheSettingsSnapshot
 }
 
 type StashLibrarySnapshot struct {
@@ -320,6 +397,8 @@ type PerformerService interface {
 	GetPerformerDetail(ctx context.Context, performerID string) (performer.Detail, error)
 	ListPerformerScenes(ctx context.Context, performerID string, query performer.SceneQuery) (performer.ScenePage, error)
 	RefreshPerformerScenes(ctx context.Context, performerID string, query performer.SceneQuery) (performer.ScenePage, error)
+	MissingScenesReport(ctx context.Context, query performer.MissingScenesQuery) (performer.MissingScenesReport, error)
+	QueueMissingScenes(ctx context.Context, query performer.MissingScenesQuery, selections []performer.QueueSceneSelection) (performer.QueueScenesResult, error)
 }
 
 type DiscoveryService interface {
@@ -337,6 +416,22 @@ type SubscriptionService interface {
 	SubscribePerformers(ctx context.Context, ids []string) (subscription.PerformerBatchPayload, error)
 	UnsubscribePerformers(ctx context.Context, ids []string) (subscription.PerformerBatchPayload, error)
 	RefreshSubscribedPerformers(ctx context.Context, ids []string) (subscription.PerformerBatchPayload, error)
+	SetPerformerReleasePolicies(ctx context.Context, ids []string, policy *subscription.ReleasePolicyConfig) (subscription.PerformerBatchPayload, error)
+}
+
+type StudioSubscriptionService interface {
+	ListSubscribedStudios(ctx context.Context) ([]subscription.SubscribedStudio, error)
+	SubscribeStudio(ctx context.Context, endpoint, studioID string) (subscription.SubscribedStudio, error)
+	UnsubscribeStudio(ctx context.Context, endpoint, studioID string) error
+	RefreshSubscribedStudio(ctx context.Context, endpoint, studioID string) (subscription.SubscribedStudio, error)
+	RefreshStudios(ctx context.Context) ([]subscription.SubscribedStudio, error)
+}
+
+type ReleaseReviewService interface {
+	ListQueuedReleases(ctx context.Context, filter subscription.ReleaseReviewFilter) (subscription.ReleaseReviewPage, error)
+	ApproveQueuedReleases(ctx context.Context, keys []string) (subscription.ReleaseReviewPayload, error)
+	RejectQueuedReleases(ctx context.Context, keys []string) (subscription.ReleaseReviewPayload, error)
+	SnoozeQueuedReleases(ctx context.Context, keys []string, duration time.Duration) (subscription.ReleaseReviewPayload, error)
 }
 
 type StashBoxService interface {
@@ -357,6 +452,8 @@ type Resolver struct {
 	Performer                        PerformerService
 	Discovery                        DiscoveryService
 	PerformerSubscription            SubscriptionService
+	StudioSubscription               StudioSubscriptionService
+	ReleaseReview                    ReleaseReviewService
 	TaskEventSource                  taskruntime.TaskEventSource
 	ServiceStatusEventSource         stats.ServiceStatusEventSource
 	PerformerSubscriptionEventSource subscription.PerformerSubscriptionEventSource
@@ -369,6 +466,11 @@ type Resolver struct {
 	Stats                            StatsProvider
 	ImageCache                       ImageCacheService
 	StashBoxDataCache                StashBoxDataCacheService
+	TorrentInspectionCache           TorrentInspectionCacheService
+	LibraryIndex                     LibraryIndexService
+	Webhooks                         WebhookService
+	Watchlist                        WatchlistService
+	Bandwidth                        BandwidthService
 	AppVersion                       string
 }
 
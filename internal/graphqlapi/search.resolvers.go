@@ -9,9 +9,11 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/graphqlapi/model"
 	"github.com/leothevan2444/moji/internal/taskruntime"
 	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/pkg/jackett"
 )
 
 // QueueDiscoveredScene is the resolver for the queueDiscoveredScene field.
@@ -159,9 +161,15 @@ func (r *queryResolver) BlockedTaskTorrentCandidates(ctx context.Context, id str
 	if limit != nil {
 		options = append(options, tracker.WithLimit(*limit))
 	}
-	results, err := r.Tracker.Search(task.Code, options...)
-	if err != nil {
-		return nil, err
+	var results []jackett.SearchResult
+	for _, query := range codeparser.SearchQueries(task.Code) {
+		results, err = r.Tracker.Search(query, options...)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			break
+		}
 	}
 	out := make([]*model.JackettSearchResult, 0, len(results))
 	for _, result := range results {
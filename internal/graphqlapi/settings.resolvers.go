@@ -135,9 +135,11 @@ func (r *mutationResolver) UpdateSystemSettings(ctx context.Context, input model
 
 	cacheSettings := ImageCacheSettingsSnapshot{Enabled: true, MaxSizeMB: 1024, RetentionDays: 30}
 	stashBoxCacheSettings := StashBoxDataCacheSettingsSnapshot{TTLHours: 24}
+	inspectionCacheSettings := TorrentInspectionCacheSettingsSnapshot{TTLHours: 168, MaxEntries: 20000}
 	if current := r.SettingsEditor.Snapshot(); current != nil {
 		cacheSettings = current.System.ImageCache
 		stashBoxCacheSettings = current.System.StashBoxDataCache
+		inspectionCacheSettings = current.System.TorrentInspectionCache
 	}
 	if input.ImageCache != nil {
 		if input.ImageCache.MaxSizeMb < 64 || input.ImageCache.MaxSizeMb > 20480 {
@@ -154,7 +156,16 @@ func (r *mutationResolver) UpdateSystemSettings(ctx context.Context, input model
 		}
 		stashBoxCacheSettings = StashBoxDataCacheSettingsSnapshot{TTLHours: input.StashBoxDataCache.TTLHours}
 	}
-	snapshot, err := r.SettingsEditor.UpdateSystemSettings(UpdateSystemSettingsInput{TaskDeletePolicy: string(input.TaskDeletePolicy), ImageCache: cacheSettings, StashBoxDataCache: stashBoxCacheSettings})
+	if input.TorrentInspectionCache != nil {
+		if input.TorrentInspectionCache.TTLHours < 1 || input.TorrentInspectionCache.TTLHours > 720 {
+			return nil, errors.New("torrent inspection cache ttlHours must be between 1 and 720")
+		}
+		if input.TorrentInspectionCache.MaxEntries < 100 || input.TorrentInspectionCache.MaxEntries > 200000 {
+			return nil, errors.New("torrent inspection cache maxEntries must be between 100 and 200000")
+		}
+		inspectionCacheSettings = TorrentInspectionCacheSettingsSnapshot{TTLHours: input.TorrentInspectionCache.TTLHours, MaxEntries: input.TorrentInspectionCache.MaxEntries}
+	}
+	snapshot, err := r.SettingsEditor.UpdateSystemSettings(UpdateSystemSettingsInput{TaskDeletePolicy: string(input.TaskDeletePolicy), ImageCache: cacheSettings, StashBoxDataCache: stashBoxCacheSettings, TorrentInspectionCache: inspectionCacheSettings})
 	if err != nil {
 		return nil, err
 	}
@@ -164,10 +175,55 @@ func (r *mutationResolver) UpdateSystemSettings(ctx context.Context, input model
 	if r.StashBoxDataCache != nil {
 		_ = r.StashBoxDataCache.Cleanup(ctx)
 	}
+	if r.TorrentInspectionCache != nil {
+		_ = r.TorrentInspectionCache.Cleanup(ctx)
+	}
+
+	return settingsSnapshotToModel(snapshot, r.AppVersion), nil
+}
+
+// UpdateNotificationSettings is the resolver for the updateNotificationSettings field.
+func (r *mutationResolver) UpdateNotificationSettings(ctx context.Context, input model.UpdateNotificationSettingsInput) (*model.Settings, error) {
+	_ = ctx
+	if r.SettingsEditor == nil {
+		return nil, errors.New("settings editor is not configured")
+	}
+
+	update := NotificationSettingsSnapshot{
+		Channels:  make([]NotificationChannelSnapshot, 0, len(input.Channels)),
+		Templates: make([]NotificationTemplateSnapshot, 0, len(input.Templates)),
+	}
+	for _, channel := range input.Channels {
+		if channel != nil {
+			update.Channels = append(update.Channels, notificationChannelFromModel(*channel))
+		}
+	}
+	for _, item := range input.Templates {
+		if item != nil {
+			update.Templates = append(update.Templates, NotificationTemplateSnapshot{Event: string(item.Event), Title: item.Title, Body: item.Body})
+		}
+	}
+	snapshot, err := r.SettingsEditor.UpdateNotificationSettings(update)
+	if err != nil {
+		return nil, err
+	}
 
 	return settingsSnapshotToModel(snapshot, r.AppVersion), nil
 }
 
+// TestNotificationChannel is the resolver for the testNotificationChannel field.
+func (r *mutationResolver) TestNotificationChannel(ctx context.Context, input model.NotificationChannelInput) (*model.NotificationTestResult, error) {
+	if r.SettingsEditor == nil {
+		return nil, errors.New("settings editor is not configured")
+	}
+
+	if err := r.SettingsEditor.TestNotificationChannel(ctx, notificationChannelFromModel(input)); err != nil {
+		message := err.Error()
+		return &model.NotificationTestResult{Ok: false, Error: &message}, nil
+	}
+	return &model.NotificationTestResult{Ok: true}, nil
+}
+
 // RefreshStashBoxes is the resolver for the refreshStashBoxes field.
 func (r *mutationResolver) RefreshStashBoxes(ctx context.Context) (*model.Settings, error) {
 	if r.StashBox == nil {
@@ -206,6 +262,30 @@ func (r *mutationResolver) ClearStashBoxDataCache(ctx context.Context) (*model.S
 	return stashBoxDataCacheStatusToModel(status), nil
 }
 
+// ClearTorrentInspectionCache is the resolver for the clearTorrentInspectionCache field.
+func (r *mutationResolver) ClearTorrentInspectionCache(ctx context.Context) (*model.TorrentInspectionCacheStatus, error) {
+	if r.TorrentInspectionCache == nil {
+		return nil, errors.New("torrent inspection cache is not configured")
+	}
+	status, err := r.TorrentInspectionCache.Clear(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return torrentInspectionCacheStatusToModel(status), nil
+}
+
+// RebuildLibraryIndex is the resolver for the rebuildLibraryIndex field.
+func (r *mutationResolver) RebuildLibraryIndex(ctx context.Context) (*model.LibraryIndexStatus, error) {
+	if r.LibraryIndex == nil {
+		return nil, errors.New("library index is not configured")
+	}
+	status, err := r.LibraryIndex.Rebuild(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return libraryIndexStatusToModel(status), nil
+}
+
 // Settings is the resolver for the settings field.
 func (r *queryResolver) Settings(ctx context.Context) (*model.Settings, error) {
 	if r.SettingsEditor != nil {
@@ -249,5 +329,23 @@ func (r *queryResolver) SettingsStatus(ctx context.Context) (*model.SettingsStat
 	if out.StashBoxDataCache == nil {
 		out.StashBoxDataCache = &model.StashBoxDataCacheStatus{}
 	}
+	if r.TorrentInspectionCache != nil {
+		status, err := r.TorrentInspectionCache.Status(ctx)
+		if err == nil {
+			out.TorrentInspectionCache = torrentInspectionCacheStatusToModel(status)
+		}
+	}
+	if out.TorrentInspectionCache == nil {
+		out.TorrentInspectionCache = &model.TorrentInspectionCacheStatus{}
+	}
+	if r.LibraryIndex != nil {
+		status, err := r.LibraryIndex.Status(ctx)
+		if err == nil {
+			out.LibraryIndex = libraryIndexStatusToModel(status)
+		}
+	}
+	if out.LibraryIndex == nil {
+		out.LibraryIndex = &model.LibraryIndexStatus{}
+	}
 	return out, nil
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/leothevan2444/moji/internal/graphqlapi/model"
 	"github.com/leothevan2444/moji/internal/imagecache"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
+	"github.com/leothevan2444/moji/internal/libraryindex"
 	"github.com/leothevan2444/moji/internal/stashboxcache"
 	"github.com/leothevan2444/moji/internal/stats"
 )
@@ -66,10 +68,11 @@ func settingsSnapshotToModel(snapshot *SettingsSnapshot, appVersion string) *mod
 				Library:   &model.LibraryIngestSettings{},
 				Transfer:  &model.TransferIngestSettings{},
 			},
-			Jackett:     &model.JackettSettings{},
-			Qbittorrent: &model.QBittorrentSettings{},
-			Automation:  &model.AutomationSettings{},
-			System:      &model.SystemSettings{ImageCache: &model.ImageCacheSettings{}, StashBoxDataCache: &model.StashBoxDataCacheSettings{TTLHours: 24}},
+			Jackett:       &model.JackettSettings{},
+			Qbittorrent:   &model.QBittorrentSettings{},
+			Automation:    &model.AutomationSettings{},
+			System:        &model.SystemSettings{ImageCache: &model.ImageCacheSettings{}, StashBoxDataCache: &model.StashBoxDataCacheSettings{TTLHours: 24}, TorrentInspectionCache: &model.TorrentInspectionCacheSettings{TTLHours: 168, MaxEntries: 20000}},
+			Notifications: &model.NotificationSettings{Channels: []*model.NotificationChannel{}, Templates: []*model.NotificationTemplate{}},
 		}
 	}
 
@@ -124,8 +127,69 @@ func settingsSnapshotToModel(snapshot *SettingsSnapshot, appVersion string) *mod
 			TaskDeletePolicy:  model.TaskDeletePolicy(snapshot.System.TaskDeletePolicy),
 			ImageCache:        &model.ImageCacheSettings{Enabled: snapshot.System.ImageCache.Enabled, MaxSizeMb: snapshot.System.ImageCache.MaxSizeMB, RetentionDays: snapshot.System.ImageCache.RetentionDays},
 			StashBoxDataCache: &model.StashBoxDataCacheSettings{TTLHours: snapshot.System.StashBoxDataCache.TTLHours},
+			TorrentInspectionCache: &model.TorrentInspectionCacheSettings{
+				TTLHours:   snapshot.System.TorrentInspectionCache.TTLHours,
+				MaxEntries: snapshot.System.TorrentInspectionCache.MaxEntries,
+			},
 		},
+		Notifications: notificationSettingsToModel(snapshot.Notifications),
+	}
+}
+
+func notificationSettingsToModel(snapshot NotificationSettingsSnapshot) *model.NotificationSettings {
+	out := &model.NotificationSettings{
+		Channels:  make([]*model.NotificationChannel, 0, len(snapshot.Channels)),
+		Templates: make([]*model.NotificationTemplate, 0, len(snapshot.Templates)),
+	}
+	for _, channel := range snapshot.Channels {
+		events := make([]model.NotificationEventType, 0, len(channel.Events))
+		for _, event := range channel.Events {
+			events = append(events, model.NotificationEventType(event))
+		}
+		out.Channels = append(out.Channels, &model.NotificationChannel{
+			Name:     channel.Name,
+			Type:     model.NotificationChannelType(channel.Type),
+			Enabled:  channel.Enabled,
+			Events:   events,
+			URL:      channel.URL,
+			Token:    channel.Token,
+			ChatID:   channel.ChatID,
+			Topic:    channel.Topic,
+			Priority: channel.Priority,
+		})
+	}
+	for _, item := range snapshot.Templates {
+		out.Templates = append(out.Templates, &model.NotificationTemplate{Event: model.NotificationEventType(item.Event), Title: item.Title, Body: item.Body})
+	}
+	return out
+}
+
+func notificationChannelFromModel(input model.NotificationChannelInput) NotificationChannelSnapshot {
+	channel := NotificationChannelSnapshot{
+		Name:    input.Name,
+		Type:    string(input.Type),
+		Enabled: input.Enabled,
+		Events:  make([]string, 0, len(input.Events)),
 	}
+	for _, event := range input.Events {
+		channel.Events = append(channel.Events, string(event))
+	}
+	if input.URL != nil {
+		channel.URL = *input.URL
+	}
+	if input.Token != nil {
+		channel.Token = *input.Token
+	}
+	if input.ChatID != nil {
+		channel.ChatID = *input.ChatID
+	}
+	if input.Topic != nil {
+		channel.Topic = *input.Topic
+	}
+	if input.Priority != nil {
+		channel.Priority = *input.Priority
+	}
+	return channel
 }
 
 func subscriptionReleasePolicyToModel(snapshot SubscriptionReleasePolicySnapshot) *model.SubscriptionReleasePolicy {
@@ -197,17 +261,18 @@ func torrentSelectionRuleToModel(rule TorrentSelectionRuleSnapshot) *model.Torre
 func settingsStatusSnapshotToModel(snapshot *SettingsStatusSnapshot) *model.SettingsStatus {
 	if snapshot == nil {
 		return &model.SettingsStatus{
-			Stash:             &model.ServiceStatus{},
-			Jackett:           &model.ServiceStatus{},
-			Qbittorrent:       &model.ServiceStatus{},
-			Automation:        &model.AutomationStatus{},
-			StashBox:          &model.StashBoxStatus{},
-			Ingest:            &model.IngestStatus{},
-			StashLibraries:    []*model.StashLibrary{},
-			StashStats:        emptyStashStatsModel(),
-			StashBoxDataCache: &model.StashBoxDataCacheStatus{},
-			JackettStats:      emptyJackettStatsModel(),
-			QbittorrentStats:  emptyQBittorrentStatsModel(),
+			Stash:                  &model.ServiceStatus{},
+			Jackett:                &model.ServiceStatus{},
+			Qbittorrent:            &model.ServiceStatus{},
+			Automation:             &model.AutomationStatus{},
+			StashBox:               &model.StashBoxStatus{},
+			Ingest:                 &model.IngestStatus{},
+			StashLibraries:         []*model.StashLibrary{},
+			StashStats:             emptyStashStatsModel(),
+			StashBoxDataCache:      &model.StashBoxDataCacheStatus{},
+			TorrentInspectionCache: &model.TorrentInspectionCacheStatus{},
+			JackettStats:           emptyJackettStatsModel(),
+			QbittorrentStats:       emptyQBittorrentStatsModel(),
 		}
 	}
 
@@ -250,6 +315,25 @@ func stashBoxDataCacheStatusToModel(status stashboxcache.Status) *model.StashBox
 	return &model.StashBoxDataCacheStatus{UsedBytes: status.UsedBytes, SceneCount: status.SceneCount, PerformerCount: status.PerformerCount, SnapshotCount: status.SnapshotCount, DatabasePath: status.DatabasePath, LastCleanupAt: formatTimePointer(status.LastCleanupAt), LastError: nilIfEmpty(status.LastError)}
 }
 
+func torrentInspectionCacheStatusToModel(status inspectioncache.Status) *model.TorrentInspectionCacheStatus {
+	return &model.TorrentInspectionCacheStatus{UsedBytes: status.UsedBytes, EntryCount: status.EntryCount, Hits: status.Hits, Misses: status.Misses, DatabasePath: status.DatabasePath, LastCleanupAt: formatTimePointer(status.LastCleanupAt), LastError: nilIfEmpty(status.LastError)}
+}
+
+func libraryIndexStatusToModel(status libraryindex.Status) *model.LibraryIndexStatus {
+	return &model.LibraryIndexStatus{
+		Ready:         status.Ready,
+		Rebuilding:    status.Rebuilding,
+		SceneCount:    status.SceneCount,
+		CodeCount:     status.CodeCount,
+		StashIDCount:  status.StashIDCount,
+		OshashCount:   status.OshashCount,
+		DatabasePath:  status.DatabasePath,
+		LastRebuildAt: formatTimePointer(status.LastRebuildAt),
+		LastSyncAt:    formatTimePointer(status.LastSyncAt),
+		LastError:     nilIfEmpty(status.LastError),
+	}
+}
+
 // SettingsStatusWithStats is settingsStatusSnapshotToModel combined with the
 // optional runtime-stats snapshot from the stats collector. When stats is nil
 // (collector not wired, e.g. in tests), the stats fields are returned as
@@ -19,15 +19,17 @@ func (r *mutationResolver) StashMetadataScan(ctx context.Context, input model.St
 	}
 
 	return r.Stash.MetadataScan(ctx, stashsync.ScanRequest{
-		Paths:                     input.Paths,
-		Rescan:                    input.Rescan,
-		ScanGenerateCovers:        input.ScanGenerateCovers,
-		ScanGeneratePreviews:      input.ScanGeneratePreviews,
-		ScanGenerateImagePreviews: input.ScanGenerateImagePreviews,
-		ScanGenerateSprites:       input.ScanGenerateSprites,
-		ScanGeneratePhashes:       input.ScanGeneratePhashes,
-		ScanGenerateThumbnails:    input.ScanGenerateThumbnails,
-		ScanGenerateClipPreviews:  input.ScanGenerateClipPreviews,
+		Paths: input.Paths,
+		ScanOptions: stashsync.ScanOptions{
+			Rescan:                    input.Rescan,
+			ScanGenerateCovers:        input.ScanGenerateCovers,
+			ScanGeneratePreviews:      input.ScanGeneratePreviews,
+			ScanGenerateImagePreviews: input.ScanGenerateImagePreviews,
+			ScanGenerateSprites:       input.ScanGenerateSprites,
+			ScanGeneratePhashes:       input.ScanGeneratePhashes,
+			ScanGenerateThumbnails:    input.ScanGenerateThumbnails,
+			ScanGenerateClipPreviews:  input.ScanGenerateClipPreviews,
+		},
 	})
 }
 
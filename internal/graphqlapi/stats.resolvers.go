@@ -39,7 +39,7 @@ func (r *queryResolver) DashboardStats(ctx context.Context) (*model.DashboardSta
 		if task.Stage == taskruntime.TaskStageDownloading && task.StageStatus == taskruntime.TaskStageStatusRunning {
 			stats.Downloading++
 		}
-		if task.Stage == taskruntime.TaskStagePendingIngest || task.Stage == taskruntime.TaskStageTransferring || task.Stage == taskruntime.TaskStageScanning {
+		if task.Stage == taskruntime.TaskStagePendingIngest || task.Stage == taskruntime.TaskStageTransferring || task.Stage == taskruntime.TaskStageSubtitling || task.Stage == taskruntime.TaskStageScanning {
 			stats.PendingScans++
 		}
 		if task.StageStatus == taskruntime.TaskStageStatusBlocked {
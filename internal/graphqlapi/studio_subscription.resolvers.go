@@ -0,0 +1,67 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// SubscribeStudio is the resolver for the subscribeStudio field.
+func (r *mutationResolver) SubscribeStudio(ctx context.Context, endpoint string, studioID string) (*model.SubscribedStudio, error) {
+	if r.StudioSubscription == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	item, err := r.StudioSubscription.SubscribeStudio(ctx, endpoint, studioID)
+	if err != nil {
+		return nil, err
+	}
+	return subscribedStudioToModel(item), nil
+}
+
+// UnsubscribeStudio is the resolver for the unsubscribeStudio field.
+func (r *mutationResolver) UnsubscribeStudio(ctx context.Context, endpoint string, studioID string) (bool, error) {
+	if r.StudioSubscription == nil {
+		return false, errors.New("subscription service is not configured")
+	}
+
+	if err := r.StudioSubscription.UnsubscribeStudio(ctx, endpoint, studioID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RefreshSubscribedStudio is the resolver for the refreshSubscribedStudio field.
+func (r *mutationResolver) RefreshSubscribedStudio(ctx context.Context, endpoint string, studioID string) (*model.SubscribedStudio, error) {
+	if r.StudioSubscription == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+
+	item, err := r.StudioSubscription.RefreshSubscribedStudio(ctx, endpoint, studioID)
+	if err != nil {
+		return nil, err
+	}
+	return subscribedStudioToModel(item), nil
+}
+
+// SubscribedStudios is the resolver for the subscribedStudios field.
+func (r *queryResolver) SubscribedStudios(ctx context.Context) ([]*model.SubscribedStudio, error) {
+	if r.StudioSubscription == nil {
+		return []*model.SubscribedStudio{}, nil
+	}
+
+	items, err := r.StudioSubscription.ListSubscribedStudios(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.SubscribedStudio, 0, len(items))
+	for _, item := range items {
+		out = append(out, subscribedStudioToModel(item))
+	}
+	return out, nil
+}
@@ -104,6 +104,18 @@ func (r *mutationResolver) RefreshSubscribedPerformers(ctx context.Context, ids
 	return performerBatchPayloadToModel(payload), nil
 }
 
+// SetPerformerReleasePolicies is the resolver for the setPerformerReleasePolicies field.
+func (r *mutationResolver) SetPerformerReleasePolicies(ctx context.Context, ids []string, policy *model.SubscriptionReleasePolicyInput) (*model.PerformerBatchPayload, error) {
+	if r.PerformerSubscription == nil {
+		return nil, errors.New("subscription service is not configured")
+	}
+	payload, err := r.PerformerSubscription.SetPerformerReleasePolicies(ctx, ids, subscriptionReleasePolicyFromInput(policy))
+	if err != nil {
+		return nil, err
+	}
+	return performerBatchPayloadToModel(payload), nil
+}
+
 // QueuePerformerScenes is the resolver for the queuePerformerScenes field.
 func (r *mutationResolver) QueuePerformerScenes(ctx context.Context, input model.QueuePerformerScenesInput) (*model.QueuePerformerScenesPayload, error) {
 	if r.Performer == nil {
@@ -141,6 +153,24 @@ func (r *mutationResolver) RefreshStashPerformerScenes(ctx context.Context, id s
 	return performerScenePageToModel(page), nil
 }
 
+// QueueMissingScenes is the resolver for the queueMissingScenes field.
+func (r *mutationResolver) QueueMissingScenes(ctx context.Context, input model.QueueMissingScenesInput) (*model.QueuePerformerScenesPayload, error) {
+	if r.Performer == nil {
+		return nil, errors.New("performer service is not configured")
+	}
+
+	selections := make([]performer.QueueSceneSelection, 0, len(input.SceneKeys))
+	for _, key := range input.SceneKeys {
+		selections = append(selections, performer.QueueSceneSelection{Key: key})
+	}
+
+	result, err := r.Performer.QueueMissingScenes(ctx, missingScenesQueryFromInput(input.Report), selections)
+	if err != nil {
+		return nil, err
+	}
+	return queuePerformerScenesResultToModel(result), nil
+}
+
 // StashPerformers is the resolver for the stashPerformers field.
 func (r *queryResolver) StashPerformers(ctx context.Context, search *string, page *int, pageSize *int) (*model.StashPerformerConnection, error) {
 	if r.Performer == nil {
@@ -286,3 +316,15 @@ func (r *queryResolver) SubscribedPerformers(ctx context.Context) ([]*model.Subs
 	}
 	return out, nil
 }
+
+// MissingScenesReport is the resolver for the missingScenesReport field.
+func (r *queryResolver) MissingScenesReport(ctx context.Context, input model.MissingScenesReportInput) (*model.MissingScenesReport, error) {
+	if r.Performer == nil {
+		return nil, errors.New("performer service is not configured")
+	}
+	report, err := r.Performer.MissingScenesReport(ctx, missingScenesQueryFromInput(&input))
+	if err != nil {
+		return nil, err
+	}
+	return missingScenesReportToModel(report), nil
+}
@@ -3,6 +3,7 @@ package graphqlapi
 import (
 	"time"
 
+	"github.com/leothevan2444/moji/internal/config"
 	"github.com/leothevan2444/moji/internal/discovery"
 	"github.com/leothevan2444/moji/internal/graphqlapi/model"
 	performerdomain "github.com/leothevan2444/moji/internal/performer"
@@ -200,9 +201,69 @@ func subscriptionPerformerToModel(item subscription.SubscribedPerformer) *model.
 		Performer:             stashPerformerToModel(item.Performer),
 		LastCheckedAt:         formatTimePointer(item.LastCheckedAt),
 		LastError:             nilIfEmpty(item.LastError),
+		ReleasePolicy:         performerReleasePolicyToModel(item.ReleasePolicy),
 		PendingReleaseCount:   item.PendingReleaseCount,
 		ProcessedReleaseCount: item.ProcessedReleaseCount,
 		RecentReleases:        releases,
+		UpcomingReleases:      upcomingReleasesToModel(item.UpcomingReleases),
+	}
+}
+
+func upcomingReleasesToModel(items []subscription.RecordedRelease) []*model.UpcomingRelease {
+	out := make([]*model.UpcomingRelease, 0, len(items))
+	for _, release := range items {
+		out = append(out, &model.UpcomingRelease{
+			Release:        subscriptionReleaseToModel(release),
+			ScheduledAt:    formatTime(release.ScheduledAt),
+			SourceAttempts: release.SourceAttempts,
+		})
+	}
+	return out
+}
+
+func performerReleasePolicyToModel(policy *subscription.ReleasePolicyConfig) *model.SubscriptionReleasePolicy {
+	if policy == nil {
+		return nil
+	}
+	return &model.SubscriptionReleasePolicy{
+		SoloBehavior:           model.SubscriptionReleaseBehavior(policy.SoloBehavior),
+		GroupBehavior:          model.SubscriptionReleaseBehavior(policy.GroupBehavior),
+		CompilationBehavior:    model.SubscriptionReleaseBehavior(policy.CompilationBehavior),
+		MaxGroupPerformerCount: policy.MaxGroupPerformerCount,
+		ReleaseDateRange:       model.SubscriptionReleaseDateRange(policy.ReleaseDateRange),
+	}
+}
+
+func subscriptionReleasePolicyFromInput(input *model.SubscriptionReleasePolicyInput) *subscription.ReleasePolicyConfig {
+	if input == nil {
+		return nil
+	}
+	return &subscription.ReleasePolicyConfig{
+		SoloBehavior:           config.SubscriptionReleaseBehavior(input.SoloBehavior),
+		GroupBehavior:          config.SubscriptionReleaseBehavior(input.GroupBehavior),
+		CompilationBehavior:    config.SubscriptionReleaseBehavior(input.CompilationBehavior),
+		MaxGroupPerformerCount: input.MaxGroupPerformerCount,
+		ReleaseDateRange:       config.SubscriptionReleaseDateRange(input.ReleaseDateRange),
+	}
+}
+
+func subscribedStudioToModel(item subscription.SubscribedStudio) *model.SubscribedStudio {
+	releases := make([]*model.SubscriptionRelease, 0, len(item.RecentReleases))
+	for _, release := range item.RecentReleases {
+		releases = append(releases, subscriptionReleaseToModel(release))
+	}
+
+	return &model.SubscribedStudio{
+		Endpoint:              item.Endpoint,
+		StudioID:              item.StudioID,
+		Name:                  item.Name,
+		SubscribedAt:          formatTime(item.SubscribedAt),
+		LastCheckedAt:         formatTimePointer(item.LastCheckedAt),
+		LastError:             nilIfEmpty(item.LastError),
+		PendingReleaseCount:   item.PendingReleaseCount,
+		ProcessedReleaseCount: item.ProcessedReleaseCount,
+		RecentReleases:        releases,
+		UpcomingReleases:      upcomingReleasesToModel(item.UpcomingReleases),
 	}
 }
 
@@ -258,6 +319,44 @@ func queuePerformerScenesResultToModel(result performerdomain.QueueScenesResult)
 	}
 }
 
+func missingScenesQueryFromInput(input *model.MissingScenesReportInput) performerdomain.MissingScenesQuery {
+	if input == nil {
+		return performerdomain.MissingScenesQuery{}
+	}
+	return performerdomain.MissingScenesQuery{
+		Subject:         performerdomain.MissingScenesSubject(input.Subject),
+		ID:              input.ID,
+		Endpoint:        derefString(input.Endpoint),
+		DateFrom:        derefString(input.DateFrom),
+		DateTo:          derefString(input.DateTo),
+		Classifications: append([]string(nil), input.Classifications...),
+		Studios:         append([]string(nil), input.Studios...),
+	}
+}
+
+func missingScenesReportToModel(report performerdomain.MissingScenesReport) *model.MissingScenesReport {
+	items := make([]*model.MissingScene, 0, len(report.Items))
+	for _, item := range report.Items {
+		items = append(items, &model.MissingScene{
+			Scene:          performerSceneToModel(item.Scene),
+			Classification: nilIfEmpty(item.Classification),
+		})
+	}
+	return &model.MissingScenesReport{
+		Subject:        model.MissingScenesSubject(report.Query.Subject),
+		ID:             report.Query.ID,
+		Name:           nilIfEmpty(report.Name),
+		Endpoint:       nilIfEmpty(report.Endpoint),
+		CatalogCount:   report.CatalogCount,
+		CatalogPartial: report.CatalogPartial,
+		OwnedCount:     report.OwnedCount,
+		MissingCount:   len(report.Items),
+		Items:          items,
+		CacheUpdatedAt: formatTimePointer(report.CacheUpdatedAt),
+		CacheStale:     report.CacheStale,
+	}
+}
+
 func queuePerformerSceneResultToModel(item performerdomain.QueueSceneResult) *model.QueuePerformerSceneResult {
 	return &model.QueuePerformerSceneResult{
 		Key:          item.Key,
@@ -0,0 +1,40 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// AdoptTorrents is the resolver for the adoptTorrents field.
+func (r *mutationResolver) AdoptTorrents(ctx context.Context, input model.AdoptTorrentsInput) (*model.TorrentAdoptionPayload, error) {
+	if r.TaskRuntime == nil {
+		return nil, errors.New("task runtime is not configured")
+	}
+	payload, err := r.TaskRuntime.AdoptTorrents(ctx, torrentAdoptionRequestFromModel(input))
+	if err != nil {
+		return nil, err
+	}
+	return torrentAdoptionPayloadToModel(payload), nil
+}
+
+// AdoptableTorrents is the resolver for the adoptableTorrents field.
+func (r *queryResolver) AdoptableTorrents(ctx context.Context, filter model.TorrentAdoptionFilterInput) ([]*model.AdoptableTorrent, error) {
+	if r.TaskRuntime == nil {
+		return nil, errors.New("task runtime is not configured")
+	}
+	candidates, err := r.TaskRuntime.PreviewTorrentAdoption(ctx, torrentAdoptionFilterFromModel(&filter))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.AdoptableTorrent, 0, len(candidates))
+	for _, candidate := range candidates {
+		out = append(out, adoptableTorrentToModel(candidate))
+	}
+	return out, nil
+}
@@ -0,0 +1,36 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// ApplyTaskReconciliationFix is the resolver for the applyTaskReconciliationFix field.
+func (r *mutationResolver) ApplyTaskReconciliationFix(ctx context.Context, input model.TaskReconciliationFixInput) (*model.Task, error) {
+	if r.TaskRuntime == nil {
+		return nil, errors.New("task runtime is not configured")
+	}
+	task, err := r.TaskRuntime.ApplyReconcileFix(ctx, taskReconcileFixFromModel(input))
+	if task != nil {
+		return taskToModel(task), err
+	}
+	return nil, err
+}
+
+// TaskReconciliation is the resolver for the taskReconciliation field.
+func (r *queryResolver) TaskReconciliation(ctx context.Context) (*model.TaskReconciliationReport, error) {
+	if r.TaskRuntime == nil {
+		return nil, errors.New("task runtime is not configured")
+	}
+	report, err := r.TaskRuntime.Reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return taskReconcileReportToModel(report), nil
+}
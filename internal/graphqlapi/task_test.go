@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/leothevan2444/moji/internal/graphqlapi/generated"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
 	"github.com/leothevan2444/moji/internal/logging"
 	performerdomain "github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/stashboxcache"
@@ -1131,6 +1133,40 @@ func TestUpdateSystemSettingsMutation(t *testing.T) {
 	}
 }
 
+func TestTestNotificationChannelMutationReportsProviderError(t *testing.T) {
+	editor := &fakeSettingsEditor{testChannelErr: errors.New("notify: telegram: HTTP 401")}
+	resolver := NewResolver(nil, nil, nil, nil, "test-version")
+	resolver.SettingsEditor = editor
+
+	var resp struct {
+		Data struct {
+			TestNotificationChannel struct {
+				Ok    bool    `json:"ok"`
+				Error *string `json:"error"`
+			} `json:"testNotificationChannel"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	executeGraphQLInto(t, resolver, `mutation {
+		testNotificationChannel(input: { name: "tg", type: TELEGRAM, enabled: true, token: "123:abc", chatId: "42", events: [TASK_BLOCKED] }) {
+			ok
+			error
+		}
+	}`, &resp)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("expected no errors, got %+v", resp.Errors)
+	}
+	result := resp.Data.TestNotificationChannel
+	if result.Ok || result.Error == nil || *result.Error != "notify: telegram: HTTP 401" {
+		t.Fatalf("unexpected test result: %+v", result)
+	}
+	if got := editor.testedChannel; got.Type != "TELEGRAM" || got.Token != "123:abc" || got.ChatID != "42" || len(got.Events) != 1 || got.Events[0] != "TASK_BLOCKED" {
+		t.Fatalf("unexpected tested channel: %+v", got)
+	}
+}
+
 func TestUpdateSystemSettingsRejectsInvalidStashBoxCacheTTL(t *testing.T) {
 	for _, ttl := range []int{11, 361} {
 		resolver := NewResolver(nil, nil, nil, nil, "test-version")
@@ -1190,6 +1226,49 @@ func TestStashBoxDataCacheStatusAndClearGraphQLContract(t *testing.T) {
 	}
 }
 
+func TestTorrentInspectionCacheStatusAndClearGraphQLContract(t *testing.T) {
+	cache := &fakeTorrentInspectionCache{status: inspectioncache.Status{UsedBytes: 512, EntryCount: 6, Hits: 9, Misses: 4, DatabasePath: "cache/inspection/cache.db"}}
+	resolver := NewResolver(nil, nil, nil, nil, "test-version")
+	resolver.RuntimeStatus = &SettingsStatusSnapshot{}
+	resolver.TorrentInspectionCache = cache
+
+	var statusResponse struct {
+		Data struct {
+			SettingsStatus struct {
+				TorrentInspectionCache struct {
+					EntryCount int   `json:"entryCount"`
+					Hits       int64 `json:"hits"`
+					Misses     int64 `json:"misses"`
+				} `json:"torrentInspectionCache"`
+			} `json:"settingsStatus"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	executeGraphQLInto(t, resolver, `{ settingsStatus { torrentInspectionCache { entryCount hits misses } } }`, &statusResponse)
+	got := statusResponse.Data.SettingsStatus.TorrentInspectionCache
+	if len(statusResponse.Errors) != 0 || got.EntryCount != 6 || got.Hits != 9 || got.Misses != 4 {
+		t.Fatalf("unexpected inspection cache status response: %+v", statusResponse)
+	}
+
+	var clearResponse struct {
+		Data struct {
+			Clear struct {
+				EntryCount int   `json:"entryCount"`
+				Hits       int64 `json:"hits"`
+			} `json:"clearTorrentInspectionCache"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	executeGraphQLInto(t, resolver, `mutation { clearTorrentInspectionCache { entryCount hits } }`, &clearResponse)
+	if len(clearResponse.Errors) != 0 || !cache.cleared || clearResponse.Data.Clear.EntryCount != 0 || clearResponse.Data.Clear.Hits != 0 {
+		t.Fatalf("unexpected inspection cache clear response: %+v cleared=%v", clearResponse, cache.cleared)
+	}
+}
+
 func TestUpdateSystemSettingsRejectsInvalidImageCacheBounds(t *testing.T) {
 	for _, imageCache := range []string{
 		`{ enabled: true, maxSizeMb: 63, retentionDays: 30 }`,
@@ -1250,6 +1329,8 @@ type fakeTaskRuntime struct {
 	resolveSourcingReq  taskruntime.ResolveBlockedSourcingRequest
 	resolveSourcingTask *taskruntime.Task
 	batchPayload        taskruntime.TaskBatchPayload
+	reconcileReport     taskruntime.ReconcileReport
+	reconcileFix        taskruntime.ReconcileFixRequest
 }
 
 type fakeGraphQLTracker struct {
@@ -1313,6 +1394,8 @@ func (f *fakeTaskRuntime) TriggerStashScans(_ context.Context, _ taskruntime.Sta
 	return f.stashTasks, nil
 }
 
+func (f *fakeTaskRuntime) WatchStashJobs(context.Context, taskruntime.StashJobWatcher) {}
+
 func (f *fakeTaskRuntime) RetryTasks(_ context.Context, _ []string, _ taskruntime.StashScanner) (taskruntime.TaskBatchPayload, error) {
 	return f.batchPayload, nil
 }
@@ -1325,6 +1408,23 @@ func (f *fakeTaskRuntime) DeleteTasks(_ context.Context, _ []string) (taskruntim
 	return f.batchPayload, nil
 }
 
+func (f *fakeTaskRuntime) Reconcile(context.Context) (taskruntime.ReconcileReport, error) {
+	return f.reconcileReport, nil
+}
+
+func (f *fakeTaskRuntime) ApplyReconcileFix(_ context.Context, req taskruntime.ReconcileFixRequest) (*taskruntime.Task, error) {
+	f.reconcileFix = req
+	return f.findTask, nil
+}
+
+func (f *fakeTaskRuntime) PreviewTorrentAdoption(context.Context, taskruntime.TorrentAdoptionFilter) ([]taskruntime.TorrentAdoptionCandidate, error) {
+	return nil, nil
+}
+
+func (f *fakeTaskRuntime) AdoptTorrents(context.Context, taskruntime.TorrentAdoptionRequest) (taskruntime.TorrentAdoptionPayload, error) {
+	return taskruntime.TorrentAdoptionPayload{}, nil
+}
+
 type graphQLTaskResponse struct {
 	Data struct {
 		AddTorrent struct {
@@ -1558,6 +1658,9 @@ type fakeSettingsEditor struct {
 	updateAutomationSnapshot  *SettingsSnapshot
 	systemInput               UpdateSystemSettingsInput
 	updateSystemSnapshot      *SettingsSnapshot
+	notificationsInput        NotificationSettingsSnapshot
+	testedChannel             NotificationChannelSnapshot
+	testChannelErr            error
 }
 
 type fakeStashBoxDataCache struct {
@@ -1579,6 +1682,26 @@ func (f *fakeStashBoxDataCache) Clear(context.Context) (stashboxcache.Status, er
 
 func (*fakeStashBoxDataCache) Cleanup(context.Context) error { return nil }
 
+type fakeTorrentInspectionCache struct {
+	status  inspectioncache.Status
+	cleared bool
+}
+
+func (f *fakeTorrentInspectionCache) Status(context.Context) (inspectioncache.Status, error) {
+	return f.status, nil
+}
+
+func (f *fakeTorrentInspectionCache) Clear(context.Context) (inspectioncache.Status, error) {
+	f.cleared = true
+	f.status.EntryCount = 0
+	f.status.UsedBytes = 0
+	f.status.Hits = 0
+	f.status.Misses = 0
+	return f.status, nil
+}
+
+func (*fakeTorrentInspectionCache) Cleanup(context.Context) error { return nil }
+
 func (f *fakeSettingsEditor) Snapshot() *SettingsSnapshot {
 	return f.snapshot
 }
@@ -1620,6 +1743,16 @@ func (f *fakeSettingsEditor) UpdateSystemSettings(input UpdateSystemSettingsInpu
 	return f.updateSystemSnapshot, nil
 }
 
+func (f *fakeSettingsEditor) UpdateNotificationSettings(input NotificationSettingsSnapshot) (*SettingsSnapshot, error) {
+	f.notificationsInput = input
+	return &SettingsSnapshot{Notifications: input}, nil
+}
+
+func (f *fakeSettingsEditor) TestNotificationChannel(_ context.Context, channel NotificationChannelSnapshot) error {
+	f.testedChannel = channel
+	return f.testChannelErr
+}
+
 func executeGraphQL(t *testing.T, resolver *Resolver, query string) graphQLTaskResponse {
 	var resp graphQLTaskResponse
 	executeGraphQLInto(t, resolver, query, &resp)
@@ -1657,6 +1790,8 @@ type fakePerformerService struct {
 	queuePerformerResult performerdomain.QueueScenesResult
 	queuePerformerID     string
 	queueSelections      []performerdomain.QueueSceneSelection
+	missingReport        performerdomain.MissingScenesReport
+	missingQuery         performerdomain.MissingScenesQuery
 }
 
 type fakeLogReader struct {
@@ -1692,10 +1827,25 @@ func (f *fakePerformerService) RefreshPerformerScenes(context.Context, string, p
 	return f.performerPage, nil
 }
 
+func (f *fakePerformerService) MissingScenesReport(_ context.Context, query performerdomain.MissingScenesQuery) (performerdomain.MissingScenesReport, error) {
+	f.missingQuery = query
+	return f.missingReport, nil
+}
+
+func (f *fakePerformerService) QueueMissingScenes(_ context.Context, query performerdomain.MissingScenesQuery, selections []performerdomain.QueueSceneSelection) (performerdomain.QueueScenesResult, error) {
+	f.missingQuery = query
+	f.queueSelections = append([]performerdomain.QueueSceneSelection(nil), selections...)
+	return f.queuePerformerResult, nil
+}
+
 func (fakeStashService) MetadataScan(context.Context, stashsync.ScanRequest) (string, error) {
 	return "job-1", nil
 }
 
+func (fakeStashService) MetadataIdentify(context.Context, stashsync.IdentifyRequest) (string, error) {
+	return "job-2", nil
+}
+
 func (fakeStashService) FindJob(context.Context, string) (*stashsync.Job, error) {
 	return nil, nil
 }
@@ -1704,6 +1854,10 @@ func (fakeStashService) CurrentConfig() stashsync.IntegrationConfig {
 	return stashsync.IntegrationConfig{}
 }
 
+func (fakeStashService) WatchJobs(context.Context, func(), func(stashsync.Job)) error {
+	return stashsync.ErrJobSubscriptionUnsupported
+}
+
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
@@ -0,0 +1,95 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// CreateWatchlist is the resolver for the createWatchlist field.
+func (r *mutationResolver) CreateWatchlist(ctx context.Context, input model.WatchlistInput) (*model.Watchlist, error) {
+	if r.Watchlist == nil {
+		return nil, errors.New("watchlists are not configured")
+	}
+	item, err := r.Watchlist.Create(ctx, watchlistInputFromModel(input))
+	if err != nil {
+		return nil, err
+	}
+	return watchlistToModel(item), nil
+}
+
+// UpdateWatchlist is the resolver for the updateWatchlist field.
+func (r *mutationResolver) UpdateWatchlist(ctx context.Context, id string, input model.WatchlistInput) (*model.Watchlist, error) {
+	if r.Watchlist == nil {
+		return nil, errors.New("watchlists are not configured")
+	}
+	item, err := r.Watchlist.Update(ctx, id, watchlistInputFromModel(input))
+	if err != nil {
+		return nil, err
+	}
+	return watchlistToModel(item), nil
+}
+
+// DeleteWatchlist is the resolver for the deleteWatchlist field.
+func (r *mutationResolver) DeleteWatchlist(ctx context.Context, id string) (bool, error) {
+	if r.Watchlist == nil {
+		return false, errors.New("watchlists are not configured")
+	}
+	if err := r.Watchlist.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RunWatchlist is the resolver for the runWatchlist field.
+func (r *mutationResolver) RunWatchlist(ctx context.Context, id string) (*model.WatchlistRun, error) {
+	if r.Watchlist == nil {
+		return nil, errors.New("watchlists are not configured")
+	}
+	summary, err := r.Watchlist.Run(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WatchlistRun{
+		Watchlist:    watchlistToModel(summary.Watchlist),
+		FetchedCount: summary.Fetched,
+		NewResults:   watchlistResultsToModel(summary.New),
+	}, nil
+}
+
+// Watchlists is the resolver for the watchlists field.
+func (r *queryResolver) Watchlists(ctx context.Context) ([]*model.Watchlist, error) {
+	if r.Watchlist == nil {
+		return []*model.Watchlist{}, nil
+	}
+	items, err := r.Watchlist.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.Watchlist, 0, len(items))
+	for _, item := range items {
+		out = append(out, watchlistToModel(item))
+	}
+	return out, nil
+}
+
+// WatchlistResults is the resolver for the watchlistResults field.
+func (r *queryResolver) WatchlistResults(ctx context.Context, id string, limit *int) ([]*model.WatchlistResult, error) {
+	if r.Watchlist == nil {
+		return []*model.WatchlistResult{}, nil
+	}
+	limitValue := 0
+	if limit != nil {
+		limitValue = *limit
+	}
+	results, err := r.Watchlist.Results(ctx, id, limitValue)
+	if err != nil {
+		return nil, err
+	}
+	return watchlistResultsToModel(results), nil
+}
@@ -0,0 +1,73 @@
+package graphqlapi
+
+import (
+	"time"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+	"github.com/leothevan2444/moji/internal/watchlist"
+)
+
+func watchlistInputFromModel(input model.WatchlistInput) watchlist.Input {
+	out := watchlist.Input{
+		Name:       derefString(input.Name),
+		Query:      input.Query,
+		Indexers:   input.Indexers,
+		Categories: input.Categories,
+		Exclude:    input.Exclude,
+		Action:     watchlist.ActionNotify,
+		Enabled:    true,
+	}
+	if input.MinSeeders != nil {
+		out.MinSeeders = *input.MinSeeders
+	}
+	if input.Action != nil {
+		out.Action = watchlist.Action(*input.Action)
+	}
+	if input.Enabled != nil {
+		out.Enabled = *input.Enabled
+	}
+	if input.IntervalMinutes != nil {
+		out.Interval = time.Duration(*input.IntervalMinutes) * time.Minute
+	}
+	return out
+}
+
+func watchlistToModel(item watchlist.Watchlist) *model.Watchlist {
+	return &model.Watchlist{
+		ID:              item.ID,
+		Name:            item.Name,
+		Query:           item.Query,
+		Indexers:        append([]string{}, item.Indexers...),
+		Categories:      append([]int{}, item.Categories...),
+		Exclude:         append([]string{}, item.Exclude...),
+		MinSeeders:      item.MinSeeders,
+		Action:          model.WatchlistAction(item.Action),
+		Enabled:         item.Enabled,
+		IntervalMinutes: int(item.Interval / time.Minute),
+		CreatedAt:       formatTime(item.CreatedAt),
+		UpdatedAt:       formatTime(item.UpdatedAt),
+		LastRunAt:       formatOptionalTime(item.LastRunAt),
+		LastSuccessAt:   formatOptionalTime(item.LastSuccessAt),
+		LastError:       nilIfEmpty(item.LastError),
+	}
+}
+
+func watchlistResultsToModel(results []watchlist.Result) []*model.WatchlistResult {
+	out := make([]*model.WatchlistResult, 0, len(results))
+	for _, result := range results {
+		out = append(out, &model.WatchlistResult{
+			Key:         result.Key,
+			Title:       result.Title,
+			Tracker:     nilIfEmpty(result.Tracker),
+			Link:        nilIfEmpty(result.Link),
+			Size:        result.Size,
+			Seeders:     result.Seeders,
+			PublishDate: nilIfEmpty(result.PublishDate),
+			Status:      model.WatchlistResultStatus(result.Status),
+			TaskID:      nilIfEmpty(result.TaskID),
+			Error:       nilIfEmpty(result.Error),
+			SeenAt:      formatTime(result.SeenAt),
+		})
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leothevan2444/moji/internal/graphqlapi/model"
+)
+
+// RedeliverWebhook is the resolver for the redeliverWebhook field.
+func (r *mutationResolver) RedeliverWebhook(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	if r.Webhooks == nil {
+		return nil, errors.New("webhooks are not configured")
+	}
+	delivery, err := r.Webhooks.Redeliver(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return webhookDeliveryToModel(delivery), nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhook *string, status *model.WebhookDeliveryStatus, limit *int) ([]*model.WebhookDelivery, error) {
+	if r.Webhooks == nil {
+		return []*model.WebhookDelivery{}, nil
+	}
+	deliveries, err := r.Webhooks.Deliveries(ctx, webhookDeliveryFilterFromModel(webhook, status, limit))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		out = append(out, webhookDeliveryToModel(delivery))
+	}
+	return out, nil
+}
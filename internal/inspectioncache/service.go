@@ -0,0 +1,148 @@
+package inspectioncache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const accessTouchInterval = time.Hour
+
+type Service struct {
+	store  *sqliteStore
+	config ConfigProvider
+	now    func() time.Time
+	opsMu  sync.RWMutex
+
+	hits   atomic.Int64
+	misses atomic.Int64
+
+	statusMu    sync.RWMutex
+	lastCleanup *time.Time
+	lastError   string
+}
+
+func New(path string, provider ConfigProvider) (*Service, error) {
+	store, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		provider = func() Config { return Config{} }
+	}
+	return &Service{store: store, config: provider, now: time.Now}, nil
+}
+
+func (s *Service) Close() error {
+	if s == nil || s.store == nil || s.store.db == nil {
+		return nil
+	}
+	s.opsMu.Lock()
+	defer s.opsMu.Unlock()
+	return s.store.db.Close()
+}
+
+// Lookup returns the cached inspection for a torrent, preferring the infohash
+// key so that the same torrent served by different indexers shares one entry.
+func (s *Service) Lookup(ctx context.Context, torrentURL, infoHash string) (Entry, bool) {
+	s.opsMu.RLock()
+	defer s.opsMu.RUnlock()
+	now := s.now().UTC()
+	ttl := s.config().normalize().TTL
+	for _, key := range cacheKeys(torrentURL, infoHash) {
+		stored, found, err := s.store.get(ctx, key)
+		if err != nil || !found || now.Sub(stored.FetchedAt) >= ttl {
+			continue
+		}
+		if now.Sub(stored.LastAccessed) >= accessTouchInterval {
+			s.store.touch(ctx, stored.ID, now)
+		}
+		s.hits.Add(1)
+		return stored.Entry, true
+	}
+	s.misses.Add(1)
+	return Entry{}, false
+}
+
+// Store records an inspection once and makes it reachable through both its
+// URL and infohash keys.
+func (s *Service) Store(ctx context.Context, torrentURL, infoHash string, entry Entry) error {
+	s.opsMu.RLock()
+	defer s.opsMu.RUnlock()
+	if strings.TrimSpace(infoHash) == "" {
+		infoHash = entry.InfoHash
+	}
+	keys := cacheKeys(torrentURL, infoHash)
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.store.put(ctx, keys, entry, s.now().UTC())
+}
+
+func cacheKeys(torrentURL, infoHash string) []string {
+	keys := make([]string, 0, 2)
+	if trimmed := strings.TrimSpace(infoHash); trimmed != "" {
+		keys = append(keys, "hash:"+strings.ToUpper(trimmed))
+	}
+	if trimmed := strings.TrimSpace(torrentURL); trimmed != "" {
+		keys = append(keys, "url:"+trimmed)
+	}
+	return keys
+}
+
+func (s *Service) Cleanup(ctx context.Context) error {
+	s.opsMu.RLock()
+	defer s.opsMu.RUnlock()
+	now := s.now().UTC()
+	cfg := s.config().normalize()
+	err := s.store.cleanup(ctx, now.Add(-cfg.TTL), cfg.MaxEntries)
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if err != nil {
+		s.lastError = err.Error()
+		return err
+	}
+	s.lastCleanup = &now
+	s.lastError = ""
+	return nil
+}
+
+func (s *Service) StartCleanup(ctx context.Context) {
+	go func() {
+		_ = s.Cleanup(ctx)
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = s.Cleanup(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) Clear(ctx context.Context) (Status, error) {
+	s.opsMu.Lock()
+	defer s.opsMu.Unlock()
+	if err := s.store.clear(ctx); err != nil {
+		return Status{}, err
+	}
+	s.hits.Store(0)
+	s.misses.Store(0)
+	return s.Status(ctx)
+}
+
+func (s *Service) Status(ctx context.Context) (Status, error) {
+	status, err := s.store.status(ctx)
+	status.Hits = s.hits.Load()
+	status.Misses = s.misses.Load()
+	s.statusMu.RLock()
+	status.LastCleanupAt = s.lastCleanup
+	status.LastError = s.lastError
+	s.statusMu.RUnlock()
+	return status, err
+}
@@ -0,0 +1,148 @@
+package inspectioncache
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestService(t *testing.T, now *time.Time, cfg Config) *Service {
+	t.Helper()
+	service, err := New(filepath.Join(t.TempDir(), "cache.db"), func() Config { return cfg })
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	service.now = func() time.Time { return *now }
+	t.Cleanup(func() { _ = service.Close() })
+	return service
+}
+
+func TestLookupSharesEntriesBetweenURLAndInfoHash(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now, Config{TTL: time.Hour})
+	ctx := context.Background()
+
+	if _, ok := service.Lookup(ctx, "https://indexer/a.torrent", ""); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	entry := Entry{Name: "ABC-123", InfoHash: "abcdef", Paths: []string{"ABC-123/ABC-123.mp4"}}
+	if err := service.Store(ctx, "https://indexer/a.torrent", "", entry); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := service.Lookup(ctx, "https://other/b.torrent", "ABCDEF")
+	if !ok || got.Name != "ABC-123" || len(got.Paths) != 1 {
+		t.Fatalf("unexpected infohash lookup: %+v ok=%v", got, ok)
+	}
+	if _, ok := service.Lookup(ctx, "https://indexer/a.torrent", ""); !ok {
+		t.Fatal("expected URL lookup hit")
+	}
+
+	status, err := service.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Hits != 2 || status.Misses != 1 || status.EntryCount != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestLookupIgnoresExpiredEntriesAndCleanupRemovesThem(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now, Config{TTL: time.Hour})
+	ctx := context.Background()
+
+	if err := service.Store(ctx, "https://indexer/a.torrent", "", Entry{Paths: []string{"a.mp4"}}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, ok := service.Lookup(ctx, "https://indexer/a.torrent", ""); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+	if err := service.Cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status, err := service.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.EntryCount != 0 || status.LastCleanupAt == nil {
+		t.Fatalf("unexpected status after cleanup: %+v", status)
+	}
+}
+
+func TestCleanupEnforcesMaxEntries(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now, Config{TTL: 24 * time.Hour, MaxEntries: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Minute)
+		if err := service.Store(ctx, fmt.Sprintf("https://indexer/%d.torrent", i), "", Entry{Paths: []string{"a.mp4"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := service.Cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := service.Lookup(ctx, "https://indexer/0.torrent", ""); ok {
+		t.Fatal("expected oldest entry to be evicted")
+	}
+	if _, ok := service.Lookup(ctx, "https://indexer/3.torrent", ""); !ok {
+		t.Fatal("expected newest entry to survive")
+	}
+}
+
+func TestCleanupCountsAliasedKeysAsOneEntry(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now, Config{TTL: 24 * time.Hour, MaxEntries: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Minute)
+		entry := Entry{InfoHash: fmt.Sprintf("hash%d", i), Paths: []string{"a.mp4"}}
+		if err := service.Store(ctx, fmt.Sprintf("https://indexer/%d.torrent", i), "", entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := service.Cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status, err := service.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.EntryCount != 2 {
+		t.Fatalf("expected two inspections to survive, got %+v", status)
+	}
+	for i := 1; i < 3; i++ {
+		if _, ok := service.Lookup(ctx, fmt.Sprintf("https://indexer/%d.torrent", i), ""); !ok {
+			t.Fatalf("expected URL alias of entry %d to survive", i)
+		}
+		if _, ok := service.Lookup(ctx, "", fmt.Sprintf("HASH%d", i)); !ok {
+			t.Fatalf("expected infohash alias of entry %d to survive", i)
+		}
+	}
+	if _, ok := service.Lookup(ctx, "", "HASH0"); ok {
+		t.Fatal("expected both aliases of the oldest entry to be evicted")
+	}
+}
+
+func TestClearResetsEntriesAndCounters(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now, Config{})
+	ctx := context.Background()
+
+	if err := service.Store(ctx, "https://indexer/a.torrent", "hash", Entry{Paths: []string{"a.mp4"}}); err != nil {
+		t.Fatal(err)
+	}
+	service.Lookup(ctx, "https://indexer/a.torrent", "")
+	status, err := service.Clear(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.EntryCount != 0 || status.Hits != 0 || status.Misses != 0 {
+		t.Fatalf("unexpected status after clear: %+v", status)
+	}
+}
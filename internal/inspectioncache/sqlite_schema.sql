@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS torrent_inspection_meta (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS torrent_inspections (
+    entry_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payload_json BLOB NOT NULL,
+    fetched_at TEXT NOT NULL,
+    last_accessed_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS torrent_inspection_keys (
+    cache_key TEXT PRIMARY KEY,
+    entry_id INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_inspections_fetched_at
+    ON torrent_inspections(fetched_at);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_inspections_last_accessed_at
+    ON torrent_inspections(last_accessed_at);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_inspection_keys_entry
+    ON torrent_inspection_keys(entry_id);
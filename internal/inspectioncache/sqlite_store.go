@@ -0,0 +1,236 @@
+package inspectioncache
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+const sqliteSchemaVersion = "2"
+
+type sqliteStore struct {
+	db   *sqlx.DB
+	path string
+}
+
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("inspectioncache: database path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("inspectioncache: create database directory: %w", err)
+		}
+	}
+	db, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("inspectioncache: open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("inspectioncache: configure database: %w", err)
+		}
+	}
+	store := &sqliteStore{db: db, path: path}
+	if err := store.initSchema(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *sqliteStore) initSchema() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS torrent_inspection_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`); err != nil {
+		return err
+	}
+	var version string
+	err := s.db.Get(&version, `SELECT value FROM torrent_inspection_meta WHERE key = 'schema_version'`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if version != "" && version != sqliteSchemaVersion {
+		for _, table := range []string{"torrent_inspection_keys", "torrent_inspections", "torrent_inspection_meta"} {
+			if _, err := s.db.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("inspectioncache: initialize schema: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO torrent_inspection_meta(key,value) VALUES('schema_version',?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, sqliteSchemaVersion)
+	return err
+}
+
+type storedEntry struct {
+	ID           int64
+	Entry        Entry
+	FetchedAt    time.Time
+	LastAccessed time.Time
+}
+
+func (s *sqliteStore) get(ctx context.Context, key string) (storedEntry, bool, error) {
+	var row struct {
+		ID           int64  `db:"entry_id"`
+		Payload      []byte `db:"payload_json"`
+		FetchedAt    string `db:"fetched_at"`
+		LastAccessed string `db:"last_accessed_at"`
+	}
+	err := s.db.GetContext(ctx, &row, `SELECT e.entry_id,e.payload_json,e.fetched_at,e.last_accessed_at
+		FROM torrent_inspection_keys k JOIN torrent_inspections e ON e.entry_id=k.entry_id WHERE k.cache_key=?`, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storedEntry{}, false, nil
+	}
+	if err != nil {
+		return storedEntry{}, false, err
+	}
+	out := storedEntry{ID: row.ID}
+	if err := json.Unmarshal(row.Payload, &out.Entry); err != nil {
+		return storedEntry{}, false, err
+	}
+	if out.FetchedAt, err = time.Parse(time.RFC3339Nano, row.FetchedAt); err != nil {
+		return storedEntry{}, false, err
+	}
+	if out.LastAccessed, err = time.Parse(time.RFC3339Nano, row.LastAccessed); err != nil {
+		return storedEntry{}, false, err
+	}
+	return out, true, nil
+}
+
+func (s *sqliteStore) touch(ctx context.Context, id int64, now time.Time) {
+	_, _ = s.db.ExecContext(ctx, `UPDATE torrent_inspections SET last_accessed_at=? WHERE entry_id=?`, formatTime(now), id)
+}
+
+// put stores one inspection row and points every key at it. An existing row
+// reached through any of the keys is reused, and rows left without keys are
+// removed.
+func (s *sqliteStore) put(ctx context.Context, keys []string, entry Entry, now time.Time) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var id int64
+	var previous []int64
+	for _, key := range keys {
+		var existing int64
+		err := tx.GetContext(ctx, &existing, `SELECT entry_id FROM torrent_inspection_keys WHERE cache_key=?`, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			id = existing
+		} else if existing != id {
+			previous = append(previous, existing)
+		}
+	}
+	if id != 0 {
+		if _, err := tx.ExecContext(ctx, `UPDATE torrent_inspections SET payload_json=?,fetched_at=?,last_accessed_at=? WHERE entry_id=?`,
+			payload, formatTime(now), formatTime(now), id); err != nil {
+			return err
+		}
+	} else {
+		result, err := tx.ExecContext(ctx, `INSERT INTO torrent_inspections(payload_json,fetched_at,last_accessed_at) VALUES(?,?,?)`, payload, formatTime(now), formatTime(now))
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO torrent_inspection_keys(cache_key,entry_id) VALUES(?,?)
+			ON CONFLICT(cache_key) DO UPDATE SET entry_id=excluded.entry_id`, key, id); err != nil {
+			return err
+		}
+	}
+	for _, stale := range previous {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM torrent_inspections WHERE entry_id=? AND NOT EXISTS (
+			SELECT 1 FROM torrent_inspection_keys WHERE entry_id=?
+		)`, stale, stale); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// cleanup drops expired inspections and then the least recently used ones
+// beyond maxEntries. Entries are counted once however many keys they have.
+func (s *sqliteStore) cleanup(ctx context.Context, expiredBefore time.Time, maxEntries int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM torrent_inspections WHERE fetched_at < ?`, formatTime(expiredBefore)); err != nil {
+		return err
+	}
+	if maxEntries > 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM torrent_inspections WHERE entry_id IN (
+			SELECT entry_id FROM torrent_inspections ORDER BY last_accessed_at DESC, entry_id DESC LIMIT -1 OFFSET ?
+		)`, maxEntries); err != nil {
+			return err
+		}
+	}
+	if err := deleteOrphans(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteOrphans removes keys whose inspection is gone and inspections that no
+// key points at any more.
+func deleteOrphans(ctx context.Context, tx *sqlx.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM torrent_inspection_keys WHERE entry_id NOT IN (SELECT entry_id FROM torrent_inspections)`); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DELETE FROM torrent_inspections WHERE entry_id NOT IN (SELECT entry_id FROM torrent_inspection_keys)`)
+	return err
+}
+
+func (s *sqliteStore) clear(ctx context.Context) error {
+	for _, table := range []string{"torrent_inspection_keys", "torrent_inspections"} {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.ExecContext(ctx, `VACUUM`)
+	return err
+}
+
+func (s *sqliteStore) status(ctx context.Context) (Status, error) {
+	status := Status{DatabasePath: s.path}
+	if err := s.db.GetContext(ctx, &status.EntryCount, `SELECT COUNT(*) FROM torrent_inspections`); err != nil {
+		return status, err
+	}
+	if err := s.db.GetContext(ctx, &status.UsedBytes, `SELECT COALESCE(SUM(length(payload_json)),0) FROM torrent_inspections`); err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+func formatTime(value time.Time) string { return value.UTC().Format(time.RFC3339Nano) }
@@ -0,0 +1,45 @@
+package inspectioncache
+
+import "time"
+
+const (
+	DefaultTTL = 7 * 24 * time.Hour
+	// DefaultMaxEntries caps the number of cached inspections. An inspection
+	// reachable by both URL and infohash still counts as one entry.
+	DefaultMaxEntries = 20000
+	cleanupInterval   = 6 * time.Hour
+)
+
+type Config struct {
+	TTL        time.Duration
+	MaxEntries int
+}
+
+func (c Config) normalize() Config {
+	if c.TTL <= 0 {
+		c.TTL = DefaultTTL
+	}
+	if c.MaxEntries <= 0 {
+		c.MaxEntries = DefaultMaxEntries
+	}
+	return c
+}
+
+type ConfigProvider func() Config
+
+// Entry is the file listing extracted from a downloaded .torrent file.
+type Entry struct {
+	Name     string   `json:"name,omitempty"`
+	InfoHash string   `json:"info_hash,omitempty"`
+	Paths    []string `json:"paths"`
+}
+
+type Status struct {
+	UsedBytes     int64
+	EntryCount    int
+	Hits          int64
+	Misses        int64
+	DatabasePath  string
+	LastCleanupAt *time.Time
+	LastError     string
+}
@@ -0,0 +1,342 @@
+package libraryindex
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/codeparser"
+	"github.com/leothevan2444/moji/internal/logging"
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+// Service keeps a local index of the Stash library so that dedup and
+// "already owned" checks do not query Stash for every candidate. Until the
+// first full sync finishes, lookups fall back to querying Stash directly.
+type Service struct {
+	store  *sqliteStore
+	client Client
+	now    func() time.Time
+
+	// syncMu serializes full rebuilds and incremental syncs.
+	syncMu     sync.Mutex
+	ready      atomic.Bool
+	rebuilding atomic.Bool
+
+	statusMu  sync.RWMutex
+	lastError string
+}
+
+func New(path string, client Client) (*Service, error) {
+	if client == nil {
+		return nil, errors.New("libraryindex: stash client is required")
+	}
+	store, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	service := &Service{store: store, client: client, now: time.Now}
+	built, err := store.metaTime(context.Background(), metaLastRebuildAt)
+	if err != nil {
+		_ = store.db.Close()
+		return nil, err
+	}
+	service.ready.Store(built != nil)
+	return service, nil
+}
+
+func (s *Service) Close() error {
+	if s == nil || s.store == nil || s.store.db == nil {
+		return nil
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	return s.store.db.Close()
+}
+
+// StartSync brings the index up to date in the background and keeps it
+// there: a full rebuild the first time, then a refresh every syncInterval
+// until ctx is cancelled.
+func (s *Service) StartSync(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			_ = s.refresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Rebuild replaces the index with every scene currently in Stash.
+func (s *Service) Rebuild(ctx context.Context) (Status, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	err := s.rebuildLocked(ctx)
+	status, statusErr := s.Status(ctx)
+	if err != nil {
+		return status, err
+	}
+	return status, statusErr
+}
+
+// Sync indexes the scenes Stash updated since the last sync. Moji calls it
+// after its scans so that freshly delivered scenes are owned immediately.
+// It never rebuilds; until the first rebuild finishes it does nothing and
+// lookups keep querying Stash.
+func (s *Service) Sync(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if !s.ready.Load() {
+		return nil
+	}
+	return s.recordError(s.syncUpdatedLocked(ctx))
+}
+
+// refresh is one round of the background loop. Scenes deleted in Stash never
+// show up as updated, and scenes imported with an old update time fall behind
+// the watermark, so after the incremental sync the index is rebuilt whenever
+// its scene count differs from Stash's, and at least every rebuildInterval.
+func (s *Service) refresh(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if !s.ready.Load() {
+		return s.rebuildLocked(ctx)
+	}
+	rebuiltAt, err := s.store.metaTime(ctx, metaLastRebuildAt)
+	if err != nil {
+		return s.recordError(err)
+	}
+	if rebuiltAt == nil || s.now().Sub(*rebuiltAt) >= rebuildInterval {
+		return s.rebuildLocked(ctx)
+	}
+	if err := s.syncUpdatedLocked(ctx); err != nil {
+		return s.recordError(err)
+	}
+	page, perPage := 1, 1
+	total, _, err := s.client.FindLibraryScenes(ctx, nil, &stashgraphql.FindFilterType{Page: &page, PerPage: &perPage})
+	if err != nil {
+		return s.recordError(err)
+	}
+	indexed, err := s.store.sceneCount(ctx)
+	if err != nil {
+		return s.recordError(err)
+	}
+	if indexed != total {
+		logging.Infof("libraryindex: index holds %d scenes but Stash has %d, rebuilding", indexed, total)
+		return s.rebuildLocked(ctx)
+	}
+	return s.recordError(nil)
+}
+
+func (s *Service) syncUpdatedLocked(ctx context.Context) error {
+	watermark, err := s.store.metaTime(ctx, metaWatermark)
+	if err != nil {
+		return err
+	}
+	filter := &stashgraphql.SceneFilterType{}
+	if watermark != nil && !watermark.IsZero() {
+		filter.UpdatedAt = &stashgraphql.TimestampCriterionInput{
+			Value:    watermark.Add(-syncOverlap).UTC().Format(time.RFC3339),
+			Modifier: stashgraphql.CriterionModifierGreaterThan,
+		}
+	}
+	scenes, err := s.fetchScenes(ctx, filter, "updated_at")
+	if err != nil {
+		return err
+	}
+	if err := s.store.upsert(ctx, scenes, s.now().UTC()); err != nil {
+		return err
+	}
+	logging.Infof("libraryindex: synced %d updated scenes", len(scenes))
+	return nil
+}
+
+func (s *Service) rebuildLocked(ctx context.Context) error {
+	s.rebuilding.Store(true)
+	defer s.rebuilding.Store(false)
+	started := s.now().UTC()
+	scenes, err := s.fetchScenes(ctx, nil, "created_at")
+	if err != nil {
+		return s.recordError(err)
+	}
+	if err := s.store.replaceAll(ctx, scenes, started); err != nil {
+		return s.recordError(err)
+	}
+	s.ready.Store(true)
+	logging.Infof("libraryindex: rebuilt index with %d scenes in %s", len(scenes), s.now().UTC().Sub(started).Round(time.Millisecond))
+	return s.recordError(nil)
+}
+
+func (s *Service) fetchScenes(ctx context.Context, filter *stashgraphql.SceneFilterType, sort string) ([]scene, error) {
+	direction := stashgraphql.SortDirectionEnumAsc
+	perPage := PageSize
+	var out []scene
+	for page := 1; ; page++ {
+		current := page
+		total, items, err := s.client.FindLibraryScenes(ctx, filter, &stashgraphql.FindFilterType{
+			Page:      &current,
+			PerPage:   &perPage,
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item != nil {
+				out = append(out, sceneFromStash(item))
+			}
+		}
+		if len(items) < perPage || page*perPage >= total {
+			return out, nil
+		}
+	}
+}
+
+func (s *Service) recordError(err error) error {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if err != nil {
+		logging.Warnf("libraryindex: sync failed: %v", err)
+		s.lastError = err.Error()
+		return err
+	}
+	s.lastError = ""
+	return nil
+}
+
+// HasCode reports whether the library holds a scene with code, whether it
+// was set on the scene or found in a file path or title.
+func (s *Service) HasCode(ctx context.Context, code string) (bool, error) {
+	candidates := codeparser.LibraryCodes(code)
+	if !s.ready.Load() {
+		return s.liveHasCode(ctx, candidates)
+	}
+	values := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		values = append(values, codeKey(candidate))
+	}
+	return s.store.hasKey(ctx, codeKinds, values)
+}
+
+// HasStashID reports whether a library scene is linked to the StashBox
+// scene id on endpoint.
+func (s *Service) HasStashID(ctx context.Context, endpoint, stashID string) (bool, error) {
+	endpoint, stashID = strings.TrimSpace(endpoint), strings.TrimSpace(stashID)
+	if endpoint == "" || stashID == "" {
+		return false, nil
+	}
+	if !s.ready.Load() {
+		return s.liveHas(ctx, &stashgraphql.SceneFilterType{StashIDEndpoint: &stashgraphql.StashIDCriterionInput{
+			Endpoint: &endpoint,
+			StashID:  &stashID,
+			Modifier: stashgraphql.CriterionModifierEquals,
+		}})
+	}
+	return s.store.hasKey(ctx, []KeyKind{KeyStashID}, []string{stashIDKey(endpoint, stashID)})
+}
+
+// HasOshash reports whether a library file has the oshash fingerprint.
+func (s *Service) HasOshash(ctx context.Context, oshash string) (bool, error) {
+	oshash = strings.ToLower(strings.TrimSpace(oshash))
+	if oshash == "" {
+		return false, nil
+	}
+	if !s.ready.Load() {
+		return s.liveHas(ctx, &stashgraphql.SceneFilterType{Oshash: &stashgraphql.StringCriterionInput{
+			Value:    oshash,
+			Modifier: stashgraphql.CriterionModifierEquals,
+		}})
+	}
+	return s.store.hasKey(ctx, []KeyKind{KeyOshash}, []string{oshash})
+}
+
+func (s *Service) liveHasCode(ctx context.Context, candidates []string) (bool, error) {
+	for _, candidate := range candidates {
+		found, err := s.liveHas(ctx, &stashgraphql.SceneFilterType{Code: &stashgraphql.StringCriterionInput{
+			Value:    candidate,
+			Modifier: stashgraphql.CriterionModifierEquals,
+		}})
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) liveHas(ctx context.Context, filter *stashgraphql.SceneFilterType) (bool, error) {
+	page, perPage := 1, 1
+	_, scenes, err := s.client.FindLibraryScenes(ctx, filter, &stashgraphql.FindFilterType{Page: &page, PerPage: &perPage})
+	if err != nil {
+		return false, err
+	}
+	return len(scenes) > 0, nil
+}
+
+func (s *Service) Status(ctx context.Context) (Status, error) {
+	status, err := s.store.status(ctx)
+	status.Rebuilding = s.rebuilding.Load()
+	s.statusMu.RLock()
+	status.LastError = s.lastError
+	s.statusMu.RUnlock()
+	return status, err
+}
+
+func sceneFromStash(value *stashgraphql.LibrarySceneFragment) scene {
+	out := scene{ID: value.ID, Title: stringValue(value.Title), Code: strings.TrimSpace(stringValue(value.Code)), UpdatedAt: value.UpdatedAt.UTC()}
+	seen := map[sceneKey]struct{}{}
+	add := func(kind KeyKind, value string) {
+		key := sceneKey{Kind: kind, Value: value}
+		if _, ok := seen[key]; value == "" || ok {
+			return
+		}
+		seen[key] = struct{}{}
+		out.Keys = append(out.Keys, key)
+	}
+	if out.Code != "" {
+		add(KeyCode, codeKey(out.Code))
+		add(KeyCode, codeKey(codeparser.Normalize(out.Code)))
+	}
+	add(KeyTitleCode, codeKey(codeparser.Normalize(out.Title)))
+	for _, file := range value.Files {
+		if file == nil {
+			continue
+		}
+		filePath := strings.ReplaceAll(file.Path, "\\", "/")
+		add(KeyPathCode, codeKey(codeparser.Extract(file.Basename, path.Base(path.Dir(filePath)))))
+		for _, fingerprint := range file.Fingerprints {
+			if fingerprint != nil && strings.EqualFold(fingerprint.Type, "oshash") {
+				add(KeyOshash, strings.ToLower(strings.TrimSpace(fingerprint.Value)))
+			}
+		}
+	}
+	for _, stashID := range value.StashIds {
+		if stashID != nil && strings.TrimSpace(stashID.StashID) != "" {
+			add(KeyStashID, stashIDKey(stashID.Endpoint, stashID.StashID))
+		}
+	}
+	return out
+}
+
+func codeKey(code string) string { return strings.ToUpper(strings.TrimSpace(code)) }
+
+func stashIDKey(endpoint, stashID string) string {
+	endpoint = strings.TrimRight(strings.ToLower(strings.TrimSpace(endpoint)), "/")
+	return endpoint + " " + strings.TrimSpace(stashID)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,209 @@
+package libraryindex
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+func newTestService(t *testing.T, client *fakeClient) *Service {
+	t.Helper()
+	service, err := New(filepath.Join(t.TempDir(), "index.db"), client)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+	return service
+}
+
+func TestRebuildIndexesCodesStashIDsAndOshashes(t *testing.T) {
+	ctx := context.Background()
+	updated := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{scenes: []*stashgraphql.LibrarySceneFragment{
+		libraryScene("1", "", "abc00123", updated, "/library/ABC-123/ABC-123.mp4", "AbCdEf"),
+		libraryScene("2", "[FHD] XYZ-042 Title", "", updated, "/library/misc/movie.mp4", ""),
+		libraryScene("3", "", "", updated, "/library/DEF-777/cd1.mp4", ""),
+	}}
+	client.scenes[1].StashIds = []*stashgraphql.LibrarySceneFragment_StashIds{{Endpoint: "https://stashdb.org/graphql/", StashID: "scene-uuid"}}
+	service := newTestService(t, client)
+
+	// Until the first rebuild lookups go to Stash.
+	if _, err := service.HasCode(ctx, "ABC-123"); err != nil || len(client.filters) == 0 {
+		t.Fatalf("expected a live lookup before the index is built, err=%v", err)
+	}
+	status, err := service.Rebuild(ctx)
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if !status.Ready || status.SceneCount != 3 || status.StashIDCount != 1 || status.OshashCount != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	client.filters = nil
+	for code, want := range map[string]bool{"ABC-123": true, "xyz-042": true, "DEF-777": true, "ABC-124": false} {
+		if got, err := service.HasCode(ctx, code); err != nil || got != want {
+			t.Fatalf("HasCode(%q) = %v, %v; want %v", code, got, err, want)
+		}
+	}
+	if got, _ := service.HasStashID(ctx, "https://STASHDB.org/graphql", "scene-uuid"); !got {
+		t.Fatal("expected the stash id to be indexed")
+	}
+	if got, _ := service.HasOshash(ctx, "ABCDEF"); !got {
+		t.Fatal("expected the oshash to be indexed")
+	}
+	if len(client.filters) != 0 {
+		t.Fatalf("expected indexed lookups not to query Stash, got %d queries", len(client.filters))
+	}
+}
+
+func TestSyncAddsScenesUpdatedSinceLastSync(t *testing.T) {
+	ctx := context.Background()
+	updated := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{scenes: []*stashgraphql.LibrarySceneFragment{
+		libraryScene("1", "", "ABC-123", updated, "/library/ABC-123.mp4", ""),
+	}}
+	service := newTestService(t, client)
+	if err := service.refresh(ctx); err != nil {
+		t.Fatalf("initial refresh: %v", err)
+	}
+
+	client.scenes = append(client.scenes, libraryScene("2", "", "NEW-001", updated.Add(time.Hour), "/library/NEW-001.mp4", ""))
+	client.filters = nil
+	if err := service.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(client.filters) == 0 || client.filters[0] == nil || client.filters[0].UpdatedAt == nil {
+		t.Fatalf("expected an updated_at filtered query, got %+v", client.filters)
+	}
+	if got := client.filters[0].UpdatedAt.Value; got != updated.Add(-syncOverlap).Format(time.RFC3339) {
+		t.Fatalf("unexpected watermark %q", got)
+	}
+	if got, _ := service.HasCode(ctx, "NEW-001"); !got {
+		t.Fatal("expected the new scene to be indexed")
+	}
+
+	client.err = errors.New("stash down")
+	if err := service.Sync(ctx); err == nil {
+		t.Fatal("expected the sync error")
+	}
+	status, _ := service.Status(ctx)
+	if status.LastError != "stash down" || status.SceneCount != 2 {
+		t.Fatalf("expected a failed sync to keep the index, got %+v", status)
+	}
+}
+
+func TestSyncWaitsForTheFirstRebuild(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{scenes: []*stashgraphql.LibrarySceneFragment{
+		libraryScene("1", "", "ABC-123", time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), "/library/ABC-123.mp4", ""),
+	}}
+	service := newTestService(t, client)
+	if err := service.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(client.filters) != 0 {
+		t.Fatalf("expected the post-scan sync to leave the first rebuild to the background loop, got %d queries", len(client.filters))
+	}
+	if status, _ := service.Status(ctx); status.Ready {
+		t.Fatalf("expected the index to stay unbuilt, got %+v", status)
+	}
+}
+
+func TestRefreshDropsScenesDeletedInStash(t *testing.T) {
+	ctx := context.Background()
+	updated := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClient{scenes: []*stashgraphql.LibrarySceneFragment{
+		libraryScene("1", "", "ABC-123", updated, "/library/ABC-123.mp4", ""),
+		libraryScene("2", "", "DEF-456", updated, "/library/DEF-456.mp4", ""),
+	}}
+	service := newTestService(t, client)
+	now := updated
+	service.now = func() time.Time { return now }
+	if err := service.refresh(ctx); err != nil {
+		t.Fatalf("initial refresh: %v", err)
+	}
+
+	// A post-scan sync only upserts, so a deletion survives it.
+	client.scenes = client.scenes[1:]
+	if err := service.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got, _ := service.HasCode(ctx, "ABC-123"); !got {
+		t.Fatal("expected the post-scan sync not to rebuild")
+	}
+
+	// One deletion plus one addition: once the addition is indexed the
+	// index holds more scenes than Stash, so the refresh rebuilds.
+	client.scenes = append(client.scenes, libraryScene("3", "", "NEW-001", updated.Add(time.Hour), "/library/NEW-001.mp4", ""))
+	if err := service.refresh(ctx); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if got, _ := service.HasCode(ctx, "ABC-123"); got {
+		t.Fatal("expected the deleted scene to leave the index")
+	}
+	if got, _ := service.HasCode(ctx, "NEW-001"); !got {
+		t.Fatal("expected the added scene to be indexed")
+	}
+
+	// A scene imported with an update time behind the watermark leaves the
+	// index short of Stash, which also triggers a rebuild.
+	client.scenes = append(client.scenes, libraryScene("4", "", "GHI-789", updated.Add(-time.Hour), "/library/GHI-789.mp4", ""))
+	if err := service.refresh(ctx); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if got, _ := service.HasCode(ctx, "GHI-789"); !got {
+		t.Fatal("expected the rebuild to index the back-dated scene")
+	}
+}
+
+func libraryScene(id, title, code string, updated time.Time, path, oshash string) *stashgraphql.LibrarySceneFragment {
+	scene := &stashgraphql.LibrarySceneFragment{ID: id, UpdatedAt: updated}
+	if title != "" {
+		scene.Title = &title
+	}
+	if code != "" {
+		scene.Code = &code
+	}
+	file := &stashgraphql.LibrarySceneFragment_Files{Path: path, Basename: filepath.Base(path)}
+	if oshash != "" {
+		file.Fingerprints = []*stashgraphql.LibrarySceneFragment_Files_Fingerprints{{Type: "oshash", Value: oshash}}
+	}
+	scene.Files = []*stashgraphql.LibrarySceneFragment_Files{file}
+	return scene
+}
+
+type fakeClient struct {
+	scenes  []*stashgraphql.LibrarySceneFragment
+	filters []*stashgraphql.SceneFilterType
+	err     error
+}
+
+func (f *fakeClient) FindLibraryScenes(_ context.Context, sceneFilter *stashgraphql.SceneFilterType, filter *stashgraphql.FindFilterType) (int, []*stashgraphql.LibrarySceneFragment, error) {
+	f.filters = append(f.filters, sceneFilter)
+	if f.err != nil {
+		return 0, nil, f.err
+	}
+	var matched []*stashgraphql.LibrarySceneFragment
+	for _, scene := range f.scenes {
+		if sceneFilter != nil && sceneFilter.UpdatedAt != nil {
+			since, _ := time.Parse(time.RFC3339, sceneFilter.UpdatedAt.Value)
+			if !scene.UpdatedAt.After(since) {
+				continue
+			}
+		}
+		if sceneFilter != nil && sceneFilter.Code != nil {
+			continue
+		}
+		matched = append(matched, scene)
+	}
+	start := (*filter.Page - 1) * *filter.PerPage
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := min(start+*filter.PerPage, len(matched))
+	return len(matched), matched[start:end], nil
+}
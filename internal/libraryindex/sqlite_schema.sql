@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS library_index_meta (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS library_scenes (
+    scene_id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    code TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS library_scene_keys (
+    kind TEXT NOT NULL,
+    value TEXT NOT NULL,
+    scene_id TEXT NOT NULL REFERENCES library_scenes(scene_id) ON DELETE CASCADE,
+    PRIMARY KEY (kind, value, scene_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_library_scene_keys_scene_id
+    ON library_scene_keys(scene_id);
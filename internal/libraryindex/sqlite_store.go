@@ -0,0 +1,243 @@
+package libraryindex
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+const sqliteSchemaVersion = "1"
+
+const (
+	metaLastRebuildAt = "last_rebuild_at"
+	metaLastSyncAt    = "last_sync_at"
+	metaWatermark     = "watermark"
+)
+
+type sqliteStore struct {
+	db   *sqlx.DB
+	path string
+}
+
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("libraryindex: database path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("libraryindex: create database directory: %w", err)
+		}
+	}
+	db, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("libraryindex: open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	for _, pragma := range []string{"PRAGMA foreign_keys = ON", "PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("libraryindex: configure database: %w", err)
+		}
+	}
+	store := &sqliteStore{db: db, path: path}
+	if err := store.initSchema(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *sqliteStore) initSchema() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS library_index_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`); err != nil {
+		return err
+	}
+	var version string
+	err := s.db.Get(&version, `SELECT value FROM library_index_meta WHERE key = 'schema_version'`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if version != "" && version != sqliteSchemaVersion {
+		for _, table := range []string{"library_scene_keys", "library_scenes", "library_index_meta"} {
+			if _, err := s.db.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("libraryindex: initialize schema: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO library_index_meta(key,value) VALUES('schema_version',?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, sqliteSchemaVersion)
+	return err
+}
+
+func (s *sqliteStore) metaTime(ctx context.Context, key string) (*time.Time, error) {
+	var value string
+	err := s.db.GetContext(ctx, &value, `SELECT value FROM library_index_meta WHERE key=?`, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("libraryindex: parse %s: %w", key, err)
+	}
+	return &parsed, nil
+}
+
+// replaceAll swaps the whole index for scenes in one transaction.
+func (s *sqliteStore) replaceAll(ctx context.Context, scenes []scene, now time.Time) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"library_scene_keys", "library_scenes"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+			return err
+		}
+	}
+	if err := putScenes(ctx, tx, scenes); err != nil {
+		return err
+	}
+	if err := setMeta(ctx, tx, metaLastRebuildAt, formatTime(now)); err != nil {
+		return err
+	}
+	if err := setMeta(ctx, tx, metaLastSyncAt, formatTime(now)); err != nil {
+		return err
+	}
+	if err := setMeta(ctx, tx, metaWatermark, formatTime(latestUpdate(scenes, time.Time{}))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsert replaces the given scenes and advances the sync watermark.
+func (s *sqliteStore) upsert(ctx context.Context, scenes []scene, now time.Time) error {
+	watermark, err := s.metaTime(ctx, metaWatermark)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, item := range scenes {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM library_scenes WHERE scene_id=?`, item.ID); err != nil {
+			return err
+		}
+	}
+	if err := putScenes(ctx, tx, scenes); err != nil {
+		return err
+	}
+	if err := setMeta(ctx, tx, metaLastSyncAt, formatTime(now)); err != nil {
+		return err
+	}
+	previous := time.Time{}
+	if watermark != nil {
+		previous = *watermark
+	}
+	if err := setMeta(ctx, tx, metaWatermark, formatTime(latestUpdate(scenes, previous))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func putScenes(ctx context.Context, tx *sqlx.Tx, scenes []scene) error {
+	for _, item := range scenes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO library_scenes(scene_id,title,code,updated_at) VALUES(?,?,?,?)`, item.ID, item.Title, item.Code, formatTime(item.UpdatedAt)); err != nil {
+			return err
+		}
+		for _, key := range item.Keys {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO library_scene_keys(kind,value,scene_id) VALUES(?,?,?)`, key.Kind, key.Value, item.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setMeta(ctx context.Context, tx *sqlx.Tx, key, value string) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO library_index_meta(key,value) VALUES(?,?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, key, value)
+	return err
+}
+
+func (s *sqliteStore) hasKey(ctx context.Context, kinds []KeyKind, values []string) (bool, error) {
+	if len(kinds) == 0 || len(values) == 0 {
+		return false, nil
+	}
+	query, args, err := sqlx.In(`SELECT COUNT(*) FROM library_scene_keys WHERE kind IN (?) AND value IN (?)`, kinds, values)
+	if err != nil {
+		return false, err
+	}
+	var count int
+	if err := s.db.GetContext(ctx, &count, s.db.Rebind(query), args...); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *sqliteStore) sceneCount(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM library_scenes`)
+	return count, err
+}
+
+func (s *sqliteStore) status(ctx context.Context) (Status, error) {
+	status := Status{DatabasePath: s.path}
+	var err error
+	if status.SceneCount, err = s.sceneCount(ctx); err != nil {
+		return status, err
+	}
+	for _, count := range []struct {
+		kinds []KeyKind
+		out   *int
+	}{
+		{kinds: codeKinds, out: &status.CodeCount},
+		{kinds: []KeyKind{KeyStashID}, out: &status.StashIDCount},
+		{kinds: []KeyKind{KeyOshash}, out: &status.OshashCount},
+	} {
+		query, args, err := sqlx.In(`SELECT COUNT(DISTINCT value) FROM library_scene_keys WHERE kind IN (?)`, count.kinds)
+		if err != nil {
+			return status, err
+		}
+		if err := s.db.GetContext(ctx, count.out, s.db.Rebind(query), args...); err != nil {
+			return status, err
+		}
+	}
+	if status.LastRebuildAt, err = s.metaTime(ctx, metaLastRebuildAt); err != nil {
+		return status, err
+	}
+	if status.LastSyncAt, err = s.metaTime(ctx, metaLastSyncAt); err != nil {
+		return status, err
+	}
+	status.Ready = status.LastRebuildAt != nil
+	return status, nil
+}
+
+func latestUpdate(scenes []scene, since time.Time) time.Time {
+	for _, item := range scenes {
+		if item.UpdatedAt.After(since) {
+			since = item.UpdatedAt
+		}
+	}
+	return since
+}
+
+func formatTime(value time.Time) string { return value.UTC().Format(time.RFC3339Nano) }
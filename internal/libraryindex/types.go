@@ -0,0 +1,65 @@
+package libraryindex
+
+import (
+	"context"
+	"time"
+
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+const (
+	PageSize = 200
+	// syncOverlap re-reads scenes updated shortly before the last sync so
+	// that updates sharing its timestamp are not missed.
+	syncOverlap = time.Minute
+	// syncInterval is how often the index picks up scenes added, changed or
+	// deleted in Stash outside Moji.
+	syncInterval = 10 * time.Minute
+	// rebuildInterval bounds how long the index can drift from Stash when
+	// its scene count happens to match.
+	rebuildInterval = 24 * time.Hour
+)
+
+type Client interface {
+	FindLibraryScenes(ctx context.Context, sceneFilter *stashgraphql.SceneFilterType, filter *stashgraphql.FindFilterType) (int, []*stashgraphql.LibrarySceneFragment, error)
+}
+
+// KeyKind is what an index key was taken from.
+type KeyKind string
+
+const (
+	KeyCode      KeyKind = "CODE"
+	KeyPathCode  KeyKind = "PATH_CODE"
+	KeyTitleCode KeyKind = "TITLE_CODE"
+	KeyStashID   KeyKind = "STASH_ID"
+	KeyOshash    KeyKind = "OSHASH"
+)
+
+var codeKinds = []KeyKind{KeyCode, KeyPathCode, KeyTitleCode}
+
+type Status struct {
+	SceneCount    int
+	CodeCount     int
+	StashIDCount  int
+	OshashCount   int
+	DatabasePath  string
+	Ready         bool
+	Rebuilding    bool
+	LastRebuildAt *time.Time
+	LastSyncAt    *time.Time
+	LastError     string
+}
+
+// scene is one indexed Stash scene and the keys it can be found by.
+type scene struct {
+	ID        string
+	Title     string
+	Code      string
+	UpdatedAt time.Time
+	Keys      []sceneKey
+}
+
+type sceneKey struct {
+	Kind  KeyKind
+	Value string
+}
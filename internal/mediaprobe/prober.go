@@ -0,0 +1,186 @@
+package mediaprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/command"
+)
+
+var ErrNoVideo = errors.New("no video file found")
+
+// Settings is read for every probe so config changes apply without a
+// restart.
+type Settings struct {
+	Enabled     bool
+	FFprobePath string
+	Timeout     time.Duration
+}
+
+type ConfigProvider func() Settings
+
+// Result describes the main video stream and container of a file. Codecs are
+// ffprobe codec names such as "h264" or "aac".
+type Result struct {
+	Path            string
+	Container       string
+	DurationSeconds float64
+	Width           int
+	Height          int
+	VideoCodec      string
+	AudioCodec      string
+	BitRate         int64
+}
+
+type Prober struct {
+	settings ConfigProvider
+}
+
+func New(provider ConfigProvider) *Prober {
+	if provider == nil {
+		provider = func() Settings { return Settings{} }
+	}
+	return &Prober{settings: provider}
+}
+
+func (p *Prober) Enabled() bool {
+	return p != nil && p.settings().Enabled
+}
+
+// Probe runs ffprobe on path, or on the largest video file below it when path
+// is a directory.
+func (p *Prober) Probe(ctx context.Context, path string) (Result, error) {
+	settings := p.settings()
+	video, err := mainVideo(path)
+	if err != nil {
+		return Result{Path: path}, err
+	}
+	binary := strings.TrimSpace(settings.FFprobePath)
+	if binary == "" {
+		binary = "ffprobe"
+	}
+	timeout := settings.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	var stdout bytes.Buffer
+	args := []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", video}
+	if err := command.Run(ctx, timeout, binary, args, command.Options{Stdout: &stdout}); err != nil {
+		return Result{Path: video}, fmt.Errorf("ffprobe: %w", err)
+	}
+	result, err := parseOutput(stdout.Bytes())
+	result.Path = video
+	return result, err
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Duration  string `json:"duration"`
+		BitRate   string `json:"bit_rate"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+func parseOutput(data []byte) (Result, error) {
+	var output ffprobeOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return Result{}, fmt.Errorf("decode ffprobe output: %w", err)
+	}
+	result := Result{
+		Container:       containerName(output.Format.FormatName),
+		DurationSeconds: parseFloat(output.Format.Duration),
+		BitRate:         int64(parseFloat(output.Format.BitRate)),
+	}
+	for _, stream := range output.Streams {
+		switch stream.CodecType {
+		case "video":
+			// Cover art is reported as a video stream; keep the largest.
+			if result.VideoCodec != "" && stream.Width*stream.Height <= result.Width*result.Height {
+				continue
+			}
+			result.VideoCodec = stream.CodecName
+			result.Width = stream.Width
+			result.Height = stream.Height
+			if result.DurationSeconds == 0 {
+				result.DurationSeconds = parseFloat(stream.Duration)
+			}
+		case "audio":
+			if result.AudioCodec == "" {
+				result.AudioCodec = stream.CodecName
+			}
+		}
+	}
+	if result.VideoCodec == "" {
+		return result, errors.New("ffprobe found no video stream")
+	}
+	return result, nil
+}
+
+// containerName picks the first of ffprobe's comma separated format names,
+// e.g. "mov" from "mov,mp4,m4a,3gp,3g2,mj2".
+func containerName(formats string) string {
+	name, _, _ := strings.Cut(formats, ",")
+	return strings.TrimSpace(name)
+}
+
+func parseFloat(value string) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+var videoExts = map[string]bool{".mp4": true, ".mkv": true, ".avi": true, ".wmv": true, ".mov": true, ".ts": true, ".m2ts": true, ".m4v": true, ".webm": true}
+
+func mainVideo(root string) (string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return root, nil
+	}
+	var largest string
+	var largestSize int64
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !videoExts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > largestSize || largest == "" {
+			largest, largestSize = path, info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if largest == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoVideo, root)
+	}
+	return largest, nil
+}
@@ -0,0 +1,74 @@
+package mediaprobe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleOutput = `{
+  "streams": [
+    {"codec_type": "video", "codec_name": "mjpeg", "width": 800, "height": 538},
+    {"codec_type": "video", "codec_name": "hevc", "width": 3840, "height": 2160, "duration": "7201.5"},
+    {"codec_type": "audio", "codec_name": "aac"}
+  ],
+  "format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "7200.040000", "bit_rate": "15000000"}
+}`
+
+func TestParseOutputPicksMainVideoStream(t *testing.T) {
+	result, err := parseOutput([]byte(sampleOutput))
+	if err != nil {
+		t.Fatalf("parseOutput: %v", err)
+	}
+	want := Result{Container: "mov", DurationSeconds: 7200.04, Width: 3840, Height: 2160, VideoCodec: "hevc", AudioCodec: "aac", BitRate: 15000000}
+	if result != want {
+		t.Fatalf("got %+v, want %+v", result, want)
+	}
+	if _, err := parseOutput([]byte(`{"streams": [{"codec_type": "audio", "codec_name": "flac"}]}`)); err == nil {
+		t.Fatal("expected an error without a video stream")
+	}
+}
+
+func TestProbeRunsFFprobeOnLargestVideo(t *testing.T) {
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+	dir := t.TempDir()
+	fake := filepath.Join(dir, "ffprobe")
+	script := "#!" + shell + "\nfor last; do :; done\necho \"$last\" > \"$0.args\"\ncat <<'EOF'\n" + sampleOutput + "\nEOF\n"
+	if err := os.WriteFile(fake, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := filepath.Join(dir, "SSIS-200")
+	if err := os.MkdirAll(content, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"sample.mp4": 10, "SSIS-200.mkv": 100, "cover.jpg": 1000} {
+		if err := os.WriteFile(filepath.Join(content, name), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prober := New(func() Settings { return Settings{Enabled: true, FFprobePath: fake, Timeout: 5 * time.Second} })
+	result, err := prober.Probe(context.Background(), content)
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if result.Path != filepath.Join(content, "SSIS-200.mkv") || result.Height != 2160 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	args, _ := os.ReadFile(fake + ".args")
+	if strings.TrimSpace(string(args)) != result.Path {
+		t.Fatalf("ffprobe ran on %q", args)
+	}
+
+	if _, err := prober.Probe(context.Background(), t.TempDir()); !errors.Is(err, ErrNoVideo) {
+		t.Fatalf("expected ErrNoVideo, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -206,23 +207,51 @@ func (s *Service) LoadPerformerScenes(ctx context.Context, target *MatchedPerfor
 		return stashboxcache.Result{Complete: true}, nil
 	}
 	if s.cache == nil {
-		result := stashboxcache.Result{}
-		for pageNumber := 1; ; pageNumber++ {
-			page, err := target.Client.QueryScenesPage(ctx, stashboxgraphql.SceneQueryInput{
-				Performers: &stashboxgraphql.MultiIDCriterionInput{Value: []string{target.Performer.ID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
-				Page:       pageNumber, PerPage: stashboxcache.PageSize, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate,
-			})
-			if err != nil {
-				return stashboxcache.Result{}, err
-			}
-			result.Scenes = append(result.Scenes, page.Scenes...)
-			result.RemoteCount = page.Count
-			result.LoadedCount = len(result.Scenes)
-			result.Complete = result.LoadedCount >= result.RemoteCount || len(page.Scenes) == 0
-			if result.Complete || result.LoadedCount >= minimum {
-				return result, nil
-			}
-		}
+		return loadScenesUncached(ctx, target.Client, stashboxgraphql.SceneQueryInput{
+			Performers: &stashboxgraphql.MultiIDCriterionInput{Value: []string{target.Performer.ID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
+		}, minimum)
 	}
 	return s.cache.Load(ctx, target.Client, target.Endpoint, target.Performer.ID, minimum, policy)
 }
+
+// LoadStudioScenes loads the scenes of a StashBox studio, newest first.
+func (s *Service) LoadStudioScenes(ctx context.Context, endpoint, studioID string, minimum int, policy CachePolicy) (stashboxcache.Result, error) {
+	client, ok := s.registry.Get(endpoint)
+	if !ok {
+		return stashboxcache.Result{}, fmt.Errorf("metadata: stash-box endpoint %q is not configured", endpoint)
+	}
+	if s.cache == nil {
+		return loadScenesUncached(ctx, client, stashboxgraphql.SceneQueryInput{
+			Studios: &stashboxgraphql.MultiIDCriterionInput{Value: []string{studioID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
+		}, minimum)
+	}
+	return s.cache.LoadStudio(ctx, client, endpoint, studioID, minimum, policy)
+}
+
+// FindStudio looks a studio up on one configured StashBox endpoint.
+func (s *Service) FindStudio(ctx context.Context, endpoint, studioID string) (*stashboxgraphql.StudioFragment, error) {
+	client, ok := s.registry.Get(endpoint)
+	if !ok {
+		return nil, fmt.Errorf("metadata: stash-box endpoint %q is not configured", endpoint)
+	}
+	return client.FindStudioByID(ctx, studioID)
+}
+
+func loadScenesUncached(ctx context.Context, client Client, input stashboxgraphql.SceneQueryInput, minimum int) (stashboxcache.Result, error) {
+	result := stashboxcache.Result{}
+	input.PerPage, input.Direction, input.Sort = stashboxcache.PageSize, stashboxgraphql.SortDirectionEnumDesc, stashboxgraphql.SceneSortEnumDate
+	for pageNumber := 1; ; pageNumber++ {
+		input.Page = pageNumber
+		page, err := client.QueryScenesPage(ctx, input)
+		if err != nil {
+			return stashboxcache.Result{}, err
+		}
+		result.Scenes = append(result.Scenes, page.Scenes...)
+		result.RemoteCount = page.Count
+		result.LoadedCount = len(result.Scenes)
+		result.Complete = result.LoadedCount >= result.RemoteCount || len(page.Scenes) == 0
+		if result.Complete || result.LoadedCount >= minimum {
+			return result, nil
+		}
+	}
+}
@@ -17,6 +17,7 @@ type Client interface {
 	SearchScene(context.Context, string) ([]*stashboxgraphql.SceneFragment, error)
 	QueryScenes(context.Context, stashboxgraphql.SceneQueryInput) ([]*stashboxgraphql.SceneFragment, error)
 	QueryScenesPage(context.Context, stashboxgraphql.SceneQueryInput) (stashboxpkg.ScenePage, error)
+	FindStudioByID(context.Context, string) (*stashboxgraphql.StudioFragment, error)
 }
 
 // StashBoxEndpoint is the public description of a Stash-Box instance. The API
@@ -177,3 +177,7 @@ func (*registryTestClient) QueryScenes(context.Context, stashboxgraphql.SceneQue
 func (*registryTestClient) QueryScenesPage(context.Context, stashboxgraphql.SceneQueryInput) (stashboxpkg.ScenePage, error) {
 	return stashboxpkg.ScenePage{}, nil
 }
+
+func (*registryTestClient) FindStudioByID(context.Context, string) (*stashboxgraphql.StudioFragment, error) {
+	return nil, nil
+}
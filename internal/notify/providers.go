@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// Provider delivers a rendered message to one kind of chat service.
+type Provider interface {
+	Send(ctx context.Context, client *http.Client, channel Channel, message Message) error
+}
+
+var providers = map[ChannelType]Provider{
+	ChannelTelegram: telegramProvider{},
+	ChannelDiscord:  discordProvider{},
+	ChannelNtfy:     ntfyProvider{},
+	ChannelGotify:   gotifyProvider{},
+}
+
+// telegramProvider calls the Bot API sendMessage method. Token is the bot
+// token and ChatID the destination chat; URL optionally points at a
+// self-hosted Bot API server.
+type telegramProvider struct{}
+
+func (telegramProvider) Send(ctx context.Context, client *http.Client, channel Channel, message Message) error {
+	base := strings.TrimRight(channel.URL, "/")
+	if base == "" {
+		base = telegramAPIURL
+	}
+	body, err := json.Marshal(map[string]any{
+		"chat_id": channel.ChatID,
+		"text":    message.text(),
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/bot"+channel.Token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return do(client, request, "telegram")
+}
+
+// discordProvider posts an embed to a Discord channel webhook URL.
+type discordProvider struct{}
+
+func (discordProvider) Send(ctx context.Context, client *http.Client, channel Channel, message Message) error {
+	body, err := json.Marshal(map[string]any{
+		"username": "Moji",
+		"embeds": []map[string]string{{
+			"title":       message.Title,
+			"description": message.Body,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return do(client, request, "discord")
+}
+
+// ntfyProvider publishes to URL/Topic. Token, when set, is sent as a bearer
+// access token and Priority is mapped onto ntfy's 1–5 scale.
+type ntfyProvider struct{}
+
+func (ntfyProvider) Send(ctx context.Context, client *http.Client, channel Channel, message Message) error {
+	target := strings.TrimRight(channel.URL, "/") + "/" + url.PathEscape(channel.Topic)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(message.Body))
+	if err != nil {
+		return err
+	}
+	if message.Title != "" {
+		request.Header.Set("Title", message.Title)
+	}
+	if channel.Priority > 0 {
+		request.Header.Set("Priority", strconv.Itoa(min(channel.Priority, 5)))
+	}
+	if channel.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+channel.Token)
+	}
+	return do(client, request, "ntfy")
+}
+
+// gotifyProvider posts to URL/message using Token as the application token.
+type gotifyProvider struct{}
+
+func (gotifyProvider) Send(ctx context.Context, client *http.Client, channel Channel, message Message) error {
+	body, err := json.Marshal(map[string]any{
+		"title":    message.Title,
+		"message":  message.Body,
+		"priority": channel.Priority,
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(channel.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Gotify-Key", channel.Token)
+	return do(client, request, "gotify")
+}
+
+func do(client *http.Client, request *http.Request, provider string) error {
+	request.Header.Set("User-Agent", "Moji-Notify")
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("notify: %s: %w", provider, redact(err))
+	}
+	defer response.Body.Close()
+	payload, _ := io.ReadAll(io.LimitReader(response.Body, 4<<10))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		detail := strings.TrimSpace(string(payload))
+		if len(detail) > 300 {
+			detail = detail[:300] + "..."
+		}
+		if detail == "" {
+			return fmt.Errorf("notify: %s: HTTP %d", provider, response.StatusCode)
+		}
+		return fmt.Errorf("notify: %s: HTTP %d: %s", provider, response.StatusCode, detail)
+	}
+	return nil
+}
+
+// redact strips the request URL from transport errors; Telegram carries the
+// bot token in the path and Discord webhook URLs are secrets themselves.
+func redact(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
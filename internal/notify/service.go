@@ -0,0 +1,281 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stats"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+// StatsProvider supplies the current service health; service status bus
+// events only name the services whose state changed.
+type StatsProvider interface {
+	SnapshotView() stats.Snapshot
+}
+
+// Service turns task and service status bus events into chat notifications.
+// Bus events only carry the current state, so the service remembers what it
+// last saw per task and per service and notifies on the edges. Sends go
+// through a bounded queue drained by one worker; when the queue is full the
+// notification is dropped rather than stalling the bus subscriber.
+type Service struct {
+	settings    ConfigProvider
+	client      *http.Client
+	now         func() time.Time
+	taskEvents  taskruntime.TaskEventSource
+	services    stats.ServiceStatusEventSource
+	statsSource StatsProvider
+	queue       chan delivery
+
+	mu        sync.Mutex
+	startedAt time.Time
+	tasks     map[string]taskState
+	health    map[stats.ExternalService]bool
+}
+
+type taskState struct {
+	downloaded bool
+	blocked    bool
+}
+
+type delivery struct {
+	channel Channel
+	message Message
+	event   EventType
+}
+
+type Option func(*Service)
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(service *Service) {
+		if client != nil {
+			service.client = client
+		}
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(service *Service) {
+		if now != nil {
+			service.now = now
+		}
+	}
+}
+
+func WithTaskEvents(source taskruntime.TaskEventSource) Option {
+	return func(service *Service) {
+		if source != nil {
+			service.taskEvents = source
+		}
+	}
+}
+
+func WithServiceStatusEvents(source stats.ServiceStatusEventSource, provider StatsProvider) Option {
+	return func(service *Service) {
+		if source != nil && provider != nil {
+			service.services = source
+			service.statsSource = provider
+		}
+	}
+}
+
+func New(provider ConfigProvider, options ...Option) *Service {
+	if provider == nil {
+		provider = func() Settings { return Settings{} }
+	}
+	service := &Service{
+		settings: provider,
+		client:   &http.Client{},
+		now:      time.Now,
+		queue:    make(chan delivery, queueCapacity),
+		tasks:    make(map[string]taskState),
+		health:   make(map[stats.ExternalService]bool),
+	}
+	for _, option := range options {
+		option(service)
+	}
+	return service
+}
+
+// Start subscribes to the configured event buses and runs the send worker
+// until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.startedAt = s.now()
+	s.mu.Unlock()
+	if s.taskEvents != nil {
+		events := s.taskEvents.Subscribe(ctx)
+		go func() {
+			for event := range events {
+				for _, notification := range s.observeTask(event) {
+					s.Notify(notification)
+				}
+			}
+		}()
+	}
+	if s.services != nil {
+		events := s.services.Subscribe(ctx)
+		go func() {
+			for event := range events {
+				for _, notification := range s.observeServices(event) {
+					s.Notify(notification)
+				}
+			}
+		}()
+	}
+	go s.run(ctx)
+}
+
+func (s *Service) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-s.queue:
+			if err := s.send(ctx, next.channel, next.message); err != nil && ctx.Err() == nil {
+				logging.Warnf("notify: send %s to channel=%s: %v", next.event, next.channel.Name, err)
+			}
+		}
+	}
+}
+
+// Notify renders event with the configured template and queues it for every
+// channel subscribed to its type.
+func (s *Service) Notify(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = s.now()
+	}
+	settings := s.settings()
+	var message *Message
+	for _, channel := range settings.Channels {
+		if !channel.Accepts(event.Type) {
+			continue
+		}
+		if message == nil {
+			rendered, err := Render(settings.Templates, event)
+			if err != nil {
+				logging.Warnf("%v", err)
+				return
+			}
+			message = &rendered
+		}
+		select {
+		case s.queue <- delivery{channel: channel, message: *message, event: event.Type}:
+		default:
+			logging.Warnf("notify: queue full, dropping %s for channel=%s", event.Type, channel.Name)
+		}
+	}
+}
+
+// Test sends a fixed message to channel synchronously so the settings UI can
+// report the provider's answer.
+func (s *Service) Test(ctx context.Context, channel Channel) error {
+	return s.send(ctx, channel, Message{
+		Title: "Moji test notification",
+		Body:  fmt.Sprintf("Channel %q is configured correctly.", channel.Name),
+	})
+}
+
+func (s *Service) send(ctx context.Context, channel Channel, message Message) error {
+	provider, ok := providers[channel.Type]
+	if !ok {
+		return errors.New("notify: unsupported channel type " + string(channel.Type))
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	return provider.Send(sendCtx, s.client, channel, message)
+}
+
+// observeTask reports the notifications implied by one task event. A task
+// seen for the first time only notifies when the edge happened after Start,
+// so the progress sync after a restart does not replay old downloads.
+func (s *Service) observeTask(event *taskruntime.TaskEvent) []Event {
+	if event == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.Type == taskruntime.TaskEventDeleted || event.Task == nil {
+		delete(s.tasks, event.TaskID)
+		return nil
+	}
+	task := event.Task
+	previous, seen := s.tasks[task.ID]
+	current := taskState{
+		downloaded: task.DownloadCompletedAt != nil,
+		blocked:    task.StageStatus == taskruntime.TaskStageStatusBlocked,
+	}
+	s.tasks[task.ID] = current
+	fresh := func(at time.Time) bool { return seen || !at.Before(s.startedAt) }
+
+	var out []Event
+	if current.downloaded && !previous.downloaded && task.Source == taskruntime.TaskSourceSubscription && fresh(*task.DownloadCompletedAt) {
+		out = append(out, taskNotification(EventReleaseDownloaded, task, *task.DownloadCompletedAt))
+	}
+	if current.blocked && !previous.blocked && fresh(task.UpdatedAt) {
+		out = append(out, taskNotification(EventTaskBlocked, task, task.UpdatedAt))
+	}
+	return out
+}
+
+func taskNotification(eventType EventType, task *taskruntime.Task, at time.Time) Event {
+	return Event{
+		Type:         eventType,
+		OccurredAt:   at,
+		TaskID:       task.ID,
+		Code:         task.Code,
+		Stage:        string(task.Stage),
+		TorrentName:  task.TorrentName,
+		ContentPath:  task.ContentPath,
+		ErrorMessage: task.StageErrorMessage,
+	}
+}
+
+// observeServices compares the health of the changed services against the
+// last observation. A service that is down when first observed notifies; one
+// that is healthy when first observed does not.
+func (s *Service) observeServices(event *stats.ServiceStatusEvent) []Event {
+	if event == nil || s.statsSource == nil {
+		return nil
+	}
+	snapshot := s.statsSource.SnapshotView()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Event
+	for _, service := range event.Services {
+		lastError := serviceError(&snapshot, service)
+		healthy := lastError == ""
+		previous, known := s.health[service]
+		s.health[service] = healthy
+		notification := Event{OccurredAt: event.ObservedAt, Service: string(service), ErrorMessage: lastError}
+		switch {
+		case !healthy && (!known || previous):
+			notification.Type = EventServiceDown
+		case healthy && known && !previous:
+			notification.Type = EventServiceRecovered
+		default:
+			continue
+		}
+		out = append(out, notification)
+	}
+	return out
+}
+
+func serviceError(snapshot *stats.Snapshot, service stats.ExternalService) string {
+	switch service {
+	case stats.ExternalServiceStash:
+		return snapshot.Stash.LastError
+	case stats.ExternalServiceJackett:
+		return snapshot.Jackett.LastError
+	case stats.ExternalServiceQBittorrent:
+		return snapshot.QBitt.LastError
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/stats"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+type capturedRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+func captureServer(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{path: r.URL.Path, header: r.Header.Clone(), body: string(body)}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"chat not found"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestProvidersShapeRequests(t *testing.T) {
+	message := Message{Title: "Moji: ABC-123 downloaded", Body: "ABC-123 finished downloading"}
+	for _, test := range []struct {
+		channel Channel
+		check   func(t *testing.T, got capturedRequest)
+	}{
+		{Channel{Type: ChannelTelegram, Token: "123:abc", ChatID: "42"}, func(t *testing.T, got capturedRequest) {
+			var payload map[string]string
+			_ = json.Unmarshal([]byte(got.body), &payload)
+			if got.path != "/bot123:abc/sendMessage" || payload["chat_id"] != "42" || payload["text"] != message.Title+"\n"+message.Body {
+				t.Fatalf("unexpected telegram request: %+v", got)
+			}
+		}},
+		{Channel{Type: ChannelDiscord}, func(t *testing.T, got capturedRequest) {
+			if !strings.Contains(got.body, `"description":"ABC-123 finished downloading"`) {
+				t.Fatalf("unexpected discord body: %s", got.body)
+			}
+		}},
+		{Channel{Type: ChannelNtfy, Topic: "moji alerts", Token: "tk", Priority: 8}, func(t *testing.T, got capturedRequest) {
+			if got.path != "/moji alerts" || got.header.Get("Title") != message.Title || got.header.Get("Priority") != "5" || got.header.Get("Authorization") != "Bearer tk" || got.body != message.Body {
+				t.Fatalf("unexpected ntfy request: %+v", got)
+			}
+		}},
+		{Channel{Type: ChannelGotify, Token: "app", Priority: 7}, func(t *testing.T, got capturedRequest) {
+			if got.path != "/message" || got.header.Get("X-Gotify-Key") != "app" || !strings.Contains(got.body, `"priority":7`) {
+				t.Fatalf("unexpected gotify request: %+v", got)
+			}
+		}},
+	} {
+		t.Run(string(test.channel.Type), func(t *testing.T) {
+			server, requests := captureServer(t, http.StatusOK)
+			test.channel.URL = server.URL
+			if err := New(nil).send(context.Background(), test.channel, message); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			test.check(t, <-requests)
+		})
+	}
+}
+
+func TestTestReportsProviderError(t *testing.T) {
+	server, _ := captureServer(t, http.StatusBadRequest)
+	err := New(nil).Test(context.Background(), Channel{Name: "tg", Type: ChannelTelegram, URL: server.URL, Token: "t", ChatID: "1"})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 400") || !strings.Contains(err.Error(), "chat not found") {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+}
+
+func TestRenderUsesTemplatesAndFallsBack(t *testing.T) {
+	event := Event{Type: EventTaskBlocked, Code: "ABC-123", Stage: "DOWNLOADING", ErrorMessage: "no seeders"}
+	message, err := Render([]Template{{Event: EventTaskBlocked, Title: "{{.Code}} blocked", Body: "{{.Stage}}: {{.ErrorMessage}}"}}, event)
+	if err != nil || message.Title != "ABC-123 blocked" || message.Body != "DOWNLOADING: no seeders" {
+		t.Fatalf("unexpected message %+v, err %v", message, err)
+	}
+	message, err = Render(nil, event)
+	if err != nil || message.Title != string(EventTaskBlocked) {
+		t.Fatalf("expected fallback title, got %+v, err %v", message, err)
+	}
+}
+
+type staticStats struct{ snapshot *stats.Snapshot }
+
+func (s staticStats) SnapshotView() stats.Snapshot { return s.snapshot.Clone() }
+
+func TestServiceNotifiesOnTaskAndServiceEdges(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	service := New(nil, WithClock(func() time.Time { return now }))
+	service.startedAt = now
+
+	earlier := now.Add(-time.Hour)
+	if got := service.observeTask(&taskruntime.TaskEvent{Type: taskruntime.TaskEventUpdated, Task: &taskruntime.Task{ID: "old", Source: taskruntime.TaskSourceSubscription, DownloadCompletedAt: &earlier, UpdatedAt: earlier}}); len(got) != 0 {
+		t.Fatalf("download completed before start should not notify, got %+v", got)
+	}
+	task := &taskruntime.Task{ID: "t1", Code: "ABC-123", Source: taskruntime.TaskSourceSubscription, Stage: taskruntime.TaskStageDownloading, UpdatedAt: now}
+	if got := service.observeTask(&taskruntime.TaskEvent{Type: taskruntime.TaskEventCreated, Task: task}); len(got) != 0 {
+		t.Fatalf("new task should not notify, got %+v", got)
+	}
+	downloaded := *task
+	downloaded.DownloadCompletedAt = &now
+	if got := service.observeTask(&taskruntime.TaskEvent{Type: taskruntime.TaskEventUpdated, Task: &downloaded}); len(got) != 1 || got[0].Type != EventReleaseDownloaded || got[0].Code != "ABC-123" {
+		t.Fatalf("expected release downloaded, got %+v", got)
+	}
+	if got := service.observeTask(&taskruntime.TaskEvent{Type: taskruntime.TaskEventUpdated, Task: &downloaded}); len(got) != 0 {
+		t.Fatalf("repeat update should not notify again, got %+v", got)
+	}
+	blocked := downloaded
+	blocked.StageStatus = taskruntime.TaskStageStatusBlocked
+	blocked.StageErrorMessage = "transfer failed"
+	if got := service.observeTask(&taskruntime.TaskEvent{Type: taskruntime.TaskEventUpdated, Task: &blocked}); len(got) != 1 || got[0].Type != EventTaskBlocked || got[0].ErrorMessage != "transfer failed" {
+		t.Fatalf("expected task blocked, got %+v", got)
+	}
+
+	snapshot := &stats.Snapshot{}
+	service.statsSource = staticStats{snapshot: snapshot}
+	statusEvent := &stats.ServiceStatusEvent{Services: []stats.ExternalService{stats.ExternalServiceJackett}, ObservedAt: now}
+	if got := service.observeServices(statusEvent); len(got) != 0 {
+		t.Fatalf("healthy first observation should not notify, got %+v", got)
+	}
+	snapshot.Jackett.LastError = "connection refused"
+	if got := service.observeServices(statusEvent); len(got) != 1 || got[0].Type != EventServiceDown || got[0].Service != "JACKETT" {
+		t.Fatalf("expected service down, got %+v", got)
+	}
+	snapshot.Jackett.LastError = ""
+	if got := service.observeServices(statusEvent); len(got) != 1 || got[0].Type != EventServiceRecovered {
+		t.Fatalf("expected service recovered, got %+v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventReleaseDownloaded EventType = "RELEASE_DOWNLOADED"
+	EventTaskBlocked       EventType = "TASK_BLOCKED"
+	EventServiceDown       EventType = "SERVICE_DOWN"
+	EventServiceRecovered  EventType = "SERVICE_RECOVERED"
+	EventWatchlistMatch    EventType = "WATCHLIST_MATCH"
+)
+
+type ChannelType string
+
+const (
+	ChannelTelegram ChannelType = "TELEGRAM"
+	ChannelDiscord  ChannelType = "DISCORD"
+	ChannelNtfy     ChannelType = "NTFY"
+	ChannelGotify   ChannelType = "GOTIFY"
+)
+
+const (
+	sendTimeout   = 15 * time.Second
+	queueCapacity = 64
+)
+
+// Channel is one configured chat destination. The meaning of URL and Token
+// depends on Type; see the provider implementations. An empty Events list
+// subscribes the channel to every event type.
+type Channel struct {
+	Name     string
+	Type     ChannelType
+	Events   []EventType
+	URL      string
+	Token    string
+	ChatID   string
+	Topic    string
+	Priority int
+}
+
+func (c Channel) Accepts(eventType EventType) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, candidate := range c.Events {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Template holds the text/template sources for one event type. Both are
+// executed against Event.
+type Template struct {
+	Event EventType
+	Title string
+	Body  string
+}
+
+// Settings is the live notification configuration: the enabled channels and
+// one template per event type.
+type Settings struct {
+	Channels  []Channel
+	Templates []Template
+}
+
+type ConfigProvider func() Settings
+
+// Event is the template data for a notification. Task fields are empty for
+// service events and Service is empty for task events. Watchlist events fill
+// Watchlist, Title, Tracker and Link.
+type Event struct {
+	Type         EventType
+	OccurredAt   time.Time
+	TaskID       string
+	Code         string
+	Stage        string
+	TorrentName  string
+	ContentPath  string
+	Service      string
+	ErrorMessage string
+	Watchlist    string
+	Title        string
+	Tracker      string
+	Link         string
+}
+
+type Message struct {
+	Title string
+	Body  string
+}
+
+func (m Message) text() string {
+	if m.Title == "" {
+		return m.Body
+	}
+	if m.Body == "" {
+		return m.Title
+	}
+	return m.Title + "\n" + m.Body
+}
+
+// Render executes the template configured for event.Type, falling back to
+// the event type itself when no template is configured.
+func Render(templates []Template, event Event) (Message, error) {
+	selected := Template{Event: event.Type, Title: string(event.Type)}
+	for _, candidate := range templates {
+		if candidate.Event == event.Type {
+			selected = candidate
+			break
+		}
+	}
+	title, err := execute(selected.Title, event)
+	if err != nil {
+		return Message{}, fmt.Errorf("notify: render %s title: %w", event.Type, err)
+	}
+	body, err := execute(selected.Body, event)
+	if err != nil {
+		return Message{}, fmt.Errorf("notify: render %s body: %w", event.Type, err)
+	}
+	return Message{Title: title, Body: body}, nil
+}
+
+func execute(source string, event Event) (string, error) {
+	if strings.TrimSpace(source) == "" {
+		return "", nil
+	}
+	parsed, err := template.New("notification").Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, event); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,330 @@
+package performer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/stashboxcache"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
+)
+
+var (
+	ErrLibraryIndexUnavailable = errors.New("performer: library index is not configured")
+	// ErrInvalidMissingScenesQuery wraps report queries rejected before any
+	// lookup.
+	ErrInvalidMissingScenesQuery = errors.New("performer: invalid missing-scenes query")
+	// ErrMissingScenesSubjectNotFound is returned when Stash does not know
+	// the reported performer.
+	ErrMissingScenesSubjectNotFound = errors.New("performer: missing-scenes subject not found")
+	// ErrMissingScenesCatalogUnavailable wraps failures loading the subject
+	// or its catalog from Stash or StashBox.
+	ErrMissingScenesCatalogUnavailable = errors.New("performer: missing-scenes catalog unavailable")
+)
+
+// LibraryIndex answers whether the Stash library already owns a StashBox
+// scene, by stash_id or by code.
+type LibraryIndex interface {
+	HasStashID(ctx context.Context, endpoint, stashID string) (bool, error)
+	HasCode(ctx context.Context, code string) (bool, error)
+}
+
+// SceneClassifier labels a StashBox scene the way subscription releases are
+// classified (SOLO, SMALL_GROUP, ...).
+type SceneClassifier interface {
+	ClassifyScene(scene *stashboxgraphql.SceneFragment) string
+}
+
+func (s *Service) SetLibraryIndex(index LibraryIndex) {
+	if s == nil {
+		return
+	}
+	s.libraryIndex = index
+}
+
+func (s *Service) SetSceneClassifier(classifier SceneClassifier) {
+	if s == nil {
+		return
+	}
+	s.classifier = classifier
+}
+
+type MissingScenesSubject string
+
+const (
+	MissingScenesSubjectPerformer MissingScenesSubject = "PERFORMER"
+	MissingScenesSubjectStudio    MissingScenesSubject = "STUDIO"
+)
+
+// MissingScenesQuery selects the catalog to compare with the library. ID is
+// the Stash performer id for performers and the StashBox studio id on
+// Endpoint for studios. Dates are inclusive YYYY-MM-DD bounds.
+type MissingScenesQuery struct {
+	Subject         MissingScenesSubject
+	ID              string
+	Endpoint        string
+	DateFrom        string
+	DateTo          string
+	Classifications []string
+	Studios         []string
+}
+
+type MissingScene struct {
+	Scene
+	Classification string
+}
+
+// MissingScenesReport compares the loaded catalog with the library.
+// OwnedCount counts the owned scenes among those matching the filters.
+// CatalogPartial is set when StashBox stopped returning pages before the
+// whole catalog was loaded, so the report only covers CatalogCount scenes.
+type MissingScenesReport struct {
+	Query          MissingScenesQuery
+	Name           string
+	Endpoint       string
+	CatalogCount   int
+	CatalogPartial bool
+	OwnedCount     int
+	Items          []MissingScene
+	CacheUpdatedAt *time.Time
+	CacheStale     bool
+}
+
+// MissingScenesReport lists the scenes of a performer's or studio's full
+// StashBox catalog that the library owns neither by stash_id nor by code.
+// Filters are applied before ownership is looked up.
+func (s *Service) MissingScenesReport(ctx context.Context, query MissingScenesQuery) (MissingScenesReport, error) {
+	report, err := s.missingScenes(ctx, query, nil)
+	if err != nil {
+		return MissingScenesReport{}, err
+	}
+	for index := range report.Items {
+		item := &report.Items[index]
+		item.ImageURL = s.proxyStashBoxImage(ctx, item.StashBoxEndpoint, item.ImageURL)
+	}
+	return report, nil
+}
+
+// QueueMissingScenes queues report items through the same path as
+// QueuePerformerScenes. Without selections every item of the report is
+// queued, MaxQueueSceneBatchSize at a time.
+func (s *Service) QueueMissingScenes(ctx context.Context, query MissingScenesQuery, selections []QueueSceneSelection) (QueueScenesResult, error) {
+	var selected map[string]struct{}
+	if len(selections) > 0 {
+		if err := s.validateQueueSelections(selections); err != nil {
+			return QueueScenesResult{}, err
+		}
+		selected = make(map[string]struct{}, len(selections))
+		for _, selection := range selections {
+			selected[selection.Key] = struct{}{}
+		}
+	}
+	report, err := s.missingScenes(ctx, query, selected)
+	if err != nil {
+		return QueueScenesResult{}, err
+	}
+	scenes := make([]Scene, 0, len(report.Items))
+	for _, item := range report.Items {
+		scenes = append(scenes, item.Scene)
+	}
+	if len(selections) > 0 {
+		return s.queueSceneSelections(ctx, scenes, selections), nil
+	}
+
+	if len(scenes) == 0 {
+		return QueueScenesResult{}, ErrQueueSceneBatchEmpty
+	}
+	var result QueueScenesResult
+	for start := 0; start < len(scenes); start += MaxQueueSceneBatchSize {
+		batch := scenes[start:min(start+MaxQueueSceneBatchSize, len(scenes))]
+		batchSelections := make([]QueueSceneSelection, 0, len(batch))
+		for _, scene := range batch {
+			batchSelections = append(batchSelections, QueueSceneSelection{Key: scene.Key})
+		}
+		if err := s.validateQueueSelections(batchSelections); err != nil {
+			return QueueScenesResult{}, err
+		}
+		queued := s.queueSceneSelections(ctx, batch, batchSelections)
+		result.QueuedTasks = append(result.QueuedTasks, queued.QueuedTasks...)
+		result.Results = append(result.Results, queued.Results...)
+		result.Summary.RequestedCount += queued.Summary.RequestedCount
+		result.Summary.QueuedCount += queued.Summary.QueuedCount
+		result.Summary.SkippedCount += queued.Summary.SkippedCount
+		result.Summary.FailedCount += queued.Summary.FailedCount
+	}
+	return result, nil
+}
+
+// missingScenes builds the report without proxying images. A non-nil
+// selected limits the ownership lookups to the scenes with those keys.
+func (s *Service) missingScenes(ctx context.Context, query MissingScenesQuery, selected map[string]struct{}) (MissingScenesReport, error) {
+	query, err := normalizeMissingScenesQuery(query)
+	if err != nil {
+		return MissingScenesReport{}, err
+	}
+	if s.libraryIndex == nil {
+		return MissingScenesReport{}, ErrLibraryIndexUnavailable
+	}
+	report := MissingScenesReport{Query: query}
+	catalog, err := s.loadMissingScenesCatalog(ctx, &report)
+	if err != nil {
+		return MissingScenesReport{}, err
+	}
+	report.CatalogCount = len(catalog.Scenes)
+	if !catalog.UpdatedAt.IsZero() {
+		updated := catalog.UpdatedAt
+		report.CacheUpdatedAt = &updated
+	}
+	report.CacheStale = catalog.Stale
+	report.CatalogPartial = !catalog.Complete && catalog.LoadedCount < catalog.RemoteCount
+
+	report.Items = make([]MissingScene, 0)
+	for _, scene := range catalog.Scenes {
+		if scene == nil {
+			continue
+		}
+		item := MissingScene{Scene: stashBoxSceneToPerformerScene(scene, report.Endpoint)}
+		if _, ok := selected[item.Key]; selected != nil && !ok {
+			continue
+		}
+		if s.classifier != nil {
+			item.Classification = s.classifier.ClassifyScene(scene)
+		}
+		if !matchesMissingScenesQuery(item, query) {
+			continue
+		}
+		owned, err := s.ownsStashBoxScene(ctx, report.Endpoint, scene)
+		if err != nil {
+			return MissingScenesReport{}, err
+		}
+		if owned {
+			report.OwnedCount++
+			continue
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report, nil
+}
+
+func (s *Service) loadMissingScenesCatalog(ctx context.Context, report *MissingScenesReport) (stashboxcache.Result, error) {
+	query := report.Query
+	var load func(minimum int) (stashboxcache.Result, error)
+	switch query.Subject {
+	case MissingScenesSubjectPerformer:
+		performer, err := s.stash.FindPerformerByID(ctx, query.ID)
+		if err != nil {
+			return stashboxcache.Result{}, fmt.Errorf("%w: %w", ErrMissingScenesCatalogUnavailable, err)
+		}
+		if performer == nil {
+			return stashboxcache.Result{}, fmt.Errorf("%w: performer %q", ErrMissingScenesSubjectNotFound, query.ID)
+		}
+		target, err := s.metadata.ResolvePerformer(ctx, performer)
+		if err != nil {
+			return stashboxcache.Result{}, fmt.Errorf("%w: %w", ErrMissingScenesCatalogUnavailable, err)
+		}
+		report.Name, report.Endpoint = performer.Name, target.Endpoint
+		load = func(minimum int) (stashboxcache.Result, error) {
+			return s.metadata.LoadPerformerScenes(ctx, target, minimum, metadata.CachePreferred)
+		}
+	default:
+		report.Endpoint = metadata.NormalizeEndpoint(query.Endpoint)
+		load = func(minimum int) (stashboxcache.Result, error) {
+			return s.metadata.LoadStudioScenes(ctx, query.Endpoint, query.ID, minimum, metadata.CachePreferred)
+		}
+	}
+
+	// A cache-preferred load falls back to the stored snapshot when StashBox
+	// is unreachable, so stop once a pass adds nothing, the snapshot is stale
+	// or the request is gone, and report what was loaded as partial.
+	catalog, err := load(stashboxcache.PageSize)
+	for err == nil && !catalog.Complete && catalog.LoadedCount < catalog.RemoteCount && !catalog.Stale && ctx.Err() == nil {
+		loaded := catalog.LoadedCount
+		catalog, err = load(catalog.RemoteCount)
+		if err == nil && catalog.LoadedCount <= loaded {
+			break
+		}
+	}
+	if err != nil {
+		return stashboxcache.Result{}, fmt.Errorf("%w: %w", ErrMissingScenesCatalogUnavailable, err)
+	}
+	if report.Name == "" {
+		for _, scene := range catalog.Scenes {
+			if scene != nil && scene.Studio != nil {
+				report.Name = scene.Studio.Name
+				break
+			}
+		}
+	}
+	return catalog, nil
+}
+
+func (s *Service) ownsStashBoxScene(ctx context.Context, endpoint string, scene *stashboxgraphql.SceneFragment) (bool, error) {
+	owned, err := s.libraryIndex.HasStashID(ctx, endpoint, scene.ID)
+	if err != nil || owned {
+		return owned, err
+	}
+	code := buildReleaseCode(stringValue(scene.Code), stringValue(scene.Title))
+	if code == "" {
+		return false, nil
+	}
+	return s.libraryIndex.HasCode(ctx, code)
+}
+
+func normalizeMissingScenesQuery(query MissingScenesQuery) (MissingScenesQuery, error) {
+	query.ID = strings.TrimSpace(query.ID)
+	query.Endpoint = strings.TrimSpace(query.Endpoint)
+	if query.ID == "" {
+		return query, fmt.Errorf("%w: subject id is required", ErrInvalidMissingScenesQuery)
+	}
+	switch query.Subject {
+	case MissingScenesSubjectPerformer:
+	case MissingScenesSubjectStudio:
+		if query.Endpoint == "" {
+			return query, fmt.Errorf("%w: studio reports require a stash-box endpoint", ErrInvalidMissingScenesQuery)
+		}
+	default:
+		return query, fmt.Errorf("%w: unsupported subject %q", ErrInvalidMissingScenesQuery, query.Subject)
+	}
+	for _, bound := range []*string{&query.DateFrom, &query.DateTo} {
+		*bound = strings.TrimSpace(*bound)
+		if *bound == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, *bound); err != nil {
+			return query, fmt.Errorf("%w: date %q, expected YYYY-MM-DD", ErrInvalidMissingScenesQuery, *bound)
+		}
+	}
+	return query, nil
+}
+
+func matchesMissingScenesQuery(item MissingScene, query MissingScenesQuery) bool {
+	if query.DateFrom != "" || query.DateTo != "" {
+		date := item.Date
+		if len(date) > len(time.DateOnly) {
+			date = date[:len(time.DateOnly)]
+		}
+		if date == "" || (query.DateFrom != "" && date < query.DateFrom) || (query.DateTo != "" && date > query.DateTo) {
+			return false
+		}
+	}
+	if len(query.Classifications) > 0 && !containsFold(query.Classifications, item.Classification) {
+		return false
+	}
+	if len(query.Studios) > 0 && !containsFold(query.Studios, item.StudioName) {
+		return false
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, candidate := range values {
+		if value != "" && strings.EqualFold(strings.TrimSpace(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
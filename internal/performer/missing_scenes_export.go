@@ -0,0 +1,115 @@
+package performer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var missingScenesCSVHeader = []string{
+	"key", "stashbox_endpoint", "stashbox_scene_id", "code", "title", "date", "studio", "classification", "performer_count", "performers", "url",
+}
+
+type missingScenesExport struct {
+	Subject        MissingScenesSubject `json:"subject"`
+	ID             string               `json:"id"`
+	Name           string               `json:"name,omitempty"`
+	Endpoint       string               `json:"endpoint,omitempty"`
+	CatalogCount   int                  `json:"catalogCount"`
+	CatalogPartial bool                 `json:"catalogPartial"`
+	OwnedCount     int                  `json:"ownedCount"`
+	MissingCount   int                  `json:"missingCount"`
+	CacheUpdatedAt string               `json:"cacheUpdatedAt,omitempty"`
+	CacheStale     bool                 `json:"cacheStale"`
+	Items          []missingSceneExport `json:"items"`
+}
+
+type missingSceneExport struct {
+	Key             string   `json:"key"`
+	StashBoxSceneID string   `json:"stashBoxSceneId"`
+	Code            string   `json:"code,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	Date            string   `json:"date,omitempty"`
+	Studio          string   `json:"studio,omitempty"`
+	Classification  string   `json:"classification,omitempty"`
+	Performers      []string `json:"performers"`
+	URL             string   `json:"url,omitempty"`
+}
+
+// WriteMissingScenesCSV writes one row per missing scene.
+func WriteMissingScenesCSV(w io.Writer, report MissingScenesReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(missingScenesCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range report.Items {
+		code := buildReleaseCode(item.Code, item.Title)
+		row := []string{
+			item.Key,
+			item.StashBoxEndpoint,
+			item.StashBoxSceneID,
+			code,
+			item.Title,
+			item.Date,
+			item.StudioName,
+			item.Classification,
+			strconv.Itoa(item.PerformerCount),
+			strings.Join(scenePersonNames(item.Performers), "; "),
+			item.URL,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteMissingScenesJSON writes the report summary and its items as one
+// JSON document.
+func WriteMissingScenesJSON(w io.Writer, report MissingScenesReport) error {
+	out := missingScenesExport{
+		Subject:        report.Query.Subject,
+		ID:             report.Query.ID,
+		Name:           report.Name,
+		Endpoint:       report.Endpoint,
+		CatalogCount:   report.CatalogCount,
+		CatalogPartial: report.CatalogPartial,
+		OwnedCount:     report.OwnedCount,
+		MissingCount:   len(report.Items),
+		CacheStale:     report.CacheStale,
+		Items:          make([]missingSceneExport, 0, len(report.Items)),
+	}
+	if report.CacheUpdatedAt != nil {
+		out.CacheUpdatedAt = report.CacheUpdatedAt.UTC().Format(time.RFC3339)
+	}
+	for _, item := range report.Items {
+		out.Items = append(out.Items, missingSceneExport{
+			Key:             item.Key,
+			StashBoxSceneID: item.StashBoxSceneID,
+			Code:            buildReleaseCode(item.Code, item.Title),
+			Title:           item.Title,
+			Date:            item.Date,
+			Studio:          item.StudioName,
+			Classification:  item.Classification,
+			Performers:      scenePersonNames(item.Performers),
+			URL:             item.URL,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func scenePersonNames(people []ScenePerson) []string {
+	names := make([]string, 0, len(people))
+	for _, person := range people {
+		if name := strings.TrimSpace(person.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
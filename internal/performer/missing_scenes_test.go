@@ -0,0 +1,256 @@
+package performer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/stashboxcache"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/stash"
+	stashboxpkg "github.com/leothevan2444/moji/pkg/stashbox"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
+)
+
+type missingScenesLibraryIndex struct {
+	stashIDs map[string]bool
+	codes    map[string]bool
+}
+
+func (f missingScenesLibraryIndex) HasStashID(_ context.Context, endpoint, stashID string) (bool, error) {
+	return f.stashIDs[endpoint+" "+stashID], nil
+}
+
+func (f missingScenesLibraryIndex) HasCode(_ context.Context, code string) (bool, error) {
+	return f.codes[code], nil
+}
+
+type missingScenesClassifier struct{}
+
+func (missingScenesClassifier) ClassifyScene(scene *stashboxgraphql.SceneFragment) string {
+	if len(scene.Performers) == 1 {
+		return "SOLO"
+	}
+	return "SMALL_GROUP"
+}
+
+func missingScenesTestScene(id, code, date, studio string, performers int) *stashboxgraphql.SceneFragment {
+	scene := &stashboxgraphql.SceneFragment{ID: id, Title: &code}
+	if code != "" {
+		scene.Code = &code
+	}
+	if date != "" {
+		scene.Date = &date
+	}
+	if studio != "" {
+		scene.Studio = &stashboxgraphql.StudioFragment{ID: "studio-1", Name: studio}
+	}
+	for index := 0; index < performers; index++ {
+		scene.Performers = append(scene.Performers, &stashboxgraphql.PerformerAppearanceFragment{
+			Performer: &stashboxgraphql.PerformerFragment{ID: code + "-p", Name: "Performer"},
+		})
+	}
+	return scene
+}
+
+func newMissingScenesTestService(t *testing.T, creator TaskCreator) (*Service, *pagedStashBoxClient) {
+	t.Helper()
+	client := &pagedStashBoxClient{pages: map[int][]*stashboxgraphql.SceneFragment{1: {
+		missingScenesTestScene("owned-by-id", "ABC-001", "2024-03-01", "Studio A", 1),
+		missingScenesTestScene("owned-by-code", "ABC-002", "2024-03-02", "Studio A", 1),
+		missingScenesTestScene("missing-solo", "ABC-003", "2024-05-01", "Studio A", 1),
+		missingScenesTestScene("missing-group", "ABC-004", "2024-06-01", "Studio A", 2),
+		missingScenesTestScene("missing-old", "ABC-005", "2022-01-01", "Studio A", 1),
+		missingScenesTestScene("missing-undated", "ABC-006", "", "Studio A", 1),
+	}}}
+	registry := metadata.NewRegistry(performerClientFactory{client: client})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "box", Endpoint: "https://box/graphql"}})
+	service, err := NewService(testStashClient{}, metadata.NewService(nil, registry), creator, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.SetLibraryIndex(missingScenesLibraryIndex{
+		stashIDs: map[string]bool{"https://box/graphql owned-by-id": true},
+		codes:    map[string]bool{"ABC-002": true},
+	})
+	service.SetSceneClassifier(missingScenesClassifier{})
+	return service, client
+}
+
+func TestMissingScenesReportComparesStudioCatalogWithLibrary(t *testing.T) {
+	service, client := newMissingScenesTestService(t, nil)
+
+	report, err := service.MissingScenesReport(context.Background(), MissingScenesQuery{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.CatalogCount != 6 || report.OwnedCount != 2 || len(report.Items) != 4 || report.Name != "Studio A" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if input := client.inputs[0]; input.Studios == nil || input.Studios.Value[0] != "studio-1" || input.Performers != nil {
+		t.Fatalf("expected a studio catalog query, got %+v", input)
+	}
+
+	filtered, err := service.MissingScenesReport(context.Background(), MissingScenesQuery{
+		Subject:         MissingScenesSubjectStudio,
+		ID:              "studio-1",
+		Endpoint:        "https://box/graphql",
+		DateFrom:        "2024-01-01",
+		Classifications: []string{"solo"},
+		Studios:         []string{"studio a"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Items) != 1 || filtered.Items[0].SourceSceneID != "missing-solo" || filtered.Items[0].Classification != "SOLO" {
+		t.Fatalf("unexpected filtered items: %+v", filtered.Items)
+	}
+}
+
+func TestMissingScenesReportRejectsInvalidQueries(t *testing.T) {
+	service, _ := newMissingScenesTestService(t, nil)
+	for _, query := range []MissingScenesQuery{
+		{Subject: MissingScenesSubjectStudio, ID: "studio-1"},
+		{Subject: MissingScenesSubjectPerformer},
+		{Subject: "TAG", ID: "tag-1"},
+		{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql", DateFrom: "2024/01/01"},
+	} {
+		if _, err := service.MissingScenesReport(context.Background(), query); !errors.Is(err, ErrInvalidMissingScenesQuery) {
+			t.Fatalf("expected ErrInvalidMissingScenesQuery for %+v, got %v", query, err)
+		}
+	}
+	service.SetLibraryIndex(nil)
+	if _, err := service.MissingScenesReport(context.Background(), MissingScenesQuery{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql"}); err != ErrLibraryIndexUnavailable {
+		t.Fatalf("expected ErrLibraryIndexUnavailable, got %v", err)
+	}
+}
+
+func TestQueueMissingScenesQueuesEveryReportItemByDefault(t *testing.T) {
+	creator := &queueTestTaskCreator{task: &taskruntime.Task{ID: "task-1"}}
+	service, _ := newMissingScenesTestService(t, creator)
+
+	result, err := service.QueueMissingScenes(context.Background(), MissingScenesQuery{
+		Subject:  MissingScenesSubjectStudio,
+		ID:       "studio-1",
+		Endpoint: "https://box/graphql",
+		DateFrom: "2024-01-01",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary.RequestedCount != 2 || result.Summary.QueuedCount != 2 || creator.calls != 2 {
+		t.Fatalf("unexpected queue result: %+v calls=%d", result.Summary, creator.calls)
+	}
+}
+
+type countedPageClient struct {
+	pagedStashBoxClient
+	count int
+}
+
+func (c *countedPageClient) QueryScenesPage(ctx context.Context, input stashboxgraphql.SceneQueryInput) (stashboxpkg.ScenePage, error) {
+	scenes, err := c.QueryScenes(ctx, input)
+	return stashboxpkg.ScenePage{Scenes: scenes, Count: c.count}, err
+}
+
+func TestQueueMissingScenesQueuesLargeReportsInBatches(t *testing.T) {
+	const total = MaxQueueSceneBatchSize + 50
+	client := &countedPageClient{pagedStashBoxClient: pagedStashBoxClient{pages: map[int][]*stashboxgraphql.SceneFragment{}}, count: total}
+	for index := 0; index < total; index++ {
+		page := index/40 + 1
+		client.pages[page] = append(client.pages[page], missingScenesTestScene(fmt.Sprintf("scene-%03d", index), fmt.Sprintf("ABC-%03d", index), "2024-01-01", "Studio A", 1))
+	}
+	registry := metadata.NewRegistry(performerClientFactory{client: client})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "box", Endpoint: "https://box/graphql"}})
+	creator := &queueTestTaskCreator{task: &taskruntime.Task{ID: "task-1"}}
+	service, err := NewService(testStashClient{}, metadata.NewService(nil, registry), creator, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.SetLibraryIndex(missingScenesLibraryIndex{})
+
+	result, err := service.QueueMissingScenes(context.Background(), MissingScenesQuery{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary.RequestedCount != total || result.Summary.QueuedCount != total || creator.calls != total {
+		t.Fatalf("expected every missing scene to be queued, got %+v calls=%d", result.Summary, creator.calls)
+	}
+}
+
+func TestWriteMissingScenesCSV(t *testing.T) {
+	service, _ := newMissingScenesTestService(t, nil)
+	report, err := service.MissingScenesReport(context.Background(), MissingScenesQuery{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql", DateFrom: "2024-05-01", DateTo: "2024-05-31"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteMissingScenesCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][2] != "missing-solo" || rows[1][3] != "ABC-003" || rows[1][7] != "SOLO" {
+		t.Fatalf("unexpected csv rows: %q", rows)
+	}
+
+	buf.Reset()
+	if err := WriteMissingScenesJSON(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"missingCount": 1`) || !strings.Contains(buf.String(), `"stashBoxSceneId": "missing-solo"`) {
+		t.Fatalf("unexpected json export: %s", buf.String())
+	}
+}
+
+type failingPageClient struct {
+	pagedStashBoxClient
+	failFrom int
+}
+
+func (c *failingPageClient) QueryScenesPage(ctx context.Context, input stashboxgraphql.SceneQueryInput) (stashboxpkg.ScenePage, error) {
+	if input.Page >= c.failFrom {
+		c.inputs = append(c.inputs, input)
+		return stashboxpkg.ScenePage{}, errors.New("stash-box unavailable")
+	}
+	return c.pagedStashBoxClient.QueryScenesPage(ctx, input)
+}
+
+func TestMissingScenesReportStopsWhenCatalogStopsGrowing(t *testing.T) {
+	client := &failingPageClient{pagedStashBoxClient: pagedStashBoxClient{pages: map[int][]*stashboxgraphql.SceneFragment{1: sceneFragments(1, 40)}}, failFrom: 2}
+	registry := metadata.NewRegistry(performerClientFactory{client: client})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "box", Endpoint: "https://box/graphql"}})
+	metadataService := metadata.NewService(nil, registry)
+	cache, err := stashboxcache.New(filepath.Join(t.TempDir(), "cache.db"), func() stashboxcache.Config {
+		return stashboxcache.Config{TTL: 24 * time.Hour, StaleRetention: 30 * 24 * time.Hour}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cache.Close() })
+	metadataService.SetCache(cache)
+	service, err := NewService(testStashClient{}, metadataService, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.SetLibraryIndex(missingScenesLibraryIndex{})
+
+	report, err := service.MissingScenesReport(context.Background(), MissingScenesQuery{Subject: MissingScenesSubjectStudio, ID: "studio-1", Endpoint: "https://box/graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cache falls back to the first page once StashBox fails, so the
+	// report stops after one failed pass instead of retrying forever.
+	if report.CatalogCount != 40 || !report.CatalogPartial || len(client.inputs) != 2 {
+		t.Fatalf("expected a partial 40-scene report after one retry, got catalog=%d partial=%v calls=%d", report.CatalogCount, report.CatalogPartial, len(client.inputs))
+	}
+}
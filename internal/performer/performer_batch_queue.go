@@ -22,41 +22,52 @@ func (s *Service) QueuePerformerScenes(ctx context.Context, performerID string,
 	if performerID == "" {
 		return QueueScenesResult{}, errors.New("performer: performer id is required")
 	}
+	if err := s.validateQueueSelections(selections); err != nil {
+		return QueueScenesResult{}, err
+	}
+
+	performer, err := s.stash.FindPerformerByID(ctx, performerID)
+	if err != nil {
+		return QueueScenesResult{}, err
+	}
+	if performer == nil {
+		return QueueScenesResult{}, fmt.Errorf("performer: performer %q not found", performerID)
+	}
+
+	scenes, _, _, _, _, _, _, err := s.loadPerformerScenes(ctx, performer, SceneQuery{Page: 1, PageSize: 100}, true, metadata.CachePreferred)
+	if err != nil {
+		return QueueScenesResult{}, err
+	}
+	return s.queueSceneSelections(ctx, scenes, selections), nil
+}
+
+func (s *Service) validateQueueSelections(selections []QueueSceneSelection) error {
 	if len(selections) == 0 {
-		return QueueScenesResult{}, ErrQueueSceneBatchEmpty
+		return ErrQueueSceneBatchEmpty
 	}
 	if len(selections) > MaxQueueSceneBatchSize {
-		return QueueScenesResult{}, fmt.Errorf("%w: maximum is %d", ErrQueueSceneBatchTooLarge, MaxQueueSceneBatchSize)
+		return fmt.Errorf("%w: maximum is %d", ErrQueueSceneBatchTooLarge, MaxQueueSceneBatchSize)
 	}
 	if s.taskCreator == nil {
-		return QueueScenesResult{}, errors.New("performer: task creator is not configured")
+		return errors.New("performer: task creator is not configured")
 	}
 
 	seenKeys := make(map[string]struct{}, len(selections))
 	for index := range selections {
 		key := normalize(selections[index].Key)
 		if key == "" {
-			return QueueScenesResult{}, errors.New("performer: scene key is required")
+			return errors.New("performer: scene key is required")
 		}
 		if _, exists := seenKeys[key]; exists {
-			return QueueScenesResult{}, fmt.Errorf("performer: duplicate scene key %q in request", selections[index].Key)
+			return fmt.Errorf("performer: duplicate scene key %q in request", selections[index].Key)
 		}
 		seenKeys[key] = struct{}{}
 		selections[index].Key = strings.TrimSpace(selections[index].Key)
 	}
+	return nil
+}
 
-	performer, err := s.stash.FindPerformerByID(ctx, performerID)
-	if err != nil {
-		return QueueScenesResult{}, err
-	}
-	if performer == nil {
-		return QueueScenesResult{}, fmt.Errorf("performer: performer %q not found", performerID)
-	}
-
-	scenes, _, _, _, _, _, _, err := s.loadPerformerScenes(ctx, performer, SceneQuery{Page: 1, PageSize: 100}, true, metadata.CachePreferred)
-	if err != nil {
-		return QueueScenesResult{}, err
-	}
+func (s *Service) queueSceneSelections(ctx context.Context, scenes []Scene, selections []QueueSceneSelection) QueueScenesResult {
 	byKey := make(map[string]Scene, len(scenes))
 	for _, scene := range scenes {
 		byKey[scene.Key] = scene
@@ -86,7 +97,7 @@ func (s *Service) QueuePerformerScenes(ctx context.Context, performerID string,
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 func (s *Service) queuePerformerSceneSelection(ctx context.Context, byKey map[string]Scene, selection QueueSceneSelection) QueueSceneResult {
@@ -51,6 +51,10 @@ func (c *pagedStashBoxClient) QueryScenesPage(ctx context.Context, input stashbo
 	return stashboxpkg.ScenePage{Scenes: scenes, Count: 85}, err
 }
 
+func (*pagedStashBoxClient) FindStudioByID(context.Context, string) (*stashboxgraphql.StudioFragment, error) {
+	return nil, nil
+}
+
 func sceneFragments(start, count int) []*stashboxgraphql.SceneFragment {
 	out := make([]*stashboxgraphql.SceneFragment, count)
 	for index := range out {
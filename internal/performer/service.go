@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/metadata"
 	"github.com/leothevan2444/moji/internal/taskruntime"
 	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
@@ -33,6 +34,8 @@ type Service struct {
 	customFieldKey string
 	stashImage     StashImageProxy
 	stashBoxImage  StashBoxImageProxy
+	libraryIndex   LibraryIndex
+	classifier     SceneClassifier
 }
 
 func NewService(stash StashClient, source *metadata.Service, creator TaskCreator, lister TaskLister, stashImage StashImageProxy, stashBoxImage StashBoxImageProxy) (*Service, error) {
@@ -103,8 +106,16 @@ func performerMatches(item Performer, needle string) bool {
 	}
 	return false
 }
-func normalize(v string) string              { return strings.ToLower(strings.TrimSpace(v)) }
-func buildReleaseCode(code, _ string) string { return strings.TrimSpace(code) }
+func normalize(v string) string { return strings.ToLower(strings.TrimSpace(v)) }
+func buildReleaseCode(code, title string) string {
+	if code = strings.TrimSpace(code); code != "" {
+		if resolved := codeparser.Normalize(code); resolved != "" {
+			return resolved
+		}
+		return code
+	}
+	return codeparser.Normalize(title)
+}
 func stringValue(v *string) string {
 	if v == nil {
 		return ""
@@ -93,9 +93,22 @@ func (s *Service) ResolvePerformer(ctx context.Context, client Client, endpoint,
 }
 
 func (s *Service) Load(ctx context.Context, client Client, endpoint, performerID string, minimum int, policy FreshnessPolicy) (Result, error) {
+	return s.load(ctx, client, PerformerKey{Endpoint: normalizeEndpoint(endpoint), PerformerID: strings.TrimSpace(performerID)}, minimum, policy)
+}
+
+// LoadStudio is Load for the scenes of a StashBox studio. Studio snapshots
+// share the performer tables under a "studio:" prefixed id.
+func (s *Service) LoadStudio(ctx context.Context, client Client, endpoint, studioID string, minimum int, policy FreshnessPolicy) (Result, error) {
+	studioID = strings.TrimSpace(studioID)
+	if studioID == "" {
+		return Result{}, errors.New("stashboxcache: invalid scene lookup")
+	}
+	return s.load(ctx, client, PerformerKey{Endpoint: normalizeEndpoint(endpoint), PerformerID: studioKeyPrefix + studioID}, minimum, policy)
+}
+
+func (s *Service) load(ctx context.Context, client Client, key PerformerKey, minimum int, policy FreshnessPolicy) (Result, error) {
 	s.opsMu.RLock()
 	defer s.opsMu.RUnlock()
-	key := PerformerKey{Endpoint: normalizeEndpoint(endpoint), PerformerID: strings.TrimSpace(performerID)}
 	if client == nil || key.Endpoint == "" || key.PerformerID == "" {
 		return Result{}, errors.New("stashboxcache: invalid scene lookup")
 	}
@@ -150,10 +163,7 @@ func (s *Service) loadLocked(ctx context.Context, client Client, key PerformerKe
 		if !mustFetch {
 			continue
 		}
-		upstream, fetchErr := client.QueryScenesPage(ctx, stashboxgraphql.SceneQueryInput{
-			Performers: &stashboxgraphql.MultiIDCriterionInput{Value: []string{key.PerformerID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
-			Page:       pageNumber, PerPage: PageSize, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate,
-		})
+		upstream, fetchErr := client.QueryScenesPage(ctx, sceneQuery(key, pageNumber))
 		if fetchErr != nil {
 			if policy == CachePreferred && fallback != nil {
 				if shouldTouch(fallback.LastAccessed, now) {
@@ -210,6 +220,16 @@ func (s *Service) loadLocked(ctx context.Context, client Client, key PerformerKe
 	return resultFromSnapshot(current, false), nil
 }
 
+func sceneQuery(key PerformerKey, pageNumber int) stashboxgraphql.SceneQueryInput {
+	input := stashboxgraphql.SceneQueryInput{Page: pageNumber, PerPage: PageSize, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate}
+	if studioID, ok := strings.CutPrefix(key.PerformerID, studioKeyPrefix); ok {
+		input.Studios = &stashboxgraphql.MultiIDCriterionInput{Value: []string{studioID}, Modifier: stashboxgraphql.CriterionModifierIncludes}
+		return input
+	}
+	input.Performers = &stashboxgraphql.MultiIDCriterionInput{Value: []string{key.PerformerID}, Modifier: stashboxgraphql.CriterionModifierIncludes}
+	return input
+}
+
 func shouldTouch(lastAccessed, now time.Time) bool {
 	return lastAccessed.IsZero() || !now.Before(lastAccessed.Add(accessTouchInterval))
 }
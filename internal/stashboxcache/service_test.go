@@ -93,6 +93,27 @@ func TestLoadExpandsInFortyItemBatchesAndHitsCache(t *testing.T) {
 	}
 }
 
+func TestLoadStudioQueriesByStudioInItsOwnSnapshot(t *testing.T) {
+	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	service := newTestService(t, &now)
+	client := &fakeClient{scenes: testScenes(12)}
+
+	result, err := service.LoadStudio(context.Background(), client, "https://box", "s1", 12, CachePreferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := client.queryCalls[0]
+	if !result.Complete || call.Performers != nil || call.Studios == nil || call.Studios.Value[0] != "s1" {
+		t.Fatalf("unexpected studio load: %+v query=%+v", result, call)
+	}
+	if _, err := service.Load(context.Background(), client, "https://box", "s1", 12, CachePreferred); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.queryCalls) != 2 || client.queryCalls[1].Performers == nil {
+		t.Fatalf("a performer with the same id must not reuse the studio snapshot, calls=%+v", client.queryCalls)
+	}
+}
+
 func TestExpiredBrowseFallsBackToStaleButRequiredFreshFails(t *testing.T) {
 	now := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
 	service := newTestService(t, &now)
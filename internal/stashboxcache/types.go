@@ -13,6 +13,7 @@ const (
 	DefaultTTL            = 24 * time.Hour
 	DefaultStaleRetention = 30 * 24 * time.Hour
 	accessTouchInterval   = time.Hour
+	studioKeyPrefix       = "studio:"
 )
 
 type Config struct {
@@ -0,0 +1,45 @@
+package stashsync
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+// ErrJobSubscriptionUnsupported is returned by WatchJobs when the client
+// cannot subscribe to Stash job updates.
+var ErrJobSubscriptionUnsupported = errors.New("stashsync: client does not support job subscriptions")
+
+// JobSubscriber is implemented by clients that can stream Stash job status
+// updates over Stash's jobsSubscribe subscription.
+type JobSubscriber interface {
+	SubscribeJobs(ctx context.Context, ready func(), handle func(*stashgraphql.JobStatusUpdate)) error
+}
+
+// WatchJobs streams job updates to handle until ctx is cancelled or the
+// subscription drops. ready is called once updates are flowing.
+func (s *Service) WatchJobs(ctx context.Context, ready func(), handle func(Job)) error {
+	subscriber, ok := s.client.(JobSubscriber)
+	if !ok {
+		return ErrJobSubscriptionUnsupported
+	}
+	return subscriber.SubscribeJobs(ctx, ready, func(update *stashgraphql.JobStatusUpdate) {
+		if update == nil || update.Job == nil || strings.TrimSpace(update.Job.ID) == "" {
+			return
+		}
+		job := update.Job
+		handle(Job{
+			ID:          job.ID,
+			Status:      string(job.Status),
+			Description: job.Description,
+			Progress:    job.Progress,
+			StartTime:   job.StartTime,
+			EndTime:     job.EndTime,
+			AddTime:     job.AddTime,
+			Error:       job.Error,
+			SubTasks:    job.SubTasks,
+		})
+	})
+}
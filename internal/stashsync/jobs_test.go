@@ -0,0 +1,46 @@
+package stashsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+func TestWatchJobsMapsUpdates(t *testing.T) {
+	service, err := NewService(&fakeSubscribingClient{updates: []*stashgraphql.JobStatusUpdate{
+		{Type: stashgraphql.JobStatusUpdateTypeUpdate, Job: &stashgraphql.Job{ID: "7", Status: stashgraphql.JobStatusFinished, Description: "Scanning..."}},
+		{Type: stashgraphql.JobStatusUpdateTypeRemove},
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jobs []Job
+	if err := service.WatchJobs(context.Background(), nil, func(job Job) { jobs = append(jobs, job) }); err != nil {
+		t.Fatalf("WatchJobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "7" || jobs[0].Status != "FINISHED" || jobs[0].Description != "Scanning..." {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+
+	plain, _ := NewService(&fakeClient{}, nil)
+	if err := plain.WatchJobs(context.Background(), nil, func(Job) {}); !errors.Is(err, ErrJobSubscriptionUnsupported) {
+		t.Fatalf("expected ErrJobSubscriptionUnsupported, got %v", err)
+	}
+}
+
+type fakeSubscribingClient struct {
+	fakeClient
+	updates []*stashgraphql.JobStatusUpdate
+}
+
+func (f *fakeSubscribingClient) SubscribeJobs(_ context.Context, ready func(), handle func(*stashgraphql.JobStatusUpdate)) error {
+	if ready != nil {
+		ready()
+	}
+	for _, update := range f.updates {
+		handle(update)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package stashsync
+
+import "strings"
+
+// ScanConfig holds the scan options used for task scans. Libraries override
+// Defaults flag by flag for scan paths under their Stash-side Path.
+type ScanConfig struct {
+	Defaults  ScanOptions
+	Libraries []LibraryScanOptions
+}
+
+type LibraryScanOptions struct {
+	Path    string
+	Options ScanOptions
+}
+
+// OptionsFor returns the options for a Stash-side scan path. The library
+// with the longest path containing it overrides the defaults.
+func (c ScanConfig) OptionsFor(path string) ScanOptions {
+	options := c.Defaults
+	var match *LibraryScanOptions
+	for i := range c.Libraries {
+		library := &c.Libraries[i]
+		root := strings.TrimRight(strings.TrimSpace(library.Path), "/")
+		if root == "" || (path != root && !strings.HasPrefix(path, root+"/")) {
+			continue
+		}
+		if match == nil || len(root) > len(strings.TrimRight(match.Path, "/")) {
+			match = library
+		}
+	}
+	if match != nil {
+		options = options.Override(match.Options)
+	}
+	return options
+}
+
+// Override returns o with every flag set in other replaced.
+func (o ScanOptions) Override(other ScanOptions) ScanOptions {
+	for _, pair := range []struct{ dst, src **bool }{
+		{&o.Rescan, &other.Rescan},
+		{&o.ScanGenerateCovers, &other.ScanGenerateCovers},
+		{&o.ScanGeneratePreviews, &other.ScanGeneratePreviews},
+		{&o.ScanGenerateImagePreviews, &other.ScanGenerateImagePreviews},
+		{&o.ScanGenerateSprites, &other.ScanGenerateSprites},
+		{&o.ScanGeneratePhashes, &other.ScanGeneratePhashes},
+		{&o.ScanGenerateThumbnails, &other.ScanGenerateThumbnails},
+		{&o.ScanGenerateClipPreviews, &other.ScanGenerateClipPreviews},
+	} {
+		if *pair.src != nil {
+			*pair.dst = *pair.src
+		}
+	}
+	return o
+}
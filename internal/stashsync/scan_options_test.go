@@ -0,0 +1,29 @@
+package stashsync
+
+import "testing"
+
+func TestScanConfigOptionsForUsesLongestLibrary(t *testing.T) {
+	yes, no := true, false
+	cfg := ScanConfig{
+		Defaults: ScanOptions{ScanGeneratePhashes: &yes, ScanGenerateSprites: &yes},
+		Libraries: []LibraryScanOptions{
+			{Path: "/library", Options: ScanOptions{ScanGenerateSprites: &no}},
+			{Path: "/library/jav/", Options: ScanOptions{ScanGeneratePreviews: &yes}},
+		},
+	}
+
+	if got := cfg.OptionsFor("/elsewhere/ABC-123.mp4"); got != cfg.Defaults {
+		t.Fatalf("expected defaults outside libraries, got %+v", got)
+	}
+	got := cfg.OptionsFor("/library/other/ABC-123.mp4")
+	if got.ScanGeneratePhashes != &yes || got.ScanGenerateSprites != &no || got.ScanGeneratePreviews != nil {
+		t.Fatalf("unexpected /library options %+v", got)
+	}
+	got = cfg.OptionsFor("/library/jav/ABC-123")
+	if got.ScanGeneratePreviews != &yes || got.ScanGenerateSprites != &yes {
+		t.Fatalf("expected only the longest library to apply, got %+v", got)
+	}
+	if got := cfg.OptionsFor("/library-old/ABC-123.mp4"); got != cfg.Defaults {
+		t.Fatalf("library paths must match whole path segments, got %+v", got)
+	}
+}
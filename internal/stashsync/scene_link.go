@@ -0,0 +1,171 @@
+package stashsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+// Scene custom field keys written for provenance.
+const (
+	CustomFieldTaskID      = "moji_task_id"
+	CustomFieldIndexer     = "moji_indexer"
+	CustomFieldTorrentName = "moji_torrent_name"
+	CustomFieldGrabbedAt   = "moji_grabbed_at"
+)
+
+const sceneLinkPageSize = 50
+
+type SceneClient interface {
+	FindScenes(ctx context.Context, sceneFilter *stashgraphql.SceneFilterType, filter *stashgraphql.FindFilterType) ([]*stashgraphql.SceneFragment, error)
+	UpdateScene(ctx context.Context, input stashgraphql.SceneUpdateInput) (*stashgraphql.SceneFragment, error)
+	UpdateSceneCustomFields(ctx context.Context, id string, partial map[string]any) error
+}
+
+// SceneLink describes what Moji knows about the content under a scanned
+// Stash path. Empty fields are left alone on the scene.
+type SceneLink struct {
+	Path             string
+	StashBoxEndpoint string
+	StashBoxSceneID  string
+	PerformerID      string
+	Provenance       Provenance
+}
+
+type Provenance struct {
+	TaskID      string
+	Indexer     string
+	TorrentName string
+	GrabbedAt   time.Time
+}
+
+type SceneLinker struct {
+	client SceneClient
+}
+
+func NewSceneLinker(client SceneClient) (*SceneLinker, error) {
+	if client == nil {
+		return nil, errors.New("stashsync: scene client is required")
+	}
+	return &SceneLinker{client: client}, nil
+}
+
+// LinkScenes finds the Stash scenes at link.Path, or below it when the path
+// is a directory, sets the StashBox stash_id and performer, and records the
+// provenance in custom fields. It returns the IDs of the scenes it found.
+// Custom fields need a Stash release that supports them on scenes; when the
+// server rejects them the scene is still linked.
+func (l *SceneLinker) LinkScenes(ctx context.Context, link SceneLink) ([]string, error) {
+	path := strings.TrimSpace(link.Path)
+	if path == "" {
+		return nil, errors.New("stashsync: scene link path is required")
+	}
+	scenes, err := l.findScenesAtPath(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("stashsync: find scenes at %s: %w", path, err)
+	}
+
+	ids := make([]string, 0, len(scenes))
+	for _, scene := range scenes {
+		if scene == nil {
+			continue
+		}
+		if input, changed := sceneLinkUpdate(scene, link); changed {
+			if _, err := l.client.UpdateScene(ctx, input); err != nil {
+				return ids, fmt.Errorf("stashsync: link scene %s: %w", scene.ID, err)
+			}
+		}
+		if fields := link.Provenance.customFields(); len(fields) > 0 {
+			if err := l.client.UpdateSceneCustomFields(ctx, scene.ID, fields); err != nil {
+				logging.Warnf("stashsync: write provenance to scene %s failed: %v", scene.ID, err)
+			}
+		}
+		ids = append(ids, scene.ID)
+	}
+	logging.Infof("stashsync: linked %d scenes at %s stash_box=%s scene=%s", len(ids), path, link.StashBoxEndpoint, link.StashBoxSceneID)
+	return ids, nil
+}
+
+// findScenesAtPath matches the file itself first and falls back to the
+// scenes inside the directory.
+func (l *SceneLinker) findScenesAtPath(ctx context.Context, path string) ([]*stashgraphql.SceneFragment, error) {
+	page, perPage := 1, sceneLinkPageSize
+	find := func(value string, modifier stashgraphql.CriterionModifier) ([]*stashgraphql.SceneFragment, error) {
+		return l.client.FindScenes(ctx, &stashgraphql.SceneFilterType{
+			Path: &stashgraphql.StringCriterionInput{Value: value, Modifier: modifier},
+		}, &stashgraphql.FindFilterType{Page: &page, PerPage: &perPage})
+	}
+	scenes, err := find(path, stashgraphql.CriterionModifierEquals)
+	if err != nil || len(scenes) > 0 {
+		return scenes, err
+	}
+	return find(strings.TrimRight(path, "/")+"/", stashgraphql.CriterionModifierIncludes)
+}
+
+func sceneLinkUpdate(scene *stashgraphql.SceneFragment, link SceneLink) (stashgraphql.SceneUpdateInput, bool) {
+	input := stashgraphql.SceneUpdateInput{ID: scene.ID}
+	changed := false
+
+	endpoint := strings.TrimSpace(link.StashBoxEndpoint)
+	sceneID := strings.TrimSpace(link.StashBoxSceneID)
+	if endpoint != "" && sceneID != "" {
+		linked := false
+		for _, existing := range scene.StashIds {
+			if existing == nil {
+				continue
+			}
+			if existing.Endpoint == endpoint {
+				linked = existing.StashID == sceneID
+				continue
+			}
+			input.StashIds = append(input.StashIds, &stashgraphql.StashIDInput{Endpoint: existing.Endpoint, StashID: existing.StashID})
+		}
+		input.StashIds = append(input.StashIds, &stashgraphql.StashIDInput{Endpoint: endpoint, StashID: sceneID})
+		if linked {
+			input.StashIds = nil
+		} else {
+			changed = true
+		}
+	}
+
+	if performerID := strings.TrimSpace(link.PerformerID); performerID != "" {
+		ids := make([]string, 0, len(scene.Performers)+1)
+		for _, performer := range scene.Performers {
+			if performer == nil {
+				continue
+			}
+			if performer.ID == performerID {
+				ids = nil
+				break
+			}
+			ids = append(ids, performer.ID)
+		}
+		if ids != nil {
+			input.PerformerIds = append(ids, performerID)
+			changed = true
+		}
+	}
+	return input, changed
+}
+
+func (p Provenance) customFields() map[string]any {
+	if strings.TrimSpace(p.TaskID) == "" {
+		return nil
+	}
+	fields := map[string]any{CustomFieldTaskID: p.TaskID}
+	if value := strings.TrimSpace(p.Indexer); value != "" {
+		fields[CustomFieldIndexer] = value
+	}
+	if value := strings.TrimSpace(p.TorrentName); value != "" {
+		fields[CustomFieldTorrentName] = value
+	}
+	if !p.GrabbedAt.IsZero() {
+		fields[CustomFieldGrabbedAt] = p.GrabbedAt.UTC().Format(time.RFC3339)
+	}
+	return fields
+}
@@ -0,0 +1,94 @@
+package stashsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+func TestLinkScenesSetsStashIDPerformerAndProvenance(t *testing.T) {
+	client := &fakeSceneClient{
+		scenes: map[string][]*stashgraphql.SceneFragment{
+			"/library/ABC-123/": {{
+				ID:         "41",
+				StashIds:   []*stashgraphql.StashIDFragment{{Endpoint: "https://fansdb.cc/graphql", StashID: "other"}},
+				Performers: []*stashgraphql.SceneFragment_Performers{{ID: "7"}},
+			}},
+		},
+		customFieldsErr: errors.New("unknown field custom_fields"),
+	}
+	linker, err := NewSceneLinker(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := linker.LinkScenes(context.Background(), SceneLink{
+		Path:             "/library/ABC-123",
+		StashBoxEndpoint: "https://stashdb.org/graphql",
+		StashBoxSceneID:  "scene-uuid",
+		PerformerID:      "9",
+		Provenance:       Provenance{TaskID: "task-1", Indexer: "nyaa", TorrentName: "ABC-123.mp4", GrabbedAt: time.Unix(100, 0)},
+	})
+	if err != nil || len(ids) != 1 || ids[0] != "41" {
+		t.Fatalf("LinkScenes: ids %v err %v", ids, err)
+	}
+	if len(client.updates) != 1 {
+		t.Fatalf("expected one scene update, got %+v", client.updates)
+	}
+	update := client.updates[0]
+	if len(update.StashIds) != 2 || update.StashIds[0].StashID != "other" || update.StashIds[1].Endpoint != "https://stashdb.org/graphql" || update.StashIds[1].StashID != "scene-uuid" {
+		t.Fatalf("unexpected stash ids %+v", update.StashIds)
+	}
+	if len(update.PerformerIds) != 2 || update.PerformerIds[1] != "9" {
+		t.Fatalf("unexpected performers %v", update.PerformerIds)
+	}
+	if client.customFields["41"][CustomFieldTaskID] != "task-1" || client.customFields["41"][CustomFieldGrabbedAt] != "1970-01-01T00:01:40Z" {
+		t.Fatalf("unexpected custom fields %+v", client.customFields)
+	}
+}
+
+func TestLinkScenesSkipsUpdateWhenAlreadyLinked(t *testing.T) {
+	client := &fakeSceneClient{
+		scenes: map[string][]*stashgraphql.SceneFragment{
+			"/library/ABC-123.mp4": {{
+				ID:         "41",
+				StashIds:   []*stashgraphql.StashIDFragment{{Endpoint: "https://stashdb.org/graphql", StashID: "scene-uuid"}},
+				Performers: []*stashgraphql.SceneFragment_Performers{{ID: "9"}},
+			}},
+		},
+	}
+	linker, _ := NewSceneLinker(client)
+	if _, err := linker.LinkScenes(context.Background(), SceneLink{Path: "/library/ABC-123.mp4", StashBoxEndpoint: "https://stashdb.org/graphql", StashBoxSceneID: "scene-uuid", PerformerID: "9"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.updates) != 0 || len(client.customFields) != 0 {
+		t.Fatalf("expected no writes, got updates %+v fields %+v", client.updates, client.customFields)
+	}
+}
+
+type fakeSceneClient struct {
+	scenes          map[string][]*stashgraphql.SceneFragment
+	updates         []stashgraphql.SceneUpdateInput
+	customFields    map[string]map[string]any
+	customFieldsErr error
+}
+
+func (f *fakeSceneClient) FindScenes(_ context.Context, filter *stashgraphql.SceneFilterType, _ *stashgraphql.FindFilterType) ([]*stashgraphql.SceneFragment, error) {
+	return f.scenes[filter.Path.Value], nil
+}
+
+func (f *fakeSceneClient) UpdateScene(_ context.Context, input stashgraphql.SceneUpdateInput) (*stashgraphql.SceneFragment, error) {
+	f.updates = append(f.updates, input)
+	return &stashgraphql.SceneFragment{ID: input.ID}, nil
+}
+
+func (f *fakeSceneClient) UpdateSceneCustomFields(_ context.Context, id string, partial map[string]any) error {
+	if f.customFields == nil {
+		f.customFields = map[string]map[string]any{}
+	}
+	f.customFields[id] = partial
+	return f.customFieldsErr
+}
@@ -12,6 +12,7 @@ import (
 
 type Client interface {
 	MetadataScan(ctx context.Context, input stashgraphql.ScanMetadataInput) (string, error)
+	MetadataIdentify(ctx context.Context, input stashgraphql.IdentifyMetadataInput) (string, error)
 	FindJob(ctx context.Context, id string) (*stashgraphql.FindJob_FindJob, error)
 }
 
@@ -35,6 +36,9 @@ type IntegrationConfig struct {
 	Downloads    DownloadsPathConfig
 	Library      LibraryPathConfig
 	Transfer     TransferConfig
+	Identify     IdentifyConfig
+	Scan         ScanConfig
+	Routes       []LibraryRoute
 }
 
 type DownloadsPathConfig struct {
@@ -47,12 +51,51 @@ type LibraryPathConfig struct {
 	StashRoot string
 }
 
+// LibraryRoute sends tasks matching its conditions into Library instead of
+// the default library. See config.LibraryRouteConfig for the matching rules.
+type LibraryRoute struct {
+	Name          string
+	CodePrefixes  []string
+	Studios       []string
+	Tags          []string
+	TitleKeywords []string
+	Sources       []string
+	Library       LibraryPathConfig
+}
+
+// RouteLibrary returns the library of the named route, or the default
+// library when name is empty or no longer configured.
+func (c IntegrationConfig) RouteLibrary(name string) (LibraryPathConfig, bool) {
+	if name == "" {
+		return c.Library, true
+	}
+	for _, route := range c.Routes {
+		if route.Name == name {
+			return route.Library, true
+		}
+	}
+	return c.Library, false
+}
+
 type TransferConfig struct {
 	Action TransferAction
 }
 
+// IdentifyConfig enables Stash's identify task on freshly scanned paths.
+// Sources are StashBox endpoints in the order Stash should try them.
+type IdentifyConfig struct {
+	Enabled bool
+	Sources []string
+}
+
 type ScanRequest struct {
-	Paths                     []string
+	Paths []string
+	ScanOptions
+}
+
+// ScanOptions are the generation flags of a Stash scan. A nil flag is left
+// to Stash.
+type ScanOptions struct {
 	Rescan                    *bool
 	ScanGenerateCovers        *bool
 	ScanGeneratePreviews      *bool
@@ -63,6 +106,11 @@ type ScanRequest struct {
 	ScanGenerateClipPreviews  *bool
 }
 
+type IdentifyRequest struct {
+	Paths   []string
+	Sources []string
+}
+
 type Job struct {
 	ID          string
 	Status      string
@@ -117,6 +165,34 @@ func (s *Service) MetadataScan(ctx context.Context, req ScanRequest) (string, er
 	return jobID, nil
 }
 
+func (s *Service) MetadataIdentify(ctx context.Context, req IdentifyRequest) (string, error) {
+	paths := cleanPaths(req.Paths)
+	if len(paths) == 0 {
+		return "", errors.New("stashsync: at least one identify path is required")
+	}
+	sources := make([]*stashgraphql.IdentifySourceInput, 0, len(req.Sources))
+	for _, endpoint := range cleanPaths(req.Sources) {
+		sources = append(sources, &stashgraphql.IdentifySourceInput{
+			Source: &stashgraphql.ScraperSourceInput{StashBoxEndpoint: &endpoint},
+		})
+	}
+	if len(sources) == 0 {
+		return "", errors.New("stashsync: at least one identify source is required")
+	}
+	logging.Infof("stashsync: metadata identify requested for %d paths with %d sources", len(paths), len(sources))
+
+	jobID, err := s.client.MetadataIdentify(ctx, stashgraphql.IdentifyMetadataInput{
+		Sources: sources,
+		Paths:   paths,
+	})
+	if err != nil {
+		logging.Errorf("stashsync: metadata identify request failed for paths %v: %v", paths, err)
+		return "", err
+	}
+	logging.Infof("stashsync: metadata identify started with job %s for paths %v", jobID, paths)
+	return jobID, nil
+}
+
 func (s *Service) CurrentConfig() IntegrationConfig {
 	if s == nil || s.configProvider == nil {
 		return IntegrationConfig{}
@@ -126,6 +202,7 @@ func (s *Service) CurrentConfig() IntegrationConfig {
 	cfg.Downloads.MojiRoot = strings.TrimSpace(cfg.Downloads.MojiRoot)
 	cfg.Library.MojiRoot = strings.TrimSpace(cfg.Library.MojiRoot)
 	cfg.Library.StashRoot = strings.TrimSpace(cfg.Library.StashRoot)
+	cfg.Identify.Sources = cleanPaths(cfg.Identify.Sources)
 	return cfg
 }
 
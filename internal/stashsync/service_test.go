@@ -28,8 +28,8 @@ func TestMetadataScanUsesRequestPaths(t *testing.T) {
 
 	rescan := true
 	id, err := service.MetadataScan(context.Background(), ScanRequest{
-		Paths:  []string{"  /custom-a  ", "", "/custom-b"},
-		Rescan: &rescan,
+		Paths:       []string{"  /custom-a  ", "", "/custom-b"},
+		ScanOptions: ScanOptions{Rescan: &rescan},
 	})
 	if err != nil {
 		t.Fatalf("metadata scan: %v", err)
@@ -68,6 +68,33 @@ func TestMetadataScanRequiresAnyPath(t *testing.T) {
 	}
 }
 
+func TestMetadataIdentifyUsesSourcesInOrder(t *testing.T) {
+	client := &fakeClient{metadataIdentifyID: "job-2"}
+	service, err := NewService(client, nil)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	id, err := service.MetadataIdentify(context.Background(), IdentifyRequest{
+		Paths:   []string{" /library/ABC-123.mp4 "},
+		Sources: []string{"https://stashdb.org/graphql", " ", "https://fansdb.cc/graphql"},
+	})
+	if err != nil || id != "job-2" {
+		t.Fatalf("metadata identify: id %q err %v", id, err)
+	}
+	input := client.metadataIdentifyInput
+	if len(input.Paths) != 1 || input.Paths[0] != "/library/ABC-123.mp4" {
+		t.Fatalf("unexpected paths: %#v", input.Paths)
+	}
+	if len(input.Sources) != 2 || *input.Sources[0].Source.StashBoxEndpoint != "https://stashdb.org/graphql" || *input.Sources[1].Source.StashBoxEndpoint != "https://fansdb.cc/graphql" {
+		t.Fatalf("unexpected sources: %#v", input.Sources)
+	}
+
+	if _, err := service.MetadataIdentify(context.Background(), IdentifyRequest{Paths: []string{"/library"}}); err == nil {
+		t.Fatal("expected error without identify sources")
+	}
+}
+
 func TestFindJobRequiresID(t *testing.T) {
 	service, err := NewService(&fakeClient{}, nil)
 	if err != nil {
@@ -159,10 +186,12 @@ func TestFindJobPropagatesClientError(t *testing.T) {
 }
 
 type fakeClient struct {
-	metadataScanID    string
-	metadataScanInput stashgraphql.ScanMetadataInput
-	job               *stashgraphql.FindJob_FindJob
-	findJobErr        error
+	metadataScanID        string
+	metadataScanInput     stashgraphql.ScanMetadataInput
+	metadataIdentifyID    string
+	metadataIdentifyInput stashgraphql.IdentifyMetadataInput
+	job                   *stashgraphql.FindJob_FindJob
+	findJobErr            error
 }
 
 func (f *fakeClient) MetadataScan(_ context.Context, input stashgraphql.ScanMetadataInput) (string, error) {
@@ -170,6 +199,11 @@ func (f *fakeClient) MetadataScan(_ context.Context, input stashgraphql.ScanMeta
 	return f.metadataScanID, nil
 }
 
+func (f *fakeClient) MetadataIdentify(_ context.Context, input stashgraphql.IdentifyMetadataInput) (string, error) {
+	f.metadataIdentifyInput = input
+	return f.metadataIdentifyID, nil
+}
+
 func (f *fakeClient) FindJob(_ context.Context, _ string) (*stashgraphql.FindJob_FindJob, error) {
 	if f.findJobErr != nil {
 		return nil, f.findJobErr
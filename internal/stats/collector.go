@@ -360,6 +360,7 @@ func (c *Collector) refreshMojiScanCount(ctx context.Context) {
 		}
 		if t.Stage == taskruntime.TaskStagePendingIngest ||
 			t.Stage == taskruntime.TaskStageTransferring ||
+			t.Stage == taskruntime.TaskStageSubtitling ||
 			t.Stage == taskruntime.TaskStageScanning {
 			count++
 		}
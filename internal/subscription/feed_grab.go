@@ -0,0 +1,198 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+var ErrPendingReleaseNotFound = errors.New("subscription: pending release not found")
+
+// FeedTaskCreator is optionally implemented by task creators that can source
+// a release from results found outside a Jackett search, such as Torznab
+// feed items.
+type FeedTaskCreator interface {
+	QueueSubscriptionReleaseFromResults(ctx context.Context, code, title string, origin taskruntime.TaskOrigin, results []jackett.SearchResult) (*taskruntime.Task, error)
+}
+
+// PendingDownload is a pending release the policy decided to download whose
+// auto-download has not created a task yet, usually because no torrent was
+// found when the release was first seen.
+type PendingDownload struct {
+	Key   string
+	Code  string
+	Title string
+}
+
+// PendingDownloads lists the pending releases of subscribed performers and
+// studios that are waiting for a torrent.
+func (s *Service) PendingDownloads(ctx context.Context) ([]PendingDownload, error) {
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PendingDownload, 0)
+	seen := make(map[string]struct{})
+	collect := func(pending []RecordedRelease) {
+		for _, release := range pending {
+			if release.Decision != ReleaseDecisionDownloaded || strings.TrimSpace(release.Code) == "" {
+				continue
+			}
+			if _, ok := seen[release.Key]; ok {
+				continue
+			}
+			seen[release.Key] = struct{}{}
+			out = append(out, PendingDownload{Key: release.Key, Code: release.Code, Title: release.Title})
+		}
+	}
+	for _, state := range states {
+		collect(state.PendingReleases)
+	}
+	if studios, err := s.studioStore(); err == nil {
+		studioStates, err := studios.ListStudios(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range studioStates {
+			collect(state.PendingReleases)
+		}
+	}
+	return out, nil
+}
+
+// GrabPendingDownload creates the task for a pending release from results
+// matched in a Torznab feed and files the release as processed.
+func (s *Service) GrabPendingDownload(ctx context.Context, key string, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	creator, ok := s.taskCreator.(FeedTaskCreator)
+	if !ok {
+		return nil, errors.New("subscription: task creator cannot source releases from feed results")
+	}
+	key = strings.TrimSpace(key)
+
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range states {
+		if hasPendingDownload(snapshot.PendingReleases, key) {
+			return s.grabPerformerPendingDownload(ctx, creator, snapshot.PerformerID, key, results)
+		}
+	}
+
+	studios, err := s.studioStore()
+	if err != nil {
+		return nil, ErrPendingReleaseNotFound
+	}
+	studioStates, err := studios.ListStudios(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range studioStates {
+		if hasPendingDownload(snapshot.PendingReleases, key) {
+			return s.grabStudioPendingDownload(ctx, creator, studios, snapshot.Endpoint, snapshot.StudioID, key, results)
+		}
+	}
+	return nil, ErrPendingReleaseNotFound
+}
+
+func (s *Service) grabPerformerPendingDownload(ctx context.Context, creator FeedTaskCreator, performerID, key string, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	unlock := s.lockPerformerOperation(performerID)
+	defer unlock()
+	state, err := s.store.Get(ctx, performerID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, ErrPendingReleaseNotFound
+	}
+	ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases, lastError: state.LastError}
+	task, err := grabPendingRelease(ctx, creator, &ledger, key, results, func(release RecordedRelease) taskruntime.TaskOrigin {
+		origin := pendingReleaseOrigin(release)
+		origin.StashPerformerID = state.PerformerID
+		return origin
+	})
+	if err != nil {
+		return task, err
+	}
+	state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+	if err := s.store.Put(ctx, state); err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+func (s *Service) grabStudioPendingDownload(ctx context.Context, creator FeedTaskCreator, studios StudioStore, endpoint, studioID, key string, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	unlock := s.lockPerformerOperation(studioOperationKey(endpoint, studioID))
+	defer unlock()
+	state, err := studios.GetStudio(ctx, endpoint, studioID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, ErrPendingReleaseNotFound
+	}
+	ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases, lastError: state.LastError}
+	task, err := grabPendingRelease(ctx, creator, &ledger, key, results, pendingReleaseOrigin)
+	if err != nil {
+		return task, err
+	}
+	state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+	if err := studios.PutStudio(ctx, state); err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+// grabPendingRelease queues the pending release key from results and, once a
+// task exists, moves the release from pending to processed.
+func grabPendingRelease(ctx context.Context, creator FeedTaskCreator, ledger *releaseLedger, key string, results []jackett.SearchResult, origin func(RecordedRelease) taskruntime.TaskOrigin) (*taskruntime.Task, error) {
+	index := -1
+	for i, release := range ledger.pending {
+		if release.Key == key && release.Decision == ReleaseDecisionDownloaded {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrPendingReleaseNotFound
+	}
+	release := ledger.pending[index]
+	task, err := creator.QueueSubscriptionReleaseFromResults(ctx, release.Code, release.Title, origin(release), results)
+	if err != nil {
+		return task, err
+	}
+	if task != nil {
+		release.TaskID = task.ID
+		logging.Infof("subscription: feed grab created task %s for release %s code %q", task.ID, release.Key, release.Code)
+	}
+	ledger.pending = append(append([]RecordedRelease(nil), ledger.pending[:index]...), ledger.pending[index+1:]...)
+	ledger.processed = trimRecordedReleases(append([]RecordedRelease{release}, ledger.processed...), 25)
+	return task, nil
+}
+
+func hasPendingDownload(pending []RecordedRelease, key string) bool {
+	for _, release := range pending {
+		if release.Key == key && release.Decision == ReleaseDecisionDownloaded {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingReleaseOrigin rebuilds the StashBox origin of a recorded release
+// from its source and key.
+func pendingReleaseOrigin(release RecordedRelease) taskruntime.TaskOrigin {
+	endpoint := strings.TrimPrefix(release.Source, "stash-box:")
+	if endpoint == release.Source {
+		return taskruntime.TaskOrigin{}
+	}
+	origin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint}
+	if sceneID, ok := strings.CutPrefix(release.Key, "stashbox:"+endpointKey(endpoint)+":"); ok {
+		origin.StashBoxSceneID = sceneID
+	}
+	return origin
+}
@@ -0,0 +1,62 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+func TestGrabPendingDownloadQueuesFeedResultsAndFilesRelease(t *testing.T) {
+	endpoint := "https://javstash.example.org/graphql"
+	store := NewMemoryStore()
+	if err := store.Put(context.Background(), &PerformerState{
+		PerformerID: "performer-1",
+		PendingReleases: []RecordedRelease{
+			{Key: "stashbox:" + endpointKey(endpoint) + ":scene-1", Source: "stash-box:" + endpoint, Title: "Release One", Code: "SONE-786", Decision: ReleaseDecisionDownloaded},
+			{Key: "stashbox:" + endpointKey(endpoint) + ":scene-2", Source: "stash-box:" + endpoint, Title: "Release Two", Code: "SONE-787", Decision: ReleaseDecisionQueued},
+		},
+	}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	taskRuntime := &fakeTaskRuntime{tasks: []*taskruntime.Task{{ID: "task-1"}}}
+	service, err := newServiceForTest(&fakeStashClient{}, metadata.NewRegistry(stubFactory{}), taskRuntime, store)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	pending, err := service.PendingDownloads(context.Background())
+	if err != nil {
+		t.Fatalf("PendingDownloads failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Code != "SONE-786" {
+		t.Fatalf("expected only the DOWNLOADED release to wait for a torrent, got %+v", pending)
+	}
+
+	results := []jackett.SearchResult{{Title: "SONE-786 feed", MagnetURI: "magnet:?xt=urn:btih:feed"}}
+	task, err := service.GrabPendingDownload(context.Background(), pending[0].Key, results)
+	if err != nil {
+		t.Fatalf("GrabPendingDownload failed: %v", err)
+	}
+	if task == nil || task.ID != "task-1" {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+	wantOrigin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: "scene-1", StashPerformerID: "performer-1"}
+	if taskRuntime.calls != 1 || taskRuntime.origins[0] != wantOrigin || len(taskRuntime.results[0]) != 1 {
+		t.Fatalf("unexpected task request: calls=%d origins=%+v results=%+v", taskRuntime.calls, taskRuntime.origins, taskRuntime.results)
+	}
+
+	state, err := store.Get(context.Background(), "performer-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(state.PendingReleases) != 1 || len(state.ProcessedReleases) != 1 || state.ProcessedReleases[0].TaskID != "task-1" {
+		t.Fatalf("expected the grabbed release to be processed, got %+v", state)
+	}
+	if _, err := service.GrabPendingDownload(context.Background(), pending[0].Key, results); !errors.Is(err, ErrPendingReleaseNotFound) {
+		t.Fatalf("expected ErrPendingReleaseNotFound, got %v", err)
+	}
+}
@@ -3,16 +3,18 @@ package subscription
 import (
 	"context"
 	"sort"
+	"strings"
 	"sync"
 )
 
 type MemoryStore struct {
-	mu     sync.RWMutex
-	states map[string]*PerformerState
+	mu      sync.RWMutex
+	states  map[string]*PerformerState
+	studios map[string]*StudioState
 }
 
 func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{states: make(map[string]*PerformerState)}
+	return &MemoryStore{states: make(map[string]*PerformerState), studios: make(map[string]*StudioState)}
 }
 
 func (s *MemoryStore) Get(_ context.Context, performerID string) (*PerformerState, error) {
@@ -54,12 +56,87 @@ func (s *MemoryStore) List(_ context.Context) ([]*PerformerState, error) {
 	return out, nil
 }
 
+func (s *MemoryStore) GetStudio(_ context.Context, endpoint, studioID string) (*StudioState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, ok := s.studios[studioStateKey(endpoint, studioID)]
+	if !ok {
+		return nil, nil
+	}
+	return cloneStudioState(state), nil
+}
+
+func (s *MemoryStore) PutStudio(_ context.Context, state *StudioState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.studios[studioStateKey(state.Endpoint, state.StudioID)] = cloneStudioState(state)
+	return nil
+}
+
+func (s *MemoryStore) DeleteStudio(_ context.Context, endpoint, studioID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.studios, studioStateKey(endpoint, studioID))
+	return nil
+}
+
+func (s *MemoryStore) ListStudios(_ context.Context) ([]*StudioState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*StudioState, 0, len(s.studios))
+	for _, state := range s.studios {
+		out = append(out, cloneStudioState(state))
+	}
+	sortStudioStates(out)
+	return out, nil
+}
+
+func studioStateKey(endpoint, studioID string) string {
+	return endpoint + "\x00" + studioID
+}
+
+func cloneStudioState(state *StudioState) *StudioState {
+	if state == nil {
+		return nil
+	}
+
+	cloned := *state
+	cloned.ProcessedReleases = cloneRecordedReleases(state.ProcessedReleases)
+	cloned.PendingReleases = cloneRecordedReleases(state.PendingReleases)
+	return &cloned
+}
+
+func cloneRecordedReleases(items []RecordedRelease) []RecordedRelease {
+	cloned := append([]RecordedRelease(nil), items...)
+	for i := range cloned {
+		cloned[i].PerformerNames = append([]string(nil), cloned[i].PerformerNames...)
+	}
+	return cloned
+}
+
+// sortStudioStates orders studios by name so the list is stable between
+// polls.
+func sortStudioStates(states []*StudioState) {
+	sort.Slice(states, func(i, j int) bool {
+		left, right := strings.ToLower(states[i].Name), strings.ToLower(states[j].Name)
+		if left == right {
+			return states[i].StudioID < states[j].StudioID
+		}
+		return left < right
+	})
+}
+
 func cloneState(state *PerformerState) *PerformerState {
 	if state == nil {
 		return nil
 	}
 
 	cloned := *state
+	cloned.ReleasePolicy = cloneReleasePolicy(state.ReleasePolicy)
 	cloned.ProcessedReleases = append([]RecordedRelease(nil), state.ProcessedReleases...)
 	cloned.PendingReleases = append([]RecordedRelease(nil), state.PendingReleases...)
 	for i := range cloned.ProcessedReleases {
@@ -93,6 +93,59 @@ func (s *Service) RefreshSubscribedPerformers(ctx context.Context, ids []string)
 	})
 }
 
+// SetPerformerReleasePolicies stores policy as the release policy override of
+// the selected subscribed performers. A nil policy clears the overrides so
+// the global policy applies again.
+func (s *Service) SetPerformerReleasePolicies(ctx context.Context, ids []string, policy *ReleasePolicyConfig) (PerformerBatchPayload, error) {
+	if policy != nil {
+		effective := policy.Effective()
+		policy = &effective
+	}
+	return s.runPerformerBatch(ctx, "release-policy", ids, func(ctx context.Context, id string) PerformerBatchResult {
+		unlock := s.lockPerformerOperation(id)
+		defer unlock()
+		item, found, err := s.loadBatchPerformer(ctx, id)
+		if err != nil {
+			return PerformerBatchResult{PerformerID: id, Status: PerformerBatchStatusFailed, ReasonCode: PerformerBatchReasonPolicyFailed}
+		}
+		if !found {
+			return PerformerBatchResult{PerformerID: id, Status: PerformerBatchStatusSkipped, ReasonCode: PerformerBatchReasonPerformerNotFound}
+		}
+		if !item.Subscribed {
+			return PerformerBatchResult{PerformerID: id, Status: PerformerBatchStatusSkipped, ReasonCode: PerformerBatchReasonNotSubscribed, Performer: &item}
+		}
+		state, err := s.setPerformerReleasePolicy(ctx, item, policy)
+		if err != nil {
+			return PerformerBatchResult{PerformerID: id, Status: PerformerBatchStatusFailed, ReasonCode: PerformerBatchReasonPolicyFailed, Performer: &item}
+		}
+		return PerformerBatchResult{PerformerID: id, Status: PerformerBatchStatusSucceeded, ReasonCode: PerformerBatchReasonPolicyUpdated, Performer: &item, State: &state}
+	})
+}
+
+func (s *Service) setPerformerReleasePolicy(ctx context.Context, item performer.Performer, policy *ReleasePolicyConfig) (SubscribedPerformer, error) {
+	state, err := s.store.Get(ctx, item.ID)
+	if err != nil {
+		logging.Errorf("subscription: load state for performer %s failed: %v", item.ID, err)
+		return SubscribedPerformer{}, err
+	}
+	if state == nil {
+		state = &PerformerState{PerformerID: item.ID}
+	}
+	state.ReleasePolicy = cloneReleasePolicy(policy)
+	if err := s.store.Put(ctx, state); err != nil {
+		logging.Errorf("subscription: persist release policy for performer %s failed: %v", item.ID, err)
+		return SubscribedPerformer{}, err
+	}
+	if policy == nil {
+		logging.Infof("subscription: cleared release policy override for performer %s (%s)", item.ID, item.Name)
+	} else {
+		logging.Infof("subscription: set release policy override for performer %s (%s)", item.ID, item.Name)
+	}
+	result := buildSubscribedPerformer(item, state)
+	s.publishEvent(PerformerSubscriptionEventUpdated, item.ID, &result)
+	return result, nil
+}
+
 func (s *Service) loadBatchPerformer(ctx context.Context, id string) (performer.Performer, bool, error) {
 	raw, err := s.stash.FindPerformerByID(ctx, id)
 	if err != nil {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 
+	"github.com/leothevan2444/moji/internal/config"
 	"github.com/leothevan2444/moji/internal/performer"
 	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
 )
 
 func TestNormalizePerformerBatchIDs(t *testing.T) {
@@ -76,3 +79,89 @@ func TestBuildSubscribedPerformersUsesProvidedSnapshot(t *testing.T) {
 		t.Fatalf("unexpected subscribed performers: %#v", items)
 	}
 }
+
+func TestSetPerformerReleasePoliciesStoresAndClearsOverrides(t *testing.T) {
+	stash := &fakeStashClient{performers: map[string]*stashgraphql.PerformerFragment{
+		"p1": {ID: "p1", Name: "One", CustomFields: map[string]any{DefaultCustomFieldKey: true}},
+		"p2": {ID: "p2", Name: "Two", CustomFields: map[string]any{}},
+	}}
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "subscriptions.db"))
+	if err != nil {
+		t.Fatalf("new sqlite store: %v", err)
+	}
+	service, err := newServiceForTest(stash, nil, nil, store)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+
+	policy := &ReleasePolicyConfig{SoloBehavior: config.SubscriptionReleaseBehaviorDownload, GroupBehavior: config.SubscriptionReleaseBehaviorDownload}
+	payload, err := service.SetPerformerReleasePolicies(context.Background(), []string{"p1", "p2"}, policy)
+	if err != nil {
+		t.Fatalf("set policies: %v", err)
+	}
+	if payload.Summary.SucceededCount != 1 || payload.Results[0].ReasonCode != PerformerBatchReasonPolicyUpdated || payload.Results[1].ReasonCode != PerformerBatchReasonNotSubscribed {
+		t.Fatalf("unexpected policy batch results: %#v", payload.Results)
+	}
+	state, err := store.Get(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if state.ReleasePolicy == nil || state.ReleasePolicy.GroupBehavior != config.SubscriptionReleaseBehaviorDownload || state.ReleasePolicy.CompilationBehavior != config.SubscriptionReleaseBehaviorReview {
+		t.Fatalf("expected effective override to be stored, got %#v", state.ReleasePolicy)
+	}
+	if payload.Results[0].State == nil || payload.Results[0].State.ReleasePolicy == nil {
+		t.Fatalf("expected the override on the returned state: %#v", payload.Results[0].State)
+	}
+
+	if _, err := service.SetPerformerReleasePolicies(context.Background(), []string{"p1"}, nil); err != nil {
+		t.Fatalf("clear policies: %v", err)
+	}
+	state, err = store.Get(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if state.ReleasePolicy != nil {
+		t.Fatalf("expected override to be cleared, got %#v", state.ReleasePolicy)
+	}
+}
+
+func TestScanReleasesAppliesPerformerPolicyOverride(t *testing.T) {
+	service, err := newServiceForTest(&fakeStashClient{}, nil, nil, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	code := "GRP-001"
+	target := &stashboxgraphql.PerformerFragment{ID: "js-1", Name: "One"}
+	scene := &stashboxgraphql.SceneFragment{ID: "scene-1", Code: &code, Performers: []*stashboxgraphql.PerformerAppearanceFragment{
+		{Performer: target},
+		{Performer: &stashboxgraphql.PerformerFragment{ID: "js-2", Name: "Two"}},
+	}}
+	scan := releaseScan{
+		subject:  "performer=p1",
+		endpoint: "https://stashbox.example/graphql",
+		target:   target,
+		loadPage: func(page, _ int) ([]*stashboxgraphql.SceneFragment, error) {
+			if page > 1 {
+				return nil, nil
+			}
+			return []*stashboxgraphql.SceneFragment{scene}, nil
+		},
+	}
+
+	releases, err := service.scanReleases(scan, nil, nil)
+	if err != nil {
+		t.Fatalf("scan releases: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Decision != ReleaseDecisionQueued {
+		t.Fatalf("expected the global policy to review group scenes, got %#v", releases)
+	}
+
+	scan.policy = &ReleasePolicyConfig{GroupBehavior: config.SubscriptionReleaseBehaviorDownload}
+	releases, err = service.scanReleases(scan, nil, nil)
+	if err != nil {
+		t.Fatalf("scan releases: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Decision != ReleaseDecisionDownloaded || releases[0].DecisionReason != "group_behavior_download" {
+		t.Fatalf("expected the override to download group scenes, got %#v", releases)
+	}
+}
@@ -27,6 +27,16 @@ func DefaultReleasePolicyConfig() ReleasePolicyConfig {
 	return config.DefaultSubscriptionReleasePolicyConfig()
 }
 
+func cloneReleasePolicy(policy *ReleasePolicyConfig) *ReleasePolicyConfig {
+	if policy == nil {
+		return nil
+	}
+	cloned := *policy
+	cloned.CompilationKeywords = append([]string(nil), policy.CompilationKeywords...)
+	cloned.Rules = append([]config.SubscriptionReleaseRuleConfig(nil), policy.Rules...)
+	return &cloned
+}
+
 func evaluateReleasePolicy(policy ReleasePolicyConfig, now time.Time, targetPerformer *stashboxgraphql.PerformerFragment, scene *stashboxgraphql.SceneFragment) (ReleaseEvaluation, bool) {
 	policy = policy.Effective()
 	names, matched := releasePerformerNames(targetPerformer, scene)
@@ -37,7 +47,13 @@ func evaluateReleasePolicy(policy ReleasePolicyConfig, now time.Time, targetPerf
 	evaluation := ReleaseEvaluation{
 		PerformerCount: len(names),
 		PerformerNames: append([]string(nil), names...),
-		Classification: classifyRelease(scene, names, policy.MaxGroupPerformerCount),
+		Classification: classifyRelease(scene, names, policy),
+	}
+
+	if rule, ok := matchReleaseRule(policy.Rules, targetPerformer, scene, len(names)); ok {
+		applyBehavior(&evaluation, rule.Behavior, "rule:"+rule.Name)
+		applyReleaseDateRange(&evaluation, policy.ReleaseDateRange, now, scene)
+		return evaluation, true
 	}
 
 	switch evaluation.Classification {
@@ -132,9 +148,16 @@ func releasePerformerNames(targetPerformer *stashboxgraphql.PerformerFragment, s
 	return names, matchedTarget
 }
 
-func classifyRelease(scene *stashboxgraphql.SceneFragment, performerNames []string, maxGroupPerformerCount int) ReleaseClassification {
+// ClassifyScene classifies a StashBox scene with the current release policy,
+// counting every credited performer.
+func (s *Service) ClassifyScene(scene *stashboxgraphql.SceneFragment) string {
+	names, _ := releasePerformerNames(nil, scene)
+	return string(classifyRelease(scene, names, s.currentReleasePolicy()))
+}
+
+func classifyRelease(scene *stashboxgraphql.SceneFragment, performerNames []string, policy ReleasePolicyConfig) ReleaseClassification {
 	count := len(performerNames)
-	if isCompilationLikeScene(scene, count) {
+	if isCompilationLikeScene(scene, count, policy.CompilationKeywords) {
 		return ReleaseClassificationCompilationLike
 	}
 	switch {
@@ -142,17 +165,22 @@ func classifyRelease(scene *stashboxgraphql.SceneFragment, performerNames []stri
 		return ReleaseClassificationUnknown
 	case count == 1:
 		return ReleaseClassificationSolo
-	case count <= config.NormalizeSubscriptionReleaseMaxGroupPerformerCount(maxGroupPerformerCount):
+	case count <= config.NormalizeSubscriptionReleaseMaxGroupPerformerCount(policy.MaxGroupPerformerCount):
 		return ReleaseClassificationSmallGroup
 	default:
 		return ReleaseClassificationLargeGroup
 	}
 }
 
-func isCompilationLikeScene(scene *stashboxgraphql.SceneFragment, performerCount int) bool {
+// isCompilationLikeScene reports whether scene looks like a compilation.
+// keywords replace compilationLikeKeywords when set.
+func isCompilationLikeScene(scene *stashboxgraphql.SceneFragment, performerCount int, keywords []string) bool {
 	if scene == nil {
 		return false
 	}
+	if len(keywords) == 0 {
+		keywords = compilationLikeKeywords
+	}
 	if performerCount >= compilationLikePerformerThreshold {
 		return true
 	}
@@ -167,7 +195,7 @@ func isCompilationLikeScene(scene *stashboxgraphql.SceneFragment, performerCount
 		fields = append(fields, normalizeForKeywordMatch(tag.Name))
 	}
 	for _, field := range fields {
-		for _, keyword := range compilationLikeKeywords {
+		for _, keyword := range keywords {
 			if strings.Contains(field, normalizeForKeywordMatch(keyword)) {
 				return true
 			}
@@ -176,6 +204,115 @@ func isCompilationLikeScene(scene *stashboxgraphql.SceneFragment, performerCount
 	return false
 }
 
+// matchReleaseRule returns the first rule whose conditions all hold for
+// scene. performerCount counts every credited performer.
+func matchReleaseRule(rules []config.SubscriptionReleaseRuleConfig, targetPerformer *stashboxgraphql.PerformerFragment, scene *stashboxgraphql.SceneFragment, performerCount int) (config.SubscriptionReleaseRuleConfig, bool) {
+	if scene == nil {
+		return config.SubscriptionReleaseRuleConfig{}, false
+	}
+	for _, rule := range rules {
+		if releaseRuleMatches(rule, targetPerformer, scene, performerCount) {
+			return rule, true
+		}
+	}
+	return config.SubscriptionReleaseRuleConfig{}, false
+}
+
+func releaseRuleMatches(rule config.SubscriptionReleaseRuleConfig, targetPerformer *stashboxgraphql.PerformerFragment, scene *stashboxgraphql.SceneFragment, performerCount int) bool {
+	tags := make([]string, 0, len(scene.Tags))
+	for _, tag := range scene.Tags {
+		if tag != nil {
+			tags = append(tags, tag.Name)
+		}
+	}
+	if len(rule.TagsInclude) > 0 && !anyKeywordEquals(rule.TagsInclude, tags...) {
+		return false
+	}
+	if len(rule.TagsExclude) > 0 && anyKeywordEquals(rule.TagsExclude, tags...) {
+		return false
+	}
+	if len(rule.Studios) > 0 {
+		if scene.Studio == nil || !anyKeywordEquals(rule.Studios, scene.Studio.Name, scene.Studio.ID) {
+			return false
+		}
+	}
+	if rule.MinDurationMinutes > 0 || rule.MaxDurationMinutes > 0 {
+		if scene.Duration == nil || *scene.Duration <= 0 {
+			return false
+		}
+		minutes := *scene.Duration / 60
+		if minutes < rule.MinDurationMinutes || (rule.MaxDurationMinutes > 0 && minutes > rule.MaxDurationMinutes) {
+			return false
+		}
+	}
+	title := stringValue(scene.Title)
+	if len(rule.TitleKeywords) > 0 {
+		normalizedTitle := normalizeForKeywordMatch(title)
+		found := false
+		for _, keyword := range rule.TitleKeywords {
+			if strings.Contains(normalizedTitle, normalizeForKeywordMatch(keyword)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.TitleRegex != "" {
+		// Invalid patterns are rejected when the config is loaded; one that
+		// slipped through matches nothing.
+		pattern := rule.TitlePattern()
+		if pattern == nil || !pattern.MatchString(title) {
+			return false
+		}
+	}
+	if performerCount < rule.MinPerformers || (rule.MaxPerformers > 0 && performerCount > rule.MaxPerformers) {
+		return false
+	}
+	if len(rule.CoPerformers) > 0 && !hasCoPerformer(rule.CoPerformers, targetPerformer, scene) {
+		return false
+	}
+	return true
+}
+
+// hasCoPerformer reports whether a performer other than the subscribed one
+// is listed in names, by name or StashBox ID.
+func hasCoPerformer(names []string, targetPerformer *stashboxgraphql.PerformerFragment, scene *stashboxgraphql.SceneFragment) bool {
+	targetID := ""
+	if targetPerformer != nil {
+		targetID = strings.TrimSpace(targetPerformer.ID)
+	}
+	for _, appearance := range scene.Performers {
+		if appearance == nil || appearance.Performer == nil {
+			continue
+		}
+		performer := appearance.Performer
+		if targetID != "" && strings.TrimSpace(performer.ID) == targetID {
+			continue
+		}
+		if anyKeywordEquals(names, performer.Name, performer.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyKeywordEquals(keywords []string, values ...string) bool {
+	for _, value := range values {
+		value = normalizeForKeywordMatch(value)
+		if value == "" {
+			continue
+		}
+		for _, keyword := range keywords {
+			if normalizeForKeywordMatch(keyword) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func normalizeForKeywordMatch(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
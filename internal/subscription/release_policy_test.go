@@ -163,3 +163,78 @@ func TestEvaluateReleasePolicyReleaseDateUnknownReviews(t *testing.T) {
 		t.Fatalf("unexpected unknown-date evaluation: %+v", evaluation)
 	}
 }
+
+func TestEvaluateReleasePolicyFirstMatchingRuleDecides(t *testing.T) {
+	target := &stashboxgraphql.PerformerFragment{ID: "p1", Name: "Actor A"}
+	title := "Summer Special VR"
+	duration := 150 * 60
+	scene := releasePolicyScene("scene-7", target, target, &stashboxgraphql.PerformerFragment{ID: "p2", Name: "Actor B"})
+	scene.Title = &title
+	scene.Duration = &duration
+	scene.Studio = &stashboxgraphql.StudioFragment{ID: "s1", Name: "Studio One"}
+	scene.Tags = []*stashboxgraphql.TagFragment{{ID: "t1", Name: "Virtual Reality"}}
+
+	policy := config.SubscriptionReleasePolicyConfig{
+		SoloBehavior:           config.SubscriptionReleaseBehaviorDownload,
+		GroupBehavior:          config.SubscriptionReleaseBehaviorReview,
+		CompilationBehavior:    config.SubscriptionReleaseBehaviorBlock,
+		MaxGroupPerformerCount: 3,
+		Rules: []config.SubscriptionReleaseRuleConfig{
+			{Name: "short", Behavior: config.SubscriptionReleaseBehaviorBlock, MaxDurationMinutes: 30},
+			{Name: "favourite pair", Behavior: config.SubscriptionReleaseBehaviorDownload, Studios: []string{"studio one"}, CoPerformers: []string{"Actor B"}, MaxPerformers: 2},
+			{Name: "vr", Behavior: config.SubscriptionReleaseBehaviorBlock, TagsInclude: []string{"virtual reality"}},
+		},
+	}
+	now := time.Date(2026, 7, 8, 0, 0, 0, 0, time.UTC)
+	evaluation, matched := evaluateReleasePolicy(policy, now, target, scene)
+	if !matched {
+		t.Fatalf("expected target performer to match")
+	}
+	if evaluation.Decision != ReleaseDecisionDownloaded || evaluation.DecisionReason != "rule:favourite pair_download" {
+		t.Fatalf("expected the co-performer rule to download, got %+v", evaluation)
+	}
+
+	policy.Rules[1].CoPerformers = []string{"Actor A"}
+	evaluation, _ = evaluateReleasePolicy(policy, now, target, scene)
+	if evaluation.Decision != ReleaseDecisionBlocked || evaluation.DecisionReason != "rule:vr_block" {
+		t.Fatalf("expected the subscribed performer not to count as a co-performer, got %+v", evaluation)
+	}
+
+	policy.Rules = []config.SubscriptionReleaseRuleConfig{
+		{Name: "not vr", Behavior: config.SubscriptionReleaseBehaviorDownload, TagsExclude: []string{"Virtual Reality"}},
+		{Name: "regex", Behavior: config.SubscriptionReleaseBehaviorReview, TitleRegex: `(?i)special\s+vr$`, MinDurationMinutes: 120},
+	}
+	evaluation, _ = evaluateReleasePolicy(policy, now, target, scene)
+	if evaluation.Decision != ReleaseDecisionQueued || evaluation.DecisionReason != "rule:regex_review" {
+		t.Fatalf("expected the title regex rule to review, got %+v", evaluation)
+	}
+
+	policy.Rules = []config.SubscriptionReleaseRuleConfig{{Name: "keywords", Behavior: config.SubscriptionReleaseBehaviorBlock, TitleKeywords: []string{"winter"}}}
+	evaluation, _ = evaluateReleasePolicy(policy, now, target, scene)
+	if evaluation.DecisionReason != "group_behavior_review" {
+		t.Fatalf("expected the group behavior when no rule matches, got %+v", evaluation)
+	}
+}
+
+func TestEvaluateReleasePolicyCompilationKeywordsReplaceDefaults(t *testing.T) {
+	target := &stashboxgraphql.PerformerFragment{ID: "p1", Name: "Actor A"}
+	title := "Best of Spring"
+	scene := releasePolicyScene("scene-8", target, target)
+	scene.Title = &title
+
+	policy := config.SubscriptionReleasePolicyConfig{
+		SoloBehavior:           config.SubscriptionReleaseBehaviorDownload,
+		CompilationBehavior:    config.SubscriptionReleaseBehaviorBlock,
+		MaxGroupPerformerCount: 3,
+	}
+	evaluation, _ := evaluateReleasePolicy(policy, time.Date(2026, 7, 8, 0, 0, 0, 0, time.UTC), target, scene)
+	if evaluation.Classification != ReleaseClassificationCompilationLike {
+		t.Fatalf("expected the built-in keywords to flag a compilation, got %+v", evaluation)
+	}
+
+	policy.CompilationKeywords = []string{"recap"}
+	evaluation, _ = evaluateReleasePolicy(policy, time.Date(2026, 7, 8, 0, 0, 0, 0, time.UTC), target, scene)
+	if evaluation.Classification != ReleaseClassificationSolo {
+		t.Fatalf("expected configured keywords to replace the defaults, got %+v", evaluation)
+	}
+}
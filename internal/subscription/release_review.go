@@ -0,0 +1,393 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+const MaxReleaseReviewBatchSize = 100
+
+var (
+	ErrReleaseReviewUnavailable = errors.New("subscription: store does not support the release review queue")
+	ErrReleaseReviewEmpty       = errors.New("release review requires at least one release")
+	ErrReleaseReviewTooLarge    = errors.New("release review exceeds maximum size")
+	ErrReleaseReviewSnooze      = errors.New("release review snooze must be positive")
+)
+
+// ReleaseReviewFilter selects QUEUED releases for the review queue. Empty
+// lists match everything; DateFrom and DateTo are inclusive YYYY-MM-DD bounds
+// on the release date and exclude undated releases when set.
+type ReleaseReviewFilter struct {
+	Reasons         []string
+	Classifications []ReleaseClassification
+	DateFrom        string
+	DateTo          string
+	Page            int
+	PageSize        int
+}
+
+// QueuedRelease is a QUEUED release with the subscriptions it was found by.
+type QueuedRelease struct {
+	Release      RecordedRelease
+	PerformerIDs []string
+	Studios      []StudioKey
+}
+
+type StudioKey struct {
+	Endpoint string
+	StudioID string
+}
+
+type ReleaseReviewPage struct {
+	Items      []QueuedRelease
+	Page       int
+	PageSize   int
+	TotalCount int
+}
+
+type ReleaseReviewStatus string
+
+const (
+	ReleaseReviewStatusSucceeded ReleaseReviewStatus = "SUCCEEDED"
+	ReleaseReviewStatusSkipped   ReleaseReviewStatus = "SKIPPED"
+	ReleaseReviewStatusFailed    ReleaseReviewStatus = "FAILED"
+)
+
+const (
+	ReleaseReviewReasonApproved    = "APPROVED"
+	ReleaseReviewReasonRejected    = "REJECTED"
+	ReleaseReviewReasonSnoozed     = "SNOOZED"
+	ReleaseReviewReasonNotQueued   = "NOT_QUEUED"
+	ReleaseReviewReasonTaskFailed  = "TASK_CREATE_FAILED"
+	ReleaseReviewReasonStoreFailed = "STORE_UPDATE_FAILED"
+	ReleaseReviewReasonCancelled   = "CANCELLED"
+)
+
+type ReleaseReviewResult struct {
+	Key        string
+	Status     ReleaseReviewStatus
+	ReasonCode string
+	Task       *taskruntime.Task
+}
+
+type ReleaseReviewSummary struct {
+	RequestedCount int
+	SucceededCount int
+	SkippedCount   int
+	FailedCount    int
+}
+
+type ReleaseReviewPayload struct {
+	Summary ReleaseReviewSummary
+	Results []ReleaseReviewResult
+}
+
+// releaseOwner is a performer or studio subscription holding a release.
+type releaseOwner struct {
+	performerID string
+	studio      StudioKey
+}
+
+func (s *Service) releaseReviewStore() (ReleaseReviewStore, error) {
+	store, ok := s.store.(ReleaseReviewStore)
+	if !ok {
+		return nil, ErrReleaseReviewUnavailable
+	}
+	return store, nil
+}
+
+// ListQueuedReleases pages through the QUEUED releases of every subscribed
+// performer and studio, newest first. Snoozed releases are hidden until
+// their snooze expires.
+func (s *Service) ListQueuedReleases(ctx context.Context, filter ReleaseReviewFilter) (ReleaseReviewPage, error) {
+	store, err := s.releaseReviewStore()
+	if err != nil {
+		return ReleaseReviewPage{}, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = 24
+	}
+	filter.Reasons = cleanReviewValues(filter.Reasons)
+	items, total, err := store.ListQueuedReleases(ctx, filter, s.now().UTC())
+	if err != nil {
+		return ReleaseReviewPage{}, err
+	}
+	return ReleaseReviewPage{Items: items, Page: filter.Page, PageSize: filter.PageSize, TotalCount: total}, nil
+}
+
+// ApproveQueuedReleases creates a download task for each QUEUED release and
+// files it as processed for every subscription holding it.
+func (s *Service) ApproveQueuedReleases(ctx context.Context, keys []string) (ReleaseReviewPayload, error) {
+	if s.taskCreator == nil {
+		return ReleaseReviewPayload{}, errors.New("subscription: task creator is not configured")
+	}
+	return s.reviewQueuedReleases(ctx, "approve", keys, func(ctx context.Context, release RecordedRelease, owner releaseOwner) ReleaseReviewResult {
+		origin := pendingReleaseOrigin(release)
+		origin.StashPerformerID = owner.performerID
+		task, err := s.taskCreator.QueueSubscriptionRelease(ctx, release.Code, release.Title, origin)
+		if err != nil {
+			logging.Errorf("subscription: review approve failed to create task for release %s code %q: %v", release.Key, release.Code, err)
+			return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonTaskFailed}
+		}
+		release.Decision = ReleaseDecisionDownloaded
+		release.DecisionReason = "review_approved"
+		if task != nil {
+			release.TaskID = task.ID
+		}
+		if err := s.fileQueuedRelease(ctx, release); err != nil {
+			logging.Errorf("subscription: review approve failed to file release %s: %v", release.Key, err)
+			return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonStoreFailed, Task: task}
+		}
+		return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusSucceeded, ReasonCode: ReleaseReviewReasonApproved, Task: task}
+	})
+}
+
+// RejectQueuedReleases records a rejection for each QUEUED release so later
+// refreshes never queue it again, and files it as processed.
+func (s *Service) RejectQueuedReleases(ctx context.Context, keys []string) (ReleaseReviewPayload, error) {
+	store, err := s.releaseReviewStore()
+	if err != nil {
+		return ReleaseReviewPayload{}, err
+	}
+	return s.reviewQueuedReleases(ctx, "reject", keys, func(ctx context.Context, release RecordedRelease, _ releaseOwner) ReleaseReviewResult {
+		if err := store.RejectReleases(ctx, []string{release.Key}, s.now().UTC()); err != nil {
+			logging.Errorf("subscription: review reject failed to record release %s: %v", release.Key, err)
+			return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonStoreFailed}
+		}
+		release.Decision = ReleaseDecisionRejected
+		release.DecisionReason = "review_rejected"
+		if err := s.fileQueuedRelease(ctx, release); err != nil {
+			logging.Errorf("subscription: review reject failed to file release %s: %v", release.Key, err)
+			return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonStoreFailed}
+		}
+		return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusSucceeded, ReasonCode: ReleaseReviewReasonRejected}
+	})
+}
+
+// SnoozeQueuedReleases hides each QUEUED release from the review queue for
+// duration. The releases stay pending.
+func (s *Service) SnoozeQueuedReleases(ctx context.Context, keys []string, duration time.Duration) (ReleaseReviewPayload, error) {
+	store, err := s.releaseReviewStore()
+	if err != nil {
+		return ReleaseReviewPayload{}, err
+	}
+	if duration <= 0 {
+		return ReleaseReviewPayload{}, ErrReleaseReviewSnooze
+	}
+	return s.reviewQueuedReleases(ctx, "snooze", keys, func(ctx context.Context, release RecordedRelease, _ releaseOwner) ReleaseReviewResult {
+		now := s.now().UTC()
+		if err := store.SnoozeReleases(ctx, []string{release.Key}, now.Add(duration), now); err != nil {
+			logging.Errorf("subscription: review snooze failed for release %s: %v", release.Key, err)
+			return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonStoreFailed}
+		}
+		return ReleaseReviewResult{Key: release.Key, Status: ReleaseReviewStatusSucceeded, ReasonCode: ReleaseReviewReasonSnoozed}
+	})
+}
+
+// reviewQueuedReleases resolves keys to QUEUED pending releases and applies
+// action to each one found. Keys that are not queued are skipped.
+func (s *Service) reviewQueuedReleases(ctx context.Context, action string, keys []string, apply func(context.Context, RecordedRelease, releaseOwner) ReleaseReviewResult) (ReleaseReviewPayload, error) {
+	cleaned, err := normalizeReleaseReviewKeys(keys)
+	if err != nil {
+		return ReleaseReviewPayload{}, err
+	}
+	queued, err := s.queuedReleasesByKey(ctx)
+	if err != nil {
+		return ReleaseReviewPayload{}, err
+	}
+
+	payload := ReleaseReviewPayload{Results: make([]ReleaseReviewResult, 0, len(cleaned))}
+	for _, key := range cleaned {
+		result := ReleaseReviewResult{Key: key, Status: ReleaseReviewStatusFailed, ReasonCode: ReleaseReviewReasonCancelled}
+		if ctx.Err() == nil {
+			if entry, ok := queued[key]; ok {
+				result = apply(ctx, entry.release, entry.owner)
+			} else {
+				result = ReleaseReviewResult{Key: key, Status: ReleaseReviewStatusSkipped, ReasonCode: ReleaseReviewReasonNotQueued}
+			}
+		}
+		payload.Results = append(payload.Results, result)
+		switch result.Status {
+		case ReleaseReviewStatusSucceeded:
+			payload.Summary.SucceededCount++
+		case ReleaseReviewStatusSkipped:
+			payload.Summary.SkippedCount++
+		default:
+			payload.Summary.FailedCount++
+		}
+	}
+	payload.Summary.RequestedCount = len(payload.Results)
+	logging.Infof("subscription: release review completed action=%s requested=%d succeeded=%d skipped=%d failed=%d", action, payload.Summary.RequestedCount, payload.Summary.SucceededCount, payload.Summary.SkippedCount, payload.Summary.FailedCount)
+	return payload, nil
+}
+
+type queuedReleaseEntry struct {
+	release RecordedRelease
+	owner   releaseOwner
+}
+
+// queuedReleasesByKey indexes the QUEUED pending releases by key, keeping
+// the first performer or studio found for each.
+func (s *Service) queuedReleasesByKey(ctx context.Context) (map[string]queuedReleaseEntry, error) {
+	out := make(map[string]queuedReleaseEntry)
+	collect := func(pending []RecordedRelease, owner releaseOwner) {
+		for _, release := range pending {
+			if release.Decision != ReleaseDecisionQueued {
+				continue
+			}
+			if _, ok := out[release.Key]; !ok {
+				out[release.Key] = queuedReleaseEntry{release: release, owner: owner}
+			}
+		}
+	}
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		collect(state.PendingReleases, releaseOwner{performerID: state.PerformerID})
+	}
+	if studios, err := s.studioStore(); err == nil {
+		studioStates, err := studios.ListStudios(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range studioStates {
+			collect(state.PendingReleases, releaseOwner{studio: StudioKey{Endpoint: state.Endpoint, StudioID: state.StudioID}})
+		}
+	}
+	return out, nil
+}
+
+// fileQueuedRelease replaces the QUEUED pending release with release.Key in
+// every performer and studio holding it and moves it to processed.
+func (s *Service) fileQueuedRelease(ctx context.Context, release RecordedRelease) error {
+	file := func(ledger *releaseLedger) bool {
+		for i, pending := range ledger.pending {
+			if pending.Key != release.Key || pending.Decision != ReleaseDecisionQueued {
+				continue
+			}
+			ledger.pending = append(append([]RecordedRelease(nil), ledger.pending[:i]...), ledger.pending[i+1:]...)
+			ledger.processed = trimRecordedReleases(append([]RecordedRelease{release}, ledger.processed...), 25)
+			return true
+		}
+		return false
+	}
+
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range states {
+		if !hasQueuedRelease(snapshot.PendingReleases, release.Key) {
+			continue
+		}
+		if err := func() error {
+			unlock := s.lockPerformerOperation(snapshot.PerformerID)
+			defer unlock()
+			state, err := s.store.Get(ctx, snapshot.PerformerID)
+			if err != nil || state == nil {
+				return err
+			}
+			ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases}
+			if !file(&ledger) {
+				return nil
+			}
+			state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+			return s.store.Put(ctx, state)
+		}(); err != nil {
+			return fmt.Errorf("performer %s: %w", snapshot.PerformerID, err)
+		}
+	}
+
+	studios, err := s.studioStore()
+	if err != nil {
+		return nil
+	}
+	studioStates, err := studios.ListStudios(ctx)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range studioStates {
+		if !hasQueuedRelease(snapshot.PendingReleases, release.Key) {
+			continue
+		}
+		if err := func() error {
+			unlock := s.lockPerformerOperation(studioOperationKey(snapshot.Endpoint, snapshot.StudioID))
+			defer unlock()
+			state, err := studios.GetStudio(ctx, snapshot.Endpoint, snapshot.StudioID)
+			if err != nil || state == nil {
+				return err
+			}
+			ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases}
+			if !file(&ledger) {
+				return nil
+			}
+			state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+			return studios.PutStudio(ctx, state)
+		}(); err != nil {
+			return fmt.Errorf("studio %s/%s: %w", snapshot.Endpoint, snapshot.StudioID, err)
+		}
+	}
+	return nil
+}
+
+// rejectedReleaseKeys returns the keys among releases rejected in review.
+// Stores without a review queue reject nothing.
+func (s *Service) rejectedReleaseKeys(ctx context.Context, releases []Release) (map[string]struct{}, error) {
+	store, err := s.releaseReviewStore()
+	if err != nil || len(releases) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(releases))
+	for _, release := range releases {
+		keys = append(keys, release.Key)
+	}
+	return store.RejectedReleaseKeys(ctx, keys)
+}
+
+func hasQueuedRelease(pending []RecordedRelease, key string) bool {
+	for _, release := range pending {
+		if release.Key == key && release.Decision == ReleaseDecisionQueued {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeReleaseReviewKeys(keys []string) ([]string, error) {
+	out := cleanReviewValues(keys)
+	if len(out) == 0 {
+		return nil, ErrReleaseReviewEmpty
+	}
+	if len(out) > MaxReleaseReviewBatchSize {
+		return nil, fmt.Errorf("%w: maximum is %d", ErrReleaseReviewTooLarge, MaxReleaseReviewBatchSize)
+	}
+	return out, nil
+}
+
+func cleanReviewValues(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, raw := range values {
+		value := strings.TrimSpace(raw)
+		if value == "" {
+			continue
+		}
+		if _, exists := seen[value]; exists {
+			continue
+		}
+		seen[value] = struct{}{}
+		out = append(out, value)
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package subscription
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+func TestReleaseReviewQueueFiltersAndActsAcrossSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	endpoint := "https://javstash.example.org/graphql"
+	key := func(sceneID string) string { return "stashbox:" + endpointKey(endpoint) + ":" + sceneID }
+	source := "stash-box:" + endpoint
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "review.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.db.Close() })
+
+	seenAt := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	group := RecordedRelease{Key: key("scene-1"), Source: source, Title: "Group", Code: "SONE-001", Date: "2026-06-01", SeenAt: seenAt, Classification: ReleaseClassificationSmallGroup, Decision: ReleaseDecisionQueued, DecisionReason: "group_behavior_review"}
+	unknown := RecordedRelease{Key: key("scene-2"), Source: source, Title: "Unknown", Code: "SONE-002", SeenAt: seenAt.Add(time.Hour), Classification: ReleaseClassificationUnknown, Decision: ReleaseDecisionQueued, DecisionReason: "metadata_unknown_review"}
+	waiting := RecordedRelease{Key: key("scene-3"), Source: source, Title: "Waiting", Code: "SONE-003", SeenAt: seenAt, Decision: ReleaseDecisionDownloaded}
+	studioOnly := RecordedRelease{Key: key("scene-4"), Source: source, Title: "Studio", Code: "SONE-004", Date: "2025-01-01", SeenAt: seenAt.Add(2 * time.Hour), Classification: ReleaseClassificationSolo, Decision: ReleaseDecisionQueued, DecisionReason: "release_date_out_of_range_review"}
+	for _, state := range []*PerformerState{
+		{PerformerID: "performer-1", PendingReleases: []RecordedRelease{group, unknown, waiting}},
+		{PerformerID: "performer-2", PendingReleases: []RecordedRelease{group}},
+	} {
+		if err := store.Put(ctx, state); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if err := store.PutStudio(ctx, &StudioState{Endpoint: endpoint, StudioID: "studio-1", SubscribedAt: seenAt, PendingReleases: []RecordedRelease{studioOnly}}); err != nil {
+		t.Fatalf("PutStudio failed: %v", err)
+	}
+
+	taskRuntime := &fakeTaskRuntime{tasks: []*taskruntime.Task{{ID: "task-1"}}}
+	service, err := newServiceForTest(&fakeStashClient{}, metadata.NewRegistry(stubFactory{}), taskRuntime, store)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	now := seenAt.Add(24 * time.Hour)
+	service.now = func() time.Time { return now }
+
+	page, err := service.ListQueuedReleases(ctx, ReleaseReviewFilter{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListQueuedReleases failed: %v", err)
+	}
+	if page.TotalCount != 3 || len(page.Items) != 2 || page.Items[0].Release.Key != studioOnly.Key || page.Items[1].Release.Key != unknown.Key {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if studios := page.Items[0].Studios; len(studios) != 1 || studios[0] != (StudioKey{Endpoint: endpoint, StudioID: "studio-1"}) {
+		t.Fatalf("expected the studio owner, got %+v", studios)
+	}
+	page, err = service.ListQueuedReleases(ctx, ReleaseReviewFilter{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListQueuedReleases failed: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Release.Key != group.Key || len(page.Items[0].PerformerIDs) != 2 {
+		t.Fatalf("expected the shared group release on page two, got %+v", page.Items)
+	}
+
+	for name, filter := range map[string]ReleaseReviewFilter{
+		"reason":         {Reasons: []string{" metadata_unknown_review "}},
+		"classification": {Classifications: []ReleaseClassification{ReleaseClassificationUnknown}},
+	} {
+		page, err := service.ListQueuedReleases(ctx, filter)
+		if err != nil {
+			t.Fatalf("%s: ListQueuedReleases failed: %v", name, err)
+		}
+		if page.TotalCount != 1 || page.Items[0].Release.Key != unknown.Key {
+			t.Fatalf("%s: unexpected items %+v", name, page.Items)
+		}
+	}
+	page, err = service.ListQueuedReleases(ctx, ReleaseReviewFilter{DateFrom: "2026-01-01", DateTo: "2026-12-31"})
+	if err != nil {
+		t.Fatalf("ListQueuedReleases failed: %v", err)
+	}
+	if page.TotalCount != 1 || page.Items[0].Release.Key != group.Key {
+		t.Fatalf("expected only the dated 2026 release, got %+v", page.Items)
+	}
+
+	approved, err := service.ApproveQueuedReleases(ctx, []string{group.Key, waiting.Key})
+	if err != nil {
+		t.Fatalf("ApproveQueuedReleases failed: %v", err)
+	}
+	if approved.Summary.SucceededCount != 1 || approved.Summary.SkippedCount != 1 || approved.Results[0].Task == nil || approved.Results[1].ReasonCode != ReleaseReviewReasonNotQueued {
+		t.Fatalf("unexpected approve payload: %+v", approved)
+	}
+	wantOrigin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: "scene-1", StashPerformerID: "performer-1"}
+	if taskRuntime.calls != 1 || taskRuntime.origins[0] != wantOrigin {
+		t.Fatalf("expected one task for the approved release, got calls=%d origins=%+v", taskRuntime.calls, taskRuntime.origins)
+	}
+	for _, performerID := range []string{"performer-1", "performer-2"} {
+		state, err := store.Get(ctx, performerID)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if hasQueuedRelease(state.PendingReleases, group.Key) || len(state.ProcessedReleases) != 1 || state.ProcessedReleases[0].Decision != ReleaseDecisionDownloaded {
+			t.Fatalf("expected %s to file the approved release, got %+v", performerID, state)
+		}
+	}
+
+	if payload, err := service.RejectQueuedReleases(ctx, []string{unknown.Key}); err != nil || payload.Summary.SucceededCount != 1 {
+		t.Fatalf("RejectQueuedReleases failed: %+v %v", payload, err)
+	}
+	if payload, err := service.SnoozeQueuedReleases(ctx, []string{studioOnly.Key}, 48*time.Hour); err != nil || payload.Summary.SucceededCount != 1 {
+		t.Fatalf("SnoozeQueuedReleases failed: %+v %v", payload, err)
+	}
+	page, err = service.ListQueuedReleases(ctx, ReleaseReviewFilter{})
+	if err != nil {
+		t.Fatalf("ListQueuedReleases failed: %v", err)
+	}
+	if page.TotalCount != 0 {
+		t.Fatalf("expected an empty queue, got %+v", page.Items)
+	}
+	now = now.Add(72 * time.Hour)
+	page, err = service.ListQueuedReleases(ctx, ReleaseReviewFilter{})
+	if err != nil {
+		t.Fatalf("ListQueuedReleases failed: %v", err)
+	}
+	if page.TotalCount != 1 || page.Items[0].Release.Key != studioOnly.Key {
+		t.Fatalf("expected the snoozed release back after the snooze, got %+v", page.Items)
+	}
+
+	// A rejected release that fell out of the history is not queued again.
+	ledger := releaseLedger{}
+	if _, err := service.recordReleases(ctx, "performer test", &ledger, []Release{{Key: unknown.Key, Source: source, Code: unknown.Code, Decision: ReleaseDecisionQueued}}, now, func(Release) taskruntime.TaskOrigin { return taskruntime.TaskOrigin{} }); err != nil {
+		t.Fatalf("recordReleases failed: %v", err)
+	}
+	if len(ledger.pending) != 0 {
+		t.Fatalf("expected the rejected release to be skipped, got %+v", ledger.pending)
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/imagecache"
 	"github.com/leothevan2444/moji/internal/logging"
 	"github.com/leothevan2444/moji/internal/metadata"
 	"github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/tracker"
 	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
 	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
 )
@@ -27,8 +29,15 @@ type StashClient interface {
 	UpdatePerformerCustomFields(ctx context.Context, id string, partial map[string]any, remove []string) (*stashgraphql.PerformerFragment, error)
 }
 
+// LibraryIndex answers in-library checks from a local index of the Stash
+// library instead of querying Stash for every release.
+type LibraryIndex interface {
+	HasStashID(ctx context.Context, endpoint, stashID string) (bool, error)
+	HasCode(ctx context.Context, code string) (bool, error)
+}
+
 type TaskCreator interface {
-	QueueSubscriptionRelease(ctx context.Context, code, title string) (*taskruntime.Task, error)
+	QueueSubscriptionRelease(ctx context.Context, code, title string, origin taskruntime.TaskOrigin) (*taskruntime.Task, error)
 }
 
 type Service struct {
@@ -41,9 +50,12 @@ type Service struct {
 	customFieldKey   string
 	now              func() time.Time
 	eventPublisher   PerformerSubscriptionEventPublisher
+	libraryIndex     LibraryIndex
 
 	policyMu      sync.RWMutex
 	releasePolicy ReleasePolicyConfig
+	upcoming      UpcomingConfigProvider
+	searcher      tracker.Tracker
 	operationMu   sync.Mutex
 	operations    map[string]*performerOperationLock
 }
@@ -77,6 +89,7 @@ const (
 	releaseQueryPerPage          = 40
 	releaseQueryPollMaxPages     = 2
 	releaseQueryBackfillMaxPages = 6
+	pendingReleaseLimit          = 25
 )
 
 type releaseFetchMode string
@@ -127,6 +140,13 @@ func (s *Service) SetTaskCreator(creator TaskCreator) {
 	s.taskCreator = creator
 }
 
+func (s *Service) SetLibraryIndex(index LibraryIndex) {
+	if s == nil {
+		return
+	}
+	s.libraryIndex = index
+}
+
 func (s *Service) SetEventPublisher(publisher PerformerSubscriptionEventPublisher) {
 	if s == nil {
 		return
@@ -277,30 +297,87 @@ func (s *Service) refreshSubscribedPerformer(ctx context.Context, performerID st
 	}
 	logging.Infof("subscription: refresh fetched %d releases for performer %s (%s)", len(releases), performerID, performer.Name)
 
-	processed := make(map[string]RecordedRelease, len(state.ProcessedReleases)+len(state.PendingReleases))
-	for _, release := range state.ProcessedReleases {
+	ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases, lastError: state.LastError}
+	subject := fmt.Sprintf("performer %s (%s)", performerID, performer.Name)
+	skippedInLibrary, err := s.recordReleases(ctx, subject, &ledger, releases, now, func(release Release) taskruntime.TaskOrigin {
+		return taskruntime.TaskOrigin{
+			StashBoxEndpoint: strings.TrimPrefix(release.Source, "stash-box:"),
+			StashBoxSceneID:  release.SceneID,
+			StashPerformerID: performerID,
+		}
+	})
+	if err != nil {
+		return SubscribedPerformer{}, err
+	}
+	state.ProcessedReleases, state.PendingReleases, state.LastError = ledger.processed, ledger.pending, ledger.lastError
+	if state.LastError == "" && len(state.PendingReleases) > 0 && previousLastError != "" {
+		state.LastError = previousLastError
+	}
+	if err := s.store.Put(ctx, state); err != nil {
+		logging.Errorf("subscription: persist state for performer %s failed: %v", performerID, err)
+		return SubscribedPerformer{}, err
+	}
+	logging.Infof(
+		"subscription: refresh completed for performer %s (%s), processed=%d pending=%d skipped_in_library=%d",
+		performerID,
+		performer.Name,
+		len(state.ProcessedReleases),
+		len(state.PendingReleases),
+		skippedInLibrary,
+	)
+	result := buildSubscribedPerformer(item, state)
+	s.publishEvent(PerformerSubscriptionEventUpdated, performerID, &result)
+	return result, nil
+}
+
+// releaseLedger is the release bookkeeping shared by performer and studio
+// subscriptions.
+type releaseLedger struct {
+	processed, pending []RecordedRelease
+	lastError          string
+}
+
+// recordReleases files releases that are neither known, rejected in review
+// nor already in the library, queues the ones the policy downloads and keeps
+// the rest pending. Downloads dated in the future are kept pending as
+// upcoming until their scheduled sourcing. It returns how many releases were skipped as in-library.
+func (s *Service) recordReleases(ctx context.Context, subject string, ledger *releaseLedger, releases []Release, now time.Time, origin func(Release) taskruntime.TaskOrigin) (int, error) {
+	processed := make(map[string]RecordedRelease, len(ledger.processed)+len(ledger.pending))
+	for _, release := range ledger.processed {
 		processed[release.Key] = release
 	}
-	for _, release := range state.PendingReleases {
+	for _, release := range ledger.pending {
 		processed[release.Key] = release
 	}
+	rejected, err := s.rejectedReleaseKeys(ctx, releases)
+	if err != nil {
+		return 0, err
+	}
 
-	existingPending := append([]RecordedRelease(nil), state.PendingReleases...)
+	upcoming := s.upcomingSettings()
+
+	existingPending := append([]RecordedRelease(nil), ledger.pending...)
 	pending := make([]RecordedRelease, 0)
+	// origins remembers the StashBox scene of each new release so created
+	// tasks can link the ingested Stash scene back to it.
+	origins := make(map[string]taskruntime.TaskOrigin)
 	skippedInLibrary := 0
 	for _, release := range releases {
 		if _, exists := processed[release.Key]; exists {
 			continue
 		}
+		if _, exists := rejected[release.Key]; exists {
+			continue
+		}
 		inLibrary, err := s.stashSceneExistsForRelease(ctx, release)
 		if err != nil {
-			return SubscribedPerformer{}, err
+			return 0, err
 		}
 		if inLibrary {
 			skippedInLibrary++
 			logging.Infof(
-				"subscription: skipped in-library release performer=%s release_key=%s stash_box=%s scene_id=%s",
-				performerID,
+				"subscription: skipped in-library release %s release_key=%s stash_box=%s scene_id=%s",
+				subject,
 				release.Key,
 				release.Source,
 				release.SceneID,
@@ -321,10 +398,14 @@ func (s *Service) refreshSubscribedPerformer(ctx context.Context, performerID st
 			Decision:       release.Decision,
 			DecisionReason: release.DecisionReason,
 		}
+		if markUpcoming(&record, upcoming, now) {
+			logging.Infof("subscription: scheduled upcoming release %s code %q for %s", subject, record.Code, record.ScheduledAt.Format(time.RFC3339))
+		}
 		pending = append(pending, record)
+		origins[release.Key] = origin(release)
 	}
 	if len(pending) > 0 {
-		logging.Infof("subscription: detected %d new releases for performer %s (%s)", len(pending), performerID, performer.Name)
+		logging.Infof("subscription: detected %d new releases for %s", len(pending), subject)
 	}
 
 	if s.taskCreator != nil {
@@ -334,47 +415,30 @@ func (s *Service) refreshSubscribedPerformer(ctx context.Context, performerID st
 				nextPending = append(nextPending, pending[i])
 				continue
 			}
-			task, err := s.taskCreator.QueueSubscriptionRelease(ctx, pending[i].Code, pending[i].Title)
+			task, err := s.taskCreator.QueueSubscriptionRelease(ctx, pending[i].Code, pending[i].Title, origins[pending[i].Key])
 			if err != nil {
-				state.LastError = err.Error()
-				logging.Errorf("subscription: auto-download failed for performer %s code %q: %v", performerID, pending[i].Code, err)
+				ledger.lastError = err.Error()
+				logging.Errorf("subscription: auto-download failed for %s code %q: %v", subject, pending[i].Code, err)
 				nextPending = append(nextPending, pending[i])
 				continue
 			}
 			if task != nil {
 				pending[i].TaskID = task.ID
-				logging.Infof("subscription: auto-download created task %s for performer %s code %q", task.ID, performerID, pending[i].Code)
+				logging.Infof("subscription: auto-download created task %s for %s code %q", task.ID, subject, pending[i].Code)
 			}
-			state.ProcessedReleases = append([]RecordedRelease{pending[i]}, state.ProcessedReleases...)
+			ledger.processed = append([]RecordedRelease{pending[i]}, ledger.processed...)
 		}
-		state.PendingReleases = nextPending
+		ledger.pending = nextPending
 	} else {
-		state.PendingReleases = append(existingPending, pending...)
+		ledger.pending = append(existingPending, pending...)
 		if len(pending) > 0 {
-			logging.Infof("subscription: queued %d pending releases for performer %s (%s)", len(pending), performerID, performer.Name)
+			logging.Infof("subscription: queued %d pending releases for %s", len(pending), subject)
 		}
 	}
-	if state.LastError == "" && len(state.PendingReleases) > 0 && previousLastError != "" {
-		state.LastError = previousLastError
-	}
 
-	state.ProcessedReleases = trimRecordedReleases(state.ProcessedReleases, 25)
-	state.PendingReleases = trimRecordedReleases(state.PendingReleases, 25)
-	if err := s.store.Put(ctx, state); err != nil {
-		logging.Errorf("subscription: persist state for performer %s failed: %v", performerID, err)
-		return SubscribedPerformer{}, err
-	}
-	logging.Infof(
-		"subscription: refresh completed for performer %s (%s), processed=%d pending=%d skipped_in_library=%d",
-		performerID,
-		performer.Name,
-		len(state.ProcessedReleases),
-		len(state.PendingReleases),
-		skippedInLibrary,
-	)
-	result := buildSubscribedPerformer(item, state)
-	s.publishEvent(PerformerSubscriptionEventUpdated, performerID, &result)
-	return result, nil
+	ledger.processed = trimRecordedReleases(ledger.processed, 25)
+	ledger.pending = trimPendingReleases(subject, ledger.pending)
+	return skippedInLibrary, nil
 }
 
 func (s *Service) RefreshAll(ctx context.Context) ([]SubscribedPerformer, error) {
@@ -414,12 +478,53 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 		return nil, fmt.Errorf("subscription: no stash-box performer match for %q", performer.Name)
 	}
 
-	source := "stash-box:" + target.Endpoint
-	keyPrefix := "stashbox:" + endpointKey(target.Endpoint) + ":"
-	strategy := selectReleaseFetchStrategy(state)
+	return s.scanReleases(releaseScan{
+		subject:  "performer=" + performer.ID,
+		endpoint: target.Endpoint,
+		target:   target.Performer,
+		policy:   state.ReleasePolicy,
+		loadPage: func(page, perPage int) ([]*stashboxgraphql.SceneFragment, error) {
+			if s.metadata.HasCache() {
+				cached, err := s.metadata.LoadPerformerScenes(ctx, target, page*perPage, releaseCachePolicy(page))
+				if err != nil {
+					return nil, err
+				}
+				return cachedReleasePage(cached.Scenes, page, perPage), nil
+			}
+			return target.Client.QueryScenes(ctx, stashboxgraphql.SceneQueryInput{
+				Performers: &stashboxgraphql.MultiIDCriterionInput{Value: []string{target.Performer.ID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
+				Page:       page, PerPage: perPage, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate,
+			})
+		},
+	}, state.ProcessedReleases, state.PendingReleases)
+}
+
+// releaseScan describes one newest-first StashBox scene listing to turn into
+// releases. target is the subscribed performer, or nil when every scene of
+// the listing belongs to the subscription (studios). policy overrides the
+// global release policy when set.
+type releaseScan struct {
+	subject  string
+	endpoint string
+	target   *stashboxgraphql.PerformerFragment
+	policy   *ReleasePolicyConfig
+	loadPage func(page, perPage int) ([]*stashboxgraphql.SceneFragment, error)
+}
+
+func (s *Service) scanReleases(scan releaseScan, processedReleases, pendingReleases []RecordedRelease) ([]Release, error) {
+	source := "stash-box:" + scan.endpoint
+	keyPrefix := "stashbox:" + endpointKey(scan.endpoint) + ":"
+	strategy := selectReleaseFetchStrategy(processedReleases, pendingReleases)
 	policy := s.currentReleasePolicy()
+	if scan.policy != nil {
+		// Per-performer overrides replace the behaviors and date range; the
+		// global filter rules and compilation keywords still apply.
+		override := scan.policy.Effective()
+		override.CompilationKeywords, override.Rules = policy.CompilationKeywords, policy.Rules
+		policy = override
+	}
 	now := s.now()
-	knownReleaseKeys := recordedReleaseKeys(state)
+	knownReleaseKeys := recordedReleaseKeys(processedReleases, pendingReleases)
 	seenSceneIDs := make(map[string]struct{})
 	seenReleaseKeys := make(map[string]struct{})
 	releases := make([]Release, 0, releaseQueryPerPage)
@@ -427,28 +532,9 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 
 	for page := 1; page <= strategy.maxPages; page++ {
 		stats.pagesRequested++
-		var scenes []*stashboxgraphql.SceneFragment
-		if s.metadata.HasCache() {
-			cachePolicy := metadata.RequireFresh
-			if page == 1 {
-				cachePolicy = metadata.RefreshHead
-			}
-			cached, err := s.metadata.LoadPerformerScenes(ctx, target, page*strategy.perPage, cachePolicy)
-			if err != nil {
-				return nil, err
-			}
-			start := min((page-1)*strategy.perPage, len(cached.Scenes))
-			end := min(start+strategy.perPage, len(cached.Scenes))
-			scenes = cached.Scenes[start:end]
-		} else {
-			var err error
-			scenes, err = target.Client.QueryScenes(ctx, stashboxgraphql.SceneQueryInput{
-				Performers: &stashboxgraphql.MultiIDCriterionInput{Value: []string{target.Performer.ID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
-				Page:       page, PerPage: strategy.perPage, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate,
-			})
-			if err != nil {
-				return nil, err
-			}
+		scenes, err := scan.loadPage(page, strategy.perPage)
+		if err != nil {
+			return nil, err
 		}
 		if len(scenes) == 0 {
 			stats.stopReason = "empty_page"
@@ -472,7 +558,7 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 			seenSceneIDs[scene.ID] = struct{}{}
 			pageHasUniqueScene = true
 
-			evaluation, matched := evaluateReleasePolicy(policy, now, target.Performer, scene)
+			evaluation, matched := evaluateReleasePolicy(policy, now, scan.target, scene)
 			if !matched {
 				continue
 			}
@@ -504,7 +590,7 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 			if _, exists := knownReleaseKeys[release.Key]; exists {
 				pageKnownReleaseCount++
 			}
-			if candidate, hitBoundary := releaseDateBoundaryCandidate(policy, now, target.Performer, scene, evaluation); candidate {
+			if candidate, hitBoundary := releaseDateBoundaryCandidate(policy, now, scan.target, scene, evaluation); candidate {
 				pageDownloadCandidateCount++
 				if hitBoundary {
 					pageDateBoundaryHitCount++
@@ -531,9 +617,9 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 		stats.stopReason = "max_pages_reached"
 	}
 	logging.Infof(
-		"subscription: fetched releases performer=%s stash_box=%s mode=%s pages_requested=%d pages_with_results=%d releases=%d stop_reason=%s",
-		performer.ID,
-		target.Endpoint,
+		"subscription: fetched releases %s stash_box=%s mode=%s pages_requested=%d pages_with_results=%d releases=%d stop_reason=%s",
+		scan.subject,
+		scan.endpoint,
 		strategy.mode,
 		stats.pagesRequested,
 		stats.pagesWithResult,
@@ -543,6 +629,21 @@ func (s *Service) fetchReleases(ctx context.Context, performer *stashgraphql.Per
 	return releases, nil
 }
 
+// releaseCachePolicy refreshes the head of a cached listing on every poll and
+// only requires fresh coverage for the pages behind it.
+func releaseCachePolicy(page int) metadata.CachePolicy {
+	if page == 1 {
+		return metadata.RefreshHead
+	}
+	return metadata.RequireFresh
+}
+
+func cachedReleasePage(scenes []*stashboxgraphql.SceneFragment, page, perPage int) []*stashboxgraphql.SceneFragment {
+	start := min((page-1)*perPage, len(scenes))
+	end := min(start+perPage, len(scenes))
+	return scenes[start:end]
+}
+
 func (s *Service) SetReleasePolicy(policy ReleasePolicyConfig) {
 	if s == nil {
 		return
@@ -592,11 +693,13 @@ func buildSubscribedPerformer(performer performer.Performer, state *PerformerSta
 
 	item.LastCheckedAt = state.LastCheckedAt
 	item.LastError = state.LastError
+	item.ReleasePolicy = cloneReleasePolicy(state.ReleasePolicy)
 	item.PendingReleaseCount = len(state.PendingReleases)
 	item.ProcessedReleaseCount = len(state.ProcessedReleases)
 	item.RecentReleases = append([]RecordedRelease(nil), state.PendingReleases...)
 	item.RecentReleases = append(item.RecentReleases, state.ProcessedReleases...)
 	item.RecentReleases = trimRecordedReleases(item.RecentReleases, 10)
+	item.UpcomingReleases = upcomingReleases(state.PendingReleases)
 	return item
 }
 
@@ -611,12 +714,21 @@ func normalize(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
 
-func buildReleaseCode(code, _ string) string {
-	return strings.TrimSpace(code)
+// buildReleaseCode normalises the scene code, keeping it as is when its
+// format is unrecognised. Only a scene without a code falls back to a code
+// found in the title.
+func buildReleaseCode(code, title string) string {
+	if code = strings.TrimSpace(code); code != "" {
+		if resolved := codeparser.Normalize(code); resolved != "" {
+			return resolved
+		}
+		return code
+	}
+	return codeparser.Normalize(title)
 }
 
-func selectReleaseFetchStrategy(state *PerformerState) releaseFetchStrategy {
-	if state == nil || (len(state.ProcessedReleases) == 0 && len(state.PendingReleases) == 0) {
+func selectReleaseFetchStrategy(processed, pending []RecordedRelease) releaseFetchStrategy {
+	if len(processed) == 0 && len(pending) == 0 {
 		return releaseFetchStrategy{
 			mode:     releaseFetchModeBackfill,
 			perPage:  releaseQueryPerPage,
@@ -630,15 +742,12 @@ func selectReleaseFetchStrategy(state *PerformerState) releaseFetchStrategy {
 	}
 }
 
-func recordedReleaseKeys(state *PerformerState) map[string]struct{} {
-	if state == nil {
-		return map[string]struct{}{}
-	}
-	out := make(map[string]struct{}, len(state.ProcessedReleases)+len(state.PendingReleases))
-	for _, release := range state.ProcessedReleases {
+func recordedReleaseKeys(processed, pending []RecordedRelease) map[string]struct{} {
+	out := make(map[string]struct{}, len(processed)+len(pending))
+	for _, release := range processed {
 		out[release.Key] = struct{}{}
 	}
-	for _, release := range state.PendingReleases {
+	for _, release := range pending {
 		out[release.Key] = struct{}{}
 	}
 	return out
@@ -651,6 +760,9 @@ func (s *Service) stashSceneExistsForRelease(ctx context.Context, release Releas
 		return false, nil
 	}
 	endpoint := strings.TrimPrefix(source, "stash-box:")
+	if s.libraryIndex != nil {
+		return s.indexedSceneExistsForRelease(ctx, release, endpoint, sceneID)
+	}
 	scenes, err := s.stash.FindScenes(ctx, &stashgraphql.SceneFilterType{
 		StashIDEndpoint: &stashgraphql.StashIDCriterionInput{
 			Endpoint: stringPointer(endpoint),
@@ -667,6 +779,39 @@ func (s *Service) stashSceneExistsForRelease(ctx context.Context, release Releas
 	return len(scenes) > 0, nil
 }
 
+// indexedSceneExistsForRelease also matches the release code, which catches
+// library scenes that were never linked to the StashBox scene.
+func (s *Service) indexedSceneExistsForRelease(ctx context.Context, release Release, endpoint, sceneID string) (bool, error) {
+	found, err := s.libraryIndex.HasStashID(ctx, endpoint, sceneID)
+	if err == nil && !found && strings.TrimSpace(release.Code) != "" {
+		found, err = s.libraryIndex.HasCode(ctx, release.Code)
+	}
+	if err != nil {
+		return false, fmt.Errorf("subscription: check library index for release %q: %w", release.Key, err)
+	}
+	return found, nil
+}
+
+// trimPendingReleases caps the releases waiting in the pending ledger at
+// pendingReleaseLimit, keeping the oldest. Upcoming releases are exempt since
+// they leave the ledger on their own once sourced. Dropped releases are
+// logged; they are detected again while the source still lists them.
+func trimPendingReleases(subject string, items []RecordedRelease) []RecordedRelease {
+	kept := make([]RecordedRelease, 0, len(items))
+	waiting := 0
+	for _, release := range items {
+		if release.Decision != ReleaseDecisionUpcoming {
+			if waiting >= pendingReleaseLimit {
+				logging.Warnf("subscription: pending releases for %s are full, dropped release %s code %q", subject, release.Key, release.Code)
+				continue
+			}
+			waiting++
+		}
+		kept = append(kept, release)
+	}
+	return kept
+}
+
 func trimRecordedReleases(items []RecordedRelease, limit int) []RecordedRelease {
 	if len(items) <= limit {
 		return items
@@ -11,6 +11,7 @@ import (
 	performerdomain "github.com/leothevan2444/moji/internal/performer"
 	"github.com/leothevan2444/moji/internal/taskflow"
 	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
 	"github.com/leothevan2444/moji/pkg/stash"
 	stashgraphql "github.com/leothevan2444/moji/pkg/stash/graphql"
 	stashboxpkg "github.com/leothevan2444/moji/pkg/stashbox"
@@ -110,6 +111,7 @@ func (f *fakeStashClient) GetStashBoxes(_ context.Context) ([]stash.StashBoxEndp
 
 type fakeStashboxClient struct {
 	performer    *stashboxgraphql.PerformerFragment
+	studio       *stashboxgraphql.StudioFragment
 	scenes       []*stashboxgraphql.SceneFragment
 	scenesByPage map[int][]*stashboxgraphql.SceneFragment
 	searchErr    error
@@ -123,6 +125,8 @@ type fakeTaskRuntime struct {
 	calls   int
 	codes   []string
 	sources []taskruntime.TaskSource
+	origins []taskruntime.TaskOrigin
+	results [][]jackett.SearchResult
 }
 
 type fakeTaskCreator struct {
@@ -158,6 +162,13 @@ func (f *fakeStashboxClient) FindPerformerByID(_ context.Context, id string) (*s
 	return nil, nil
 }
 
+func (f *fakeStashboxClient) FindStudioByID(_ context.Context, id string) (*stashboxgraphql.StudioFragment, error) {
+	if f.studio != nil && f.studio.ID == id {
+		return f.studio, nil
+	}
+	return nil, nil
+}
+
 func (f *fakeStashboxClient) FindSceneByID(_ context.Context, id string) (*stashboxgraphql.SceneFragment, error) {
 	if f.findSceneErr != nil {
 		return nil, f.findSceneErr
@@ -222,6 +233,8 @@ func (f *fakeTaskRuntime) DownloadMediaContext(_ context.Context, req taskruntim
 	f.calls++
 	f.codes = append(f.codes, req.Code)
 	f.sources = append(f.sources, req.Source)
+	f.origins = append(f.origins, req.Origin)
+	f.results = append(f.results, req.Results)
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -245,7 +258,7 @@ func (f *fakeTaskCreator) QueueDiscoveredScene(_ context.Context, sceneID string
 	return f.queueTask, f.queueErr
 }
 
-func (f *fakeTaskCreator) QueueSubscriptionRelease(_ context.Context, code, _ string) (*taskruntime.Task, error) {
+func (f *fakeTaskCreator) QueueSubscriptionRelease(_ context.Context, code, _ string, _ taskruntime.TaskOrigin) (*taskruntime.Task, error) {
 	if f.subscriptionCode != "" {
 		code = f.subscriptionCode
 	}
@@ -593,6 +606,61 @@ func TestRefreshPerformerKeepsReleaseWhenNotFoundInStashLibrary(t *testing.T) {
 	}
 }
 
+func TestRefreshPerformerChecksLibraryIndexByStashIDAndCode(t *testing.T) {
+	endpoint := "https://javstash.example.org/graphql"
+	linked, unlinked, missing := "ABCD-123", "ABCD-124", "ABCD-125"
+	stashClient := &fakeStashClient{
+		performers: map[string]*stashgraphql.PerformerFragment{
+			"p1": {
+				ID:           "p1",
+				Name:         "Rara Anzai",
+				CustomFields: map[string]any{DefaultCustomFieldKey: true},
+				StashIds:     []*stashgraphql.StashIDFragment{{Endpoint: endpoint, StashID: "js-1"}},
+			},
+		},
+	}
+	registry := metadata.NewRegistry(stubFactory{
+		client: &fakeStashboxClient{
+			performer: &stashboxgraphql.PerformerFragment{ID: "js-1", Name: "Rara Anzai"},
+			scenes: []*stashboxgraphql.SceneFragment{
+				{ID: "js-scene-1", Title: &linked, Code: &linked},
+				{ID: "js-scene-2", Title: &unlinked, Code: &unlinked},
+				{ID: "js-scene-3", Title: &missing, Code: &missing},
+			},
+		},
+	})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "javstash", Endpoint: endpoint, APIKey: "ignored"}})
+	service, err := newServiceForTest(stashClient, registry, nil, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	service.SetLibraryIndex(fakeLibraryIndex{stashIDs: map[string]bool{"js-scene-1": true}, codes: map[string]bool{unlinked: true}})
+
+	item, err := service.RefreshSubscribedPerformer(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("RefreshSubscribedPerformer failed: %v", err)
+	}
+	if item.PendingReleaseCount != 1 {
+		t.Fatalf("expected only the release missing from the index to be pending, got %d", item.PendingReleaseCount)
+	}
+	if len(stashClient.findScenesCalls) != 0 {
+		t.Fatalf("expected no stash dedupe queries with an index, got %d", len(stashClient.findScenesCalls))
+	}
+}
+
+type fakeLibraryIndex struct {
+	stashIDs map[string]bool
+	codes    map[string]bool
+}
+
+func (f fakeLibraryIndex) HasStashID(_ context.Context, _, stashID string) (bool, error) {
+	return f.stashIDs[stashID], nil
+}
+
+func (f fakeLibraryIndex) HasCode(_ context.Context, code string) (bool, error) {
+	return f.codes[code], nil
+}
+
 func TestRefreshPerformerSkipsStashLookupForKnownRelease(t *testing.T) {
 	endpoint := "https://javstash.example.org/graphql"
 	code := "ABCD-123"
@@ -1017,6 +1085,10 @@ func TestRefreshPerformerKeepsFailedAutoDownloadsPending(t *testing.T) {
 	if taskRuntime.calls != 1 {
 		t.Fatalf("expected taskRuntime to be called once for the new release, got %d", taskRuntime.calls)
 	}
+	wantOrigin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: "js-scene-1", StashPerformerID: "p1"}
+	if taskRuntime.origins[0] != wantOrigin {
+		t.Fatalf("expected release origin %+v, got %+v", wantOrigin, taskRuntime.origins[0])
+	}
 	if second.LastError == "" {
 		t.Fatalf("expected last error to be preserved after auto-download failure")
 	}
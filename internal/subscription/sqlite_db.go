@@ -20,6 +20,9 @@ func ensureSubscriptionSQLiteSchema(db *sqlx.DB) error {
 	if _, err := db.Exec(sqliteSchema); err != nil {
 		return fmt.Errorf("subscription: initialize sqlite schema: %w", err)
 	}
+	if err := addSubscriptionReleaseScheduleColumns(db); err != nil {
+		return err
+	}
 	if err := ensureSubscriptionSQLiteRuntimeState(db); err != nil {
 		return err
 	}
@@ -43,8 +46,10 @@ func resetLegacySubscriptionSchemaIfNeeded(db *sqlx.DB) error {
 
 	statements := []string{
 		`PRAGMA foreign_keys = OFF`,
+		`DROP TABLE IF EXISTS subscription_studio_releases`,
 		`DROP TABLE IF EXISTS subscription_performer_releases`,
 		`DROP TABLE IF EXISTS subscription_release_entities`,
+		`DROP TABLE IF EXISTS subscription_performer_policies`,
 		`DROP TABLE IF EXISTS subscription_performer_state`,
 		`PRAGMA foreign_keys = ON`,
 	}
@@ -95,6 +100,24 @@ func subscriptionSchemaNeedsReset(db *sqlx.DB) (bool, error) {
 	return false, nil
 }
 
+// addSubscriptionReleaseScheduleColumns adds the upcoming release schedule
+// to release tables created before it existed.
+func addSubscriptionReleaseScheduleColumns(db *sqlx.DB) error {
+	hasColumn, err := sqliteColumnExists(db, "subscription_release_entities", "scheduled_at")
+	if err != nil || hasColumn {
+		return err
+	}
+	for _, statement := range []string{
+		`ALTER TABLE subscription_release_entities ADD COLUMN scheduled_at TEXT`,
+		`ALTER TABLE subscription_release_entities ADD COLUMN source_attempts INTEGER NOT NULL DEFAULT 0`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("subscription: migrate sqlite schema with %q: %w", statement, err)
+		}
+	}
+	return nil
+}
+
 func ensureSubscriptionSQLiteRuntimeState(db *sqlx.DB) error {
 	tx, err := db.Beginx()
 	if err != nil {
@@ -0,0 +1,172 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type sqliteQueuedReleaseRow struct {
+	sqliteReleaseRow
+	PerformerIDs string `db:"performer_ids"`
+	StudioKeys   string `db:"studio_keys"`
+}
+
+// ListQueuedReleases returns one page of pending QUEUED releases that are
+// neither rejected nor snoozed past now, with the total match count.
+func (s *SQLiteStore) ListQueuedReleases(ctx context.Context, filter ReleaseReviewFilter, now time.Time) ([]QueuedRelease, int, error) {
+	where := []string{
+		`sre.status = 'pending'`,
+		`sre.decision = ?`,
+		`NOT EXISTS (
+  SELECT 1
+  FROM subscription_release_reviews srr
+  WHERE srr.release_key = sre.release_key
+    AND (srr.action = 'rejected' OR srr.snoozed_until > ?)
+)`,
+	}
+	args := []any{string(ReleaseDecisionQueued), formatTimestamp(now)}
+	if len(filter.Reasons) > 0 {
+		where = append(where, `sre.decision_reason IN (?)`)
+		args = append(args, filter.Reasons)
+	}
+	if len(filter.Classifications) > 0 {
+		classifications := make([]string, 0, len(filter.Classifications))
+		for _, classification := range filter.Classifications {
+			classifications = append(classifications, string(classification))
+		}
+		where = append(where, `sre.classification IN (?)`)
+		args = append(args, classifications)
+	}
+	if from := strings.TrimSpace(filter.DateFrom); from != "" {
+		where = append(where, `substr(sre.release_date, 1, 10) >= ?`)
+		args = append(args, from)
+	}
+	if to := strings.TrimSpace(filter.DateTo); to != "" {
+		where = append(where, `substr(sre.release_date, 1, 10) <= ?`)
+		args = append(args, to)
+	}
+	clause := strings.Join(where, "\n  AND ")
+
+	countQuery, countArgs, err := sqlx.In(`SELECT COUNT(*) FROM subscription_release_entities sre WHERE `+clause, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("subscription: build queued release count: %w", err)
+	}
+	var total int
+	if err := s.db.GetContext(ctx, &total, s.db.Rebind(countQuery), countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("subscription: count queued releases: %w", err)
+	}
+
+	pageArgs := append(append([]any(nil), args...), filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query, queryArgs, err := sqlx.In(`
+SELECT
+  sre.status,
+  sre.release_key,
+  sre.source,
+  sre.title,
+  sre.code,
+  sre.release_date,
+  sre.url,
+  sre.task_id,
+  sre.performer_count,
+  sre.performer_names,
+  sre.classification,
+  sre.decision,
+  sre.decision_reason,
+  sre.seen_at,
+  (SELECT json_group_array(spr.performer_id) FROM subscription_performer_releases spr WHERE spr.release_id = sre.id) AS performer_ids,
+  (SELECT json_group_array(json_array(ssr.endpoint, ssr.studio_id)) FROM subscription_studio_releases ssr WHERE ssr.release_id = sre.id) AS studio_keys
+FROM subscription_release_entities sre
+WHERE `+clause+`
+ORDER BY sre.seen_at DESC, sre.release_key ASC
+LIMIT ? OFFSET ?`, pageArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("subscription: build queued release query: %w", err)
+	}
+	rows := make([]sqliteQueuedReleaseRow, 0)
+	if err := s.db.SelectContext(ctx, &rows, s.db.Rebind(query), queryArgs...); err != nil {
+		return nil, 0, fmt.Errorf("subscription: list queued releases: %w", err)
+	}
+
+	items := make([]QueuedRelease, 0, len(rows))
+	for _, row := range rows {
+		_, release, err := row.toRecordedRelease()
+		if err != nil {
+			return nil, 0, err
+		}
+		item := QueuedRelease{Release: release}
+		if err := json.Unmarshal([]byte(row.PerformerIDs), &item.PerformerIDs); err != nil {
+			return nil, 0, fmt.Errorf("subscription: parse performers of release %q: %w", release.Key, err)
+		}
+		var studios [][2]string
+		if err := json.Unmarshal([]byte(row.StudioKeys), &studios); err != nil {
+			return nil, 0, fmt.Errorf("subscription: parse studios of release %q: %w", release.Key, err)
+		}
+		for _, studio := range studios {
+			item.Studios = append(item.Studios, StudioKey{Endpoint: studio[0], StudioID: studio[1]})
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// RejectReleases records keys as rejected. Rejections are kept after the
+// releases leave every subscription's history.
+func (s *SQLiteStore) RejectReleases(ctx context.Context, keys []string, now time.Time) error {
+	return s.putReleaseReviews(ctx, keys, "rejected", nil, now)
+}
+
+// SnoozeReleases hides keys from the review queue until until. A rejected
+// release stays rejected.
+func (s *SQLiteStore) SnoozeReleases(ctx context.Context, keys []string, until, now time.Time) error {
+	return s.putReleaseReviews(ctx, keys, "snoozed", formatTimestamp(until), now)
+}
+
+func (s *SQLiteStore) putReleaseReviews(ctx context.Context, keys []string, action string, snoozedUntil any, now time.Time) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscription: begin release review tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO subscription_release_reviews (release_key, action, snoozed_until, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(release_key) DO UPDATE SET
+  action = excluded.action,
+  snoozed_until = excluded.snoozed_until,
+  updated_at = excluded.updated_at
+WHERE subscription_release_reviews.action <> 'rejected'`, key, action, snoozedUntil, formatTimestamp(now)); err != nil {
+			return fmt.Errorf("subscription: record %s release %q: %w", action, key, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscription: commit release review tx: %w", err)
+	}
+	return nil
+}
+
+// RejectedReleaseKeys returns the subset of keys that were rejected.
+func (s *SQLiteStore) RejectedReleaseKeys(ctx context.Context, keys []string) (map[string]struct{}, error) {
+	out := make(map[string]struct{})
+	if len(keys) == 0 {
+		return out, nil
+	}
+	query, args, err := sqlx.In(`SELECT release_key FROM subscription_release_reviews WHERE action = 'rejected' AND release_key IN (?)`, keys)
+	if err != nil {
+		return nil, fmt.Errorf("subscription: build rejected release query: %w", err)
+	}
+	rejected := make([]string, 0)
+	if err := s.db.SelectContext(ctx, &rejected, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("subscription: load rejected releases: %w", err)
+	}
+	for _, key := range rejected {
+		out[key] = struct{}{}
+	}
+	return out, nil
+}
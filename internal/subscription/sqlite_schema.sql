@@ -12,6 +12,17 @@ CREATE INDEX IF NOT EXISTS idx_subscription_state_last_checked_at
 CREATE INDEX IF NOT EXISTS idx_subscription_state_updated_at
   ON subscription_performer_state (updated_at DESC);
 
+CREATE TABLE IF NOT EXISTS subscription_performer_policies (
+  performer_id TEXT PRIMARY KEY,
+  solo_behavior TEXT NOT NULL,
+  group_behavior TEXT NOT NULL,
+  compilation_behavior TEXT NOT NULL,
+  max_group_performer_count INTEGER NOT NULL,
+  release_date_range TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  FOREIGN KEY (performer_id) REFERENCES subscription_performer_state(performer_id) ON DELETE CASCADE
+) STRICT;
+
 CREATE TABLE IF NOT EXISTS subscription_release_entities (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
   release_key TEXT NOT NULL,
@@ -28,6 +39,8 @@ CREATE TABLE IF NOT EXISTS subscription_release_entities (
   decision TEXT NOT NULL DEFAULT '',
   decision_reason TEXT NOT NULL DEFAULT '',
   seen_at TEXT NOT NULL,
+  scheduled_at TEXT,
+  source_attempts INTEGER NOT NULL DEFAULT 0,
   created_at TEXT NOT NULL,
   updated_at TEXT NOT NULL,
   FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE SET NULL
@@ -62,3 +75,47 @@ CREATE INDEX IF NOT EXISTS idx_subscription_performer_releases_performer_linked_
 
 CREATE INDEX IF NOT EXISTS idx_subscription_performer_releases_release_id
   ON subscription_performer_releases (release_id);
+
+CREATE TABLE IF NOT EXISTS subscription_studio_state (
+  endpoint TEXT NOT NULL,
+  studio_id TEXT NOT NULL,
+  name TEXT NOT NULL DEFAULT '',
+  subscribed_at TEXT NOT NULL,
+  last_checked_at TEXT,
+  last_error TEXT,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL,
+  PRIMARY KEY (endpoint, studio_id)
+) STRICT;
+
+CREATE TABLE IF NOT EXISTS subscription_studio_releases (
+  endpoint TEXT NOT NULL,
+  studio_id TEXT NOT NULL,
+  release_id INTEGER NOT NULL,
+  linked_at TEXT NOT NULL,
+  PRIMARY KEY (endpoint, studio_id, release_id),
+  FOREIGN KEY (endpoint, studio_id) REFERENCES subscription_studio_state(endpoint, studio_id) ON DELETE CASCADE,
+  FOREIGN KEY (release_id) REFERENCES subscription_release_entities(id) ON DELETE CASCADE
+) STRICT;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_studio_releases_studio_linked_at
+  ON subscription_studio_releases (endpoint, studio_id, linked_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_studio_releases_release_id
+  ON subscription_studio_releases (release_id);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_release_entities_review_seen_at
+  ON subscription_release_entities (status, decision, seen_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_release_entities_review_reason
+  ON subscription_release_entities (status, decision, decision_reason, classification);
+
+CREATE TABLE IF NOT EXISTS subscription_release_reviews (
+  release_key TEXT PRIMARY KEY,
+  action TEXT NOT NULL CHECK (action IN ('rejected', 'snoozed')),
+  snoozed_until TEXT,
+  updated_at TEXT NOT NULL
+) STRICT;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_release_reviews_action_snoozed_until
+  ON subscription_release_reviews (action, snoozed_until);
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/leothevan2444/moji/internal/config"
 	"github.com/leothevan2444/moji/internal/taskruntime"
 )
 
@@ -47,6 +48,18 @@ WHERE performer_id = ?`, performerID); err != nil {
 		return nil, err
 	}
 
+	var policyRow sqlitePerformerPolicyRow
+	err = s.db.GetContext(ctx, &policyRow, `
+SELECT solo_behavior, group_behavior, compilation_behavior, max_group_performer_count, release_date_range
+FROM subscription_performer_policies
+WHERE performer_id = ?`, performerID)
+	switch {
+	case err == nil:
+		state.ReleasePolicy = policyRow.toPolicy()
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("subscription: load release policy for %q: %w", performerID, err)
+	}
+
 	releaseRows := make([]sqliteReleaseRow, 0)
 	if err := s.db.SelectContext(ctx, &releaseRows, `
 SELECT
@@ -63,7 +76,9 @@ SELECT
   sre.classification,
   sre.decision,
   sre.decision_reason,
-  sre.seen_at
+  sre.seen_at,
+  sre.scheduled_at,
+  sre.source_attempts
 FROM subscription_performer_releases spr
 JOIN subscription_release_entities sre ON sre.id = spr.release_id
 WHERE spr.performer_id = ?
@@ -116,6 +131,10 @@ ON CONFLICT(performer_id) DO UPDATE SET
 		return fmt.Errorf("subscription: upsert performer state %q: %w", state.PerformerID, err)
 	}
 
+	if err := putPerformerPolicy(ctx, tx, state, now); err != nil {
+		return err
+	}
+
 	if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_performer_releases WHERE performer_id = ?`, state.PerformerID); err != nil {
 		return fmt.Errorf("subscription: clear release links for %q: %w", state.PerformerID, err)
 	}
@@ -222,6 +241,60 @@ func (r sqlitePerformerStateRow) toState() (*PerformerState, error) {
 	}, nil
 }
 
+type sqlitePerformerPolicyRow struct {
+	SoloBehavior           string `db:"solo_behavior"`
+	GroupBehavior          string `db:"group_behavior"`
+	CompilationBehavior    string `db:"compilation_behavior"`
+	MaxGroupPerformerCount int    `db:"max_group_performer_count"`
+	ReleaseDateRange       string `db:"release_date_range"`
+}
+
+func (r sqlitePerformerPolicyRow) toPolicy() *ReleasePolicyConfig {
+	policy := ReleasePolicyConfig{
+		SoloBehavior:           config.SubscriptionReleaseBehavior(r.SoloBehavior),
+		GroupBehavior:          config.SubscriptionReleaseBehavior(r.GroupBehavior),
+		CompilationBehavior:    config.SubscriptionReleaseBehavior(r.CompilationBehavior),
+		MaxGroupPerformerCount: r.MaxGroupPerformerCount,
+		ReleaseDateRange:       config.SubscriptionReleaseDateRange(r.ReleaseDateRange),
+	}.Effective()
+	return &policy
+}
+
+// putPerformerPolicy stores the performer's release policy override, or
+// removes it when the state has none.
+func putPerformerPolicy(ctx context.Context, tx *sqlx.Tx, state *PerformerState, now time.Time) error {
+	if state.ReleasePolicy == nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_performer_policies WHERE performer_id = ?`, state.PerformerID); err != nil {
+			return fmt.Errorf("subscription: clear release policy for %q: %w", state.PerformerID, err)
+		}
+		return nil
+	}
+	policy := state.ReleasePolicy.Effective()
+	if _, err := tx.NamedExecContext(ctx, `
+INSERT INTO subscription_performer_policies (performer_id, solo_behavior, group_behavior, compilation_behavior, max_group_performer_count, release_date_range, updated_at)
+VALUES (:performer_id, :solo_behavior, :group_behavior, :compilation_behavior, :max_group_performer_count, :release_date_range, :updated_at)
+ON CONFLICT(performer_id) DO UPDATE SET
+  solo_behavior = excluded.solo_behavior,
+  group_behavior = excluded.group_behavior,
+  compilation_behavior = excluded.compilation_behavior,
+  max_group_performer_count = excluded.max_group_performer_count,
+  release_date_range = excluded.release_date_range,
+  updated_at = excluded.updated_at`,
+		map[string]any{
+			"performer_id":              state.PerformerID,
+			"solo_behavior":             string(policy.SoloBehavior),
+			"group_behavior":            string(policy.GroupBehavior),
+			"compilation_behavior":      string(policy.CompilationBehavior),
+			"max_group_performer_count": policy.MaxGroupPerformerCount,
+			"release_date_range":        string(policy.ReleaseDateRange),
+			"updated_at":                formatTimestamp(now),
+		},
+	); err != nil {
+		return fmt.Errorf("subscription: upsert release policy for %q: %w", state.PerformerID, err)
+	}
+	return nil
+}
+
 type sqliteReleaseRow struct {
 	Status         string         `db:"status"`
 	Key            string         `db:"release_key"`
@@ -237,6 +310,8 @@ type sqliteReleaseRow struct {
 	Decision       string         `db:"decision"`
 	DecisionReason string         `db:"decision_reason"`
 	SeenAt         string         `db:"seen_at"`
+	ScheduledAt    sql.NullString `db:"scheduled_at"`
+	SourceAttempts int            `db:"source_attempts"`
 }
 
 func (r sqliteReleaseRow) toRecordedRelease() (string, RecordedRelease, error) {
@@ -252,6 +327,7 @@ func (r sqliteReleaseRow) toRecordedRelease() (string, RecordedRelease, error) {
 		Classification: ReleaseClassification(r.Classification),
 		Decision:       ReleaseDecision(r.Decision),
 		DecisionReason: r.DecisionReason,
+		SourceAttempts: r.SourceAttempts,
 	}
 	if err := json.Unmarshal([]byte(r.PerformerNames), &item.PerformerNames); err != nil {
 		return "", RecordedRelease{}, fmt.Errorf("subscription: parse performer_names for release %q: %w", r.Key, err)
@@ -261,6 +337,13 @@ func (r sqliteReleaseRow) toRecordedRelease() (string, RecordedRelease, error) {
 		return "", RecordedRelease{}, fmt.Errorf("subscription: parse seen_at for release %q: %w", r.Key, err)
 	}
 	item.SeenAt = seenAt
+	scheduledAt, err := parseOptionalTimestamp(r.ScheduledAt)
+	if err != nil {
+		return "", RecordedRelease{}, fmt.Errorf("subscription: parse scheduled_at for release %q: %w", r.Key, err)
+	}
+	if scheduledAt != nil {
+		item.ScheduledAt = *scheduledAt
+	}
 	return r.Status, item, nil
 }
 
@@ -289,15 +372,20 @@ func upsertReleaseEntity(ctx context.Context, tx *sqlx.Tx, status string, releas
 		"decision":        string(release.Decision),
 		"decision_reason": release.DecisionReason,
 		"seen_at":         formatTimestamp(seenAt),
+		"scheduled_at":    nil,
+		"source_attempts": release.SourceAttempts,
 		"created_at":      formatTimestamp(now),
 		"updated_at":      formatTimestamp(now),
 	}
+	if !release.ScheduledAt.IsZero() {
+		params["scheduled_at"] = formatTimestamp(release.ScheduledAt)
+	}
 
 	rows, err := sqlx.NamedQueryContext(ctx, tx, `
 INSERT INTO subscription_release_entities (
-  release_key, status, source, title, code, release_date, url, task_id, performer_count, performer_names, classification, decision, decision_reason, seen_at, created_at, updated_at
+  release_key, status, source, title, code, release_date, url, task_id, performer_count, performer_names, classification, decision, decision_reason, seen_at, scheduled_at, source_attempts, created_at, updated_at
 ) VALUES (
-  :release_key, :status, :source, :title, :code, :release_date, :url, :task_id, :performer_count, :performer_names, :classification, :decision, :decision_reason, :seen_at, :created_at, :updated_at
+  :release_key, :status, :source, :title, :code, :release_date, :url, :task_id, :performer_count, :performer_names, :classification, :decision, :decision_reason, :seen_at, :scheduled_at, :source_attempts, :created_at, :updated_at
 )
 ON CONFLICT(release_key) DO UPDATE SET
   status = excluded.status,
@@ -313,6 +401,8 @@ ON CONFLICT(release_key) DO UPDATE SET
   decision = excluded.decision,
   decision_reason = excluded.decision_reason,
   seen_at = excluded.seen_at,
+  scheduled_at = excluded.scheduled_at,
+  source_attempts = excluded.source_attempts,
   updated_at = excluded.updated_at
 RETURNING id`, params)
 	if err != nil {
@@ -384,6 +474,11 @@ WHERE NOT EXISTS (
   SELECT 1
   FROM subscription_performer_releases spr
   WHERE spr.release_id = subscription_release_entities.id
+)
+AND NOT EXISTS (
+  SELECT 1
+  FROM subscription_studio_releases ssr
+  WHERE ssr.release_id = subscription_release_entities.id
 )`); err != nil {
 		return fmt.Errorf("subscription: delete orphan release entities: %w", err)
 	}
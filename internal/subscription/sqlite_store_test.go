@@ -375,3 +375,64 @@ func TestSQLiteStoreGetMissingReturnsNil(t *testing.T) {
 		t.Fatalf("expected nil state for missing performer, got %#v", state)
 	}
 }
+
+func TestNewSQLiteStoreAddsReleaseScheduleColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscription-unscheduled.db")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if _, err := db.Exec(`
+CREATE TABLE subscription_release_entities (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  release_key TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'discovered' CHECK (status IN ('discovered', 'pending', 'processed', 'failed')),
+  source TEXT NOT NULL DEFAULT '',
+  title TEXT NOT NULL DEFAULT '',
+  code TEXT NOT NULL DEFAULT '',
+  release_date TEXT,
+  url TEXT,
+  task_id TEXT,
+  performer_count INTEGER NOT NULL DEFAULT 0,
+  performer_names TEXT NOT NULL DEFAULT '[]',
+  classification TEXT NOT NULL DEFAULT '',
+  decision TEXT NOT NULL DEFAULT '',
+  decision_reason TEXT NOT NULL DEFAULT '',
+  seen_at TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+) STRICT;`); err != nil {
+		t.Fatalf("create unscheduled schema: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer store.db.Close()
+
+	scheduledAt := time.Unix(900, 0).UTC()
+	if err := store.Put(context.Background(), &PerformerState{
+		PerformerID: "performer-1",
+		PendingReleases: []RecordedRelease{{
+			Key:            "release-1",
+			Code:           "ABCD-123",
+			Date:           "2026-07-10",
+			SeenAt:         time.Unix(600, 0).UTC(),
+			Decision:       ReleaseDecisionUpcoming,
+			ScheduledAt:    scheduledAt,
+			SourceAttempts: 2,
+		}},
+	}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	loaded, err := store.Get(context.Background(), "performer-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if release := loaded.PendingReleases[0]; !release.ScheduledAt.Equal(scheduledAt) || release.SourceAttempts != 2 {
+		t.Fatalf("expected the release schedule to round-trip, got %+v", release)
+	}
+}
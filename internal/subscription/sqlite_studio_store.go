@@ -0,0 +1,225 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (s *SQLiteStore) GetStudio(ctx context.Context, endpoint, studioID string) (*StudioState, error) {
+	var stateRow sqliteStudioStateRow
+	if err := s.db.GetContext(ctx, &stateRow, `
+SELECT endpoint, studio_id, name, subscribed_at, last_checked_at, last_error
+FROM subscription_studio_state
+WHERE endpoint = ? AND studio_id = ?`, endpoint, studioID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("subscription: load studio state %q/%q: %w", endpoint, studioID, err)
+	}
+
+	state, err := stateRow.toState()
+	if err != nil {
+		return nil, err
+	}
+
+	releaseRows := make([]sqliteReleaseRow, 0)
+	if err := s.db.SelectContext(ctx, &releaseRows, `
+SELECT
+  sre.status,
+  sre.release_key,
+  sre.source,
+  sre.title,
+  sre.code,
+  sre.release_date,
+  sre.url,
+  sre.task_id,
+  sre.performer_count,
+  sre.performer_names,
+  sre.classification,
+  sre.decision,
+  sre.decision_reason,
+  sre.seen_at,
+  sre.scheduled_at,
+  sre.source_attempts
+FROM subscription_studio_releases ssr
+JOIN subscription_release_entities sre ON sre.id = ssr.release_id
+WHERE ssr.endpoint = ? AND ssr.studio_id = ?
+ORDER BY sre.seen_at DESC, sre.release_key ASC`, endpoint, studioID); err != nil {
+		return nil, fmt.Errorf("subscription: load releases for studio %q/%q: %w", endpoint, studioID, err)
+	}
+
+	for _, row := range releaseRows {
+		status, release, err := row.toRecordedRelease()
+		if err != nil {
+			return nil, fmt.Errorf("subscription: decode release for studio %q/%q: %w", endpoint, studioID, err)
+		}
+		if status == "processed" {
+			state.ProcessedReleases = append(state.ProcessedReleases, release)
+		} else {
+			state.PendingReleases = append(state.PendingReleases, release)
+		}
+	}
+
+	return cloneStudioState(state), nil
+}
+
+func (s *SQLiteStore) PutStudio(ctx context.Context, state *StudioState) error {
+	if state == nil {
+		return errors.New("subscription: studio state is nil")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscription: begin put studio state tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	subscribedAt := state.SubscribedAt
+	if subscribedAt.IsZero() {
+		subscribedAt = now
+	}
+	if _, err := tx.NamedExecContext(ctx, `
+INSERT INTO subscription_studio_state (endpoint, studio_id, name, subscribed_at, last_checked_at, last_error, created_at, updated_at)
+VALUES (:endpoint, :studio_id, :name, :subscribed_at, :last_checked_at, :last_error, :created_at, :updated_at)
+ON CONFLICT(endpoint, studio_id) DO UPDATE SET
+  name = excluded.name,
+  last_checked_at = excluded.last_checked_at,
+  last_error = excluded.last_error,
+  updated_at = excluded.updated_at`,
+		map[string]any{
+			"endpoint":        state.Endpoint,
+			"studio_id":       state.StudioID,
+			"name":            state.Name,
+			"subscribed_at":   formatTimestamp(subscribedAt),
+			"last_checked_at": formatOptionalTimestamp(state.LastCheckedAt),
+			"last_error":      nullableStringParam(state.LastError),
+			"created_at":      formatTimestamp(now),
+			"updated_at":      formatTimestamp(now),
+		},
+	); err != nil {
+		return fmt.Errorf("subscription: upsert studio state %q/%q: %w", state.Endpoint, state.StudioID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_studio_releases WHERE endpoint = ? AND studio_id = ?`, state.Endpoint, state.StudioID); err != nil {
+		return fmt.Errorf("subscription: clear release links for studio %q/%q: %w", state.Endpoint, state.StudioID, err)
+	}
+
+	writeRelease := func(status string, release RecordedRelease) error {
+		releaseID, err := upsertReleaseEntity(ctx, tx, status, release, now)
+		if err != nil {
+			return err
+		}
+		linkedAt := release.SeenAt
+		if linkedAt.IsZero() {
+			linkedAt = now
+		}
+		if _, err := tx.NamedExecContext(ctx, `
+INSERT INTO subscription_studio_releases (endpoint, studio_id, release_id, linked_at)
+VALUES (:endpoint, :studio_id, :release_id, :linked_at)`,
+			map[string]any{
+				"endpoint":   state.Endpoint,
+				"studio_id":  state.StudioID,
+				"release_id": releaseID,
+				"linked_at":  formatTimestamp(linkedAt),
+			},
+		); err != nil {
+			return fmt.Errorf("subscription: insert studio-release link %q/%q: %w", state.StudioID, release.Key, err)
+		}
+		return nil
+	}
+
+	for _, release := range state.ProcessedReleases {
+		if err := writeRelease("processed", release); err != nil {
+			return err
+		}
+	}
+	for _, release := range state.PendingReleases {
+		if err := writeRelease("pending", release); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteOrphanReleaseEntities(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscription: commit put studio state tx: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteStudio(ctx context.Context, endpoint, studioID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("subscription: begin delete studio state tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_studio_state WHERE endpoint = ? AND studio_id = ?`, endpoint, studioID); err != nil {
+		return fmt.Errorf("subscription: delete studio state %q/%q: %w", endpoint, studioID, err)
+	}
+	if err := deleteOrphanReleaseEntities(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("subscription: commit delete studio state tx: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListStudios(ctx context.Context) ([]*StudioState, error) {
+	keys := make([]struct {
+		Endpoint string `db:"endpoint"`
+		StudioID string `db:"studio_id"`
+	}, 0)
+	if err := s.db.SelectContext(ctx, &keys, `SELECT endpoint, studio_id FROM subscription_studio_state`); err != nil {
+		return nil, fmt.Errorf("subscription: list studio states: %w", err)
+	}
+
+	states := make([]*StudioState, 0, len(keys))
+	for _, key := range keys {
+		state, err := s.GetStudio(ctx, key.Endpoint, key.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil {
+			states = append(states, state)
+		}
+	}
+
+	sortStudioStates(states)
+	return states, nil
+}
+
+type sqliteStudioStateRow struct {
+	Endpoint      string         `db:"endpoint"`
+	StudioID      string         `db:"studio_id"`
+	Name          string         `db:"name"`
+	SubscribedAt  string         `db:"subscribed_at"`
+	LastCheckedAt sql.NullString `db:"last_checked_at"`
+	LastError     sql.NullString `db:"last_error"`
+}
+
+func (r sqliteStudioStateRow) toState() (*StudioState, error) {
+	subscribedAt, err := parseTimestamp(r.SubscribedAt)
+	if err != nil {
+		return nil, fmt.Errorf("subscription: parse subscribed_at for studio %q: %w", r.StudioID, err)
+	}
+	lastCheckedAt, err := parseOptionalTimestamp(r.LastCheckedAt)
+	if err != nil {
+		return nil, fmt.Errorf("subscription: parse last_checked_at for studio %q: %w", r.StudioID, err)
+	}
+	return &StudioState{
+		Endpoint:      r.Endpoint,
+		StudioID:      r.StudioID,
+		Name:          r.Name,
+		SubscribedAt:  subscribedAt,
+		LastCheckedAt: lastCheckedAt,
+		LastError:     nullableStringValue(r.LastError),
+	}, nil
+}
@@ -1,6 +1,9 @@
 package subscription
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Store interface {
 	Get(ctx context.Context, performerID string) (*PerformerState, error)
@@ -8,3 +11,22 @@ type Store interface {
 	Delete(ctx context.Context, performerID string) error
 	List(ctx context.Context) ([]*PerformerState, error)
 }
+
+// StudioStore persists studio subscriptions. Stores that do not implement it
+// leave studio subscriptions unavailable.
+type StudioStore interface {
+	GetStudio(ctx context.Context, endpoint, studioID string) (*StudioState, error)
+	PutStudio(ctx context.Context, state *StudioState) error
+	DeleteStudio(ctx context.Context, endpoint, studioID string) error
+	ListStudios(ctx context.Context) ([]*StudioState, error)
+}
+
+// ReleaseReviewStore backs the global review queue of QUEUED releases.
+// Rejections outlive the release history so a rejected release is never
+// queued again; snoozes hide a release from the queue until they expire.
+type ReleaseReviewStore interface {
+	ListQueuedReleases(ctx context.Context, filter ReleaseReviewFilter, now time.Time) ([]QueuedRelease, int, error)
+	RejectReleases(ctx context.Context, keys []string, now time.Time) error
+	SnoozeReleases(ctx context.Context, keys []string, until, now time.Time) error
+	RejectedReleaseKeys(ctx context.Context, keys []string) (map[string]struct{}, error)
+}
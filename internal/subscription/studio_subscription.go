@@ -0,0 +1,245 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
+)
+
+var (
+	ErrStudioSubscriptionsUnavailable = errors.New("subscription: store does not support studio subscriptions")
+	ErrStudioNotSubscribed            = errors.New("subscription: studio is not subscribed")
+)
+
+func (s *Service) studioStore() (StudioStore, error) {
+	store, ok := s.store.(StudioStore)
+	if !ok {
+		return nil, ErrStudioSubscriptionsUnavailable
+	}
+	return store, nil
+}
+
+func (s *Service) ListSubscribedStudios(ctx context.Context) ([]SubscribedStudio, error) {
+	store, err := s.studioStore()
+	if err != nil {
+		return nil, err
+	}
+	states, err := store.ListStudios(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SubscribedStudio, 0, len(states))
+	for _, state := range states {
+		out = append(out, buildSubscribedStudio(state))
+	}
+	return out, nil
+}
+
+// SubscribeStudio subscribes a StashBox studio. Subscribing an already
+// subscribed studio returns its current state.
+func (s *Service) SubscribeStudio(ctx context.Context, endpoint, studioID string) (SubscribedStudio, error) {
+	endpoint, studioID, err := normalizeStudioKey(endpoint, studioID)
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	store, err := s.studioStore()
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	unlock := s.lockPerformerOperation(studioOperationKey(endpoint, studioID))
+	defer unlock()
+
+	existing, err := store.GetStudio(ctx, endpoint, studioID)
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	if existing != nil {
+		return buildSubscribedStudio(existing), nil
+	}
+
+	studio, err := s.metadata.FindStudio(ctx, endpoint, studioID)
+	if err != nil {
+		logging.Errorf("subscription: look up studio %s on %s failed: %v", studioID, endpoint, err)
+		return SubscribedStudio{}, err
+	}
+	if studio == nil {
+		return SubscribedStudio{}, fmt.Errorf("subscription: studio %q not found on %s", studioID, endpoint)
+	}
+
+	state := &StudioState{Endpoint: endpoint, StudioID: studioID, Name: studio.Name, SubscribedAt: s.now().UTC()}
+	if err := store.PutStudio(ctx, state); err != nil {
+		logging.Errorf("subscription: persist studio %s subscription failed: %v", studioID, err)
+		return SubscribedStudio{}, err
+	}
+	logging.Infof("subscription: subscribed studio %s (%s) on %s", studioID, studio.Name, endpoint)
+	return buildSubscribedStudio(state), nil
+}
+
+func (s *Service) UnsubscribeStudio(ctx context.Context, endpoint, studioID string) error {
+	endpoint, studioID, err := normalizeStudioKey(endpoint, studioID)
+	if err != nil {
+		return err
+	}
+	store, err := s.studioStore()
+	if err != nil {
+		return err
+	}
+	unlock := s.lockPerformerOperation(studioOperationKey(endpoint, studioID))
+	defer unlock()
+
+	if err := store.DeleteStudio(ctx, endpoint, studioID); err != nil {
+		logging.Errorf("subscription: delete studio %s subscription failed: %v", studioID, err)
+		return err
+	}
+	logging.Infof("subscription: unsubscribed studio %s on %s", studioID, endpoint)
+	return nil
+}
+
+func (s *Service) RefreshSubscribedStudio(ctx context.Context, endpoint, studioID string) (SubscribedStudio, error) {
+	endpoint, studioID, err := normalizeStudioKey(endpoint, studioID)
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	store, err := s.studioStore()
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	unlock := s.lockPerformerOperation(studioOperationKey(endpoint, studioID))
+	defer unlock()
+
+	state, err := store.GetStudio(ctx, endpoint, studioID)
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	if state == nil {
+		return SubscribedStudio{}, ErrStudioNotSubscribed
+	}
+	subject := fmt.Sprintf("studio %s (%s)", studioID, state.Name)
+	logging.Infof("subscription: refresh started for %s", subject)
+
+	now := s.now().UTC()
+	state.LastCheckedAt = &now
+	previousLastError := state.LastError
+	state.LastError = ""
+
+	releases, err := s.fetchStudioReleases(ctx, state)
+	if err != nil {
+		state.LastError = err.Error()
+		if putErr := store.PutStudio(ctx, state); putErr != nil {
+			logging.Errorf("subscription: persist error state for %s failed: %v", subject, putErr)
+			return SubscribedStudio{}, putErr
+		}
+		logging.Errorf("subscription: refresh failed for %s: %v", subject, err)
+		return buildSubscribedStudio(state), err
+	}
+
+	ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases, lastError: state.LastError}
+	skippedInLibrary, err := s.recordReleases(ctx, subject, &ledger, releases, now, func(release Release) taskruntime.TaskOrigin {
+		return taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: release.SceneID}
+	})
+	if err != nil {
+		return SubscribedStudio{}, err
+	}
+	state.ProcessedReleases, state.PendingReleases, state.LastError = ledger.processed, ledger.pending, ledger.lastError
+	if state.LastError == "" && len(state.PendingReleases) > 0 && previousLastError != "" {
+		state.LastError = previousLastError
+	}
+	if err := store.PutStudio(ctx, state); err != nil {
+		logging.Errorf("subscription: persist state for %s failed: %v", subject, err)
+		return SubscribedStudio{}, err
+	}
+	logging.Infof(
+		"subscription: refresh completed for %s, processed=%d pending=%d skipped_in_library=%d",
+		subject,
+		len(state.ProcessedReleases),
+		len(state.PendingReleases),
+		skippedInLibrary,
+	)
+	return buildSubscribedStudio(state), nil
+}
+
+// RefreshStudios polls every subscribed studio. A failing studio records its
+// error and does not stop the others.
+func (s *Service) RefreshStudios(ctx context.Context) ([]SubscribedStudio, error) {
+	items, err := s.ListSubscribedStudios(ctx)
+	if err != nil {
+		if errors.Is(err, ErrStudioSubscriptionsUnavailable) {
+			return nil, nil
+		}
+		logging.Errorf("subscription: list subscribed studios failed: %v", err)
+		return nil, err
+	}
+	out := make([]SubscribedStudio, 0, len(items))
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		refreshed, err := s.RefreshSubscribedStudio(ctx, item.Endpoint, item.StudioID)
+		if err != nil && refreshed.StudioID == "" {
+			refreshed = item
+		}
+		out = append(out, refreshed)
+	}
+	return out, nil
+}
+
+func (s *Service) fetchStudioReleases(ctx context.Context, state *StudioState) ([]Release, error) {
+	client, ok := s.metadata.Get(state.Endpoint)
+	if !ok {
+		return nil, fmt.Errorf("subscription: stash-box endpoint %q is not configured in Stash", state.Endpoint)
+	}
+	return s.scanReleases(releaseScan{
+		subject:  "studio=" + state.StudioID,
+		endpoint: state.Endpoint,
+		loadPage: func(page, perPage int) ([]*stashboxgraphql.SceneFragment, error) {
+			if s.metadata.HasCache() {
+				cached, err := s.metadata.LoadStudioScenes(ctx, state.Endpoint, state.StudioID, page*perPage, releaseCachePolicy(page))
+				if err != nil {
+					return nil, err
+				}
+				return cachedReleasePage(cached.Scenes, page, perPage), nil
+			}
+			return client.QueryScenes(ctx, stashboxgraphql.SceneQueryInput{
+				Studios: &stashboxgraphql.MultiIDCriterionInput{Value: []string{state.StudioID}, Modifier: stashboxgraphql.CriterionModifierIncludes},
+				Page:    page, PerPage: perPage, Direction: stashboxgraphql.SortDirectionEnumDesc, Sort: stashboxgraphql.SceneSortEnumDate,
+			})
+		},
+	}, state.ProcessedReleases, state.PendingReleases)
+}
+
+func normalizeStudioKey(endpoint, studioID string) (string, string, error) {
+	endpoint = metadata.NormalizeEndpoint(endpoint)
+	studioID = strings.TrimSpace(studioID)
+	if endpoint == "" || studioID == "" {
+		return "", "", errors.New("subscription: studio endpoint and id are required")
+	}
+	return endpoint, studioID, nil
+}
+
+func studioOperationKey(endpoint, studioID string) string {
+	return "studio:" + endpoint + ":" + studioID
+}
+
+func buildSubscribedStudio(state *StudioState) SubscribedStudio {
+	item := SubscribedStudio{
+		Endpoint:              state.Endpoint,
+		StudioID:              state.StudioID,
+		Name:                  state.Name,
+		SubscribedAt:          state.SubscribedAt,
+		LastCheckedAt:         state.LastCheckedAt,
+		LastError:             state.LastError,
+		PendingReleaseCount:   len(state.PendingReleases),
+		ProcessedReleaseCount: len(state.ProcessedReleases),
+	}
+	item.RecentReleases = append([]RecordedRelease(nil), state.PendingReleases...)
+	item.RecentReleases = append(item.RecentReleases, state.ProcessedReleases...)
+	item.RecentReleases = trimRecordedReleases(item.RecentReleases, 10)
+	item.UpcomingReleases = upcomingReleases(state.PendingReleases)
+	return item
+}
@@ -0,0 +1,141 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/stash"
+	stashboxgraphql "github.com/leothevan2444/moji/pkg/stashbox/graphql"
+)
+
+func TestSubscribeAndRefreshStudioQueuesReleasesWithoutPerformer(t *testing.T) {
+	endpoint := "https://javstash.example.org/graphql"
+	code := "STU-001"
+	title := "Studio Release"
+	client := &fakeStashboxClient{
+		studio: &stashboxgraphql.StudioFragment{ID: "studio-1", Name: "Studio A"},
+		scenes: []*stashboxgraphql.SceneFragment{{
+			ID:         "js-scene-1",
+			Title:      &title,
+			Code:       &code,
+			Performers: []*stashboxgraphql.PerformerAppearanceFragment{{Performer: &stashboxgraphql.PerformerFragment{ID: "js-1", Name: "Rara Anzai"}}},
+		}},
+	}
+	registry := metadata.NewRegistry(stubFactory{client: client})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "javstash", Endpoint: endpoint, APIKey: "ignored"}})
+
+	taskRuntime := &fakeTaskRuntime{tasks: []*taskruntime.Task{{ID: "task-1"}}}
+	service, err := newServiceForTest(&fakeStashClient{}, registry, taskRuntime, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	subscribed, err := service.SubscribeStudio(context.Background(), endpoint, " studio-1 ")
+	if err != nil {
+		t.Fatalf("SubscribeStudio failed: %v", err)
+	}
+	if subscribed.Name != "Studio A" || subscribed.Endpoint != endpoint || subscribed.StudioID != "studio-1" {
+		t.Fatalf("unexpected subscribed studio: %+v", subscribed)
+	}
+
+	refreshed, err := service.RefreshStudios(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshStudios failed: %v", err)
+	}
+	if len(refreshed) != 1 || refreshed[0].ProcessedReleaseCount != 1 || refreshed[0].LastCheckedAt == nil {
+		t.Fatalf("unexpected refreshed studios: %+v", refreshed)
+	}
+	if input := client.queryInputs[0]; input.Studios == nil || input.Studios.Value[0] != "studio-1" || input.Performers != nil {
+		t.Fatalf("expected a studio scene query, got %+v", input)
+	}
+	wantOrigin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: "js-scene-1"}
+	if taskRuntime.calls != 1 || taskRuntime.origins[0] != wantOrigin {
+		t.Fatalf("expected one queued release with origin %+v, got calls=%d origins=%+v", wantOrigin, taskRuntime.calls, taskRuntime.origins)
+	}
+
+	if err := service.UnsubscribeStudio(context.Background(), endpoint, "studio-1"); err != nil {
+		t.Fatalf("UnsubscribeStudio failed: %v", err)
+	}
+	if _, err := service.RefreshSubscribedStudio(context.Background(), endpoint, "studio-1"); !errors.Is(err, ErrStudioNotSubscribed) {
+		t.Fatalf("expected ErrStudioNotSubscribed after unsubscribe, got %v", err)
+	}
+}
+
+func TestSubscribeStudioRejectsUnknownStudio(t *testing.T) {
+	endpoint := "https://javstash.example.org/graphql"
+	registry := metadata.NewRegistry(stubFactory{client: &fakeStashboxClient{}})
+	registry.Replace([]stash.StashBoxEndpoint{{Name: "javstash", Endpoint: endpoint, APIKey: "ignored"}})
+
+	service, err := newServiceForTest(&fakeStashClient{}, registry, nil, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	if _, err := service.SubscribeStudio(context.Background(), endpoint, "missing"); err == nil {
+		t.Fatal("expected error for an unknown studio")
+	}
+	items, err := service.ListSubscribedStudios(context.Background())
+	if err != nil {
+		t.Fatalf("ListSubscribedStudios failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("expected no subscribed studios, got %+v", items)
+	}
+}
+
+func TestSQLiteStoreStudioRoundTrip(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "studios.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer store.db.Close()
+
+	now := time.Unix(900, 0).UTC()
+	state := &StudioState{
+		Endpoint:      "https://javstash.example.org/graphql",
+		StudioID:      "studio-1",
+		Name:          "Studio A",
+		SubscribedAt:  now,
+		LastCheckedAt: &now,
+		PendingReleases: []RecordedRelease{{
+			Key:    "stashbox:javstash.example.org/graphql:scene-1",
+			Source: "stash-box:https://javstash.example.org/graphql",
+			Title:  "Title",
+			Code:   "STU-001",
+			SeenAt: now,
+		}},
+	}
+	if err := store.PutStudio(context.Background(), state); err != nil {
+		t.Fatalf("PutStudio failed: %v", err)
+	}
+
+	loaded, err := store.GetStudio(context.Background(), state.Endpoint, state.StudioID)
+	if err != nil {
+		t.Fatalf("GetStudio failed: %v", err)
+	}
+	if loaded == nil || loaded.Name != "Studio A" || len(loaded.PendingReleases) != 1 || loaded.PendingReleases[0].Code != "STU-001" {
+		t.Fatalf("unexpected loaded studio: %#v", loaded)
+	}
+
+	if err := store.DeleteStudio(context.Background(), state.Endpoint, state.StudioID); err != nil {
+		t.Fatalf("DeleteStudio failed: %v", err)
+	}
+	states, err := store.ListStudios(context.Background())
+	if err != nil {
+		t.Fatalf("ListStudios failed: %v", err)
+	}
+	if len(states) != 0 {
+		t.Fatalf("expected no studios after delete, got %#v", states)
+	}
+	var releaseCount int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM subscription_release_entities`).Scan(&releaseCount); err != nil {
+		t.Fatalf("count release entities after delete: %v", err)
+	}
+	if releaseCount != 0 {
+		t.Fatalf("expected orphan release entities to be removed, got %d", releaseCount)
+	}
+}
@@ -31,6 +31,10 @@ type RecordedRelease struct {
 	Classification ReleaseClassification `json:"classification,omitempty"`
 	Decision       ReleaseDecision       `json:"decision,omitempty"`
 	DecisionReason string                `json:"decision_reason,omitempty"`
+	// ScheduledAt is when sourcing of an UPCOMING release is next tried and
+	// SourceAttempts how often it was tried already.
+	ScheduledAt    time.Time `json:"scheduled_at,omitzero"`
+	SourceAttempts int       `json:"source_attempts,omitempty"`
 }
 type PerformerState struct {
 	PerformerID       string            `json:"performer_id"`
@@ -38,13 +42,40 @@ type PerformerState struct {
 	LastError         string            `json:"last_error,omitempty"`
 	ProcessedReleases []RecordedRelease `json:"processed_releases,omitempty"`
 	PendingReleases   []RecordedRelease `json:"pending_releases,omitempty"`
+	// ReleasePolicy replaces the global release policy for this performer
+	// when set.
+	ReleasePolicy *ReleasePolicyConfig `json:"release_policy,omitempty"`
+}
+
+// StudioState is a StashBox studio subscription. Unlike performers, which
+// carry their subscription mark in Stash, studios are only known to Moji.
+type StudioState struct {
+	Endpoint          string            `json:"endpoint"`
+	StudioID          string            `json:"studio_id"`
+	Name              string            `json:"name"`
+	SubscribedAt      time.Time         `json:"subscribed_at"`
+	LastCheckedAt     *time.Time        `json:"last_checked_at,omitempty"`
+	LastError         string            `json:"last_error,omitempty"`
+	ProcessedReleases []RecordedRelease `json:"processed_releases,omitempty"`
+	PendingReleases   []RecordedRelease `json:"pending_releases,omitempty"`
+}
+type SubscribedStudio struct {
+	Endpoint, StudioID, Name                   string
+	SubscribedAt                               time.Time
+	LastCheckedAt                              *time.Time
+	LastError                                  string
+	PendingReleaseCount, ProcessedReleaseCount int
+	RecentReleases                             []RecordedRelease
+	UpcomingReleases                           []RecordedRelease
 }
 type SubscribedPerformer struct {
 	Performer                                  performer.Performer
 	LastCheckedAt                              *time.Time
 	LastError                                  string
+	ReleasePolicy                              *ReleasePolicyConfig
 	PendingReleaseCount, ProcessedReleaseCount int
 	RecentReleases                             []RecordedRelease
+	UpcomingReleases                           []RecordedRelease
 }
 
 type PerformerBatchStatus string
@@ -64,6 +95,8 @@ const (
 	PerformerBatchReasonPerformerNotFound = "PERFORMER_NOT_FOUND"
 	PerformerBatchReasonStashUpdateFailed = "STASH_UPDATE_FAILED"
 	PerformerBatchReasonRefreshFailed     = "REFRESH_FAILED"
+	PerformerBatchReasonPolicyUpdated     = "RELEASE_POLICY_UPDATED"
+	PerformerBatchReasonPolicyFailed      = "RELEASE_POLICY_UPDATE_FAILED"
 	PerformerBatchReasonCancelled         = "CANCELLED"
 )
 
@@ -103,6 +136,11 @@ const (
 	ReleaseDecisionDownloaded ReleaseDecision = "DOWNLOADED"
 	ReleaseDecisionQueued     ReleaseDecision = "QUEUED"
 	ReleaseDecisionBlocked    ReleaseDecision = "BLOCKED"
+	// ReleaseDecisionRejected marks a QUEUED release rejected in review.
+	ReleaseDecisionRejected ReleaseDecision = "REJECTED"
+	// ReleaseDecisionUpcoming marks a release the policy downloads whose
+	// release date is still in the future; it is sourced on a schedule.
+	ReleaseDecisionUpcoming ReleaseDecision = "UPCOMING"
 )
 
 type ReleaseEvaluation struct {
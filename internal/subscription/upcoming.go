@@ -0,0 +1,265 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/codeparser"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+const upcomingTickInterval = 15 * time.Minute
+
+var errNoUpcomingCandidate = errors.New("subscription: no torrent found for upcoming release")
+
+// UpcomingSettings controls the scheduled sourcing of releases dated in the
+// future. Disabled settings queue them immediately like any other release.
+type UpcomingSettings struct {
+	Enabled       bool
+	Delay         time.Duration
+	RetryInterval time.Duration
+	MaxAttempts   int
+}
+
+type UpcomingConfigProvider func() UpcomingSettings
+
+func (s *Service) SetUpcomingReleases(provider UpcomingConfigProvider) {
+	if s == nil {
+		return
+	}
+	s.policyMu.Lock()
+	s.upcoming = provider
+	s.policyMu.Unlock()
+}
+
+// SetReleaseSearcher lets scheduled sourcing search for an upcoming release
+// before creating its task, so a release that is not out yet is retried
+// later instead of leaving a blocked task behind.
+func (s *Service) SetReleaseSearcher(searcher tracker.Tracker) {
+	if s == nil {
+		return
+	}
+	s.policyMu.Lock()
+	s.searcher = searcher
+	s.policyMu.Unlock()
+}
+
+func (s *Service) upcomingSettings() UpcomingSettings {
+	s.policyMu.RLock()
+	provider := s.upcoming
+	s.policyMu.RUnlock()
+	if provider == nil {
+		return UpcomingSettings{}
+	}
+	settings := provider()
+	settings.MaxAttempts = max(settings.MaxAttempts, 1)
+	return settings
+}
+
+// markUpcoming defers a release the policy downloads when its release date
+// is after now, scheduling its first sourcing attempt.
+func markUpcoming(release *RecordedRelease, settings UpcomingSettings, now time.Time) bool {
+	if !settings.Enabled || release.Decision != ReleaseDecisionDownloaded {
+		return false
+	}
+	releaseDate, ok := parseReleaseDate(release.Date)
+	if !ok || !releaseDate.After(now) {
+		return false
+	}
+	release.Decision = ReleaseDecisionUpcoming
+	release.DecisionReason = "release_date_upcoming"
+	release.ScheduledAt = releaseDate.Add(settings.Delay)
+	return true
+}
+
+// StartUpcomingSourcing sources due upcoming releases every tick until ctx is
+// cancelled.
+func (s *Service) StartUpcomingSourcing(ctx context.Context) {
+	if s == nil || s.taskCreator == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(upcomingTickInterval)
+		defer ticker.Stop()
+		for {
+			if s.upcomingSettings().Enabled {
+				if _, err := s.SourceDueUpcomingReleases(ctx); err != nil && ctx.Err() == nil {
+					logging.Warnf("subscription: source upcoming releases: %v", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SourceDueUpcomingReleases tries to source every upcoming release whose
+// scheduled time has passed and returns the tasks created. A release that
+// is still not found is rescheduled after the retry interval; once its
+// attempts are used up it waits for a torrent as a pending download.
+func (s *Service) SourceDueUpcomingReleases(ctx context.Context) ([]*taskruntime.Task, error) {
+	if s.taskCreator == nil {
+		return nil, errors.New("subscription: task creator is not configured")
+	}
+	settings := s.upcomingSettings()
+	tasks := make([]*taskruntime.Task, 0)
+
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range states {
+		if !hasDueUpcomingRelease(snapshot.PendingReleases, s.now()) {
+			continue
+		}
+		if err := func() error {
+			unlock := s.lockPerformerOperation(snapshot.PerformerID)
+			defer unlock()
+			state, err := s.store.Get(ctx, snapshot.PerformerID)
+			if err != nil || state == nil {
+				return err
+			}
+			ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases}
+			created := s.sourceDueUpcoming(ctx, &ledger, settings, func(release RecordedRelease) taskruntime.TaskOrigin {
+				origin := pendingReleaseOrigin(release)
+				origin.StashPerformerID = state.PerformerID
+				return origin
+			})
+			tasks = append(tasks, created...)
+			state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+			return s.store.Put(ctx, state)
+		}(); err != nil {
+			return tasks, fmt.Errorf("performer %s: %w", snapshot.PerformerID, err)
+		}
+	}
+
+	studios, err := s.studioStore()
+	if err != nil {
+		return tasks, nil
+	}
+	studioStates, err := studios.ListStudios(ctx)
+	if err != nil {
+		return tasks, err
+	}
+	for _, snapshot := range studioStates {
+		if !hasDueUpcomingRelease(snapshot.PendingReleases, s.now()) {
+			continue
+		}
+		if err := func() error {
+			unlock := s.lockPerformerOperation(studioOperationKey(snapshot.Endpoint, snapshot.StudioID))
+			defer unlock()
+			state, err := studios.GetStudio(ctx, snapshot.Endpoint, snapshot.StudioID)
+			if err != nil || state == nil {
+				return err
+			}
+			ledger := releaseLedger{processed: state.ProcessedReleases, pending: state.PendingReleases}
+			tasks = append(tasks, s.sourceDueUpcoming(ctx, &ledger, settings, pendingReleaseOrigin)...)
+			state.ProcessedReleases, state.PendingReleases = ledger.processed, ledger.pending
+			return studios.PutStudio(ctx, state)
+		}(); err != nil {
+			return tasks, fmt.Errorf("studio %s/%s: %w", snapshot.Endpoint, snapshot.StudioID, err)
+		}
+	}
+	return tasks, nil
+}
+
+// sourceDueUpcoming attempts the due upcoming releases of one ledger and
+// files the ones that got a task as processed.
+func (s *Service) sourceDueUpcoming(ctx context.Context, ledger *releaseLedger, settings UpcomingSettings, origin func(RecordedRelease) taskruntime.TaskOrigin) []*taskruntime.Task {
+	now := s.now().UTC()
+	tasks := make([]*taskruntime.Task, 0)
+	pending := make([]RecordedRelease, 0, len(ledger.pending))
+	for _, release := range ledger.pending {
+		if release.Decision != ReleaseDecisionUpcoming || release.ScheduledAt.After(now) {
+			pending = append(pending, release)
+			continue
+		}
+		release.SourceAttempts++
+		task, err := s.queueUpcomingRelease(ctx, release, origin(release))
+		if task != nil {
+			// A task that blocked while sourcing is retried by the task
+			// runtime and the feed poller from here on.
+			release.Decision = ReleaseDecisionDownloaded
+			release.DecisionReason = "upcoming_sourced"
+			release.TaskID = task.ID
+			release.ScheduledAt = time.Time{}
+			ledger.processed = append([]RecordedRelease{release}, ledger.processed...)
+			tasks = append(tasks, task)
+			logging.Infof("subscription: upcoming release %s code %q sourced as task %s after %d attempts", release.Key, release.Code, task.ID, release.SourceAttempts)
+			continue
+		}
+		if release.SourceAttempts >= settings.MaxAttempts {
+			release.Decision = ReleaseDecisionDownloaded
+			release.DecisionReason = "upcoming_attempts_exhausted"
+			release.ScheduledAt = time.Time{}
+			logging.Warnf("subscription: upcoming release %s code %q not sourced after %d attempts: %v", release.Key, release.Code, release.SourceAttempts, err)
+		} else {
+			release.ScheduledAt = now.Add(settings.RetryInterval)
+			logging.Infof("subscription: upcoming release %s code %q not sourced yet, retrying at %s: %v", release.Key, release.Code, release.ScheduledAt.Format(time.RFC3339), err)
+		}
+		pending = append(pending, release)
+	}
+	ledger.pending = pending
+	ledger.processed = trimRecordedReleases(ledger.processed, 25)
+	return tasks
+}
+
+// queueUpcomingRelease creates the task for an upcoming release. With a
+// searcher configured the release is searched first and no task is created
+// while nothing is found.
+func (s *Service) queueUpcomingRelease(ctx context.Context, release RecordedRelease, origin taskruntime.TaskOrigin) (*taskruntime.Task, error) {
+	s.policyMu.RLock()
+	searcher := s.searcher
+	s.policyMu.RUnlock()
+	creator, ok := s.taskCreator.(FeedTaskCreator)
+	if searcher == nil || !ok {
+		return s.taskCreator.QueueSubscriptionRelease(ctx, release.Code, release.Title, origin)
+	}
+
+	code := buildReleaseCode(release.Code, release.Title)
+	var results []jackett.SearchResult
+	for _, query := range codeparser.SearchQueries(code) {
+		found, err := searcher.Search(query)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			results = found
+			break
+		}
+	}
+	if len(results) == 0 {
+		return nil, errNoUpcomingCandidate
+	}
+	return creator.QueueSubscriptionReleaseFromResults(ctx, release.Code, release.Title, origin, results)
+}
+
+func hasDueUpcomingRelease(pending []RecordedRelease, now time.Time) bool {
+	for _, release := range pending {
+		if release.Decision == ReleaseDecisionUpcoming && !release.ScheduledAt.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// upcomingReleases returns the UPCOMING releases in pending, soonest first.
+func upcomingReleases(pending []RecordedRelease) []RecordedRelease {
+	out := make([]RecordedRelease, 0)
+	for _, release := range pending {
+		if release.Decision == ReleaseDecisionUpcoming {
+			out = append(out, release)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ScheduledAt.Before(out[j].ScheduledAt) })
+	return out
+}
@@ -0,0 +1,142 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/metadata"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+type fakeReleaseSearcher struct {
+	results map[string][]jackett.SearchResult
+	queries []string
+}
+
+func (f *fakeReleaseSearcher) Search(query string, _ ...tracker.SearchOption) ([]jackett.SearchResult, error) {
+	f.queries = append(f.queries, query)
+	for code, results := range f.results {
+		if strings.Contains(query, code) {
+			return results, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestUpcomingReleasesAreScheduledAndRetriedUntilSourced(t *testing.T) {
+	ctx := context.Background()
+	endpoint := "https://javstash.example.org/graphql"
+	key := func(sceneID string) string { return "stashbox:" + endpointKey(endpoint) + ":" + sceneID }
+	source := "stash-box:" + endpoint
+	store := NewMemoryStore()
+	taskRuntime := &fakeTaskRuntime{tasks: []*taskruntime.Task{{ID: "task-1"}, {ID: "task-2"}}}
+	service, err := newServiceForTest(&fakeStashClient{}, metadata.NewRegistry(stubFactory{}), taskRuntime, store)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	now := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	service.SetUpcomingReleases(func() UpcomingSettings {
+		return UpcomingSettings{Enabled: true, Delay: 12 * time.Hour, RetryInterval: 24 * time.Hour, MaxAttempts: 2}
+	})
+	searcher := &fakeReleaseSearcher{}
+	service.SetReleaseSearcher(searcher)
+
+	for performerID, releases := range map[string][]Release{
+		"performer-1": {
+			{Key: key("scene-1"), Source: source, Code: "SONE-900", Date: "2026-07-10", Decision: ReleaseDecisionDownloaded},
+			{Key: key("scene-2"), Source: source, Code: "SONE-800", Date: "2026-06-01", Decision: ReleaseDecisionDownloaded},
+		},
+		"performer-2": {
+			{Key: key("scene-3"), Source: source, Code: "SONE-901", Date: "2026-07-10", Decision: ReleaseDecisionDownloaded},
+		},
+	} {
+		ledger := releaseLedger{}
+		if _, err := service.recordReleases(ctx, performerID, &ledger, releases, now, func(Release) taskruntime.TaskOrigin { return taskruntime.TaskOrigin{} }); err != nil {
+			t.Fatalf("recordReleases failed: %v", err)
+		}
+		if err := store.Put(ctx, &PerformerState{PerformerID: performerID, ProcessedReleases: ledger.processed, PendingReleases: ledger.pending}); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if taskRuntime.calls != 1 || taskRuntime.codes[0] != "SONE-800" {
+		t.Fatalf("expected only the released scene to be queued, got %+v", taskRuntime.codes)
+	}
+	state, err := store.Get(ctx, "performer-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	upcoming := upcomingReleases(state.PendingReleases)
+	wantScheduled := time.Date(2026, 7, 10, 12, 0, 0, 0, time.UTC)
+	if len(upcoming) != 1 || upcoming[0].Decision != ReleaseDecisionUpcoming || !upcoming[0].ScheduledAt.Equal(wantScheduled) {
+		t.Fatalf("expected the future release to be upcoming at %s, got %+v", wantScheduled, upcoming)
+	}
+
+	if tasks, err := service.SourceDueUpcomingReleases(ctx); err != nil || len(tasks) != 0 || len(searcher.queries) != 0 {
+		t.Fatalf("expected nothing due before the schedule, got tasks=%+v queries=%v err=%v", tasks, searcher.queries, err)
+	}
+
+	now = wantScheduled.Add(time.Hour)
+	if tasks, err := service.SourceDueUpcomingReleases(ctx); err != nil || len(tasks) != 0 {
+		t.Fatalf("expected no task without search results, got %+v %v", tasks, err)
+	}
+	state, err = store.Get(ctx, "performer-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if release := state.PendingReleases[0]; release.SourceAttempts != 1 || !release.ScheduledAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("expected a retry a day later, got %+v", release)
+	}
+
+	now = now.Add(25 * time.Hour)
+	searcher.results = map[string][]jackett.SearchResult{"SONE-900": {{Title: "SONE-900", MagnetURI: "magnet:?xt=urn:btih:upcoming"}}}
+	tasks, err := service.SourceDueUpcomingReleases(ctx)
+	if err != nil {
+		t.Fatalf("SourceDueUpcomingReleases failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "task-2" {
+		t.Fatalf("expected the found release to be queued, got %+v", tasks)
+	}
+	wantOrigin := taskruntime.TaskOrigin{StashBoxEndpoint: endpoint, StashBoxSceneID: "scene-1", StashPerformerID: "performer-1"}
+	if taskRuntime.calls != 2 || taskRuntime.origins[1] != wantOrigin || len(taskRuntime.results[1]) != 1 {
+		t.Fatalf("unexpected task request: origins=%+v results=%+v", taskRuntime.origins, taskRuntime.results)
+	}
+	state, err = store.Get(ctx, "performer-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(state.PendingReleases) != 0 || state.ProcessedReleases[0].TaskID != "task-2" || state.ProcessedReleases[0].Decision != ReleaseDecisionDownloaded {
+		t.Fatalf("expected the sourced release to be processed, got %+v", state)
+	}
+
+	// The release that was never found waits for a torrent once its
+	// attempts are used up.
+	pending, err := service.PendingDownloads(ctx)
+	if err != nil {
+		t.Fatalf("PendingDownloads failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Code != "SONE-901" {
+		t.Fatalf("expected the exhausted release to wait for a torrent, got %+v", pending)
+	}
+}
+
+func TestTrimPendingReleasesKeepsUpcomingReleases(t *testing.T) {
+	items := make([]RecordedRelease, 0, pendingReleaseLimit+3)
+	for index := range pendingReleaseLimit + 1 {
+		items = append(items, RecordedRelease{Key: fmt.Sprintf("queued-%d", index), Decision: ReleaseDecisionQueued})
+	}
+	items = append(items, RecordedRelease{Key: "upcoming-1", Decision: ReleaseDecisionUpcoming}, RecordedRelease{Key: "upcoming-2", Decision: ReleaseDecisionUpcoming})
+
+	kept := trimPendingReleases("performer-1", items)
+	if len(kept) != pendingReleaseLimit+2 || len(upcomingReleases(kept)) != 2 {
+		t.Fatalf("expected every upcoming release and %d others, got %d", pendingReleaseLimit, len(kept))
+	}
+	if kept[pendingReleaseLimit-1].Key != fmt.Sprintf("queued-%d", pendingReleaseLimit-1) {
+		t.Fatalf("expected the oldest queued releases to be kept, got %+v", kept[pendingReleaseLimit-1])
+	}
+}
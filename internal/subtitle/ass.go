@@ -0,0 +1,113 @@
+package subtitle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var assOverridePattern = regexp.MustCompile(`\{[^}]*\}`)
+
+type srtCue struct {
+	start time.Duration
+	end   time.Duration
+	text  string
+}
+
+// convertASSToSRT keeps the timing and plain text of ASS dialogue lines,
+// dropping styling, because Stash only reads SRT and VTT captions. It
+// returns nil when the input has no usable dialogue.
+func convertASSToSRT(data []byte) []byte {
+	var cues []srtCue
+	inEvents := false
+	fields := []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "format":
+			fields = fields[:0]
+			for _, field := range strings.Split(value, ",") {
+				fields = append(fields, strings.ToLower(strings.TrimSpace(field)))
+			}
+		case "dialogue":
+			parts := strings.SplitN(value, ",", len(fields))
+			if len(parts) != len(fields) {
+				continue
+			}
+			var cue srtCue
+			var startOK, endOK bool
+			for index, field := range fields {
+				switch field {
+				case "start":
+					cue.start, startOK = parseASSTime(parts[index])
+				case "end":
+					cue.end, endOK = parseASSTime(parts[index])
+				case "text":
+					cue.text = assText(parts[index])
+				}
+			}
+			if startOK && endOK && cue.text != "" {
+				cues = append(cues, cue)
+			}
+		}
+	}
+	if len(cues) == 0 {
+		return nil
+	}
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].start < cues[j].start })
+	var out bytes.Buffer
+	for index, cue := range cues {
+		fmt.Fprintf(&out, "%d\n%s --> %s\n%s\n\n", index+1, formatSRTTime(cue.start), formatSRTTime(cue.end), cue.text)
+	}
+	return out.Bytes()
+}
+
+// parseASSTime parses "H:MM:SS.cc".
+func parseASSTime(value string) (time.Duration, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)).Round(time.Millisecond), true
+}
+
+func formatSRTTime(value time.Duration) string {
+	ms := value.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func assText(value string) string {
+	text := assOverridePattern.ReplaceAllString(value, "")
+	text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+	return strings.TrimSpace(text)
+}
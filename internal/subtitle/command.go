@@ -0,0 +1,54 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/command"
+)
+
+// CommandProvider runs a local command, for example whisper.cpp, to generate
+// subtitles. Args may use {video}, {output} and {code}; the command is
+// expected to write {output}.srt, .vtt or .ass.
+type CommandProvider struct {
+	name     string
+	command  string
+	args     []string
+	timeout  time.Duration
+	language string
+}
+
+func NewCommandProvider(name, command string, args []string, timeout time.Duration, language string) *CommandProvider {
+	if timeout <= 0 {
+		timeout = time.Hour
+	}
+	return &CommandProvider{name: name, command: command, args: args, timeout: timeout, language: normalizeLanguage(language)}
+}
+
+func (p *CommandProvider) Name() string { return p.name }
+
+func (p *CommandProvider) Fetch(ctx context.Context, req Request) ([]Subtitle, error) {
+	output := req.OutputBase
+	if p.language != "" {
+		output += "." + p.language
+	}
+	replacer := strings.NewReplacer("{video}", req.VideoPath, "{output}", output, "{code}", req.Code)
+	args := make([]string, 0, len(p.args))
+	for _, arg := range p.args {
+		args = append(args, replacer.Replace(arg))
+	}
+
+	if err := command.Run(ctx, p.timeout, p.command, args, command.Options{}); err != nil {
+		return nil, fmt.Errorf("command failed: %w", err)
+	}
+
+	for _, ext := range []string{".srt", ".vtt", ".ass"} {
+		if info, err := os.Stat(output + ext); err == nil && !info.IsDir() {
+			return []Subtitle{{Path: output + ext, Language: p.language}}, nil
+		}
+	}
+	return nil, fmt.Errorf("command did not write %s.srt", output)
+}
@@ -0,0 +1,73 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/codeparser"
+	"github.com/leothevan2444/moji/internal/logging"
+)
+
+// LibraryProvider matches .srt and .ass files in local subtitle folders to a
+// video by the code in their file name.
+type LibraryProvider struct {
+	name     string
+	dirs     []string
+	language string
+}
+
+func NewLibraryProvider(name string, dirs []string, language string) *LibraryProvider {
+	return &LibraryProvider{name: name, dirs: dirs, language: normalizeLanguage(language)}
+}
+
+func (p *LibraryProvider) Name() string { return p.name }
+
+func (p *LibraryProvider) Fetch(ctx context.Context, req Request) ([]Subtitle, error) {
+	code := codeparser.Normalize(req.Code)
+	if code == "" {
+		return nil, nil
+	}
+	var found []Subtitle
+	for _, dir := range p.dirs {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir {
+					return err
+				}
+				logging.Warnf("subtitle: skip %s: %v", path, err)
+				return nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".srt", ".ass":
+			default:
+				return nil
+			}
+			if codeparser.Extract(entry.Name()) != code {
+				return nil
+			}
+			language := languageFromName(path)
+			if language == "" {
+				language = p.language
+			}
+			found = append(found, Subtitle{Path: path, Language: language})
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				logging.Warnf("subtitle: library dir %s does not exist", dir)
+				continue
+			}
+			return found, err
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,83 @@
+package subtitle
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// Request describes one video that needs subtitles. OutputBase is the video
+// path without its extension; providers that generate files should write
+// OutputBase plus a subtitle extension so Stash pairs it with the video.
+type Request struct {
+	Code       string
+	VideoPath  string
+	OutputBase string
+}
+
+// Subtitle is a file a provider found or generated. Language is a two-letter
+// code or empty when unknown.
+type Subtitle struct {
+	Path     string
+	Language string
+}
+
+// Provider finds or generates subtitles for a video. Returning no subtitles
+// and no error means the provider has nothing for it and the next provider
+// is tried.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, req Request) ([]Subtitle, error)
+}
+
+// captionExts are the caption formats Stash reads next to a video.
+var captionExts = []string{".srt", ".vtt"}
+
+var videoExts = []string{".mp4", ".mkv", ".avi", ".wmv", ".mov", ".ts", ".m2ts", ".m4v", ".webm"}
+
+func isVideoPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, candidate := range videoExts {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+var languageAliases = map[string]string{
+	"chs": "zh", "cht": "zh", "chi": "zh", "zho": "zh", "sc": "zh", "tc": "zh", "cn": "zh",
+	"jpn": "ja", "jp": "ja",
+	"eng": "en",
+	"kor": "ko",
+}
+
+// normalizeLanguage maps common subtitle language tags onto the two-letter
+// codes Stash expects in caption file names, or returns "" when the tag is
+// not recognised.
+func normalizeLanguage(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if alias, ok := languageAliases[value]; ok {
+		return alias
+	}
+	if len(value) != 2 {
+		return ""
+	}
+	for _, r := range value {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return value
+}
+
+// languageFromName reads the language tag of "name.<lang>.srt" style file
+// names.
+func languageFromName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	dot := strings.LastIndex(base, ".")
+	if dot < 0 {
+		return ""
+	}
+	return normalizeLanguage(base[dot+1:])
+}
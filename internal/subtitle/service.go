@@ -0,0 +1,191 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+)
+
+// Settings is the provider chain read for every task, so config changes
+// apply without a restart.
+type Settings struct {
+	Enabled   bool
+	Providers []Provider
+}
+
+type ConfigProvider func() Settings
+
+// Service installs subtitles next to delivered videos as
+// "<video name>.<lang>.srt", the caption naming Stash scans for.
+type Service struct {
+	settings ConfigProvider
+}
+
+func New(provider ConfigProvider) *Service {
+	if provider == nil {
+		provider = func() Settings { return Settings{} }
+	}
+	return &Service{settings: provider}
+}
+
+// Enabled reports whether the subtitle stage should run at all.
+func (s *Service) Enabled() bool {
+	if s == nil {
+		return false
+	}
+	settings := s.settings()
+	return settings.Enabled && len(settings.Providers) > 0
+}
+
+// Subtitle fetches subtitles for every video under path that has no caption
+// yet, trying providers in order, and returns how many caption files were
+// written. Videos no provider has anything for are not an error; an error is
+// returned only when providers failed and nothing could be installed.
+func (s *Service) Subtitle(ctx context.Context, code string, path string) (int, error) {
+	videos, err := listVideos(path)
+	if err != nil {
+		return 0, err
+	}
+	providers := s.settings().Providers
+	installed := 0
+	var errs []error
+	for _, video := range videos {
+		base := strings.TrimSuffix(video, filepath.Ext(video))
+		if hasCaption(base) {
+			continue
+		}
+		req := Request{Code: code, VideoPath: video, OutputBase: base}
+		for _, provider := range providers {
+			subtitles, err := provider.Fetch(ctx, req)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", provider.Name(), filepath.Base(video), err))
+				if ctx.Err() != nil {
+					return installed, errors.Join(errs...)
+				}
+				continue
+			}
+			count, err := install(base, subtitles)
+			installed += count
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", provider.Name(), filepath.Base(video), err))
+				continue
+			}
+			if count > 0 {
+				logging.Infof("subtitle: %s installed %d caption(s) for %s", provider.Name(), count, video)
+				break
+			}
+		}
+	}
+	if installed == 0 && len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		logging.Warnf("subtitle: %v", err)
+	}
+	return installed, nil
+}
+
+func listVideos(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if isVideoPath(root) {
+			return []string{root}, nil
+		}
+		return nil, nil
+	}
+	var videos []string
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isVideoPath(path) {
+			return nil
+		}
+		if strings.Contains(strings.ToLower(entry.Name()), "sample") {
+			return nil
+		}
+		videos = append(videos, path)
+		return nil
+	})
+	return videos, err
+}
+
+// hasCaption reports whether a "<base>.srt" or "<base>.<lang>.srt" style
+// caption already sits next to the video.
+func hasCaption(base string) bool {
+	entries, err := os.ReadDir(filepath.Dir(base))
+	if err != nil {
+		return false
+	}
+	name := filepath.Base(base)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".srt" && ext != ".vtt" {
+			continue
+		}
+		stem := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if stem == name {
+			return true
+		}
+		if dot := strings.LastIndex(stem, "."); dot >= 0 && stem[:dot] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// install writes each subtitle next to the video as a caption Stash reads,
+// converting ASS to SRT. One caption is kept per language.
+func install(base string, subtitles []Subtitle) (int, error) {
+	installed := 0
+	seen := map[string]struct{}{}
+	for _, subtitle := range subtitles {
+		if _, ok := seen[subtitle.Language]; ok {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(subtitle.Path))
+		target := base
+		if subtitle.Language != "" {
+			target += "." + subtitle.Language
+		}
+		switch ext {
+		case ".srt", ".vtt":
+			target += ext
+		case ".ass":
+			target += ".srt"
+		default:
+			continue
+		}
+		if _, err := os.Stat(target); err == nil && target != subtitle.Path {
+			seen[subtitle.Language] = struct{}{}
+			continue
+		}
+		if target != subtitle.Path {
+			data, err := os.ReadFile(subtitle.Path)
+			if err != nil {
+				return installed, err
+			}
+			if ext == ".ass" {
+				data = convertASSToSRT(data)
+				if len(data) == 0 {
+					continue
+				}
+			}
+			if err := os.WriteFile(target, data, 0o644); err != nil {
+				return installed, err
+			}
+		}
+		seen[subtitle.Language] = struct{}{}
+		installed++
+	}
+	return installed, nil
+}
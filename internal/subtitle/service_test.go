@@ -0,0 +1,104 @@
+package subtitle
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const sampleASS = `[Script Info]
+Title: test
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:05.50,0:00:07.00,Default,,0,0,0,,{\i1}second{\i0}
+Dialogue: 0,0:00:01.00,0:00:02.25,Default,,0,0,0,,first, line\Nwrapped
+`
+
+func TestLibraryProviderInstallsCaptionsByCode(t *testing.T) {
+	root := t.TempDir()
+	library := filepath.Join(root, "subs")
+	writeFile(t, filepath.Join(library, "ssis-200.zh.ass"), sampleASS)
+	writeFile(t, filepath.Join(library, "nested", "[sub] SSIS-200.srt"), "1\n00:00:01,000 --> 00:00:02,000\nhi\n")
+	writeFile(t, filepath.Join(library, "SSIS-201.srt"), "other")
+	content := filepath.Join(root, "library", "SSIS-200")
+	writeFile(t, filepath.Join(content, "SSIS-200.mp4"), "video")
+	writeFile(t, filepath.Join(content, "sample.mp4"), "sample")
+	writeFile(t, filepath.Join(content, "SSIS-200-B.mkv"), "video")
+	writeFile(t, filepath.Join(content, "SSIS-200-B.ja.srt"), "existing")
+
+	service := New(func() Settings {
+		return Settings{Enabled: true, Providers: []Provider{NewLibraryProvider("library", []string{library, filepath.Join(root, "missing")}, "ja")}}
+	})
+	if !service.Enabled() {
+		t.Fatal("expected service to be enabled")
+	}
+	installed, err := service.Subtitle(context.Background(), "ssis-200", content)
+	if err != nil {
+		t.Fatalf("Subtitle: %v", err)
+	}
+	if installed != 2 {
+		t.Fatalf("expected 2 captions, got %d", installed)
+	}
+	converted, err := os.ReadFile(filepath.Join(content, "SSIS-200.zh.srt"))
+	if err != nil {
+		t.Fatalf("expected converted zh caption: %v", err)
+	}
+	want := "1\n00:00:01,000 --> 00:00:02,250\nfirst, line\nwrapped\n\n2\n00:00:05,500 --> 00:00:07,000\nsecond\n\n"
+	if string(converted) != want {
+		t.Fatalf("unexpected conversion:\n%q", converted)
+	}
+	if _, err := os.Stat(filepath.Join(content, "SSIS-200.ja.srt")); err != nil {
+		t.Fatalf("expected fallback-language caption: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(content, "sample.ja.srt")); !os.IsNotExist(err) {
+		t.Fatalf("sample video should be skipped, got %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(content, "SSIS-200-B.*.srt")); len(matches) != 1 {
+		t.Fatalf("video with a caption should be left alone, got %v", matches)
+	}
+}
+
+func TestCommandProviderFallsBackAndReportsFailures(t *testing.T) {
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+	video := filepath.Join(t.TempDir(), "ABP-100.mp4")
+	writeFile(t, video, "video")
+	failing := NewCommandProvider("broken", shell, []string{"-c", "echo 'model not found' >&2; exit 1"}, 5*time.Second, "")
+	whisper := NewCommandProvider("whisper", shell, []string{"-c", `printf '1\n00:00:00,000 --> 00:00:01,000\n%s\n' "$1" > "$2.srt"`, "sh", "{code}", "{output}"}, 5*time.Second, "ja")
+
+	service := New(func() Settings {
+		return Settings{Enabled: true, Providers: []Provider{NewLibraryProvider("library", []string{t.TempDir()}, ""), failing, whisper}}
+	})
+	installed, err := service.Subtitle(context.Background(), "ABP-100", video)
+	if err != nil || installed != 1 {
+		t.Fatalf("expected whisper caption after failures, installed %d err %v", installed, err)
+	}
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(video), "ABP-100.ja.srt"))
+	if err != nil || !strings.Contains(string(data), "ABP-100") {
+		t.Fatalf("unexpected generated caption %q err %v", data, err)
+	}
+
+	other := filepath.Join(t.TempDir(), "ABP-101.mp4")
+	writeFile(t, other, "video")
+	service = New(func() Settings { return Settings{Enabled: true, Providers: []Provider{failing}} })
+	if _, err := service.Subtitle(context.Background(), "ABP-101", other); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Fatalf("expected provider failure to surface, got %v", err)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/pkg/jackett"
 )
 
 // Service is Moji's application-layer task creation seam. Callers hand it
@@ -50,8 +52,9 @@ type CreateFromSearchCodeInput struct {
 }
 
 type CreateFromDiscoveredSceneInput struct {
-	Code  string
-	Title string
+	Code   string
+	Title  string
+	Origin taskruntime.TaskOrigin
 }
 
 type CreateFromDiscoveredSceneRefInput struct {
@@ -60,8 +63,12 @@ type CreateFromDiscoveredSceneRefInput struct {
 }
 
 type CreateFromSubscriptionReleaseInput struct {
-	Code  string
-	Title string
+	Code   string
+	Title  string
+	Origin taskruntime.TaskOrigin
+	// Results, when set, are the candidates to select from instead of a
+	// Jackett search, e.g. items matched in a Torznab feed.
+	Results []jackett.SearchResult
 }
 
 func NewService(taskRuntime TaskRuntime) *Service {
@@ -109,7 +116,7 @@ func (s *Service) CreateFromSearchCode(ctx context.Context, input CreateFromSear
 }
 
 func (s *Service) CreateFromDiscoveredScene(ctx context.Context, input CreateFromDiscoveredSceneInput) (*taskruntime.Task, error) {
-	return s.createFromCode(ctx, taskruntime.TaskSourceSearch, input.Code, input.Title)
+	return s.createFromCode(ctx, taskruntime.TaskSourceSearch, input.Code, input.Title, input.Origin, nil)
 }
 
 func (s *Service) CreateFromDiscoveredSceneRef(ctx context.Context, input CreateFromDiscoveredSceneRefInput) (*taskruntime.Task, error) {
@@ -127,6 +134,10 @@ func (s *Service) CreateFromDiscoveredSceneRef(ctx context.Context, input Create
 	return s.CreateFromDiscoveredScene(ctx, CreateFromDiscoveredSceneInput{
 		Code:  resolved.Code,
 		Title: resolved.Title,
+		Origin: taskruntime.TaskOrigin{
+			StashBoxEndpoint: input.StashBoxEndpoint,
+			StashBoxSceneID:  input.SceneID,
+		},
 	})
 }
 
@@ -139,17 +150,27 @@ func (s *Service) QueueDiscoveredScene(ctx context.Context, sceneID string, stas
 }
 
 func (s *Service) CreateFromSubscriptionRelease(ctx context.Context, input CreateFromSubscriptionReleaseInput) (*taskruntime.Task, error) {
-	return s.createFromCode(ctx, taskruntime.TaskSourceSubscription, input.Code, input.Title)
+	return s.createFromCode(ctx, taskruntime.TaskSourceSubscription, input.Code, input.Title, input.Origin, input.Results)
 }
 
-func (s *Service) QueueSubscriptionRelease(ctx context.Context, code, title string) (*taskruntime.Task, error) {
+func (s *Service) QueueSubscriptionRelease(ctx context.Context, code, title string, origin taskruntime.TaskOrigin) (*taskruntime.Task, error) {
 	return s.CreateFromSubscriptionRelease(ctx, CreateFromSubscriptionReleaseInput{
-		Code:  code,
-		Title: title,
+		Code:   code,
+		Title:  title,
+		Origin: origin,
 	})
 }
 
-func (s *Service) createFromCode(ctx context.Context, source taskruntime.TaskSource, code, title string) (*taskruntime.Task, error) {
+func (s *Service) QueueSubscriptionReleaseFromResults(ctx context.Context, code, title string, origin taskruntime.TaskOrigin, results []jackett.SearchResult) (*taskruntime.Task, error) {
+	return s.CreateFromSubscriptionRelease(ctx, CreateFromSubscriptionReleaseInput{
+		Code:    code,
+		Title:   title,
+		Origin:  origin,
+		Results: results,
+	})
+}
+
+func (s *Service) createFromCode(ctx context.Context, source taskruntime.TaskSource, code, title string, origin taskruntime.TaskOrigin, results []jackett.SearchResult) (*taskruntime.Task, error) {
 	if s == nil || s.taskRuntime == nil {
 		return nil, errors.New("taskflow: task runtime is not configured")
 	}
@@ -160,12 +181,23 @@ func (s *Service) createFromCode(ctx context.Context, source taskruntime.TaskSou
 	}
 
 	task, err := s.taskRuntime.DownloadMediaContext(ctx, taskruntime.DownloadRequest{
-		Source: source,
-		Code:   resolvedCode,
+		Source:  source,
+		Code:    resolvedCode,
+		Origin:  origin,
+		Results: results,
 	})
 	return task, err
 }
 
-func buildReleaseCode(code, _ string) string {
-	return strings.TrimSpace(code)
+// buildReleaseCode normalises the scene code, keeping it as is when its
+// format is unrecognised. Only a scene without a code falls back to a code
+// found in the title.
+func buildReleaseCode(code, title string) string {
+	if code = strings.TrimSpace(code); code != "" {
+		if resolved := codeparser.Normalize(code); resolved != "" {
+			return resolved
+		}
+		return code
+	}
+	return codeparser.Normalize(title)
 }
@@ -152,6 +152,9 @@ func TestCreateFromDiscoveredSceneRefUsesResolver(t *testing.T) {
 	if dl.downloadRequest.Source != taskruntime.TaskSourceSearch || dl.downloadRequest.Code != "ABCD-321" {
 		t.Fatalf("unexpected request: %+v", dl.downloadRequest)
 	}
+	if dl.downloadRequest.Origin.StashBoxSceneID != "scene-1" || dl.downloadRequest.Origin.StashBoxEndpoint != "https://box.example/graphql" {
+		t.Fatalf("unexpected origin: %+v", dl.downloadRequest.Origin)
+	}
 }
 
 func TestCreateFromSubscriptionReleaseUsesCode(t *testing.T) {
@@ -175,6 +178,20 @@ func TestCreateFromSubscriptionReleaseUsesCode(t *testing.T) {
 	}
 }
 
+func TestBuildReleaseCodePrefersSceneCode(t *testing.T) {
+	for _, test := range []struct {
+		code, title, want string
+	}{
+		{code: "ssis001", title: "ABP-123 remaster", want: "SSIS-001"},
+		{code: "Studio Special 7", title: "ABP-123 remaster", want: "Studio Special 7"},
+		{code: "", title: "[FHD] ABP-123 remaster", want: "ABP-123"},
+	} {
+		if got := buildReleaseCode(test.code, test.title); got != test.want {
+			t.Errorf("buildReleaseCode(%q, %q) = %q, want %q", test.code, test.title, got, test.want)
+		}
+	}
+}
+
 func TestCreateFromSubscriptionReleaseRejectsMissingCode(t *testing.T) {
 	service := NewService(&fakeTaskRuntime{})
 
@@ -0,0 +1,179 @@
+package taskhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/command"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+// Hook is a local command run when a task leaves one of AfterStages.
+type Hook struct {
+	Name           string
+	Command        string
+	Args           []string
+	AfterStages    []taskruntime.TaskStage
+	Timeout        time.Duration
+	BlockOnFailure bool
+}
+
+type ConfigProvider func() []Hook
+
+// stageOrder is the forward pipeline order. A single persisted update can
+// skip stages (PENDING_INGEST straight to SCANNING), so hooks fire for every
+// stage the task passed through, not only the one it was persisted in.
+var stageOrder = []taskruntime.TaskStage{
+	taskruntime.TaskStageSourcing,
+	taskruntime.TaskStageDownloading,
+	taskruntime.TaskStagePendingIngest,
+	taskruntime.TaskStageTransferring,
+	taskruntime.TaskStageSubtitling,
+	taskruntime.TaskStageScanning,
+	taskruntime.TaskStageCompleted,
+}
+
+type Runner struct {
+	hooks ConfigProvider
+}
+
+func New(provider ConfigProvider) *Runner {
+	if provider == nil {
+		provider = func() []Hook { return nil }
+	}
+	return &Runner{hooks: provider}
+}
+
+// Enabled implements taskruntime.StageTransitionHook.
+func (r *Runner) Enabled() bool {
+	return r != nil && len(r.hooks()) > 0
+}
+
+// BeforeStageTransition implements taskruntime.StageTransitionHook. It runs
+// the hooks that block on failure and stops at the first one that fails,
+// returning the stage that hook guards.
+func (r *Runner) BeforeStageTransition(ctx context.Context, previous *taskruntime.Task, next *taskruntime.Task) (taskruntime.TaskStage, error) {
+	for _, match := range r.matching(previous, next, true) {
+		if err := run(ctx, match.hook, match.stage, previous, next); err != nil {
+			return match.stage, err
+		}
+	}
+	return "", nil
+}
+
+// AfterStageTransition implements taskruntime.StageTransitionHook. It runs
+// the remaining hooks in order and only logs their failures.
+func (r *Runner) AfterStageTransition(ctx context.Context, previous *taskruntime.Task, next *taskruntime.Task) {
+	for _, match := range r.matching(previous, next, false) {
+		if err := run(ctx, match.hook, match.stage, previous, next); err != nil {
+			logging.Warnf("task hooks: %v task_id=%s", err, next.ID)
+		}
+	}
+}
+
+type hookMatch struct {
+	hook  Hook
+	stage taskruntime.TaskStage
+}
+
+// matching lists the hooks with the given BlockOnFailure that fire for the
+// transition, with the completed stage each one fires after.
+func (r *Runner) matching(previous *taskruntime.Task, next *taskruntime.Task, blocking bool) []hookMatch {
+	if r == nil || previous == nil || next == nil {
+		return nil
+	}
+	completed := completedStages(previous.Stage, next.Stage)
+	if len(completed) == 0 {
+		return nil
+	}
+	var out []hookMatch
+	for _, hook := range r.hooks() {
+		if hook.BlockOnFailure != blocking {
+			continue
+		}
+		if stage, ok := matchingStage(hook, completed); ok {
+			out = append(out, hookMatch{hook: hook, stage: stage})
+		}
+	}
+	return out
+}
+
+func completedStages(previous, next taskruntime.TaskStage) []taskruntime.TaskStage {
+	from, to := stageIndex(previous), stageIndex(next)
+	if from < 0 || to < 0 || from == to {
+		return nil
+	}
+	if to < from {
+		return []taskruntime.TaskStage{previous}
+	}
+	return stageOrder[from:to]
+}
+
+func stageIndex(stage taskruntime.TaskStage) int {
+	for index, candidate := range stageOrder {
+		if candidate == stage {
+			return index
+		}
+	}
+	return -1
+}
+
+func matchingStage(hook Hook, completed []taskruntime.TaskStage) (taskruntime.TaskStage, bool) {
+	for _, stage := range completed {
+		for _, after := range hook.AfterStages {
+			if after == stage {
+				return stage, true
+			}
+		}
+	}
+	return "", false
+}
+
+func run(ctx context.Context, hook Hook, stage taskruntime.TaskStage, previous *taskruntime.Task, next *taskruntime.Task) error {
+	if strings.TrimSpace(hook.Command) == "" {
+		return nil
+	}
+	payload, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("hook %q: encode task: %w", hook.Name, err)
+	}
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	started := time.Now()
+	err = command.Run(ctx, timeout, hook.Command, hook.Args, command.Options{
+		Stdin: bytes.NewReader(payload),
+		Env:   append(os.Environ(), environment(hook, stage, previous, next)...),
+	})
+	if err != nil {
+		return fmt.Errorf("hook %q: %w", hook.Name, err)
+	}
+	logging.Infof("task hooks: hook %q finished task_id=%s after_stage=%s duration=%s", hook.Name, next.ID, stage, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+func environment(hook Hook, stage taskruntime.TaskStage, previous *taskruntime.Task, next *taskruntime.Task) []string {
+	libraryPath := next.StashScanPath
+	if libraryPath == "" {
+		libraryPath = next.MojiTransferPath
+	}
+	return []string{
+		"MOJI_HOOK_NAME=" + hook.Name,
+		"MOJI_TASK_ID=" + next.ID,
+		"MOJI_CODE=" + next.Code,
+		"MOJI_STAGE=" + string(next.Stage),
+		"MOJI_PREVIOUS_STAGE=" + string(previous.Stage),
+		"MOJI_COMPLETED_STAGE=" + string(stage),
+		"MOJI_CONTENT_PATH=" + next.ContentPath,
+		"MOJI_LIBRARY_PATH=" + libraryPath,
+		"MOJI_TORRENT_HASH=" + next.TorrentHash,
+		"MOJI_TORRENT_NAME=" + next.TorrentName,
+	}
+}
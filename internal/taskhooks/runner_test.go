@@ -0,0 +1,117 @@
+package taskhooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+func requireShell(t *testing.T) string {
+	t.Helper()
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+	return shell
+}
+
+func TestRunnerPassesTaskOnStdinAndEnvironment(t *testing.T) {
+	shell := requireShell(t)
+	output := filepath.Join(t.TempDir(), "out.txt")
+	runner := New(func() []Hook {
+		return []Hook{{
+			Name:           "record",
+			Command:        shell,
+			Args:           []string{"-c", `{ echo "$MOJI_CODE|$MOJI_COMPLETED_STAGE|$MOJI_STAGE|$MOJI_CONTENT_PATH|$MOJI_LIBRARY_PATH"; cat; } > "$0"`, output},
+			AfterStages:    []taskruntime.TaskStage{taskruntime.TaskStageTransferring},
+			Timeout:        5 * time.Second,
+			BlockOnFailure: true,
+		}}
+	})
+	previous := &taskruntime.Task{ID: "task-1", Code: "ABC-123", Stage: taskruntime.TaskStagePendingIngest}
+	next := &taskruntime.Task{ID: "task-1", Code: "ABC-123", Stage: taskruntime.TaskStageScanning, ContentPath: "/downloads/ABC-123.mp4", StashScanPath: "/library/ABC-123.mp4"}
+	if _, err := runner.BeforeStageTransition(context.Background(), previous, next); err != nil {
+		t.Fatalf("BeforeStageTransition: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read hook output: %v", err)
+	}
+	lines := strings.SplitN(string(data), "\n", 2)
+	if lines[0] != "ABC-123|TRANSFERRING|SCANNING|/downloads/ABC-123.mp4|/library/ABC-123.mp4" {
+		t.Fatalf("unexpected hook environment: %q", lines[0])
+	}
+	if len(lines) < 2 || !strings.Contains(lines[1], `"Code":"ABC-123"`) {
+		t.Fatalf("expected task JSON on stdin, got %q", data)
+	}
+}
+
+func TestRunnerReturnsStderrForBlockingHookFailure(t *testing.T) {
+	shell := requireShell(t)
+	runner := New(func() []Hook {
+		return []Hook{
+			{Name: "unrelated", Command: shell, Args: []string{"-c", "exit 3"}, AfterStages: []taskruntime.TaskStage{taskruntime.TaskStageScanning}, BlockOnFailure: true},
+			{Name: "verify", Command: shell, Args: []string{"-c", "echo 'checksum mismatch' >&2; exit 1"}, AfterStages: []taskruntime.TaskStage{taskruntime.TaskStageDownloading}, BlockOnFailure: true},
+		}
+	})
+	stage, err := runner.BeforeStageTransition(context.Background(),
+		&taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStageDownloading},
+		&taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStagePendingIngest},
+	)
+	if err == nil || err.Error() != `hook "verify": checksum mismatch` {
+		t.Fatalf("expected stderr from failing hook, got %v", err)
+	}
+	if stage != taskruntime.TaskStageDownloading {
+		t.Fatalf("expected the failure to name the guarded stage, got %q", stage)
+	}
+}
+
+func TestRunnerTimesOutBlockingHook(t *testing.T) {
+	shell := requireShell(t)
+	runner := New(func() []Hook {
+		return []Hook{{Name: "slow", Command: shell, Args: []string{"-c", "exec sleep 5"}, AfterStages: []taskruntime.TaskStage{taskruntime.TaskStageScanning}, Timeout: 50 * time.Millisecond, BlockOnFailure: true}}
+	})
+	_, err := runner.BeforeStageTransition(context.Background(),
+		&taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStageScanning},
+		&taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStageCompleted},
+	)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRunnerLeavesNonBlockingHooksForAfterTransition(t *testing.T) {
+	shell := requireShell(t)
+	output := filepath.Join(t.TempDir(), "out.txt")
+	runner := New(func() []Hook {
+		return []Hook{{Name: "notify", Command: shell, Args: []string{"-c", `echo "$MOJI_COMPLETED_STAGE" > "$0"`, output}, AfterStages: []taskruntime.TaskStage{taskruntime.TaskStageDownloading}}}
+	})
+	previous := &taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStageDownloading}
+	next := &taskruntime.Task{ID: "task-1", Stage: taskruntime.TaskStagePendingIngest}
+	if _, err := runner.BeforeStageTransition(context.Background(), previous, next); err != nil {
+		t.Fatalf("BeforeStageTransition: %v", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Fatalf("expected the non-blocking hook not to run before the transition, stat err=%v", err)
+	}
+	runner.AfterStageTransition(context.Background(), previous, next)
+	if data, err := os.ReadFile(output); err != nil || strings.TrimSpace(string(data)) != "DOWNLOADING" {
+		t.Fatalf("expected the non-blocking hook to run after the transition, got %q err=%v", data, err)
+	}
+}
+
+func TestCompletedStagesIncludesSkippedStages(t *testing.T) {
+	got := completedStages(taskruntime.TaskStagePendingIngest, taskruntime.TaskStageScanning)
+	if len(got) != 3 || got[0] != taskruntime.TaskStagePendingIngest || got[1] != taskruntime.TaskStageTransferring || got[2] != taskruntime.TaskStageSubtitling {
+		t.Fatalf("unexpected completed stages: %v", got)
+	}
+	if got := completedStages(taskruntime.TaskStageScanning, taskruntime.TaskStageSourcing); len(got) != 1 || got[0] != taskruntime.TaskStageScanning {
+		t.Fatalf("unexpected completed stages for reset: %v", got)
+	}
+}
@@ -0,0 +1,265 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/pkg/qbittorrent"
+)
+
+const MaxTorrentAdoptionBatchSize = 500
+
+var ErrTorrentAdoptionFilterRequired = errors.New("torrent adoption requires a category, tag, save path or hash filter")
+
+const (
+	TorrentCodeSourceName  = "NAME"
+	TorrentCodeSourceFiles = "FILES"
+)
+
+const (
+	TaskBatchReasonAdopted        = "ADOPTED"
+	TaskBatchReasonAlreadyTracked = "ALREADY_TRACKED"
+	TaskBatchReasonNoCode         = "NO_CODE"
+	TaskBatchReasonDuplicateCode  = "DUPLICATE_CODE"
+	TaskBatchReasonInLibrary      = "IN_LIBRARY"
+	TaskBatchReasonAdoptFailed    = "ADOPT_FAILED"
+)
+
+// TorrentContentLister reads a torrent's file list so codes can be found in
+// file names when the torrent name has none.
+type TorrentContentLister interface {
+	GetTorrentContents(ctx context.Context, hash string, indexes []string) ([]qbittorrent.TorrentContentFile, error)
+}
+
+func WithTorrentContentLister(lister TorrentContentLister) Option {
+	return func(s *Service) {
+		if lister != nil {
+			s.contentLister = lister
+		}
+	}
+}
+
+// TorrentAdoptionFilter selects existing qBittorrent torrents. SavePath
+// matches the torrent's save path or any directory below it.
+type TorrentAdoptionFilter struct {
+	Category string
+	Tag      string
+	SavePath string
+	Hashes   []string
+}
+
+// TorrentAdoptionCandidate previews how a torrent would be adopted. TaskID
+// is set when a task already owns the torrent.
+type TorrentAdoptionCandidate struct {
+	Hash       string
+	Name       string
+	Category   string
+	Tags       string
+	SavePath   string
+	Progress   float64
+	Code       string
+	CodeSource string
+	Stage      TaskStage
+	TaskID     string
+}
+
+type TorrentAdoptionRequest struct {
+	Filter TorrentAdoptionFilter
+	// Codes overrides the extracted code per torrent hash.
+	Codes map[string]string
+}
+
+type TorrentAdoptionResult struct {
+	Hash       string
+	Name       string
+	Code       string
+	Status     TaskBatchStatus
+	ReasonCode string
+	Message    string
+	Task       *Task
+}
+
+type TorrentAdoptionPayload struct {
+	Summary TaskBatchSummary
+	Results []TorrentAdoptionResult
+}
+
+// PreviewTorrentAdoption lists the torrents matching filter with the code
+// and stage each would be adopted with.
+func (s *Service) PreviewTorrentAdoption(ctx context.Context, filter TorrentAdoptionFilter) ([]TorrentAdoptionCandidate, error) {
+	torrents, err := s.listAdoptionTorrents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]TorrentAdoptionCandidate, 0, len(torrents))
+	for _, torrent := range torrents {
+		code, source := s.torrentCode(ctx, torrent)
+		probe := &Task{}
+		applyTorrentProgress(probe, torrent, s.now().UTC())
+		candidate := TorrentAdoptionCandidate{
+			Hash:       torrent.Hash,
+			Name:       torrent.Name,
+			Category:   torrent.Category,
+			Tags:       torrent.Tags,
+			SavePath:   torrent.SavePath,
+			Progress:   torrent.Progress,
+			Code:       code,
+			CodeSource: source,
+			Stage:      probe.Stage,
+		}
+		if owner := taskOwningTorrent(tasks, torrent); owner != nil {
+			candidate.TaskID = owner.ID
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// AdoptTorrents creates tasks for the torrents matching the filter. Each task
+// takes the torrent's identity and starts in DOWNLOADING or PENDING_INGEST
+// according to its progress, so ingest and Stash scan continue normally.
+func (s *Service) AdoptTorrents(ctx context.Context, req TorrentAdoptionRequest) (TorrentAdoptionPayload, error) {
+	torrents, err := s.listAdoptionTorrents(ctx, req.Filter)
+	if err != nil {
+		return TorrentAdoptionPayload{}, err
+	}
+	if len(torrents) > MaxTorrentAdoptionBatchSize {
+		return TorrentAdoptionPayload{}, fmt.Errorf("%w: %d torrents match, limit is %d", ErrTaskBatchTooLarge, len(torrents), MaxTorrentAdoptionBatchSize)
+	}
+	overrides := make(map[string]string, len(req.Codes))
+	for hash, code := range req.Codes {
+		overrides[strings.ToUpper(strings.TrimSpace(hash))] = code
+	}
+
+	payload := TorrentAdoptionPayload{
+		Summary: TaskBatchSummary{RequestedCount: len(torrents)},
+		Results: make([]TorrentAdoptionResult, 0, len(torrents)),
+	}
+	for _, torrent := range torrents {
+		result := s.adoptTorrent(ctx, torrent, overrides[strings.ToUpper(torrent.Hash)])
+		switch result.Status {
+		case TaskBatchStatusSucceeded:
+			payload.Summary.SucceededCount++
+		case TaskBatchStatusSkipped:
+			payload.Summary.SkippedCount++
+		default:
+			payload.Summary.FailedCount++
+		}
+		payload.Results = append(payload.Results, result)
+	}
+	logging.Infof("taskruntime: adopted %d of %d torrents (%d skipped, %d failed)", payload.Summary.SucceededCount, payload.Summary.RequestedCount, payload.Summary.SkippedCount, payload.Summary.FailedCount)
+	return payload, nil
+}
+
+func (s *Service) adoptTorrent(ctx context.Context, torrent qbittorrent.Torrent, override string) TorrentAdoptionResult {
+	result := TorrentAdoptionResult{Hash: torrent.Hash, Name: torrent.Name}
+	code := extractCode(override)
+	if code == "" {
+		code, _ = s.torrentCode(ctx, torrent)
+	}
+	result.Code = code
+	if code == "" {
+		result.Status, result.ReasonCode = TaskBatchStatusSkipped, TaskBatchReasonNoCode
+		return result
+	}
+	task, err := s.createTaskFromTorrent(ctx, torrent, code)
+	switch {
+	case err == nil:
+		result.Status, result.ReasonCode, result.Task = TaskBatchStatusSucceeded, TaskBatchReasonAdopted, task
+	case errors.Is(err, ErrDuplicateTorrentTask):
+		result.Status, result.ReasonCode = TaskBatchStatusSkipped, TaskBatchReasonAlreadyTracked
+	case errors.Is(err, ErrDuplicateCodeTask):
+		result.Status, result.ReasonCode = TaskBatchStatusSkipped, TaskBatchReasonDuplicateCode
+	case errors.Is(err, ErrDuplicateLibraryCode):
+		result.Status, result.ReasonCode = TaskBatchStatusSkipped, TaskBatchReasonInLibrary
+	default:
+		result.Status, result.ReasonCode = TaskBatchStatusFailed, TaskBatchReasonAdoptFailed
+		logging.Warnf("taskruntime: adopt torrent %s failed: %v", torrent.Hash, err)
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result
+}
+
+func (s *Service) listAdoptionTorrents(ctx context.Context, filter TorrentAdoptionFilter) ([]qbittorrent.Torrent, error) {
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.Tag = strings.TrimSpace(filter.Tag)
+	filter.SavePath = strings.TrimSpace(filter.SavePath)
+	hashes := make([]string, 0, len(filter.Hashes))
+	for _, hash := range filter.Hashes {
+		if hash = strings.TrimSpace(hash); hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	if filter.Category == "" && filter.Tag == "" && filter.SavePath == "" && len(hashes) == 0 {
+		return nil, ErrTorrentAdoptionFilterRequired
+	}
+	torrents, err := s.qbt.GetTorrentList(ctx, &qbittorrent.TorrentListOptions{
+		Category: filter.Category,
+		Tag:      filter.Tag,
+		Hashes:   hashes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list torrents: %w", err)
+	}
+	if filter.SavePath == "" {
+		return torrents, nil
+	}
+	root := cleanSavePath(filter.SavePath)
+	matched := make([]qbittorrent.Torrent, 0, len(torrents))
+	for _, torrent := range torrents {
+		if _, err := relativePathWithin(root, cleanSavePath(torrent.SavePath)); err == nil {
+			matched = append(matched, torrent)
+		}
+	}
+	return matched, nil
+}
+
+// torrentCode extracts a code from the torrent name, falling back to its
+// video file names, largest first.
+func (s *Service) torrentCode(ctx context.Context, torrent qbittorrent.Torrent) (string, string) {
+	if code := extractCode(torrent.Name); code != "" {
+		return code, TorrentCodeSourceName
+	}
+	if s.contentLister == nil {
+		return "", ""
+	}
+	files, err := s.contentLister.GetTorrentContents(ctx, torrent.Hash, nil)
+	if err != nil {
+		logging.Warnf("taskruntime: list files of torrent %s: %v", torrent.Hash, err)
+		return "", ""
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if isVideoFilePath(file.Name) {
+			names = append(names, path.Base(file.Name))
+		}
+	}
+	if code := extractCode(names...); code != "" {
+		return code, TorrentCodeSourceFiles
+	}
+	return "", ""
+}
+
+func taskOwningTorrent(tasks []*Task, torrent qbittorrent.Torrent) *Task {
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		if matched, ok := matchTaskTorrent(task, []qbittorrent.Torrent{torrent}); ok && strings.EqualFold(matched.Hash, torrent.Hash) {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leothevan2444/moji/pkg/qbittorrent"
+)
+
+type fakeContentLister map[string][]qbittorrent.TorrentContentFile
+
+func (f fakeContentLister) GetTorrentContents(_ context.Context, hash string, _ []string) ([]qbittorrent.TorrentContentFile, error) {
+	return f[hash], nil
+}
+
+func TestAdoptTorrentsCreatesTasksInMatchingStage(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	if err := store.Create(ctx, &Task{ID: "existing", Code: "MIDV-001", Stage: TaskStageDownloading, StageStatus: TaskStageStatusRunning, TorrentHash: "aaaa", TorrentIdentityHash: "AAAA"}); err != nil {
+		t.Fatal(err)
+	}
+	qbt := &fakeTorrentAdder{torrents: []qbittorrent.Torrent{
+		{Hash: "aaaa", Name: "MIDV-001", SavePath: "/downloads/jav"},
+		{Hash: "bbbb", Name: "SSIS-200 4K", SavePath: "/downloads/jav", Progress: 0.4, State: qbittorrent.TorrentStateDownloading},
+		{Hash: "cccc", Name: "collection", SavePath: "/downloads/jav/old", Progress: 1, State: qbittorrent.TorrentStatePausedUP, ContentPath: "/downloads/jav/old/collection"},
+		{Hash: "dddd", Name: "holiday photos", SavePath: "/downloads/jav"},
+		{Hash: "eeee", Name: "SSIS-200 1080p", SavePath: "/downloads/jav"},
+		{Hash: "ffff", Name: "ABP-999", SavePath: "/downloads/other"},
+	}}
+	lister := fakeContentLister{"cccc": {
+		{Name: "collection/sample/sample.mp4", Size: 10 << 20},
+		{Name: "collection/cover.jpg", Size: 1 << 20},
+		{Name: "collection/ipx-123.mp4", Size: 4 << 30},
+	}}
+	service, err := NewService(fakeTracker{}, qbt, store, WithTorrentContentLister(lister))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.AdoptTorrents(ctx, TorrentAdoptionRequest{}); !errors.Is(err, ErrTorrentAdoptionFilterRequired) {
+		t.Fatalf("expected filter to be required, got %v", err)
+	}
+
+	filter := TorrentAdoptionFilter{SavePath: "/downloads/jav/"}
+	preview, err := service.PreviewTorrentAdoption(ctx, filter)
+	if err != nil {
+		t.Fatalf("PreviewTorrentAdoption: %v", err)
+	}
+	if len(preview) != 5 || preview[0].TaskID != "existing" || preview[2].Code != "IPX-123" || preview[2].CodeSource != TorrentCodeSourceFiles || preview[2].Stage != TaskStagePendingIngest {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	payload, err := service.AdoptTorrents(ctx, TorrentAdoptionRequest{Filter: filter, Codes: map[string]string{"DDDD": "abf-010"}})
+	if err != nil {
+		t.Fatalf("AdoptTorrents: %v", err)
+	}
+	want := map[string]string{
+		"aaaa": TaskBatchReasonAlreadyTracked,
+		"bbbb": TaskBatchReasonAdopted,
+		"cccc": TaskBatchReasonAdopted,
+		"dddd": TaskBatchReasonAdopted,
+		"eeee": TaskBatchReasonDuplicateCode,
+	}
+	if payload.Summary.RequestedCount != 5 || payload.Summary.SucceededCount != 3 || payload.Summary.SkippedCount != 2 {
+		t.Fatalf("unexpected summary: %+v", payload.Summary)
+	}
+	for _, result := range payload.Results {
+		if result.ReasonCode != want[result.Hash] {
+			t.Fatalf("torrent %s: reason %s, want %s", result.Hash, result.ReasonCode, want[result.Hash])
+		}
+	}
+
+	adopted := payload.Results[2].Task
+	if adopted.Code != "IPX-123" || adopted.Stage != TaskStagePendingIngest || adopted.ContentPath != "/downloads/jav/old/collection" || adopted.TorrentIdentityHash != "CCCC" || adopted.DownloadCompletedAt == nil {
+		t.Fatalf("unexpected adopted task: %+v", adopted)
+	}
+	if task := payload.Results[1].Task; task.Stage != TaskStageDownloading || task.StageStatus != TaskStageStatusRunning {
+		t.Fatalf("expected in-progress torrent to be downloading, got %+v", task)
+	}
+	if task := payload.Results[3].Task; task.Code != "ABF-010" {
+		t.Fatalf("expected code override, got %+v", task)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"unicode"
 
 	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
 	"github.com/leothevan2444/moji/internal/logging"
 	"github.com/leothevan2444/moji/pkg/jackett"
 )
@@ -22,7 +23,7 @@ type torrentInspection struct {
 	SingleVideo bool
 }
 
-type torrentInspector func(ctx context.Context, torrentURL string) (torrentInspection, error)
+type torrentInspector func(ctx context.Context, torrentURL string, infoHash string) (torrentInspection, error)
 
 type defaultCandidateSelector struct {
 	inspectTorrent torrentInspector
@@ -135,59 +136,26 @@ type inspectedCandidate struct {
 	ok         bool
 }
 
-func (s *Service) inspectSearchResultTorrent(ctx context.Context, torrentURL string) (torrentInspection, error) {
-	if inspection, ok := s.cachedTorrentInspection(torrentURL); ok {
-		return inspection, nil
+func (s *Service) inspectSearchResultTorrent(ctx context.Context, torrentURL string, infoHash string) (torrentInspection, error) {
+	if s.inspectionCache != nil {
+		if entry, ok := s.inspectionCache.Lookup(ctx, torrentURL, normalizeInfoHash(infoHash)); ok {
+			return inspectTorrentMetadata(downloadedTorrentMetadata{Name: entry.Name, InfoHash: entry.InfoHash, Paths: entry.Paths}), nil
+		}
 	}
 	metadata, err := s.fetchTorrentMetadata(ctx, torrentURL)
 	if err != nil {
 		return torrentInspection{}, err
 	}
-	inspection := inspectTorrentMetadata(metadata)
-	s.storeCachedTorrentInspection(torrentURL, inspection)
-	return inspection, nil
-}
-
-func (s *Service) cachedTorrentInspection(torrentURL string) (torrentInspection, bool) {
-	key := normalizeTorrentInspectionCacheKey(torrentURL, "")
-	if key == "" {
-		return torrentInspection{}, false
-	}
-	now := s.now().UTC()
-	s.inspectionCacheMu.Lock()
-	defer s.inspectionCacheMu.Unlock()
-	entry, ok := s.inspectionCache[key]
-	if !ok {
-		return torrentInspection{}, false
-	}
-	if !entry.expiresAt.After(now) {
-		delete(s.inspectionCache, key)
-		return torrentInspection{}, false
-	}
-	return entry.inspection, true
-}
-
-func (s *Service) storeCachedTorrentInspection(torrentURL string, inspection torrentInspection) {
-	key := normalizeTorrentInspectionCacheKey(torrentURL, "")
-	if key == "" {
-		return
-	}
-	s.inspectionCacheMu.Lock()
-	defer s.inspectionCacheMu.Unlock()
-	s.inspectionCache[key] = cachedTorrentInspection{
-		inspection: inspection,
-		expiresAt:  s.now().UTC().Add(torrentInspectionCacheTTL),
-	}
-}
-
-func normalizeTorrentInspectionCacheKey(torrentURL string, infoHash string) string {
-	if trimmed := strings.TrimSpace(infoHash); trimmed != "" {
-		return "hash:" + strings.ToUpper(trimmed)
-	}
-	if trimmed := strings.TrimSpace(torrentURL); trimmed != "" {
-		return "url:" + trimmed
+	if s.inspectionCache != nil {
+		entry := inspectioncache.Entry{Name: metadata.Name, InfoHash: metadata.InfoHash, Paths: metadata.Paths}
+		if len(entry.Paths) == 0 && metadata.FilePath != "" {
+			entry.Paths = []string{metadata.FilePath}
+		}
+		if err := s.inspectionCache.Store(ctx, torrentURL, metadata.InfoHash, entry); err != nil {
+			logging.Infof("taskruntime: cache torrent inspection %q failed: %v", torrentURL, err)
+		}
 	}
-	return ""
+	return inspectTorrentMetadata(metadata), nil
 }
 
 func inspectTorrentMetadata(metadata downloadedTorrentMetadata) torrentInspection {
@@ -362,7 +330,7 @@ func inspectTopTorrentCandidates(ctx context.Context, candidates []rankedCandida
 				return
 			}
 			defer func() { <-sem }()
-			inspection, err := inspector(ctx, torrentURL)
+			inspection, err := inspector(ctx, torrentURL, candidate.result.InfoHash)
 			if err != nil {
 				logging.Infof("taskruntime: inspect torrent candidate %q failed: %v", candidate.result.Title, err)
 				return
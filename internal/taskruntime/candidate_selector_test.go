@@ -4,12 +4,14 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
 	"github.com/leothevan2444/moji/pkg/jackett"
 )
 
@@ -115,7 +117,7 @@ func TestDefaultCandidateSelectorUsesTitleSimilarity(t *testing.T) {
 
 func TestDefaultCandidateSelectorUsesTorrentSingleVideoInspection(t *testing.T) {
 	selector := defaultCandidateSelector{
-		inspectTorrent: func(_ context.Context, torrentURL string) (torrentInspection, error) {
+		inspectTorrent: func(_ context.Context, torrentURL string, _ string) (torrentInspection, error) {
 			if strings.Contains(torrentURL, "single") {
 				return torrentInspection{
 					Paths:       []string{"ABCD-123.mp4"},
@@ -146,7 +148,7 @@ func TestDefaultCandidateSelectorUsesTorrentSingleVideoInspection(t *testing.T)
 
 func TestDefaultCandidateSelectorUsesTorrentFileNameLock(t *testing.T) {
 	selector := defaultCandidateSelector{
-		inspectTorrent: func(_ context.Context, torrentURL string) (torrentInspection, error) {
+		inspectTorrent: func(_ context.Context, torrentURL string, _ string) (torrentInspection, error) {
 			if strings.Contains(torrentURL, "locked") {
 				return torrentInspection{
 					Paths: []string{"movie/hhd800.com-ABCD-123.mp4"},
@@ -184,7 +186,7 @@ func TestDefaultCandidateSelectorOnlyInspectsTopFiveTorrentCandidates(t *testing
 	inspected := make([]string, 0)
 	var inspectedMu sync.Mutex
 	selector := defaultCandidateSelector{
-		inspectTorrent: func(_ context.Context, torrentURL string) (torrentInspection, error) {
+		inspectTorrent: func(_ context.Context, torrentURL string, _ string) (torrentInspection, error) {
 			inspectedMu.Lock()
 			inspected = append(inspected, torrentURL)
 			inspectedMu.Unlock()
@@ -223,7 +225,7 @@ func TestDefaultCandidateSelectorUsesConfiguredInspectionCandidateLimit(t *testi
 	inspected := make([]string, 0, len(results))
 	var inspectedMu sync.Mutex
 	selector := defaultCandidateSelector{
-		inspectTorrent: func(_ context.Context, torrentURL string) (torrentInspection, error) {
+		inspectTorrent: func(_ context.Context, torrentURL string, _ string) (torrentInspection, error) {
 			inspectedMu.Lock()
 			inspected = append(inspected, torrentURL)
 			inspectedMu.Unlock()
@@ -276,7 +278,7 @@ func TestDefaultCandidateSelectorPreviewUsesFastRulesOnly(t *testing.T) {
 
 func TestDefaultCandidateSelectorPreviewUsesInputOrderForFileRules(t *testing.T) {
 	selector := defaultCandidateSelector{
-		inspectTorrent: func(_ context.Context, torrentURL string) (torrentInspection, error) {
+		inspectTorrent: func(_ context.Context, torrentURL string, _ string) (torrentInspection, error) {
 			if strings.Contains(torrentURL, "second") {
 				return torrentInspection{Paths: []string{"movie.mp4"}, VideoPaths: []string{"movie.mp4"}, SingleVideo: true}, nil
 			}
@@ -324,6 +326,7 @@ func TestPreviewJackettSelectionContextCachesTorrentInspection(t *testing.T) {
 				{Type: config.CandidateSelectionRuleTypeTorrentSingleVideo, Enabled: true},
 			})
 		}),
+		WithTorrentInspectionCache(newTestInspectionCache(t)),
 	)
 	if err != nil {
 		t.Fatalf("NewService failed: %v", err)
@@ -347,6 +350,16 @@ func TestPreviewJackettSelectionContextCachesTorrentInspection(t *testing.T) {
 	}
 }
 
+func newTestInspectionCache(t *testing.T) *inspectioncache.Service {
+	t.Helper()
+	cache, err := inspectioncache.New(filepath.Join(t.TempDir(), "inspection.db"), nil)
+	if err != nil {
+		t.Fatalf("inspectioncache.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache
+}
+
 func TestDownloadMediaContextUsesConfiguredCandidateSelection(t *testing.T) {
 	qbt := &fakeTorrentAdder{}
 	service, err := NewService(
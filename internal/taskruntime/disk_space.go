@@ -0,0 +1,190 @@
+package taskruntime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+var ErrInsufficientDiskSpace = errors.New("insufficient disk space")
+
+var errSavePathNotLocal = errors.New("save path does not exist on this host")
+
+// DiskSpaceConfig describes where torrents may be saved and how much room
+// must remain afterwards. SaveRoots and DefaultSavePath are qB-side paths;
+// they are translated through Downloads before free space is measured.
+type DiskSpaceConfig struct {
+	Enabled         bool
+	Downloads       stashsync.DownloadsPathConfig
+	DefaultSavePath string
+	SaveRoots       []string
+	Policy          config.SaveRootPolicy
+	ReserveBytes    int64
+}
+
+// FreeSpaceProbe reports the bytes available to unprivileged writers on the
+// filesystem holding path.
+type FreeSpaceProbe func(path string) (uint64, error)
+
+func WithDiskSpaceProvider(provider func() DiskSpaceConfig) Option {
+	return func(s *Service) {
+		if provider != nil {
+			s.diskSpace = provider
+		}
+	}
+}
+
+func WithFreeSpaceProbe(probe FreeSpaceProbe) Option {
+	return func(s *Service) {
+		if probe != nil {
+			s.freeSpace = probe
+		}
+	}
+}
+
+type saveRootSpace struct {
+	qbPath string
+	free   uint64
+}
+
+// chooseSavePath returns the qB save path to submit with. An explicit path
+// outside the configured save roots is only checked; otherwise the roots are
+// ranked by the configured policy. Volumes that cannot be mapped or do not
+// exist on this host are skipped with a one-time warning, and when none can
+// be measured the requested path is used unchecked so a mapping mistake
+// never blocks downloads outright.
+func (s *Service) chooseSavePath(task *Task, requested string) (string, error) {
+	if s.diskSpace == nil {
+		return requested, nil
+	}
+	cfg := s.diskSpace()
+	if !cfg.Enabled {
+		return requested, nil
+	}
+	requested = strings.TrimSpace(requested)
+	candidates := cfg.SaveRoots
+	checkOnly := false
+	switch {
+	case requested != "" && !containsCleanPath(cfg.SaveRoots, requested):
+		candidates, checkOnly = []string{requested}, true
+	case len(candidates) == 0 && cfg.DefaultSavePath != "":
+		candidates, checkOnly = []string{cfg.DefaultSavePath}, true
+	case len(candidates) == 0:
+		return requested, nil
+	}
+
+	needed := uint64(max(cfg.ReserveBytes, 0))
+	if task != nil && task.Candidate.Size > 0 {
+		needed += uint64(task.Candidate.Size)
+	}
+	measured := make([]saveRootSpace, 0, len(candidates))
+	for _, qbPath := range candidates {
+		localPath, err := mapDownloadsPath(cfg.Downloads, qbPath)
+		if err == nil {
+			var free uint64
+			if free, err = s.freeSpace(localPath); err == nil {
+				measured = append(measured, saveRootSpace{qbPath: qbPath, free: free})
+				continue
+			}
+		}
+		s.warnDiskSpaceSkipped(qbPath, err)
+	}
+	if len(measured) == 0 {
+		return requested, nil
+	}
+
+	var chosen *saveRootSpace
+	for index := range measured {
+		root := &measured[index]
+		if root.free < needed {
+			continue
+		}
+		if cfg.Policy == config.SaveRootPolicyFillFirst {
+			chosen = root
+			break
+		}
+		if chosen == nil || root.free > chosen.free {
+			chosen = root
+		}
+	}
+	if chosen == nil {
+		largest := measured[0]
+		for _, root := range measured[1:] {
+			if root.free > largest.free {
+				largest = root
+			}
+		}
+		return "", fmt.Errorf("%w: need %s including reserve, largest free volume %s has %s", ErrInsufficientDiskSpace, formatBytes(needed), largest.qbPath, formatBytes(largest.free))
+	}
+	if checkOnly {
+		return requested, nil
+	}
+	return chosen.qbPath, nil
+}
+
+func mapDownloadsPath(cfg stashsync.DownloadsPathConfig, qbPath string) (string, error) {
+	qbRoot := strings.TrimSpace(cfg.QBRoot)
+	mojiRoot := strings.TrimSpace(cfg.MojiRoot)
+	if qbRoot == "" && mojiRoot == "" {
+		return filepath.Clean(qbPath), nil
+	}
+	if qbRoot == "" || mojiRoot == "" {
+		return "", errors.New("downloads.qb_root and downloads.moji_root must both be set")
+	}
+	relative, err := relativePathWithin(qbRoot, qbPath)
+	if err != nil {
+		return "", err
+	}
+	return joinRootAndRelative(mojiRoot, relative), nil
+}
+
+// warnDiskSpaceSkipped logs once per save path that its free space is not
+// checked, so a missing mapping does not flood the log on every submission.
+func (s *Service) warnDiskSpaceSkipped(qbPath string, err error) {
+	if _, warned := s.diskSpaceWarned.LoadOrStore(filepath.Clean(qbPath), struct{}{}); warned {
+		return
+	}
+	logging.Warnf("taskruntime: skip disk space check for %s: %v", qbPath, err)
+}
+
+// probeFreeSpace measures the filesystem holding path. A path missing on this
+// host is usually a qB path without a downloads mapping, so it is reported
+// instead of measuring whatever unrelated volume holds its ancestors.
+func probeFreeSpace(path string) (uint64, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("%w: %s", errSavePathNotLocal, path)
+		}
+		return 0, err
+	}
+	return statFreeSpace(path)
+}
+
+func containsCleanPath(paths []string, target string) bool {
+	target = filepath.Clean(target)
+	for _, path := range paths {
+		if filepath.Clean(path) == target {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBytes(value uint64) string {
+	const unit = 1024
+	if value < unit {
+		return fmt.Sprintf("%d B", value)
+	}
+	div, exp := uint64(unit), 0
+	for n := value / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(value)/float64(div), "KMGTP"[exp])
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package taskruntime
+
+import "errors"
+
+func statFreeSpace(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
@@ -0,0 +1,95 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/stashsync"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+func newDiskSpaceTestService(t *testing.T, qbt *fakeTorrentAdder, cfg DiskSpaceConfig, free map[string]uint64) *Service {
+	t.Helper()
+	service, err := NewService(
+		fakeTracker{results: []jackett.SearchResult{{Title: "SONE-000", MagnetURI: "magnet:?xt=urn:btih:big", Seeders: 10, Size: 40 << 30}}},
+		qbt,
+		NewMemoryTaskStore(),
+		WithDiskSpaceProvider(func() DiskSpaceConfig { return cfg }),
+		WithFreeSpaceProbe(func(path string) (uint64, error) {
+			value, ok := free[path]
+			if !ok {
+				return 0, errors.New("no such volume")
+			}
+			return value, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	return service
+}
+
+func TestSubmitPicksSaveRootByPolicy(t *testing.T) {
+	free := map[string]uint64{"/mnt/a": 50 << 30, "/mnt/b": 200 << 30, "/mnt/c": 10 << 30}
+	cfg := DiskSpaceConfig{
+		Enabled:      true,
+		Downloads:    stashsync.DownloadsPathConfig{QBRoot: "/downloads", MojiRoot: "/mnt"},
+		SaveRoots:    []string{"/downloads/c", "/downloads/a", "/downloads/b"},
+		Policy:       config.SaveRootPolicyMostFree,
+		ReserveBytes: 5 << 30,
+	}
+	for policy, want := range map[config.SaveRootPolicy]string{
+		config.SaveRootPolicyMostFree:  "/downloads/b",
+		config.SaveRootPolicyFillFirst: "/downloads/a",
+	} {
+		cfg.Policy = policy
+		qbt := &fakeTorrentAdder{}
+		task, err := newDiskSpaceTestService(t, qbt, cfg, free).DownloadMediaContext(context.Background(), DownloadRequest{Code: "SONE-000"})
+		if err != nil {
+			t.Fatalf("%s: DownloadMediaContext failed: %v", policy, err)
+		}
+		if qbt.options.SavePath == nil || *qbt.options.SavePath != want || task.SavePath != want {
+			t.Fatalf("%s: expected save path %q, got options %v task %q", policy, want, qbt.options.SavePath, task.SavePath)
+		}
+	}
+}
+
+func TestSubmitBlocksTaskWhenNoVolumeFits(t *testing.T) {
+	qbt := &fakeTorrentAdder{}
+	cfg := DiskSpaceConfig{Enabled: true, DefaultSavePath: "/downloads", ReserveBytes: 1 << 30}
+	service := newDiskSpaceTestService(t, qbt, cfg, map[string]uint64{"/downloads": 30 << 30})
+
+	task, err := service.DownloadMediaContext(context.Background(), DownloadRequest{Code: "SONE-000"})
+	if !errors.Is(err, ErrInsufficientDiskSpace) {
+		t.Fatalf("expected ErrInsufficientDiskSpace, got %v", err)
+	}
+	if task.Stage != TaskStageDownloading || task.StageStatus != TaskStageStatusBlocked || task.StageErrorCode != TaskStageErrorInsufficientDisk {
+		t.Fatalf("unexpected blocked task: %+v", task)
+	}
+	if qbt.options.URLs != nil {
+		t.Fatalf("torrent should not be submitted, got %+v", qbt.options)
+	}
+}
+
+func TestSubmitSkipsGuardWhenVolumeCannotBeMeasured(t *testing.T) {
+	qbt := &fakeTorrentAdder{}
+	cfg := DiskSpaceConfig{Enabled: true, Downloads: stashsync.DownloadsPathConfig{QBRoot: "/downloads", MojiRoot: "/mnt"}, ReserveBytes: 1 << 30}
+	service := newDiskSpaceTestService(t, qbt, cfg, nil)
+
+	task, err := service.DownloadMediaContext(context.Background(), DownloadRequest{Code: "SONE-000", SavePath: "/elsewhere"})
+	if err != nil {
+		t.Fatalf("DownloadMediaContext failed: %v", err)
+	}
+	if task.StageStatus != TaskStageStatusRunning || qbt.options.SavePath == nil || *qbt.options.SavePath != "/elsewhere" {
+		t.Fatalf("expected unchecked submission, got task %+v options %+v", task, qbt.options)
+	}
+}
+
+func TestProbeFreeSpaceRejectsPathsMissingOnThisHost(t *testing.T) {
+	if _, err := probeFreeSpace(filepath.Join(t.TempDir(), "qb", "downloads")); !errors.Is(err, errSavePathNotLocal) {
+		t.Fatalf("expected errSavePathNotLocal, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package taskruntime
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+// minDurationMismatch keeps short scenes from being flagged for a few
+// seconds of intro or padding.
+const minDurationMismatch = 60 * time.Second
+
+// MediaWarning flags a delivered video that failed a validation check.
+type MediaWarning string
+
+const (
+	MediaWarningDurationMismatch MediaWarning = "DURATION_MISMATCH"
+	MediaWarningLowResolution    MediaWarning = "LOW_RESOLUTION"
+	MediaWarningLowBitRate       MediaWarning = "LOW_BITRATE"
+)
+
+// MediaValidation is what a delivered video is checked against.
+// DurationTolerance is the allowed relative difference from the StashBox
+// duration, e.g. 0.1 for 10%. Zero minimums are not checked; MinHeight is
+// compared with the short side the way Resolution classifies it.
+type MediaValidation struct {
+	DurationTolerance float64
+	MinHeight         int
+	MinBitRate        int64
+}
+
+// MediaProbe is what ffprobe reported for the main video of a delivered
+// task. ExpectedDurationSeconds is the StashBox scene duration when one was
+// found for the task's code; DurationMismatch is set when the two differ by
+// more than the configured tolerance. Warnings lists every failed
+// validation check. Error is set when probing failed.
+type MediaProbe struct {
+	Path                    string
+	Container               string
+	DurationSeconds         float64
+	Width                   int
+	Height                  int
+	VideoCodec              string
+	AudioCodec              string
+	BitRate                 int64
+	ExpectedDurationSeconds int
+	DurationMismatch        bool
+	Warnings                []MediaWarning
+	Error                   string
+	ProbedAt                time.Time
+}
+
+// Resolution classifies the video by its short side, e.g. "1080p". Wide
+// aspect ratios are judged by width so a 1920x800 encode still counts as
+// 1080p.
+func (p *MediaProbe) Resolution() string {
+	lines := p.lines()
+	switch {
+	case lines == 0:
+		return ""
+	case lines >= 2000:
+		return "2160p"
+	case lines >= 1400:
+		return "1440p"
+	case lines >= 1000:
+		return "1080p"
+	case lines >= 700:
+		return "720p"
+	case lines >= 540:
+		return "540p"
+	case lines >= 460:
+		return "480p"
+	default:
+		return "SD"
+	}
+}
+
+func (p *MediaProbe) lines() int {
+	if p == nil || p.Width <= 0 || p.Height <= 0 {
+		return 0
+	}
+	return max(p.Height, p.Width*9/16)
+}
+
+// MediaProber probes delivered files and supplies the checks the result is
+// validated against.
+type MediaProber interface {
+	Enabled() bool
+	Validation() MediaValidation
+	Probe(ctx context.Context, path string) (MediaProbe, error)
+}
+
+// SceneDurationLookup finds the StashBox scene duration for a code.
+type SceneDurationLookup interface {
+	SceneDuration(ctx context.Context, code string) (int, bool, error)
+}
+
+func WithMediaProber(prober MediaProber) Option {
+	return func(s *Service) {
+		if prober != nil {
+			s.mediaProber = prober
+		}
+	}
+}
+
+func WithSceneDurationLookup(lookup SceneDurationLookup) Option {
+	return func(s *Service) {
+		if lookup != nil {
+			s.sceneDurations = lookup
+		}
+	}
+}
+
+// probeDeliveredMedia records the probe on the task and flags it with a
+// warning for each failed validation check. Failures and warnings are
+// recorded on the probe rather than blocking ingest.
+func (s *Service) probeDeliveredMedia(ctx context.Context, task *Task, cfg stashsync.IntegrationConfig, plan StashIntegrationPlan) {
+	if s.mediaProber == nil || !s.mediaProber.Enabled() {
+		return
+	}
+	path := mojiContentPath(cfg, plan)
+	if path == "" {
+		logging.Warnf("taskruntime: skip media probe for task %s: downloads.moji_root is required to reach path-mapped content", task.ID)
+		return
+	}
+	probe, err := s.mediaProber.Probe(ctx, path)
+	probe.ProbedAt = s.now().UTC()
+	if err != nil {
+		probe.Error = err.Error()
+		logging.Warnf("taskruntime: media probe failed for task %s path=%s: %v", task.ID, path, err)
+	}
+	if err == nil {
+		s.validateMediaProbe(ctx, task, &probe)
+	}
+	task.MediaProbe = &probe
+}
+
+func (s *Service) validateMediaProbe(ctx context.Context, task *Task, probe *MediaProbe) {
+	validation := s.mediaProber.Validation()
+	if s.sceneDurations != nil && task.Code != "" {
+		expected, ok, lookupErr := s.sceneDurations.SceneDuration(ctx, task.Code)
+		if lookupErr != nil {
+			logging.Warnf("taskruntime: scene duration lookup failed for task %s code=%s: %v", task.ID, task.Code, lookupErr)
+		}
+		if ok && expected > 0 {
+			probe.ExpectedDurationSeconds = expected
+			probe.DurationMismatch = durationMismatch(probe.DurationSeconds, expected, validation.DurationTolerance)
+		}
+	}
+	if probe.DurationMismatch {
+		probe.Warnings = append(probe.Warnings, MediaWarningDurationMismatch)
+		logging.Warnf("taskruntime: task %s duration %.0fs differs from StashBox duration %ds", task.ID, probe.DurationSeconds, probe.ExpectedDurationSeconds)
+	}
+	if lines := probe.lines(); validation.MinHeight > 0 && lines > 0 && lines < validation.MinHeight {
+		probe.Warnings = append(probe.Warnings, MediaWarningLowResolution)
+		logging.Warnf("taskruntime: task %s resolution %dx%d is below the minimum of %dp", task.ID, probe.Width, probe.Height, validation.MinHeight)
+	}
+	if validation.MinBitRate > 0 && probe.BitRate > 0 && probe.BitRate < validation.MinBitRate {
+		probe.Warnings = append(probe.Warnings, MediaWarningLowBitRate)
+		logging.Warnf("taskruntime: task %s bitrate %d b/s is below the minimum of %d b/s", task.ID, probe.BitRate, validation.MinBitRate)
+	}
+}
+
+func durationMismatch(actual float64, expected int, tolerance float64) bool {
+	if actual <= 0 || expected <= 0 {
+		return false
+	}
+	allowed := max(float64(expected)*tolerance, minDurationMismatch.Seconds())
+	return math.Abs(actual-float64(expected)) > allowed
+}
+
+func cloneMediaProbe(probe *MediaProbe) *MediaProbe {
+	if probe == nil {
+		return nil
+	}
+	cp := *probe
+	cp.Warnings = slices.Clone(probe.Warnings)
+	return &cp
+}
@@ -0,0 +1,87 @@
+package taskruntime
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+type fakeMediaProber struct {
+	probe MediaProbe
+	paths []string
+}
+
+func (f *fakeMediaProber) Enabled() bool { return true }
+
+func (f *fakeMediaProber) Validation() MediaValidation {
+	return MediaValidation{DurationTolerance: 0.1, MinHeight: 720, MinBitRate: 4_000_000}
+}
+
+func (f *fakeMediaProber) Probe(_ context.Context, path string) (MediaProbe, error) {
+	f.paths = append(f.paths, path)
+	probe := f.probe
+	probe.Path = path
+	return probe, nil
+}
+
+type fakeSceneDurations map[string]int
+
+func (f fakeSceneDurations) SceneDuration(_ context.Context, code string) (int, bool, error) {
+	duration, ok := f[code]
+	return duration, ok, nil
+}
+
+func TestDeliveredMediaIsProbedAndComparedWithStashBox(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	if err := store.Create(ctx, &Task{ID: "ABCD-123", Code: "ABCD-123", Stage: TaskStagePendingIngest, StageStatus: TaskStageStatusPending, SavePath: "/downloads", ContentPath: "/downloads/ABCD-123.mp4"}); err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{
+		jobID: "job-1",
+		config: stashsync.IntegrationConfig{
+			DeliveryMode: stashsync.DeliveryModePathMap,
+			Downloads:    stashsync.DownloadsPathConfig{QBRoot: "/downloads", MojiRoot: "/mnt/downloads"},
+			Library:      stashsync.LibraryPathConfig{StashRoot: "/library"},
+		},
+	}
+	prober := &fakeMediaProber{probe: MediaProbe{Container: "matroska", DurationSeconds: 3600, Width: 1920, Height: 800, VideoCodec: "hevc", BitRate: 2_500_000}}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithMediaProber(prober), WithSceneDurationLookup(fakeSceneDurations{"ABCD-123": 7200}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	task, _ := service.FindTask(ctx, "ABCD-123")
+	if task.Stage != TaskStageScanning || len(prober.paths) != 1 || prober.paths[0] != "/mnt/downloads/ABCD-123.mp4" {
+		t.Fatalf("expected delivered file to be probed before the scan, got %+v paths %v", task, prober.paths)
+	}
+	probe := task.MediaProbe
+	if probe == nil || probe.ExpectedDurationSeconds != 7200 || !probe.DurationMismatch || probe.Resolution() != "1080p" || probe.ProbedAt.IsZero() {
+		t.Fatalf("unexpected media probe %+v", probe)
+	}
+	if !slices.Equal(probe.Warnings, []MediaWarning{MediaWarningDurationMismatch, MediaWarningLowBitRate}) {
+		t.Fatalf("expected duration and bitrate warnings only, got %v", probe.Warnings)
+	}
+}
+
+func TestDurationMismatchAllowsToleranceAndShortPadding(t *testing.T) {
+	for _, tc := range []struct {
+		actual   float64
+		expected int
+		want     bool
+	}{
+		{actual: 7000, expected: 7200, want: false},
+		{actual: 6400, expected: 7200, want: true},
+		{actual: 350, expected: 300, want: false},
+		{actual: 0, expected: 300, want: false},
+	} {
+		if got := durationMismatch(tc.actual, tc.expected, 0.1); got != tc.want {
+			t.Errorf("durationMismatch(%v, %d) = %v, want %v", tc.actual, tc.expected, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/pkg/qbittorrent"
+)
+
+var ErrReconcileFixNotApplicable = errors.New("reconcile fix is not applicable")
+
+type ReconcileIssueKind string
+
+const (
+	ReconcileIssueTorrentMissing   ReconcileIssueKind = "TORRENT_MISSING"
+	ReconcileIssueOrphanTorrent    ReconcileIssueKind = "ORPHAN_TORRENT"
+	ReconcileIssueSavePathMismatch ReconcileIssueKind = "SAVE_PATH_MISMATCH"
+)
+
+type ReconcileFix string
+
+const (
+	ReconcileFixReaddTorrent ReconcileFix = "READD_TORRENT"
+	ReconcileFixAdoptTorrent ReconcileFix = "ADOPT_TORRENT"
+	ReconcileFixMarkFailed   ReconcileFix = "MARK_FAILED"
+	ReconcileFixSyncSavePath ReconcileFix = "SYNC_SAVE_PATH"
+)
+
+// ReconcileIssue is one disagreement between the task store and qBittorrent.
+// TaskID is empty for orphan torrents and TorrentHash is empty for tasks
+// whose torrent is gone.
+type ReconcileIssue struct {
+	Kind            ReconcileIssueKind
+	TaskID          string
+	Code            string
+	TorrentHash     string
+	TorrentName     string
+	TaskSavePath    string
+	TorrentSavePath string
+	Detail          string
+	ProposedFixes   []ReconcileFix
+}
+
+type ReconcileReport struct {
+	GeneratedAt  time.Time
+	TaskCount    int
+	TorrentCount int
+	Issues       []ReconcileIssue
+}
+
+type ReconcileFixRequest struct {
+	Fix         ReconcileFix
+	TaskID      string
+	TorrentHash string
+}
+
+// WithManagedCategory tells reconciliation which qBittorrent category Moji
+// submits into; torrents in it that no task owns are reported as orphans.
+// Orphan detection is skipped while the category is empty.
+func WithManagedCategory(provider func() string) Option {
+	return func(s *Service) {
+		if provider != nil {
+			s.managedCategory = provider
+		}
+	}
+}
+
+// Reconcile compares the task store with qBittorrent and reports drift
+// without changing anything.
+func (s *Service) Reconcile(ctx context.Context) (ReconcileReport, error) {
+	tasks, err := s.store.List(ctx)
+	if err != nil {
+		return ReconcileReport{}, err
+	}
+	torrents, err := s.qbt.GetTorrentList(ctx, nil)
+	if err != nil {
+		return ReconcileReport{}, fmt.Errorf("list torrents: %w", err)
+	}
+
+	report := ReconcileReport{
+		GeneratedAt:  s.now().UTC(),
+		TaskCount:    len(tasks),
+		TorrentCount: len(torrents),
+		Issues:       []ReconcileIssue{},
+	}
+	owned := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		torrent, ok := matchTaskTorrent(task, torrents)
+		if ok {
+			owned[strings.ToUpper(torrent.Hash)] = struct{}{}
+			if issue, mismatch := savePathMismatch(task, torrent); mismatch {
+				report.Issues = append(report.Issues, issue)
+			}
+			continue
+		}
+		if expectsTorrent(task) {
+			report.Issues = append(report.Issues, missingTorrentIssue(task))
+		}
+	}
+
+	category := ""
+	if s.managedCategory != nil {
+		category = strings.TrimSpace(s.managedCategory())
+	}
+	if category != "" {
+		for _, torrent := range torrents {
+			if torrent.Category != category {
+				continue
+			}
+			if _, ok := owned[strings.ToUpper(torrent.Hash)]; ok {
+				continue
+			}
+			report.Issues = append(report.Issues, ReconcileIssue{
+				Kind:            ReconcileIssueOrphanTorrent,
+				Code:            extractCode(torrent.Name),
+				TorrentHash:     torrent.Hash,
+				TorrentName:     torrent.Name,
+				TorrentSavePath: torrent.SavePath,
+				Detail:          fmt.Sprintf("torrent in category %s has no task", category),
+				ProposedFixes:   []ReconcileFix{ReconcileFixAdoptTorrent},
+			})
+		}
+	}
+	return report, nil
+}
+
+// expectsTorrent reports whether the task still needs its torrent in qB.
+// Tasks past ingest may have had their torrent cleaned up on purpose, and
+// tasks whose submission failed never had one.
+func expectsTorrent(task *Task) bool {
+	if task.Stage != TaskStageDownloading && task.Stage != TaskStagePendingIngest {
+		return false
+	}
+	if task.StageStatus == TaskStageStatusBlocked {
+		switch task.StageErrorCode {
+		case TaskStageErrorTorrentSubmit, TaskStageErrorInsufficientDisk, TaskStageErrorTorrentMissing:
+			return false
+		}
+	}
+	return task.TorrentURL != "" || task.TorrentHash != "" || task.TorrentIdentityHash != ""
+}
+
+func missingTorrentIssue(task *Task) ReconcileIssue {
+	fixes := []ReconcileFix{ReconcileFixMarkFailed}
+	if task.TorrentURL != "" {
+		fixes = []ReconcileFix{ReconcileFixReaddTorrent, ReconcileFixMarkFailed}
+	}
+	return ReconcileIssue{
+		Kind:          ReconcileIssueTorrentMissing,
+		TaskID:        task.ID,
+		Code:          task.Code,
+		TorrentName:   task.TorrentName,
+		TaskSavePath:  task.SavePath,
+		Detail:        fmt.Sprintf("task in %s has no torrent in qBittorrent", task.Stage),
+		ProposedFixes: fixes,
+	}
+}
+
+func savePathMismatch(task *Task, torrent qbittorrent.Torrent) (ReconcileIssue, bool) {
+	if task.SavePath == "" || torrent.SavePath == "" || cleanSavePath(task.SavePath) == cleanSavePath(torrent.SavePath) {
+		return ReconcileIssue{}, false
+	}
+	return ReconcileIssue{
+		Kind:            ReconcileIssueSavePathMismatch,
+		TaskID:          task.ID,
+		Code:            task.Code,
+		TorrentHash:     torrent.Hash,
+		TorrentName:     torrent.Name,
+		TaskSavePath:    task.SavePath,
+		TorrentSavePath: torrent.SavePath,
+		Detail:          "task save path differs from qBittorrent",
+		ProposedFixes:   []ReconcileFix{ReconcileFixSyncSavePath},
+	}, true
+}
+
+func cleanSavePath(path string) string {
+	return filepath.Clean(strings.TrimSpace(path))
+}
+
+// ApplyReconcileFix applies one proposed fix after re-checking that it
+// still applies, and returns the affected task.
+func (s *Service) ApplyReconcileFix(ctx context.Context, req ReconcileFixRequest) (*Task, error) {
+	switch req.Fix {
+	case ReconcileFixAdoptTorrent:
+		return s.adoptOrphanTorrent(ctx, req.TorrentHash)
+	case ReconcileFixReaddTorrent, ReconcileFixMarkFailed, ReconcileFixSyncSavePath:
+	default:
+		return nil, fmt.Errorf("taskruntime: unknown reconcile fix %q", req.Fix)
+	}
+
+	id := strings.TrimSpace(req.TaskID)
+	if id == "" {
+		return nil, errors.New("taskruntime: task id is required")
+	}
+	unlock := s.lockTask(id)
+	defer unlock()
+	task, err := s.store.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("taskruntime: task %q not found", id)
+	}
+	torrents, err := s.qbt.GetTorrentList(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list torrents: %w", err)
+	}
+	torrent, found := matchTaskTorrent(task, torrents)
+
+	next := cloneTask(task)
+	switch req.Fix {
+	case ReconcileFixSyncSavePath:
+		if !found {
+			return nil, fmt.Errorf("%w: task %q has no torrent in qBittorrent", ErrReconcileFixNotApplicable, id)
+		}
+		next.SavePath = torrent.SavePath
+		next.ContentPath = torrent.ContentPath
+		next.UpdatedAt = s.now().UTC()
+		if err := s.store.Update(ctx, next); err != nil {
+			return nil, fmt.Errorf("update task %q: %w", id, err)
+		}
+		logging.Infof("taskruntime: reconcile synced task %s save path to %s", id, torrent.SavePath)
+		return next, nil
+	case ReconcileFixMarkFailed:
+		if found {
+			return nil, fmt.Errorf("%w: task %q still has its torrent", ErrReconcileFixNotApplicable, id)
+		}
+		blockTask(next, TaskStageErrorTorrentMissing, "torrent was removed from qBittorrent", s.now().UTC())
+		if err := s.store.Update(ctx, next); err != nil {
+			return nil, fmt.Errorf("update task %q: %w", id, err)
+		}
+		logging.Infof("taskruntime: reconcile marked task %s failed: torrent missing", id)
+		return next, nil
+	default:
+		if found {
+			return nil, fmt.Errorf("%w: task %q still has its torrent", ErrReconcileFixNotApplicable, id)
+		}
+		if next.TorrentURL == "" {
+			return nil, fmt.Errorf("%w: task %q has no stored torrent url", ErrReconcileFixNotApplicable, id)
+		}
+		next.TorrentHash = ""
+		next.Progress = 0
+		next.ContentPath = ""
+		next.DownloadCompletedAt = nil
+		if err := s.submitTaskTorrent(ctx, next, next.TorrentURL, next.SavePath, next.Category, next.Tags, nil); err != nil {
+			return next, err
+		}
+		logging.Infof("taskruntime: reconcile re-added torrent for task %s", id)
+		return next, nil
+	}
+}
+
+// adoptOrphanTorrent creates a task for a torrent that is already in qB,
+// keyed by the torrent's own identity so progress sync picks it up.
+func (s *Service) adoptOrphanTorrent(ctx context.Context, hash string) (*Task, error) {
+	hash = strings.TrimSpace(hash)
+	if hash == "" {
+		return nil, errors.New("taskruntime: torrent hash is required")
+	}
+	torrents, err := s.qbt.GetTorrentList(ctx, &qbittorrent.TorrentListOptions{Hashes: []string{hash}})
+	if err != nil {
+		return nil, fmt.Errorf("list torrents: %w", err)
+	}
+	var torrent *qbittorrent.Torrent
+	for index := range torrents {
+		if strings.EqualFold(torrents[index].Hash, hash) {
+			torrent = &torrents[index]
+			break
+		}
+	}
+	if torrent == nil {
+		return nil, fmt.Errorf("%w: torrent %s is not in qBittorrent", ErrReconcileFixNotApplicable, hash)
+	}
+	code, _ := s.torrentCode(ctx, *torrent)
+	return s.createTaskFromTorrent(ctx, *torrent, code)
+}
+
+func (s *Service) createTaskFromTorrent(ctx context.Context, torrent qbittorrent.Torrent, code string) (*Task, error) {
+	identity := torrentIdentity{InfoHash: normalizeInfoHash(torrent.Hash), MagnetURI: normalizeMagnetURI(torrent.MagnetURI)}
+	if err := s.ensureTaskCanBeCreated(ctx, identity, code); err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	task := &Task{
+		ID:     s.newID(),
+		Source: TaskSourceManual,
+		Code:   normalizeCode(code),
+		Candidate: Candidate{
+			Title:     torrent.Name,
+			InfoHash:  identity.InfoHash,
+			MagnetURI: torrent.MagnetURI,
+			Size:      torrent.Size,
+		},
+		TorrentURL:            torrent.MagnetURI,
+		Category:              torrent.Category,
+		Tags:                  torrent.Tags,
+		TorrentIdentityHash:   identity.InfoHash,
+		TorrentIdentityMagnet: identity.MagnetURI,
+		CreatedAt:             now,
+	}
+	setTaskStage(task, TaskStageDownloading, TaskStageStatusPending)
+	applyTorrentProgress(task, torrent, now)
+	if err := s.store.Create(ctx, task); err != nil {
+		return nil, fmt.Errorf("create task: %w", err)
+	}
+	logging.Infof("taskruntime: adopted torrent %s as task %s (%s, %s)", torrent.Hash, task.ID, task.Code, task.Stage)
+	return task, nil
+}
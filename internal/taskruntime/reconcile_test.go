@@ -0,0 +1,90 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/pkg/qbittorrent"
+)
+
+func TestReconcileReportsDriftAndAppliesFixes(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for _, task := range []*Task{
+		{ID: "gone", Code: "ABP-001", Stage: TaskStageDownloading, StageStatus: TaskStageStatusRunning, TorrentURL: "magnet:?xt=urn:btih:AAAA", TorrentIdentityHash: "AAAA"},
+		{ID: "moved", Code: "ABP-002", Stage: TaskStagePendingIngest, StageStatus: TaskStageStatusPending, TorrentHash: "bbbb", SavePath: "/downloads/old"},
+		{ID: "done", Code: "ABP-003", Stage: TaskStageCompleted, StageStatus: TaskStageStatusDone, TorrentHash: "cccc"},
+	} {
+		task.CreatedAt, task.UpdatedAt = now, now
+		if err := store.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	qbt := &fakeTorrentAdder{torrents: []qbittorrent.Torrent{
+		{Hash: "bbbb", Name: "ABP-002", Category: "moji", SavePath: "/downloads/new/", ContentPath: "/downloads/new/ABP-002"},
+		{Hash: "dddd", Name: "[HD] SSIS-100.mp4", Category: "moji", SavePath: "/downloads", Progress: 1, State: qbittorrent.TorrentStateStalledUP},
+		{Hash: "eeee", Name: "linux.iso", Category: "other"},
+	}}
+	service, err := NewService(fakeTracker{}, qbt, store,
+		WithClock(func() time.Time { return now }),
+		WithManagedCategory(func() string { return "moji" }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := service.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if report.TaskCount != 3 || report.TorrentCount != 3 || len(report.Issues) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	kinds := map[ReconcileIssueKind]ReconcileIssue{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue
+	}
+	if issue := kinds[ReconcileIssueTorrentMissing]; issue.TaskID != "gone" || len(issue.ProposedFixes) != 2 || issue.ProposedFixes[0] != ReconcileFixReaddTorrent {
+		t.Fatalf("unexpected missing-torrent issue: %+v", issue)
+	}
+	if issue := kinds[ReconcileIssueSavePathMismatch]; issue.TaskID != "moved" || issue.TorrentSavePath != "/downloads/new/" {
+		t.Fatalf("unexpected save path issue: %+v", issue)
+	}
+	if issue := kinds[ReconcileIssueOrphanTorrent]; issue.TorrentHash != "dddd" || issue.Code != "SSIS-100" {
+		t.Fatalf("unexpected orphan issue: %+v", issue)
+	}
+
+	if _, err := service.ApplyReconcileFix(ctx, ReconcileFixRequest{Fix: ReconcileFixMarkFailed, TaskID: "moved"}); !errors.Is(err, ErrReconcileFixNotApplicable) {
+		t.Fatalf("expected fix to be rejected for a task with a torrent, got %v", err)
+	}
+	moved, err := service.ApplyReconcileFix(ctx, ReconcileFixRequest{Fix: ReconcileFixSyncSavePath, TaskID: "moved"})
+	if err != nil || moved.SavePath != "/downloads/new/" || moved.ContentPath != "/downloads/new/ABP-002" {
+		t.Fatalf("sync save path: task %+v err %v", moved, err)
+	}
+	readded, err := service.ApplyReconcileFix(ctx, ReconcileFixRequest{Fix: ReconcileFixReaddTorrent, TaskID: "gone"})
+	if err != nil || len(qbt.options.URLs) != 1 || qbt.options.URLs[0] != "magnet:?xt=urn:btih:AAAA" || readded.StageStatus != TaskStageStatusRunning {
+		t.Fatalf("re-add torrent: task %+v options %+v err %v", readded, qbt.options, err)
+	}
+	adopted, err := service.ApplyReconcileFix(ctx, ReconcileFixRequest{Fix: ReconcileFixAdoptTorrent, TorrentHash: "dddd"})
+	if err != nil {
+		t.Fatalf("adopt torrent: %v", err)
+	}
+	if adopted.Code != "SSIS-100" || adopted.Stage != TaskStagePendingIngest || adopted.TorrentIdentityHash != "DDDD" {
+		t.Fatalf("unexpected adopted task: %+v", adopted)
+	}
+
+	report, err = service.Reconcile(ctx)
+	if err != nil || len(report.Issues) != 1 || report.Issues[0].TaskID != "gone" {
+		t.Fatalf("expected only the re-added task to remain until qB lists it, got %+v err %v", report.Issues, err)
+	}
+	failed, err := service.ApplyReconcileFix(ctx, ReconcileFixRequest{Fix: ReconcileFixMarkFailed, TaskID: "gone"})
+	if err != nil || failed.StageStatus != TaskStageStatusBlocked || failed.StageErrorCode != TaskStageErrorTorrentMissing {
+		t.Fatalf("mark failed: task %+v err %v", failed, err)
+	}
+	if report, _ = service.Reconcile(ctx); len(report.Issues) != 0 {
+		t.Fatalf("expected clean report after fixes, got %+v", report.Issues)
+	}
+}
@@ -0,0 +1,119 @@
+package taskruntime
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+// SceneAttributes are the StashBox scene details library routes can match.
+type SceneAttributes struct {
+	Studio string
+	Tags   []string
+}
+
+// SceneAttributesLookup finds the StashBox studio and tags for a code.
+type SceneAttributesLookup interface {
+	SceneAttributes(ctx context.Context, code string) (SceneAttributes, bool, error)
+}
+
+func WithSceneAttributesLookup(lookup SceneAttributesLookup) Option {
+	return func(s *Service) {
+		if lookup != nil {
+			s.sceneAttributes = lookup
+		}
+	}
+}
+
+// routeTask returns the name of the first library route the task matches,
+// or "" for the default library. The StashBox scene is only looked up when a
+// route matches on studio or tags.
+func (s *Service) routeTask(ctx context.Context, task *Task, routes []stashsync.LibraryRoute) string {
+	var attrs *SceneAttributes
+	for _, route := range routes {
+		if len(route.Studios)+len(route.Tags) > 0 && attrs == nil {
+			attrs = s.lookupSceneAttributes(ctx, task)
+		}
+		if routeMatches(route, task, attrs) {
+			logging.Infof("taskruntime: task %s routed to library %q", task.ID, route.Name)
+			return route.Name
+		}
+	}
+	return ""
+}
+
+func (s *Service) lookupSceneAttributes(ctx context.Context, task *Task) *SceneAttributes {
+	attrs := &SceneAttributes{}
+	if s.sceneAttributes == nil || strings.TrimSpace(task.Code) == "" {
+		return attrs
+	}
+	found, ok, err := s.sceneAttributes.SceneAttributes(ctx, task.Code)
+	if err != nil {
+		logging.Warnf("taskruntime: scene lookup for routing failed for task %s code=%s: %v", task.ID, task.Code, err)
+	}
+	if ok {
+		*attrs = found
+	}
+	return attrs
+}
+
+func routeMatches(route stashsync.LibraryRoute, task *Task, attrs *SceneAttributes) bool {
+	if len(route.CodePrefixes) > 0 && !anyMatch(route.CodePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(strings.ToUpper(task.Code), strings.ToUpper(prefix))
+	}) {
+		return false
+	}
+	if len(route.TitleKeywords) > 0 {
+		titles := strings.ToLower(task.Candidate.Title + "\n" + task.TorrentName)
+		if !anyMatch(route.TitleKeywords, func(keyword string) bool {
+			return strings.Contains(titles, strings.ToLower(keyword))
+		}) {
+			return false
+		}
+	}
+	if len(route.Sources) > 0 && !anyMatch(route.Sources, func(source string) bool {
+		return strings.EqualFold(source, string(task.Source))
+	}) {
+		return false
+	}
+	if len(route.Studios) > 0 && (attrs == nil || !anyMatch(route.Studios, func(studio string) bool {
+		return strings.EqualFold(studio, attrs.Studio)
+	})) {
+		return false
+	}
+	if len(route.Tags) > 0 {
+		tags := strings.Split(task.Tags, ",")
+		if attrs != nil {
+			tags = append(tags, attrs.Tags...)
+		}
+		if !anyMatch(route.Tags, func(tag string) bool {
+			return anyMatch(tags, func(have string) bool { return strings.EqualFold(strings.TrimSpace(have), tag) })
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyMatch(values []string, match func(string) bool) bool {
+	for _, value := range values {
+		if match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// routedConfig points cfg.Library at the library of the task's route, so
+// every delivery plan for the task uses the library chosen when its
+// ingest started.
+func routedConfig(cfg stashsync.IntegrationConfig, task *Task) stashsync.IntegrationConfig {
+	library, ok := cfg.RouteLibrary(task.LibraryRoute)
+	if !ok {
+		logging.Warnf("taskruntime: library route %q of task %s is no longer configured, using the default library", task.LibraryRoute, task.ID)
+	}
+	cfg.Library = library
+	return cfg
+}
@@ -0,0 +1,93 @@
+package taskruntime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+func TestTriggerStashScansFollowsLibraryRoutes(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	completedAt := time.Unix(200, 0).UTC()
+	for _, task := range []*Task{
+		{ID: "task-vr", Code: "SIVR-100", Source: TaskSourceSearch, ContentPath: "/downloads/SIVR-100"},
+		{ID: "task-studio", Code: "ABC-123", Source: TaskSourceSubscription, ContentPath: "/downloads/ABC-123"},
+		{ID: "task-default", Code: "XYZ-001", Source: TaskSourceManual, ContentPath: "/downloads/XYZ-001", Tags: "moji"},
+	} {
+		task.Stage, task.StageStatus, task.DownloadCompletedAt = TaskStagePendingIngest, TaskStageStatusPending, &completedAt
+		if err := store.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lookup := &fakeSceneAttributes{attrs: map[string]SceneAttributes{"ABC-123": {Studio: "Madonna", Tags: []string{"Drama"}}}}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithSceneAttributesLookup(lookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobID: "job-1", config: stashsync.IntegrationConfig{
+		DeliveryMode: stashsync.DeliveryModePathMap,
+		Downloads:    stashsync.DownloadsPathConfig{QBRoot: "/downloads"},
+		Library:      stashsync.LibraryPathConfig{StashRoot: "/library"},
+		Routes: []stashsync.LibraryRoute{
+			{Name: "vr", CodePrefixes: []string{"sivr", "VRKM"}, Library: stashsync.LibraryPathConfig{StashRoot: "/vr"}},
+			{Name: "madonna", Studios: []string{"madonna"}, Sources: []string{"SUBSCRIPTION"}, Library: stashsync.LibraryPathConfig{StashRoot: "/madonna"}},
+		},
+	}}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	for id, want := range map[string][2]string{
+		"task-vr":      {"vr", "/vr/SIVR-100"},
+		"task-studio":  {"madonna", "/madonna/ABC-123"},
+		"task-default": {"", "/library/XYZ-001"},
+	} {
+		task, _ := service.FindTask(ctx, id)
+		if task.LibraryRoute != want[0] || task.StashScanPath != want[1] {
+			t.Fatalf("%s: expected route %q path %q, got route %q path %q", id, want[0], want[1], task.LibraryRoute, task.StashScanPath)
+		}
+	}
+	// Only tasks reaching the studio route need the StashBox scene.
+	if len(lookup.codes) != 2 || lookup.codes[0] == "SIVR-100" || lookup.codes[1] == "SIVR-100" {
+		t.Fatalf("unexpected scene lookups %v", lookup.codes)
+	}
+}
+
+func TestRouteMatchesRequiresEveryCondition(t *testing.T) {
+	route := stashsync.LibraryRoute{Name: "uncensored", TitleKeywords: []string{"Uncensored"}, Tags: []string{"leaked"}}
+	task := &Task{Code: "ABC-123", Candidate: Candidate{Title: "[UNCENSORED] ABC-123"}, Tags: "moji, Leaked"}
+	if !routeMatches(route, task, nil) {
+		t.Fatal("expected title keyword and qBittorrent tag to match")
+	}
+	task.Tags = "moji"
+	if routeMatches(route, task, nil) {
+		t.Fatal("expected a missing tag to reject the route")
+	}
+	if !routeMatches(route, task, &SceneAttributes{Tags: []string{"Leaked"}}) {
+		t.Fatal("expected StashBox scene tags to match")
+	}
+}
+
+type fakeSceneAttributes struct {
+	attrs map[string]SceneAttributes
+	codes []string
+}
+
+func (f *fakeSceneAttributes) SceneAttributes(_ context.Context, code string) (SceneAttributes, bool, error) {
+	f.codes = append(f.codes, code)
+	attrs, ok := f.attrs[code]
+	return attrs, ok, nil
+}
+
+func TestLibraryRoutesAcceptEveryTaskSource(t *testing.T) {
+	for _, source := range []TaskSource{TaskSourceManual, TaskSourceSearch, TaskSourceSubscription, TaskSourceWatchlist} {
+		route := config.LibraryRouteConfig{Name: "route", Sources: []string{string(source)}, Library: config.LibraryIngestConfig{StashRoot: "/library"}}
+		if err := config.ValidateLibraryRoutes([]config.LibraryRouteConfig{route}); err != nil {
+			t.Errorf("source %s: %v", source, err)
+		}
+	}
+}
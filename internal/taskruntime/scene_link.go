@@ -0,0 +1,70 @@
+package taskruntime
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+// TaskOrigin records the StashBox scene a task was created for and, for
+// subscription releases, the subscribed Stash performer.
+type TaskOrigin struct {
+	StashBoxEndpoint string
+	StashBoxSceneID  string
+	StashPerformerID string
+}
+
+// SceneLinker links the Stash scenes under a scanned path back to the task
+// that delivered them and returns their IDs.
+type SceneLinker interface {
+	LinkScenes(ctx context.Context, link stashsync.SceneLink) ([]string, error)
+}
+
+func WithSceneLinker(linker SceneLinker) Option {
+	return func(s *Service) {
+		if linker != nil {
+			s.sceneLinker = linker
+		}
+	}
+}
+
+// linkStashScenes runs once the scan (and identify) finished. Linking is
+// best effort: the task is complete either way.
+func (s *Service) linkStashScenes(ctx context.Context, task *Task) {
+	path := strings.TrimSpace(task.StashScanPath)
+	if s.sceneLinker == nil || path == "" {
+		return
+	}
+	ids, err := s.sceneLinker.LinkScenes(ctx, stashsync.SceneLink{
+		Path:             path,
+		StashBoxEndpoint: task.Origin.StashBoxEndpoint,
+		StashBoxSceneID:  task.Origin.StashBoxSceneID,
+		PerformerID:      task.Origin.StashPerformerID,
+		Provenance: stashsync.Provenance{
+			TaskID:      task.ID,
+			Indexer:     task.Candidate.Tracker,
+			TorrentName: task.TorrentName,
+			GrabbedAt:   task.CreatedAt,
+		},
+	})
+	if err != nil {
+		logging.Warnf("taskruntime: link stash scenes failed for task %s path=%s: %v", task.ID, path, err)
+	}
+	if len(ids) > 0 {
+		task.StashSceneID = ids[0]
+	}
+}
+
+// syncLibraryIndex picks up the scenes the finished scan added, so the
+// library index owns their codes before the next dedup check. Like linking
+// it is best effort.
+func (s *Service) syncLibraryIndex(ctx context.Context, task *Task) {
+	if s.libraryIndex == nil {
+		return
+	}
+	if err := s.libraryIndex.Sync(ctx); err != nil {
+		logging.Warnf("taskruntime: library index sync failed after task %s: %v", task.ID, err)
+	}
+}
@@ -14,9 +14,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/leothevan2444/moji/internal/codeparser"
 	"github.com/leothevan2444/moji/internal/config"
+	"github.com/leothevan2444/moji/internal/inspectioncache"
 	"github.com/leothevan2444/moji/internal/logging"
 	"github.com/leothevan2444/moji/internal/stashsync"
 	"github.com/leothevan2444/moji/internal/tracker"
@@ -46,6 +49,12 @@ type LibraryCodeChecker interface {
 	HasCode(ctx context.Context, code string) (bool, error)
 }
 
+// LibraryIndexSyncer refreshes the local index of the Stash library the
+// LibraryCodeChecker may answer from.
+type LibraryIndexSyncer interface {
+	Sync(ctx context.Context) error
+}
+
 type TaskStore interface {
 	Create(ctx context.Context, task *Task) error
 	Update(ctx context.Context, task *Task) error
@@ -62,6 +71,7 @@ const (
 	TaskSourceManual       TaskSource = "MANUAL"
 	TaskSourceSearch       TaskSource = "SEARCH"
 	TaskSourceSubscription TaskSource = "SUBSCRIPTION"
+	TaskSourceWatchlist    TaskSource = "WATCHLIST"
 )
 
 type Task struct {
@@ -87,6 +97,7 @@ type Task struct {
 	QBittorrentState      string
 	ContentPath           string
 	DownloadCompletedAt   *time.Time
+	LibraryRoute          string
 	DeliveryMode          string
 	MojiSourcePath        string
 	TransferAction        string
@@ -97,6 +108,10 @@ type Task struct {
 	StashScanError        string
 	StashScanHint         string
 	StashScanStartedAt    *time.Time
+	StashIdentifyJobID    string
+	StashSceneID          string
+	Origin                TaskOrigin
+	MediaProbe            *MediaProbe
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
@@ -122,6 +137,10 @@ type DownloadRequest struct {
 	Category   string
 	Tags       string
 	Paused     *bool
+	Origin     TaskOrigin
+	// Results, when set, are used as the candidates instead of searching
+	// Jackett for Code.
+	Results []jackett.SearchResult
 }
 
 type AddTorrentRequest struct {
@@ -157,14 +176,31 @@ type Service struct {
 	store              TaskStore
 	httpClient         *http.Client
 	libraryCodeChecker LibraryCodeChecker
+	libraryIndex       LibraryIndexSyncer
 	selector           CandidateSelector
 	fileOps            FileOperator
 	candidateSelection func() config.CandidateSelectionConfig
 	taskDeletePolicy   func() config.TaskDeletePolicy
 	now                func() time.Time
 	newID              func() string
-	inspectionCacheMu  sync.Mutex
-	inspectionCache    map[string]cachedTorrentInspection
+	inspectionCache    TorrentInspectionCache
+	diskSpace          func() DiskSpaceConfig
+	freeSpace          FreeSpaceProbe
+	diskSpaceWarned    sync.Map
+	managedCategory    func() string
+	contentLister      TorrentContentLister
+	subtitler          Subtitler
+	mediaProber        MediaProber
+	sceneDurations     SceneDurationLookup
+	sceneLinker        SceneLinker
+	sceneAttributes    SceneAttributesLookup
+	lifetime           context.Context
+	stopLifetime       context.CancelFunc
+	subtitleJobsMu     sync.Mutex
+	subtitleJobs       map[string]*subtitleJob
+	stashJobsLive      atomic.Bool
+	stashJobsMu        sync.Mutex
+	finishedStashJobs  []stashsync.Job
 	taskLocksMu        sync.Mutex
 	taskLocks          map[string]*taskOperationLock
 }
@@ -200,13 +236,13 @@ type PreviewJackettSelectionRequest struct {
 	InspectionCandidateLimit int
 }
 
-type cachedTorrentInspection struct {
-	inspection torrentInspection
-	expiresAt  time.Time
+// TorrentInspectionCache persists the file listings of downloaded .torrent
+// files so candidate selection does not refetch them from the indexers.
+type TorrentInspectionCache interface {
+	Lookup(ctx context.Context, torrentURL, infoHash string) (inspectioncache.Entry, bool)
+	Store(ctx context.Context, torrentURL, infoHash string, entry inspectioncache.Entry) error
 }
 
-const torrentInspectionCacheTTL = 20 * time.Minute
-
 func WithClock(now func() time.Time) Option {
 	return func(s *Service) {
 		if now != nil {
@@ -229,6 +265,14 @@ func WithLibraryCodeChecker(checker LibraryCodeChecker) Option {
 	}
 }
 
+func WithLibraryIndexSyncer(index LibraryIndexSyncer) Option {
+	return func(s *Service) {
+		if index != nil {
+			s.libraryIndex = index
+		}
+	}
+}
+
 func NewService(tr tracker.Tracker, qbt TorrentClient, store TaskStore, options ...Option) (*Service, error) {
 	if tr == nil {
 		return nil, errors.New("taskruntime: tracker is required")
@@ -250,17 +294,29 @@ func NewService(tr tracker.Tracker, qbt TorrentClient, store TaskStore, options
 		taskDeletePolicy: func() config.TaskDeletePolicy {
 			return config.TaskDeletePolicyKeepOnly
 		},
-		now:             time.Now,
-		newID:           newTaskID,
-		inspectionCache: make(map[string]cachedTorrentInspection),
-		taskLocks:       make(map[string]*taskOperationLock),
+		now:          time.Now,
+		newID:        newTaskID,
+		freeSpace:    probeFreeSpace,
+		taskLocks:    make(map[string]*taskOperationLock),
+		subtitleJobs: make(map[string]*subtitleJob),
 	}
+	s.lifetime, s.stopLifetime = context.WithCancel(context.Background())
 	for _, option := range options {
 		option(s)
 	}
 	return s, nil
 }
 
+// Close stops background work the runtime started outside a caller's
+// context, such as subtitle downloads, and waits for stage hooks the task
+// store still has running.
+func (s *Service) Close() {
+	s.stopLifetime()
+	if closer, ok := s.store.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
 func WithCandidateSelectionProvider(provider func() config.CandidateSelectionConfig) Option {
 	return func(s *Service) {
 		if provider != nil {
@@ -293,6 +349,12 @@ func WithFileOperator(fileOps FileOperator) Option {
 	}
 }
 
+func WithTorrentInspectionCache(cache TorrentInspectionCache) Option {
+	return func(s *Service) {
+		s.inspectionCache = cache
+	}
+}
+
 func WithTaskDeletePolicyProvider(provider func() config.TaskDeletePolicy) Option {
 	return func(s *Service) {
 		if provider != nil {
@@ -372,7 +434,11 @@ func (s *Service) DeleteTask(ctx context.Context, id string) (*Task, error) {
 		}
 	}
 
-	return s.store.Delete(ctx, id)
+	deleted, err := s.store.Delete(ctx, id)
+	if err == nil {
+		s.cancelSubtitleJob(id)
+	}
+	return deleted, err
 }
 
 func (s *Service) RetryTask(ctx context.Context, id string, scanner StashScanner) (*Task, error) {
@@ -402,6 +468,8 @@ func (s *Service) RetryTask(ctx context.Context, id string, scanner StashScanner
 		return s.retrySourcingTask(ctx, task)
 	case TaskStageDownloading:
 		return s.retryDownloadingTask(ctx, task)
+	case TaskStageSubtitling:
+		return s.retrySubtitlingTask(ctx, task)
 	case TaskStagePendingIngest, TaskStageTransferring, TaskStageScanning:
 		if scanner == nil {
 			return nil, errors.New("taskruntime: stash scanner is required")
@@ -507,7 +575,7 @@ func (s *Service) syncTaskProgress(ctx context.Context, snapshot *Task, torrents
 	if err != nil {
 		return snapshot, nil
 	}
-	if task.Stage == TaskStageCompleted || (task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning) || task.Stage == TaskStagePendingIngest || task.Stage == TaskStageTransferring {
+	if task.Stage == TaskStageCompleted || (task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning) || task.Stage == TaskStagePendingIngest || task.Stage == TaskStageTransferring || task.Stage == TaskStageSubtitling {
 		return task, nil
 	}
 	torrent, ok := matchTaskTorrent(task, torrents)
@@ -611,6 +679,7 @@ func (s *Service) DownloadMediaContext(ctx context.Context, req DownloadRequest)
 		SavePath:  req.SavePath,
 		Category:  req.Category,
 		Tags:      req.Tags,
+		Origin:    req.Origin,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -627,29 +696,83 @@ func (s *Service) DownloadMediaContext(ctx context.Context, req DownloadRequest)
 
 func (s *Service) runSourcingFlow(ctx context.Context, task *Task, req DownloadRequest) (*Task, error) {
 	code := strings.TrimSpace(task.Code)
-	searchOptions := []tracker.SearchOption{
-		tracker.WithTrackers(req.Trackers),
-		tracker.WithCategories(req.Categories),
+	results := req.Results
+	if len(results) == 0 {
+		searchOptions := []tracker.SearchOption{
+			tracker.WithTrackers(req.Trackers),
+			tracker.WithCategories(req.Categories),
+		}
+		if req.Limit > 0 {
+			searchOptions = append(searchOptions, tracker.WithLimit(req.Limit))
+		}
+
+		var err error
+		results, err = s.searchCode(code, searchOptions...)
+		if err != nil {
+			blockTask(task, TaskStageErrorSearch, err.Error(), s.now().UTC())
+			_ = s.store.Update(ctx, task)
+			logging.Errorf("taskruntime: search failed for code %q: %v", code, err)
+			return task, fmt.Errorf("search torrents: %w", err)
+		}
 	}
-	if req.Limit > 0 {
-		searchOptions = append(searchOptions, tracker.WithLimit(req.Limit))
+	if len(results) == 0 {
+		err := errors.New("no candidate found for the current code")
+		blockTask(task, TaskStageErrorNoCandidate, err.Error(), s.now().UTC())
+		_ = s.store.Update(ctx, task)
+		return task, err
 	}
 
-	results, err := s.tracker.Search(code, searchOptions...)
+	result, errorCode, err := s.selectCandidate(ctx, code, results)
 	if err != nil {
-		blockTask(task, TaskStageErrorSearch, err.Error(), s.now().UTC())
+		blockTask(task, errorCode, err.Error(), s.now().UTC())
 		_ = s.store.Update(ctx, task)
-		logging.Errorf("taskruntime: search failed for code %q: %v", code, err)
-		return task, fmt.Errorf("search torrents: %w", err)
+		logging.Errorf("taskruntime: select candidate failed for code %q: %v", code, err)
+		return task, err
+	}
+	return s.downloadCandidate(ctx, task, result, req)
+}
+
+// SourceBlockedTask retries a blocked SOURCING task with results found outside
+// a Jackett search, such as a Torznab feed item. The task is left untouched
+// when none of the results passes candidate selection.
+func (s *Service) SourceBlockedTask(ctx context.Context, id string, results []jackett.SearchResult) (*Task, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("taskruntime: task id is required")
+	}
+	unlock := s.lockTask(id)
+	defer unlock()
+	task, err := s.store.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("taskruntime: task %q not found", id)
+	}
+	if task.Stage != TaskStageSourcing || task.StageStatus != TaskStageStatusBlocked {
+		return nil, fmt.Errorf("taskruntime: task %q is not a blocked sourcing task", id)
 	}
-	logging.Infof("taskruntime: search returned %d results for code %q", len(results), code)
 	if len(results) == 0 {
-		err = errors.New("no candidate found for the current code")
-		blockTask(task, TaskStageErrorNoCandidate, err.Error(), s.now().UTC())
-		_ = s.store.Update(ctx, task)
+		return task, errors.New("no candidate found for the current code")
+	}
+
+	result, _, err := s.selectCandidate(ctx, task.Code, results)
+	if err != nil {
 		return task, err
 	}
+	next := cloneTask(task)
+	return s.downloadCandidate(ctx, next, result, DownloadRequest{
+		Source:   next.Source,
+		Code:     next.Code,
+		SavePath: next.SavePath,
+		Category: next.Category,
+		Tags:     next.Tags,
+	})
+}
 
+// selectCandidate runs the candidate selector and, on failure, reports the
+// stage error code a blocked task should carry.
+func (s *Service) selectCandidate(ctx context.Context, code string, results []jackett.SearchResult) (jackett.SearchResult, string, error) {
 	selectionConfig := config.DefaultCandidateSelectionConfig()
 	if s.candidateSelection != nil {
 		selectionConfig = s.candidateSelection().Effective()
@@ -664,12 +787,13 @@ func (s *Service) runSourcingFlow(ctx context.Context, task *Task, req DownloadR
 		if strings.Contains(err.Error(), "no downloadable torrent candidate found") {
 			errorCode = TaskStageErrorNoDownloadCandidate
 		}
-		blockTask(task, errorCode, err.Error(), s.now().UTC())
-		_ = s.store.Update(ctx, task)
-		logging.Errorf("taskruntime: select candidate failed for code %q: %v", code, err)
-		return task, err
+		return jackett.SearchResult{}, errorCode, err
 	}
+	return result, "", nil
+}
 
+func (s *Service) downloadCandidate(ctx context.Context, task *Task, result jackett.SearchResult, req DownloadRequest) (*Task, error) {
+	code := strings.TrimSpace(task.Code)
 	candidate := candidateFromSearchResult(result)
 	torrentURL := preferredTorrentURL(result)
 	identity := torrentIdentityFromCandidate(candidate, torrentURL)
@@ -697,6 +821,24 @@ func (s *Service) runSourcingFlow(ctx context.Context, task *Task, req DownloadR
 	return task, nil
 }
 
+// searchCode tries the code family's search queries in order and returns the
+// results of the first query that finds anything.
+func (s *Service) searchCode(code string, options ...tracker.SearchOption) ([]jackett.SearchResult, error) {
+	var results []jackett.SearchResult
+	for _, query := range codeparser.SearchQueries(code) {
+		var err error
+		results, err = s.tracker.Search(query, options...)
+		if err != nil {
+			return nil, err
+		}
+		logging.Infof("taskruntime: search returned %d results for code %q query %q", len(results), code, query)
+		if len(results) > 0 {
+			return results, nil
+		}
+	}
+	return results, nil
+}
+
 func (s *Service) AddTorrentContext(ctx context.Context, req AddTorrentRequest) (*Task, error) {
 	torrentURL := strings.TrimSpace(req.URL)
 	if torrentURL == "" {
@@ -749,6 +891,15 @@ func (s *Service) AddTorrentContext(ctx context.Context, req AddTorrentRequest)
 }
 
 func (s *Service) submitTaskTorrent(ctx context.Context, task *Task, torrentURL string, savePath string, category string, tags string, paused *bool) error {
+	savePath, err := s.chooseSavePath(task, savePath)
+	if err != nil {
+		blockTask(task, TaskStageErrorInsufficientDisk, err.Error(), s.now().UTC())
+		_ = s.store.Update(ctx, task)
+		logging.Warnf("taskruntime: task %s not submitted: %v", task.ID, err)
+		return err
+	}
+	task.SavePath = savePath
+
 	addOptions := qbittorrent.AddTorrentOptions{URLs: []string{torrentURL}}
 	if savePath != "" {
 		addOptions.SavePath = &savePath
@@ -1066,6 +1217,7 @@ func cloneTask(task *Task) *Task {
 	cp := *task
 	cp.DownloadCompletedAt = cloneTime(task.DownloadCompletedAt)
 	cp.StashScanStartedAt = cloneTime(task.StashScanStartedAt)
+	cp.MediaProbe = cloneMediaProbe(task.MediaProbe)
 	refreshTaskStageFields(&cp)
 	return &cp
 }
@@ -25,6 +25,16 @@ func (f fakeTracker) Search(_ string, _ ...tracker.SearchOption) ([]jackett.Sear
 	return f.results, f.err
 }
 
+type queryTracker struct {
+	results map[string][]jackett.SearchResult
+	queries []string
+}
+
+func (f *queryTracker) Search(query string, _ ...tracker.SearchOption) ([]jackett.SearchResult, error) {
+	f.queries = append(f.queries, query)
+	return f.results[query], nil
+}
+
 type fakeTorrentAdder struct {
 	options      qbittorrent.AddTorrentOptions
 	torrents     []qbittorrent.Torrent
@@ -113,6 +123,31 @@ func TestDownloadMediaContextAddsBestTorrent(t *testing.T) {
 	}
 }
 
+func TestDownloadMediaContextFallsBackToFamilySearchQueries(t *testing.T) {
+	qbt := &fakeTorrentAdder{}
+	tr := &queryTracker{results: map[string][]jackett.SearchResult{
+		"FC2PPV-1234567": {{Title: "FC2PPV-1234567", MagnetURI: "magnet:?xt=urn:btih:fc2", Seeders: 3}},
+	}}
+	service, err := NewService(tr, qbt, NewMemoryTaskStore())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	task, err := service.DownloadMediaContext(context.Background(), DownloadRequest{Code: "fc2 ppv 1234567"})
+	if err != nil {
+		t.Fatalf("DownloadMediaContext failed: %v", err)
+	}
+	if task.Code != "FC2-PPV-1234567" {
+		t.Fatalf("expected normalized FC2 code, got %q", task.Code)
+	}
+	if want := []string{"FC2-PPV-1234567", "FC2PPV-1234567"}; strings.Join(tr.queries, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected search queries: %v", tr.queries)
+	}
+	if task.Stage != TaskStageDownloading {
+		t.Fatalf("expected task to reach DOWNLOADING, got %s", task.Stage)
+	}
+}
+
 func TestResolveBlockedSourcingTaskUsesSelectedTorrentOnExistingTask(t *testing.T) {
 	qbt := &fakeTorrentAdder{}
 	store := NewMemoryTaskStore()
@@ -172,6 +207,41 @@ func TestResolveBlockedSourcingTaskRejectsOtherStages(t *testing.T) {
 	}
 }
 
+func TestSourceBlockedTaskUsesFeedResultsWithoutSearching(t *testing.T) {
+	qbt := &fakeTorrentAdder{}
+	store := NewMemoryTaskStore()
+	blocked := &Task{
+		ID: "task-blocked", Source: TaskSourceSubscription, Code: "SONE-786",
+		Stage: TaskStageSourcing, StageStatus: TaskStageStatusBlocked,
+		StageErrorCode: TaskStageErrorNoCandidate, StageErrorMessage: "no candidate",
+		SavePath: "/downloads", CreatedAt: time.Unix(100, 0).UTC(), UpdatedAt: time.Unix(100, 0).UTC(),
+	}
+	if err := store.Create(context.Background(), blocked); err != nil {
+		t.Fatalf("create blocked task: %v", err)
+	}
+	service, err := NewService(fakeTracker{err: errors.New("search must not run")}, qbt, store, WithClock(func() time.Time { return time.Unix(200, 0) }))
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+
+	task, err := service.SourceBlockedTask(context.Background(), blocked.ID, []jackett.SearchResult{
+		{Title: "SONE-786 feed", MagnetURI: "magnet:?xt=urn:btih:feed786", Seeders: 4, Size: 10},
+	})
+	if err != nil {
+		t.Fatalf("SourceBlockedTask failed: %v", err)
+	}
+	if task.Stage != TaskStageDownloading || task.StageErrorCode != "" || task.Candidate.Title != "SONE-786 feed" {
+		t.Fatalf("unexpected sourced task: %+v", task)
+	}
+	if len(qbt.options.URLs) != 1 || qbt.options.URLs[0] != "magnet:?xt=urn:btih:feed786" {
+		t.Fatalf("unexpected qBittorrent options: %+v", qbt.options)
+	}
+
+	if _, err := service.SourceBlockedTask(context.Background(), blocked.ID, nil); err == nil {
+		t.Fatal("expected a task that is no longer blocked to be rejected")
+	}
+}
+
 func TestDownloadMediaContextRecordsAddFailure(t *testing.T) {
 	qbtErr := errors.New("qbt rejected torrent")
 	service, err := NewService(
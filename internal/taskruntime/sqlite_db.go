@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,7 +18,22 @@ import (
 //go:embed sqlite_schema.sql
 var sqliteSchema string
 
-const sqliteSchemaVersion = "7"
+const sqliteSchemaVersion = "12"
+
+// sqliteMigrations upgrade a schema in place, keyed by the version they
+// upgrade from. Versions without a migration are reset.
+var sqliteMigrations = map[string]func(*sqlx.DB) error{
+	"7": migrateSQLiteAddSubtitlingStage,
+	"8": migrateSQLiteAlter(9, `ALTER TABLE tasks ADD COLUMN media_probe TEXT`),
+	"9": migrateSQLiteAlter(10, `ALTER TABLE tasks ADD COLUMN stash_identify_job_id TEXT`),
+	"10": migrateSQLiteAlter(11,
+		`ALTER TABLE tasks ADD COLUMN stash_scene_id TEXT`,
+		`ALTER TABLE tasks ADD COLUMN origin_stash_box_endpoint TEXT`,
+		`ALTER TABLE tasks ADD COLUMN origin_stash_box_scene_id TEXT`,
+		`ALTER TABLE tasks ADD COLUMN origin_stash_performer_id TEXT`,
+	),
+	"11": migrateSQLiteAlter(12, `ALTER TABLE tasks ADD COLUMN library_route TEXT`),
+}
 
 func OpenSQLiteDatabase(path string) (*sqlx.DB, error) {
 	trimmed := strings.TrimSpace(path)
@@ -70,6 +86,19 @@ func configureSQLiteDatabase(db *sqlx.DB) error {
 		return err
 	}
 
+	for hadSchema && versionBeforeInit != sqliteSchemaVersion {
+		migrate, ok := sqliteMigrations[versionBeforeInit]
+		if !ok {
+			break
+		}
+		if err := migrate(db); err != nil {
+			return err
+		}
+		if versionBeforeInit, err = readSQLiteSchemaVersion(db); err != nil {
+			return err
+		}
+	}
+
 	if !hadSchema || versionBeforeInit == sqliteSchemaVersion {
 		if _, err := db.Exec(sqliteSchema); err != nil {
 			return fmt.Errorf("taskruntime: initialize sqlite schema: %w", err)
@@ -89,6 +118,77 @@ func configureSQLiteDatabase(db *sqlx.DB) error {
 	return nil
 }
 
+// migrateSQLiteAddSubtitlingStage rebuilds the tasks table so its stage
+// CHECK constraint admits SUBTITLING. SQLite cannot alter a constraint, so the
+// table is copied under its original definition with only the list changed.
+func migrateSQLiteAddSubtitlingStage(db *sqlx.DB) error {
+	const (
+		previous = "'TRANSFERRING', 'SCANNING'"
+		next     = "'TRANSFERRING', 'SUBTITLING', 'SCANNING'"
+	)
+	var definition string
+	if err := db.Get(&definition, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'tasks'`); err != nil {
+		return fmt.Errorf("taskruntime: read sqlite tasks definition: %w", err)
+	}
+	if !strings.Contains(definition, previous) || !strings.HasPrefix(definition, "CREATE TABLE tasks") {
+		return fmt.Errorf("taskruntime: unexpected sqlite tasks definition for schema 7")
+	}
+	definition = "CREATE TABLE tasks_migrating" + strings.TrimPrefix(strings.Replace(definition, previous, next, 1), "CREATE TABLE tasks")
+
+	// Foreign keys must be off outside the transaction, or dropping tasks
+	// would cascade into task_events.
+	if _, err := db.Exec(`PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("taskruntime: disable sqlite foreign keys: %w", err)
+	}
+	defer db.Exec(`PRAGMA foreign_keys = ON`)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("taskruntime: begin sqlite schema 8 migration: %w", err)
+	}
+	defer tx.Rollback()
+	statements := []string{
+		definition,
+		`INSERT INTO tasks_migrating SELECT * FROM tasks`,
+		`DROP TABLE tasks`,
+		`ALTER TABLE tasks_migrating RENAME TO tasks`,
+		`UPDATE task_store_meta SET value = '8' WHERE key = 'schema_version'`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("taskruntime: migrate sqlite schema 8: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("taskruntime: commit sqlite schema 8 migration: %w", err)
+	}
+	return nil
+}
+
+// migrateSQLiteAlter runs statements and records version in one transaction,
+// for migrations that only add columns.
+func migrateSQLiteAlter(version int, statements ...string) func(*sqlx.DB) error {
+	return func(db *sqlx.DB) error {
+		tx, err := db.Beginx()
+		if err != nil {
+			return fmt.Errorf("taskruntime: begin sqlite schema %d migration: %w", version, err)
+		}
+		defer tx.Rollback()
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement); err != nil {
+				return fmt.Errorf("taskruntime: migrate sqlite schema %d: %w", version, err)
+			}
+		}
+		if _, err := tx.Exec(`UPDATE task_store_meta SET value = ? WHERE key = 'schema_version'`, strconv.Itoa(version)); err != nil {
+			return fmt.Errorf("taskruntime: migrate sqlite schema %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("taskruntime: commit sqlite schema %d migration: %w", version, err)
+		}
+		return nil
+	}
+}
+
 func resetSQLiteDatabase(db *sqlx.DB) error {
 	tx, err := db.Beginx()
 	if err != nil {
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -92,8 +94,8 @@ INSERT INTO task_store_meta (key, value) VALUES ('schema_version', '1');
 	if err != nil {
 		t.Fatalf("read schema version: %v", err)
 	}
-	if version != "7" {
-		t.Fatalf("expected schema version 7, got %q", version)
+	if version != "12" {
+		t.Fatalf("expected schema version 12, got %q", version)
 	}
 }
 
@@ -109,8 +111,8 @@ func TestOpenSQLiteDatabaseInitializesNewSchemaVersion(t *testing.T) {
 	if err != nil {
 		t.Fatalf("read schema version: %v", err)
 	}
-	if version != "7" {
-		t.Fatalf("expected schema version 7, got %q", version)
+	if version != "12" {
+		t.Fatalf("expected schema version 12, got %q", version)
 	}
 	for _, table := range []string{
 		"task_store_meta",
@@ -144,6 +146,58 @@ func TestOpenSQLiteDatabaseInitializesNewSchemaVersion(t *testing.T) {
 	}
 }
 
+func TestOpenSQLiteDatabaseMigratesSchema7KeepingTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v7.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	schema7 := strings.NewReplacer(
+		"'TRANSFERRING', 'SUBTITLING', 'SCANNING'", "'TRANSFERRING', 'SCANNING'",
+		"  stash_identify_job_id TEXT,\n", "",
+		"  stash_scene_id TEXT,\n  origin_stash_box_endpoint TEXT,\n  origin_stash_box_scene_id TEXT,\n  origin_stash_performer_id TEXT,\n", "",
+		"  library_route TEXT,\n", "",
+		"  media_probe TEXT,\n", "",
+		"VALUES ('schema_version', '12')", "VALUES ('schema_version', '7')",
+	).Replace(sqliteSchema)
+	if _, err := db.Exec(schema7); err != nil {
+		t.Fatalf("create schema 7: %v", err)
+	}
+	now := time.Unix(300, 0).UTC().Format(time.RFC3339Nano)
+	if _, err := db.Exec(`INSERT INTO tasks (id, code, stage, stage_status, created_at, updated_at) VALUES ('kept', 'ABC-123', 'TRANSFERRING', 'RUNNING', ?, ?)`, now, now); err != nil {
+		t.Fatalf("insert task: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO task_events (task_id, event_type, message, created_at) VALUES ('kept', 'stage', 'moved', ?)`, now); err != nil {
+		t.Fatalf("insert task event: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := NewSQLiteTaskStore(path)
+	if err != nil {
+		t.Fatalf("open migrated store: %v", err)
+	}
+	defer store.db.Close()
+	task, err := store.Find(context.Background(), "kept")
+	if err != nil || task == nil || task.Code != "ABC-123" {
+		t.Fatalf("expected task to survive migration, got %+v err %v", task, err)
+	}
+	task.Stage = TaskStageSubtitling
+	task.MediaProbe = &MediaProbe{Path: "/library/ABC-123.mp4", Container: "mov", DurationSeconds: 7200, Width: 1920, Height: 1080, Warnings: []MediaWarning{MediaWarningLowBitRate}, ProbedAt: time.Unix(400, 0).UTC()}
+	if err := store.Update(context.Background(), task); err != nil {
+		t.Fatalf("expected SUBTITLING to be accepted after migration: %v", err)
+	}
+	if reloaded, err := store.Find(context.Background(), "kept"); err != nil || reloaded.MediaProbe == nil || !reflect.DeepEqual(reloaded.MediaProbe, task.MediaProbe) {
+		t.Fatalf("expected media probe to round-trip, got %+v err %v", reloaded, err)
+	}
+	var events int
+	if err := store.db.Get(&events, `SELECT COUNT(*) FROM task_events WHERE task_id = 'kept'`); err != nil || events == 0 {
+		t.Fatalf("expected task events to survive migration, got %d err %v", events, err)
+	}
+	if version, err := readSQLiteSchemaVersion(store.db); err != nil || version != sqliteSchemaVersion {
+		t.Fatalf("expected schema version %s, got %q err %v", sqliteSchemaVersion, version, err)
+	}
+}
+
 func TestSQLiteTaskStoreRejectsDuplicateBusinessKeys(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "tasks.db")
 	store, err := NewSQLiteTaskStore(path)
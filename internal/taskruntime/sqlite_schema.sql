@@ -9,7 +9,7 @@ CREATE TABLE IF NOT EXISTS tasks (
   id TEXT PRIMARY KEY,
   source TEXT NOT NULL DEFAULT 'MANUAL' CHECK (source IN ('MANUAL', 'SEARCH', 'SUBSCRIPTION')),
   code TEXT NOT NULL DEFAULT '',
-  stage TEXT NOT NULL CHECK (stage IN ('SOURCING', 'DOWNLOADING', 'PENDING_INGEST', 'TRANSFERRING', 'SCANNING', 'COMPLETED')),
+  stage TEXT NOT NULL CHECK (stage IN ('SOURCING', 'DOWNLOADING', 'PENDING_INGEST', 'TRANSFERRING', 'SUBTITLING', 'SCANNING', 'COMPLETED')),
   stage_status TEXT NOT NULL DEFAULT 'PENDING' CHECK (stage_status IN ('PENDING', 'RUNNING', 'BLOCKED', 'DONE')),
   stage_error_code TEXT,
   stage_error_message TEXT,
@@ -38,6 +38,13 @@ CREATE TABLE IF NOT EXISTS tasks (
   stash_scan_error TEXT,
   stash_scan_hint TEXT,
   stash_scan_started_at TEXT,
+  stash_identify_job_id TEXT,
+  stash_scene_id TEXT,
+  origin_stash_box_endpoint TEXT,
+  origin_stash_box_scene_id TEXT,
+  origin_stash_performer_id TEXT,
+  library_route TEXT,
+  media_probe TEXT,
 
   selected_title TEXT NOT NULL DEFAULT '',
   selected_tracker TEXT NOT NULL DEFAULT '',
@@ -93,5 +100,5 @@ CREATE INDEX IF NOT EXISTS idx_task_events_created_at
   ON task_events (created_at DESC);
 
 INSERT INTO task_store_meta (key, value)
-VALUES ('schema_version', '7')
+VALUES ('schema_version', '12')
 ON CONFLICT(key) DO UPDATE SET value = excluded.value;
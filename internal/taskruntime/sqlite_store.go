@@ -3,6 +3,7 @@ package taskruntime
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -222,6 +223,13 @@ SELECT
   stash_scan_error,
   stash_scan_hint,
   stash_scan_started_at,
+  stash_identify_job_id,
+  stash_scene_id,
+  origin_stash_box_endpoint,
+  origin_stash_box_scene_id,
+  origin_stash_performer_id,
+  library_route,
+  media_probe,
   selected_title,
   selected_tracker,
   selected_info_hash,
@@ -264,6 +272,13 @@ type sqliteTaskRow struct {
 	StashScanError        sql.NullString `db:"stash_scan_error"`
 	StashScanHint         sql.NullString `db:"stash_scan_hint"`
 	StashScanStartedAt    sql.NullString `db:"stash_scan_started_at"`
+	StashIdentifyJobID    sql.NullString `db:"stash_identify_job_id"`
+	StashSceneID          sql.NullString `db:"stash_scene_id"`
+	OriginEndpoint        sql.NullString `db:"origin_stash_box_endpoint"`
+	OriginSceneID         sql.NullString `db:"origin_stash_box_scene_id"`
+	OriginPerformerID     sql.NullString `db:"origin_stash_performer_id"`
+	LibraryRoute          sql.NullString `db:"library_route"`
+	MediaProbe            sql.NullString `db:"media_probe"`
 	SelectedTitle         string         `db:"selected_title"`
 	SelectedTracker       string         `db:"selected_tracker"`
 	SelectedInfoHash      string         `db:"selected_info_hash"`
@@ -305,6 +320,14 @@ func (r sqliteTaskRow) toTask() (*Task, error) {
 		StashScanPath:         nullableStringValue(r.StashScanPath),
 		StashScanError:        nullableStringValue(r.StashScanError),
 		StashScanHint:         nullableStringValue(r.StashScanHint),
+		StashIdentifyJobID:    nullableStringValue(r.StashIdentifyJobID),
+		StashSceneID:          nullableStringValue(r.StashSceneID),
+		Origin: TaskOrigin{
+			StashBoxEndpoint: nullableStringValue(r.OriginEndpoint),
+			StashBoxSceneID:  nullableStringValue(r.OriginSceneID),
+			StashPerformerID: nullableStringValue(r.OriginPerformerID),
+		},
+		LibraryRoute: nullableStringValue(r.LibraryRoute),
 		Candidate: Candidate{
 			Title:     r.SelectedTitle,
 			Tracker:   r.SelectedTracker,
@@ -327,6 +350,9 @@ func (r sqliteTaskRow) toTask() (*Task, error) {
 	if task.StashScanStartedAt, err = parseOptionalSQLiteTimestamp(r.StashScanStartedAt); err != nil {
 		return nil, fmt.Errorf("taskruntime: parse stash_scan_started_at for task %q: %w", task.ID, err)
 	}
+	if task.MediaProbe, err = parseSQLiteMediaProbe(r.MediaProbe); err != nil {
+		return nil, fmt.Errorf("taskruntime: parse media_probe for task %q: %w", task.ID, err)
+	}
 	if task.CreatedAt, err = parseSQLiteTimestamp(r.CreatedAt); err != nil {
 		return nil, fmt.Errorf("taskruntime: parse created_at for task %q: %w", task.ID, err)
 	}
@@ -367,6 +393,13 @@ type sqliteTaskParams struct {
 	StashScanError        any     `db:"stash_scan_error"`
 	StashScanHint         any     `db:"stash_scan_hint"`
 	StashScanStartedAt    any     `db:"stash_scan_started_at"`
+	StashIdentifyJobID    any     `db:"stash_identify_job_id"`
+	StashSceneID          any     `db:"stash_scene_id"`
+	OriginEndpoint        any     `db:"origin_stash_box_endpoint"`
+	OriginSceneID         any     `db:"origin_stash_box_scene_id"`
+	OriginPerformerID     any     `db:"origin_stash_performer_id"`
+	LibraryRoute          any     `db:"library_route"`
+	MediaProbe            any     `db:"media_probe"`
 	SelectedTitle         string  `db:"selected_title"`
 	SelectedTracker       string  `db:"selected_tracker"`
 	SelectedInfoHash      string  `db:"selected_info_hash"`
@@ -415,6 +448,13 @@ func taskToSQLiteParams(task *Task) sqliteTaskParams {
 		StashScanError:        nullableStringParam(task.StashScanError),
 		StashScanHint:         nullableStringParam(task.StashScanHint),
 		StashScanStartedAt:    formatOptionalSQLiteTimestamp(task.StashScanStartedAt),
+		StashIdentifyJobID:    nullableStringParam(task.StashIdentifyJobID),
+		StashSceneID:          nullableStringParam(task.StashSceneID),
+		OriginEndpoint:        nullableStringParam(task.Origin.StashBoxEndpoint),
+		OriginSceneID:         nullableStringParam(task.Origin.StashBoxSceneID),
+		OriginPerformerID:     nullableStringParam(task.Origin.StashPerformerID),
+		LibraryRoute:          nullableStringParam(task.LibraryRoute),
+		MediaProbe:            formatSQLiteMediaProbe(task.MediaProbe),
 		SelectedTitle:         task.Candidate.Title,
 		SelectedTracker:       task.Candidate.Tracker,
 		SelectedInfoHash:      task.Candidate.InfoHash,
@@ -436,14 +476,16 @@ INSERT INTO tasks (
   torrent_identity_hash, torrent_identity_magnet, torrent_hash, torrent_name, progress, qbittorrent_state, content_path,
   download_completed_at, delivery_mode, moji_source_path, transfer_action, moji_transfer_path,
   transfer_error, stash_scan_job_id, stash_scan_path, stash_scan_error, stash_scan_hint,
-  stash_scan_started_at, selected_title, selected_tracker, selected_info_hash, selected_link, selected_magnet_uri,
+  stash_scan_started_at, stash_identify_job_id, stash_scene_id, origin_stash_box_endpoint, origin_stash_box_scene_id,
+  origin_stash_performer_id, library_route, media_probe, selected_title, selected_tracker, selected_info_hash, selected_link, selected_magnet_uri,
   selected_size, selected_seeders, selected_peers, created_at, updated_at
 ) VALUES (
   :id, :source, :code, :stage, :stage_status, :stage_error_code, :stage_error_message, :torrent_url, :save_path, :category, :tags,
   :torrent_identity_hash, :torrent_identity_magnet, :torrent_hash, :torrent_name, :progress, :qbittorrent_state, :content_path,
   :download_completed_at, :delivery_mode, :moji_source_path, :transfer_action, :moji_transfer_path,
   :transfer_error, :stash_scan_job_id, :stash_scan_path, :stash_scan_error, :stash_scan_hint,
-  :stash_scan_started_at, :selected_title, :selected_tracker, :selected_info_hash, :selected_link, :selected_magnet_uri,
+  :stash_scan_started_at, :stash_identify_job_id, :stash_scene_id, :origin_stash_box_endpoint, :origin_stash_box_scene_id,
+  :origin_stash_performer_id, :library_route, :media_probe, :selected_title, :selected_tracker, :selected_info_hash, :selected_link, :selected_magnet_uri,
   :selected_size, :selected_seeders, :selected_peers, :created_at, :updated_at
 )`
 	if isUpdate {
@@ -477,6 +519,13 @@ ON CONFLICT(id) DO UPDATE SET
   stash_scan_error = excluded.stash_scan_error,
   stash_scan_hint = excluded.stash_scan_hint,
   stash_scan_started_at = excluded.stash_scan_started_at,
+  stash_identify_job_id = excluded.stash_identify_job_id,
+  stash_scene_id = excluded.stash_scene_id,
+  origin_stash_box_endpoint = excluded.origin_stash_box_endpoint,
+  origin_stash_box_scene_id = excluded.origin_stash_box_scene_id,
+  origin_stash_performer_id = excluded.origin_stash_performer_id,
+  library_route = excluded.library_route,
+  media_probe = excluded.media_probe,
   selected_title = excluded.selected_title,
   selected_tracker = excluded.selected_tracker,
   selected_info_hash = excluded.selected_info_hash,
@@ -548,6 +597,29 @@ func nullableStringParam(value string) any {
 	return trimmed
 }
 
+// The media probe is stored as a JSON document; nothing queries its fields.
+func formatSQLiteMediaProbe(probe *MediaProbe) any {
+	if probe == nil {
+		return nil
+	}
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+func parseSQLiteMediaProbe(raw sql.NullString) (*MediaProbe, error) {
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return nil, nil
+	}
+	var probe MediaProbe
+	if err := json.Unmarshal([]byte(raw.String), &probe); err != nil {
+		return nil, err
+	}
+	return &probe, nil
+}
+
 func nullableStringValue(value sql.NullString) string {
 	if !value.Valid {
 		return ""
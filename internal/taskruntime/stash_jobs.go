@@ -0,0 +1,198 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+const (
+	stashJobWatchRetryMin = 5 * time.Second
+	stashJobWatchRetryMax = 2 * time.Minute
+	// maxFinishedStashJobs bounds the finished jobs kept for tasks that were
+	// not yet waiting on them, e.g. other Stash jobs or a scan reported
+	// before its task was saved.
+	maxFinishedStashJobs = 64
+	// stashJobSafetyPoll is how long a SCANNING task waits for the
+	// subscription before FindJob is polled anyway, in case its finish event
+	// was lost during a reconnect or pushed out of the finished-job ring.
+	stashJobSafetyPoll = 5 * time.Minute
+)
+
+// StashJobWatcher is a StashScanner that can also stream job updates. ready
+// is called once the subscription is live; WatchJobs returns when it drops.
+type StashJobWatcher interface {
+	StashScanner
+	WatchJobs(ctx context.Context, ready func(), handle func(stashsync.Job)) error
+}
+
+// WatchStashJobs keeps a Stash job subscription open until ctx is done and
+// advances SCANNING tasks as soon as their job finishes. While the
+// subscription is live TriggerStashScans only polls FindJob for tasks that
+// have waited longer than stashJobSafetyPoll; whenever it drops polling takes
+// over again until it reconnects.
+func (s *Service) WatchStashJobs(ctx context.Context, watcher StashJobWatcher) {
+	if watcher == nil {
+		return
+	}
+	finished := make(chan struct{}, 1)
+	go s.applyFinishedStashJobs(ctx, watcher, finished)
+	retry := stashJobWatchRetryMin
+	for {
+		err := watcher.WatchJobs(ctx, func() {
+			// Catch up on jobs that finished while nothing was listening
+			// before handing over from polling.
+			if err := s.syncScanningTasks(ctx, watcher, func(*Task) bool { return true }); err != nil {
+				logging.Warnf("taskruntime: sync scanning tasks before watching stash jobs: %v", err)
+			}
+			s.stashJobsLive.Store(true)
+			retry = stashJobWatchRetryMin
+			logging.Infof("taskruntime: watching stash jobs")
+		}, func(job stashsync.Job) {
+			if !stashJobDone(job.Status) {
+				return
+			}
+			s.rememberFinishedStashJob(job)
+			select {
+			case finished <- struct{}{}:
+			default:
+			}
+		})
+		wasLive := s.stashJobsLive.Swap(false)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, stashsync.ErrJobSubscriptionUnsupported) {
+			logging.Infof("taskruntime: stash job subscription unavailable, polling stash jobs")
+			return
+		}
+		if wasLive {
+			logging.Warnf("taskruntime: stash job subscription dropped, polling until it reconnects: %v", err)
+		} else {
+			logging.Warnf("taskruntime: stash job subscription failed, retrying in %s: %v", retry, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		if !wasLive {
+			retry = min(retry*2, stashJobWatchRetryMax)
+		}
+	}
+}
+
+// applyFinishedStashJobs advances the tasks waiting on the jobs the
+// subscription reported finished. It runs apart from the subscription so the
+// Stash calls made while completing a task do not hold up reading updates.
+func (s *Service) applyFinishedStashJobs(ctx context.Context, scanner StashScanner, finished <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-finished:
+		}
+		err := s.syncScanningTasks(ctx, scanner, func(task *Task) bool {
+			return s.hasFinishedStashJob(activeStashJobID(task))
+		})
+		if err != nil {
+			logging.Errorf("taskruntime: apply finished stash jobs: %v", err)
+		}
+	}
+}
+
+// syncScanningTasks runs syncStashScanJob for the SCANNING tasks accepted
+// by match and persists the ones that changed.
+func (s *Service) syncScanningTasks(ctx context.Context, scanner StashScanner, match func(*Task) bool) error {
+	tasks, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, task := range tasks {
+		if !isScanningTask(task) || !match(task) {
+			continue
+		}
+		unlock := s.lockTask(task.ID)
+		latest, findErr := s.store.Find(ctx, task.ID)
+		if findErr != nil || !isScanningTask(latest) || !match(latest) {
+			unlock()
+			continue
+		}
+		next, syncErr := s.syncStashScanJob(ctx, cloneTask(latest), scanner)
+		if next != nil {
+			if persistErr := s.store.Update(ctx, next); persistErr != nil && firstErr == nil {
+				firstErr = persistErr
+			}
+		}
+		if syncErr != nil && firstErr == nil {
+			firstErr = syncErr
+		}
+		unlock()
+	}
+	return firstErr
+}
+
+func (s *Service) rememberFinishedStashJob(job stashsync.Job) {
+	s.stashJobsMu.Lock()
+	defer s.stashJobsMu.Unlock()
+	for i := range s.finishedStashJobs {
+		if s.finishedStashJobs[i].ID == job.ID {
+			s.finishedStashJobs[i] = job
+			return
+		}
+	}
+	if len(s.finishedStashJobs) >= maxFinishedStashJobs {
+		s.finishedStashJobs = s.finishedStashJobs[1:]
+	}
+	s.finishedStashJobs = append(s.finishedStashJobs, job)
+}
+
+func (s *Service) hasFinishedStashJob(id string) bool {
+	s.stashJobsMu.Lock()
+	defer s.stashJobsMu.Unlock()
+	for _, job := range s.finishedStashJobs {
+		if job.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// takeFinishedStashJob returns and forgets a finished job reported by the
+// subscription.
+func (s *Service) takeFinishedStashJob(id string) (*stashsync.Job, bool) {
+	s.stashJobsMu.Lock()
+	defer s.stashJobsMu.Unlock()
+	for i, job := range s.finishedStashJobs {
+		if job.ID == id {
+			s.finishedStashJobs = append(s.finishedStashJobs[:i], s.finishedStashJobs[i+1:]...)
+			return &job, true
+		}
+	}
+	return nil, false
+}
+
+// activeStashJobID is the Stash job a SCANNING task is waiting on.
+func activeStashJobID(task *Task) string {
+	if id := strings.TrimSpace(task.StashIdentifyJobID); id != "" {
+		return id
+	}
+	return strings.TrimSpace(task.StashScanJobID)
+}
+
+func isScanningTask(task *Task) bool {
+	return task != nil && task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning
+}
+
+func stashJobDone(status string) bool {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "FINISHED", "FAILED", "CANCELLED":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+func TestWatchStashJobsCompletesTasksFromSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryTaskStore()
+	for _, task := range []*Task{
+		{ID: "task-caught-up", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-done", StashScanPath: "/library/A"},
+		{ID: "task-live", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-live", StashScanPath: "/library/B"},
+	} {
+		if err := store.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobs: map[string]*stashsync.Job{
+		"job-done": {ID: "job-done", Status: "FINISHED"},
+		"job-live": {ID: "job-live", Status: "RUNNING"},
+	}}
+	stage := func(id string) TaskStage {
+		task, _ := service.FindTask(context.Background(), id)
+		return task.Stage
+	}
+
+	watcher := &fakeStashJobWatcher{fakeStashScanner: scanner, run: func(ready func(), handle func(stashsync.Job)) error {
+		ready()
+		if stage("task-caught-up") != TaskStageCompleted || stage("task-live") != TaskStageScanning {
+			t.Fatalf("expected ready to catch up on finished jobs only")
+		}
+
+		// While the subscription is live the sync tick must not poll.
+		scanner.jobErr = errors.New("polled while subscribed")
+		if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+			t.Fatalf("TriggerStashScans polled while subscribed: %v", err)
+		}
+
+		handle(stashsync.Job{ID: "job-live", Status: "RUNNING"})
+		if stage("task-live") != TaskStageScanning {
+			t.Fatalf("progress updates must not complete the task")
+		}
+		handle(stashsync.Job{ID: "job-live", Status: "FINISHED"})
+		deadline := time.Now().Add(5 * time.Second)
+		for stage("task-live") != TaskStageCompleted {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the finished job to complete the task")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+		return ctx.Err()
+	}}
+
+	service.WatchStashJobs(ctx, watcher)
+	if service.stashJobsLive.Load() {
+		t.Fatal("expected polling to resume once the subscription ends")
+	}
+}
+
+func TestFinishedStashJobWaitsForItsTask(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobErr: errors.New("polled while subscribed")}
+	service.stashJobsLive.Store(true)
+
+	// The job finished before the task recording it was saved.
+	service.rememberFinishedStashJob(stashsync.Job{ID: "job-fast", Status: "FINISHED"})
+	if err := store.Create(ctx, &Task{ID: "task-fast", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-fast", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ := service.FindTask(ctx, "task-fast"); task.Stage != TaskStageCompleted {
+		t.Fatalf("expected the remembered job to complete the task, got %+v", task)
+	}
+	if _, ok := service.takeFinishedStashJob("job-fast"); ok {
+		t.Fatal("expected the finished job to be consumed")
+	}
+}
+
+func TestLiveSubscriptionStillPollsLongScans(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryTaskStore()
+	for _, task := range []*Task{
+		{ID: "task-recent", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-recent", UpdatedAt: now.Add(-time.Minute)},
+		{ID: "task-stuck", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-lost", UpdatedAt: now.Add(-stashJobSafetyPoll)},
+	} {
+		if err := store.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobs: map[string]*stashsync.Job{
+		"job-recent": {ID: "job-recent", Status: "FINISHED"},
+		"job-lost":   {ID: "job-lost", Status: "FINISHED"},
+	}}
+	service.stashJobsLive.Store(true)
+
+	// The finish event for job-lost never arrived.
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ := service.FindTask(ctx, "task-stuck"); task.Stage != TaskStageCompleted {
+		t.Fatalf("expected the safety poll to complete the stuck task, got %+v", task)
+	}
+	if task, _ := service.FindTask(ctx, "task-recent"); task.Stage != TaskStageScanning {
+		t.Fatalf("expected recent scans to wait for the subscription, got %+v", task)
+	}
+}
+
+type fakeStashJobWatcher struct {
+	*fakeStashScanner
+	run func(ready func(), handle func(stashsync.Job)) error
+}
+
+func (f *fakeStashJobWatcher) WatchJobs(_ context.Context, ready func(), handle func(stashsync.Job)) error {
+	return f.run(ready, handle)
+}
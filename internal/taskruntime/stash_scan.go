@@ -14,6 +14,7 @@ import (
 
 type StashScanner interface {
 	MetadataScan(ctx context.Context, req stashsync.ScanRequest) (string, error)
+	MetadataIdentify(ctx context.Context, req stashsync.IdentifyRequest) (string, error)
 	FindJob(ctx context.Context, id string) (*stashsync.Job, error)
 	CurrentConfig() stashsync.IntegrationConfig
 }
@@ -64,6 +65,11 @@ func (s *Service) TriggerStashScans(ctx context.Context, scanner StashScanner) (
 		task = latest
 		if task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning {
 			next, pollErr := s.syncStashScanJob(ctx, cloneTask(task), scanner)
+			if next == nil {
+				updated = append(updated, task)
+				unlock()
+				continue
+			}
 			if persistErr := s.store.Update(ctx, next); persistErr != nil {
 				logging.Errorf("taskruntime: persist stash scan job state failed for task %s: %v", next.ID, persistErr)
 				if firstErr == nil {
@@ -77,6 +83,26 @@ func (s *Service) TriggerStashScans(ctx context.Context, scanner StashScanner) (
 			unlock()
 			continue
 		}
+		if task.Stage == TaskStageSubtitling && task.StageStatus != TaskStageStatusBlocked {
+			next, subtitleErr := s.advanceTaskSubtitles(ctx, cloneTask(task), scanner)
+			if next == nil {
+				updated = append(updated, task)
+				unlock()
+				continue
+			}
+			if persistErr := s.store.Update(ctx, next); persistErr != nil {
+				logging.Errorf("taskruntime: persist subtitle state failed for task %s: %v", next.ID, persistErr)
+				if firstErr == nil {
+					firstErr = fmt.Errorf("update task %q: %w", next.ID, persistErr)
+				}
+			}
+			if subtitleErr != nil && firstErr == nil {
+				firstErr = subtitleErr
+			}
+			updated = append(updated, next)
+			unlock()
+			continue
+		}
 		if !shouldTriggerStashScan(task) {
 			updated = append(updated, task)
 			unlock()
@@ -118,7 +144,13 @@ func (s *Service) TriggerTaskStashScan(ctx context.Context, id string, scanner S
 		return nil, fmt.Errorf("taskruntime: task %q is not ready for stash scan", id)
 	}
 
-	next, execErr := s.executeTaskStashIntegration(ctx, cloneTask(task), scanner)
+	var next *Task
+	var execErr error
+	if task.Stage == TaskStageSubtitling {
+		next, execErr = s.skipTaskSubtitles(ctx, cloneTask(task), scanner)
+	} else {
+		next, execErr = s.executeTaskStashIntegration(ctx, cloneTask(task), scanner)
+	}
 	if persistErr := s.store.Update(ctx, next); persistErr != nil {
 		logging.Errorf("taskruntime: persist manual stash integration state failed for task %s: %v", next.ID, persistErr)
 		return nil, fmt.Errorf("update task %q: %w", next.ID, persistErr)
@@ -131,6 +163,8 @@ func (s *Service) TriggerTaskStashScan(ctx context.Context, id string, scanner S
 
 func (s *Service) executeTaskStashIntegration(ctx context.Context, task *Task, scanner StashScanner) (*Task, error) {
 	cfg := scanner.CurrentConfig()
+	task.LibraryRoute = s.routeTask(ctx, task, cfg.Routes)
+	cfg = routedConfig(cfg, task)
 	qbSourcePath := resolveSource(task)
 	plan := planDelivery(cfg, qbSourcePath)
 	now := s.now().UTC()
@@ -156,9 +190,20 @@ func (s *Service) executeTaskStashIntegration(ctx context.Context, task *Task, s
 		return task, fmt.Errorf("trigger stash scan for task %q: %w", task.ID, err)
 	}
 
+	s.probeDeliveredMedia(ctx, task, cfg, plan)
+	if path := s.subtitleTargetPath(cfg, plan); path != "" {
+		setTaskStage(task, TaskStageSubtitling, TaskStageStatusPending)
+		task.UpdatedAt = s.now().UTC()
+		logging.Infof("taskruntime: task %s queued for subtitles path=%s", task.ID, path)
+		return task, nil
+	}
+	return s.startStashScan(ctx, task, scanner, plan)
+}
+
+func (s *Service) startStashScan(ctx context.Context, task *Task, scanner StashScanner, plan StashIntegrationPlan) (*Task, error) {
 	setTaskStage(task, TaskStageScanning, TaskStageStatusRunning)
 	jobID, err := s.triggerScan(ctx, scanner, plan)
-	now = s.now().UTC()
+	now := s.now().UTC()
 	task.UpdatedAt = now
 	task.StashScanStartedAt = &now
 	if err != nil {
@@ -175,16 +220,35 @@ func (s *Service) executeTaskStashIntegration(ctx context.Context, task *Task, s
 	return task, nil
 }
 
+// syncStashScanJob checks the task's Stash job: the scan, and once that has
+// finished, the identify job when identify is enabled. The task completes
+// when the last of them finishes. While the job subscription is live only
+// jobs it reported finished are applied, unless the task has waited longer
+// than stashJobSafetyPoll, and nil is returned when there is nothing to
+// apply.
 func (s *Service) syncStashScanJob(ctx context.Context, task *Task, scanner StashScanner) (*Task, error) {
 	if task == nil || strings.TrimSpace(task.StashScanJobID) == "" {
 		return task, nil
 	}
+	jobID := activeStashJobID(task)
+	identifying := strings.TrimSpace(task.StashIdentifyJobID) != ""
+	errorCode, label := TaskStageErrorScanTrigger, "stash scan"
+	if identifying {
+		errorCode, label = TaskStageErrorIdentify, "stash identify"
+	}
 
-	job, err := scanner.FindJob(ctx, task.StashScanJobID)
+	job, ok := s.takeFinishedStashJob(jobID)
+	if !ok && s.stashJobsLive.Load() && s.now().Sub(task.UpdatedAt) < stashJobSafetyPoll {
+		return nil, nil
+	}
+	var err error
+	if !ok {
+		job, err = scanner.FindJob(ctx, jobID)
+	}
 	if err != nil {
 		task.StashScanError = err.Error()
-		blockTask(task, TaskStageErrorScanTrigger, err.Error(), s.now().UTC())
-		return task, fmt.Errorf("find stash job %q for task %q: %w", task.StashScanJobID, task.ID, err)
+		blockTask(task, errorCode, err.Error(), s.now().UTC())
+		return task, fmt.Errorf("find stash job %q for task %q: %w", jobID, task.ID, err)
 	}
 	if job == nil {
 		return task, nil
@@ -193,13 +257,20 @@ func (s *Service) syncStashScanJob(ctx context.Context, task *Task, scanner Stas
 	now := s.now().UTC()
 	switch strings.ToUpper(strings.TrimSpace(job.Status)) {
 	case "FINISHED":
+		if !identifying {
+			if started, err := s.startStashIdentify(ctx, task, scanner); started || err != nil {
+				return task, err
+			}
+		}
+		s.linkStashScenes(ctx, task)
+		s.syncLibraryIndex(ctx, task)
 		setTaskStage(task, TaskStageCompleted, TaskStageStatusDone)
 		clearTaskStageError(task)
 		task.StashScanError = ""
 		task.UpdatedAt = now
 	case "FAILED", "CANCELLED":
-		task.StashScanError = firstNonEmpty([]string{stringValue(job.Error), "stash scan job did not complete successfully"})
-		blockTask(task, TaskStageErrorScanTrigger, task.StashScanError, now)
+		task.StashScanError = firstNonEmpty([]string{stringValue(job.Error), label + " job did not complete successfully"})
+		blockTask(task, errorCode, task.StashScanError, now)
 	default:
 		setTaskStage(task, TaskStageScanning, TaskStageStatusRunning)
 		task.UpdatedAt = now
@@ -208,6 +279,33 @@ func (s *Service) syncStashScanJob(ctx context.Context, task *Task, scanner Stas
 	return task, nil
 }
 
+// startStashIdentify runs Stash's identify task on the scanned path against
+// the configured StashBox endpoints. It reports false when identify is off.
+func (s *Service) startStashIdentify(ctx context.Context, task *Task, scanner StashScanner) (bool, error) {
+	cfg := scanner.CurrentConfig().Identify
+	path := strings.TrimSpace(task.StashScanPath)
+	if !cfg.Enabled || path == "" {
+		return false, nil
+	}
+	if len(cfg.Sources) == 0 {
+		logging.Warnf("taskruntime: skip stash identify for task %s: no StashBox endpoints are configured", task.ID)
+		return false, nil
+	}
+	now := s.now().UTC()
+	task.UpdatedAt = now
+	jobID, err := scanner.MetadataIdentify(ctx, stashsync.IdentifyRequest{Paths: []string{path}, Sources: cfg.Sources})
+	if err != nil {
+		task.StashScanError = err.Error()
+		blockTask(task, TaskStageErrorIdentify, err.Error(), now)
+		logging.Errorf("taskruntime: stash identify trigger failed for task %s path=%s: %v", task.ID, path, err)
+		return false, fmt.Errorf("trigger stash identify for task %q: %w", task.ID, err)
+	}
+	task.StashIdentifyJobID = jobID
+	setTaskStage(task, TaskStageScanning, TaskStageStatusRunning)
+	logging.Infof("taskruntime: started stash identify for task %s path=%s job=%s sources=%d", task.ID, path, jobID, len(cfg.Sources))
+	return true, nil
+}
+
 func (s *Service) executeDelivery(ctx context.Context, task *Task, plan StashIntegrationPlan, now time.Time) error {
 	if !plan.NeedsTransfer {
 		return nil
@@ -222,12 +320,17 @@ func (s *Service) executeDelivery(ctx context.Context, task *Task, plan StashInt
 	return nil
 }
 
+// triggerScan scans the delivered path with the configured scan options,
+// e.g. so new scenes get phashes and previews generated straight away.
 func (s *Service) triggerScan(ctx context.Context, scanner StashScanner, plan StashIntegrationPlan) (string, error) {
-	return scanner.MetadataScan(ctx, stashsync.ScanRequest{Paths: []string{plan.ResolvedScanPath}})
+	return scanner.MetadataScan(ctx, stashsync.ScanRequest{
+		Paths:       []string{plan.ResolvedScanPath},
+		ScanOptions: scanner.CurrentConfig().Scan.OptionsFor(plan.ResolvedScanPath),
+	})
 }
 
 func planTaskStashIntegration(task *Task, cfg stashsync.IntegrationConfig) StashIntegrationPlan {
-	return planDelivery(cfg, resolveSource(task))
+	return planDelivery(routedConfig(cfg, task), resolveSource(task))
 }
 
 func planDelivery(cfg stashsync.IntegrationConfig, qbSourcePath string) StashIntegrationPlan {
@@ -332,6 +435,8 @@ func applyStashIntegrationPlan(task *Task, plan StashIntegrationPlan, now time.T
 	task.MojiTransferPath = plan.ResolvedTransferPath
 	task.TransferError = ""
 	task.StashScanJobID = ""
+	task.StashIdentifyJobID = ""
+	task.StashSceneID = ""
 	task.StashScanPath = plan.ResolvedScanPath
 	task.StashScanError = ""
 	task.StashScanHint = plan.UserHint
@@ -409,10 +514,10 @@ func shouldAllowManualStashScan(task *Task) bool {
 	if task == nil {
 		return false
 	}
-	if task.Stage != TaskStagePendingIngest && task.Stage != TaskStageTransferring && task.Stage != TaskStageScanning {
+	if task.Stage != TaskStagePendingIngest && task.Stage != TaskStageTransferring && task.Stage != TaskStageSubtitling && task.Stage != TaskStageScanning {
 		return false
 	}
-	if task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning {
+	if (task.Stage == TaskStageScanning || task.Stage == TaskStageSubtitling) && task.StageStatus == TaskStageStatusRunning {
 		return false
 	}
 	return task.StageStatus == TaskStageStatusPending || task.StageStatus == TaskStageStatusBlocked
@@ -27,6 +27,7 @@ func TestTriggerStashScansStartsSharedStorageScanForCompletedTask(t *testing.T)
 		t.Fatalf("Create failed: %v", err)
 	}
 
+	enabled, disabled := true, false
 	scanner := &fakeStashScanner{
 		jobID: "job-1",
 		config: stashsync.IntegrationConfig{
@@ -37,6 +38,10 @@ func TestTriggerStashScansStartsSharedStorageScanForCompletedTask(t *testing.T)
 			Library: stashsync.LibraryPathConfig{
 				StashRoot: "/library",
 			},
+			Scan: stashsync.ScanConfig{
+				Defaults:  stashsync.ScanOptions{ScanGeneratePhashes: &enabled, ScanGeneratePreviews: &enabled},
+				Libraries: []stashsync.LibraryScanOptions{{Path: "/library", Options: stashsync.ScanOptions{ScanGeneratePreviews: &disabled}}},
+			},
 		},
 	}
 	service, err := NewService(
@@ -66,6 +71,10 @@ func TestTriggerStashScansStartsSharedStorageScanForCompletedTask(t *testing.T)
 	if task.StashScanJobID != "job-1" || task.Stage != TaskStageScanning || task.StageStatus != TaskStageStatusRunning {
 		t.Fatalf("unexpected stash scan task: %+v", task)
 	}
+	options := scanner.requests[0].ScanOptions
+	if options.ScanGeneratePhashes != &enabled || options.ScanGeneratePreviews != &disabled || options.ScanGenerateCovers != nil {
+		t.Fatalf("unexpected scan options %+v", options)
+	}
 }
 
 func TestTriggerStashScansFallsBackToSavePathForPathMap(t *testing.T) {
@@ -483,12 +492,15 @@ func TestTriggerStashScansPersistsCompletedScanJobState(t *testing.T) {
 }
 
 type fakeStashScanner struct {
-	jobID    string
-	err      error
-	job      *stashsync.Job
-	jobErr   error
-	config   stashsync.IntegrationConfig
-	requests []stashsync.ScanRequest
+	jobID            string
+	err              error
+	job              *stashsync.Job
+	jobs             map[string]*stashsync.Job
+	jobErr           error
+	config           stashsync.IntegrationConfig
+	requests         []stashsync.ScanRequest
+	identifyJobID    string
+	identifyRequests []stashsync.IdentifyRequest
 }
 
 func (f *fakeStashScanner) MetadataScan(_ context.Context, req stashsync.ScanRequest) (string, error) {
@@ -496,7 +508,15 @@ func (f *fakeStashScanner) MetadataScan(_ context.Context, req stashsync.ScanReq
 	return f.jobID, f.err
 }
 
-func (f *fakeStashScanner) FindJob(_ context.Context, _ string) (*stashsync.Job, error) {
+func (f *fakeStashScanner) MetadataIdentify(_ context.Context, req stashsync.IdentifyRequest) (string, error) {
+	f.identifyRequests = append(f.identifyRequests, req)
+	return f.identifyJobID, f.err
+}
+
+func (f *fakeStashScanner) FindJob(_ context.Context, id string) (*stashsync.Job, error) {
+	if job, ok := f.jobs[id]; ok {
+		return job, f.jobErr
+	}
 	return f.job, f.jobErr
 }
 
@@ -504,6 +524,151 @@ func (f *fakeStashScanner) CurrentConfig() stashsync.IntegrationConfig {
 	return f.config
 }
 
+func TestFinishedScanWaitsForStashIdentify(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	if err := store.Create(ctx, &Task{ID: "task-identify", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-scan", StashScanPath: "/library/ABC-123.mp4"}); err != nil {
+		t.Fatal(err)
+	}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{
+		identifyJobID: "job-identify",
+		jobs: map[string]*stashsync.Job{
+			"job-scan":     {ID: "job-scan", Status: "FINISHED"},
+			"job-identify": {ID: "job-identify", Status: "RUNNING"},
+		},
+		config: stashsync.IntegrationConfig{Identify: stashsync.IdentifyConfig{Enabled: true, Sources: []string{"https://stashdb.org/graphql", "https://fansdb.cc/graphql"}}},
+	}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	task, _ := service.FindTask(ctx, "task-identify")
+	if task.Stage != TaskStageScanning || task.StageStatus != TaskStageStatusRunning || task.StashIdentifyJobID != "job-identify" {
+		t.Fatalf("expected task to wait for identify, got %+v", task)
+	}
+	if len(scanner.identifyRequests) != 1 || scanner.identifyRequests[0].Paths[0] != "/library/ABC-123.mp4" || scanner.identifyRequests[0].Sources[1] != "https://fansdb.cc/graphql" {
+		t.Fatalf("unexpected identify requests %+v", scanner.identifyRequests)
+	}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ = service.FindTask(ctx, "task-identify"); task.Stage != TaskStageScanning || len(scanner.identifyRequests) != 1 {
+		t.Fatalf("identify must only start once, got %+v", task)
+	}
+
+	scanner.jobs["job-identify"].Status = "FAILED"
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ = service.FindTask(ctx, "task-identify"); task.StageStatus != TaskStageStatusBlocked || task.StageErrorCode != TaskStageErrorIdentify {
+		t.Fatalf("expected identify failure to block the task, got %+v", task)
+	}
+}
+
+func TestFinishedScanLinksStashScenesToOrigin(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	createdAt := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err := store.Create(ctx, &Task{
+		ID:             "task-link",
+		Stage:          TaskStageScanning,
+		StageStatus:    TaskStageStatusRunning,
+		StashScanJobID: "job-scan",
+		StashScanPath:  "/library/ABC-123",
+		TorrentName:    "ABC-123 [1080p]",
+		Candidate:      Candidate{Tracker: "nyaa"},
+		Origin:         TaskOrigin{StashBoxEndpoint: "https://stashdb.org/graphql", StashBoxSceneID: "scene-uuid", StashPerformerID: "9"},
+		CreatedAt:      createdAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	linker := &fakeSceneLinker{ids: []string{"41"}}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithSceneLinker(linker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobs: map[string]*stashsync.Job{"job-scan": {ID: "job-scan", Status: "FINISHED"}}}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	task, _ := service.FindTask(ctx, "task-link")
+	if task.Stage != TaskStageCompleted || task.StashSceneID != "41" {
+		t.Fatalf("expected completed task linked to scene 41, got %+v", task)
+	}
+	want := stashsync.SceneLink{
+		Path:             "/library/ABC-123",
+		StashBoxEndpoint: "https://stashdb.org/graphql",
+		StashBoxSceneID:  "scene-uuid",
+		PerformerID:      "9",
+		Provenance:       stashsync.Provenance{TaskID: "task-link", Indexer: "nyaa", TorrentName: "ABC-123 [1080p]", GrabbedAt: createdAt},
+	}
+	if len(linker.links) != 1 || linker.links[0] != want {
+		t.Fatalf("unexpected links %+v", linker.links)
+	}
+
+	linker.err = errors.New("stash unavailable")
+	if err := store.Update(ctx, &Task{ID: "task-link", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-scan", StashScanPath: "/library/ABC-123"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ = service.FindTask(ctx, "task-link"); task.Stage != TaskStageCompleted {
+		t.Fatalf("link failures must not block completion, got %+v", task)
+	}
+}
+
+func TestFinishedScanSyncsLibraryIndex(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	if err := store.Create(ctx, &Task{ID: "task-index", Stage: TaskStageScanning, StageStatus: TaskStageStatusRunning, StashScanJobID: "job-scan", StashScanPath: "/library/ABC-123"}); err != nil {
+		t.Fatal(err)
+	}
+	index := &fakeLibraryIndex{err: errors.New("stash unavailable")}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithLibraryIndexSyncer(index))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &fakeStashScanner{jobs: map[string]*stashsync.Job{"job-scan": {ID: "job-scan", Status: "FINISHED"}}}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	if task, _ := service.FindTask(ctx, "task-index"); task.Stage != TaskStageCompleted || index.syncs != 1 {
+		t.Fatalf("expected one index sync and a completed task despite the sync error, got syncs=%d task=%+v", index.syncs, task)
+	}
+}
+
+type fakeLibraryIndex struct {
+	syncs int
+	err   error
+}
+
+func (f *fakeLibraryIndex) Sync(context.Context) error {
+	f.syncs++
+	return f.err
+}
+
+type fakeSceneLinker struct {
+	ids   []string
+	err   error
+	links []stashsync.SceneLink
+}
+
+func (f *fakeSceneLinker) LinkScenes(_ context.Context, link stashsync.SceneLink) ([]string, error) {
+	f.links = append(f.links, link)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.ids, nil
+}
+
 type fakeFileOperator struct {
 	err   error
 	calls []fileTransferCall
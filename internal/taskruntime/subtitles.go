@@ -0,0 +1,156 @@
+package taskruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+// Subtitler fetches or generates subtitles next to the videos under a
+// Moji-visible path and reports how many caption files it wrote.
+type Subtitler interface {
+	Enabled() bool
+	Subtitle(ctx context.Context, code string, path string) (int, error)
+}
+
+func WithSubtitler(subtitler Subtitler) Option {
+	return func(s *Service) {
+		if subtitler != nil {
+			s.subtitler = subtitler
+		}
+	}
+}
+
+// subtitleJob runs outside the scan worker's tick because generating
+// subtitles can take far longer than one tick.
+type subtitleJob struct {
+	done      chan struct{}
+	cancel    context.CancelFunc
+	installed int
+	err       error
+}
+
+// subtitleTargetPath returns the delivered content as Moji sees it, or ""
+// when subtitles are disabled or Moji cannot reach the files.
+func (s *Service) subtitleTargetPath(cfg stashsync.IntegrationConfig, plan StashIntegrationPlan) string {
+	if s.subtitler == nil || !s.subtitler.Enabled() {
+		return ""
+	}
+	path := mojiContentPath(cfg, plan)
+	if path == "" {
+		logging.Warnf("taskruntime: skip subtitles: downloads.moji_root is required to reach path-mapped content")
+	}
+	return path
+}
+
+// mojiContentPath is where Moji itself can read the delivered content: the
+// transfer target, or for path-mapped delivery the download seen through
+// downloads.moji_root. It is empty when Moji has no view of the files.
+func mojiContentPath(cfg stashsync.IntegrationConfig, plan StashIntegrationPlan) string {
+	if plan.NeedsTransfer {
+		return plan.ResolvedTransferPath
+	}
+	root := strings.TrimSpace(cfg.Downloads.MojiRoot)
+	if root == "" {
+		return ""
+	}
+	return joinRootAndRelative(root, plan.RelativePath)
+}
+
+// advanceTaskSubtitles starts the subtitle job for a SUBTITLING task, or
+// once it has finished, moves the task on to the Stash scan. It returns nil
+// while the job is still running. A RUNNING task without a job, left over
+// from a restart, gets a fresh job.
+func (s *Service) advanceTaskSubtitles(ctx context.Context, task *Task, scanner StashScanner) (*Task, error) {
+	cfg := routedConfig(scanner.CurrentConfig(), task)
+	plan := planDelivery(cfg, resolveSource(task))
+	if plan.ValidationError != nil {
+		blockTask(task, TaskStageErrorTransferPlan, plan.ValidationError.Error(), s.now().UTC())
+		return task, fmt.Errorf("subtitle task %q: %w", task.ID, plan.ValidationError)
+	}
+	path := s.subtitleTargetPath(cfg, plan)
+	if path == "" {
+		return s.startStashScan(ctx, task, scanner, plan)
+	}
+
+	job, started := s.subtitleJobFor(task, path)
+	if started {
+		setTaskStage(task, TaskStageSubtitling, TaskStageStatusRunning)
+		clearTaskStageError(task)
+		task.UpdatedAt = s.now().UTC()
+		logging.Infof("taskruntime: started subtitles for task %s path=%s", task.ID, path)
+		return task, nil
+	}
+	select {
+	case <-job.done:
+	default:
+		return nil, nil
+	}
+	s.subtitleJobsMu.Lock()
+	delete(s.subtitleJobs, task.ID)
+	s.subtitleJobsMu.Unlock()
+
+	if job.err != nil {
+		blockTask(task, TaskStageErrorSubtitle, job.err.Error(), s.now().UTC())
+		logging.Errorf("taskruntime: subtitles failed for task %s path=%s: %v", task.ID, path, job.err)
+		return task, fmt.Errorf("subtitle task %q: %w", task.ID, job.err)
+	}
+	logging.Infof("taskruntime: subtitles finished for task %s captions=%d", task.ID, job.installed)
+	return s.startStashScan(ctx, task, scanner, plan)
+}
+
+// subtitleJobFor returns the running subtitle job of task, starting one when
+// there is none. Jobs outlive the sync tick that started them but not the
+// runtime: they stop on Close or when their task is deleted.
+func (s *Service) subtitleJobFor(task *Task, path string) (*subtitleJob, bool) {
+	s.subtitleJobsMu.Lock()
+	defer s.subtitleJobsMu.Unlock()
+	if job, ok := s.subtitleJobs[task.ID]; ok {
+		return job, false
+	}
+	ctx, cancel := context.WithCancel(s.lifetime)
+	job := &subtitleJob{done: make(chan struct{}), cancel: cancel}
+	s.subtitleJobs[task.ID] = job
+	go func(code string) {
+		defer close(job.done)
+		defer cancel()
+		job.installed, job.err = s.subtitler.Subtitle(ctx, code, path)
+	}(task.Code)
+	return job, true
+}
+
+func (s *Service) cancelSubtitleJob(taskID string) {
+	s.subtitleJobsMu.Lock()
+	defer s.subtitleJobsMu.Unlock()
+	if job, ok := s.subtitleJobs[taskID]; ok {
+		job.cancel()
+		delete(s.subtitleJobs, taskID)
+	}
+}
+
+// skipTaskSubtitles moves a SUBTITLING task straight to the Stash scan, for
+// a manual scan of a task whose subtitles are pending or failed.
+func (s *Service) skipTaskSubtitles(ctx context.Context, task *Task, scanner StashScanner) (*Task, error) {
+	plan := planDelivery(routedConfig(scanner.CurrentConfig(), task), resolveSource(task))
+	if plan.ValidationError != nil {
+		blockTask(task, TaskStageErrorTransferPlan, plan.ValidationError.Error(), s.now().UTC())
+		return task, fmt.Errorf("trigger stash scan for task %q: %w", task.ID, plan.ValidationError)
+	}
+	clearTaskStageError(task)
+	logging.Infof("taskruntime: skipping subtitles for task %s", task.ID)
+	return s.startStashScan(ctx, task, scanner, plan)
+}
+
+func (s *Service) retrySubtitlingTask(ctx context.Context, task *Task) (*Task, error) {
+	next := cloneTask(task)
+	setTaskStage(next, TaskStageSubtitling, TaskStageStatusPending)
+	clearTaskStageError(next)
+	next.UpdatedAt = s.now().UTC()
+	if err := s.store.Update(ctx, next); err != nil {
+		return nil, fmt.Errorf("update task %q: %w", next.ID, err)
+	}
+	return next, nil
+}
@@ -0,0 +1,170 @@
+package taskruntime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/stashsync"
+)
+
+type fakeSubtitler struct {
+	mu      sync.Mutex
+	release chan struct{}
+	errs    map[string]error
+	paths   []string
+}
+
+func (f *fakeSubtitler) Enabled() bool { return true }
+
+func (f *fakeSubtitler) Subtitle(_ context.Context, _ string, path string) (int, error) {
+	f.mu.Lock()
+	f.paths = append(f.paths, path)
+	err := f.errs[path]
+	f.mu.Unlock()
+	if f.release != nil {
+		<-f.release
+	}
+	return 1, err
+}
+
+func waitForTaskStage(t *testing.T, service *Service, scanner StashScanner, id string, stage TaskStage, status TaskStageStatus) *Task {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		_, _ = service.TriggerStashScans(context.Background(), scanner)
+		task, err := service.FindTask(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Stage == stage && task.StageStatus == status {
+			return task
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("task %s did not reach %s/%s", id, stage, status)
+	return nil
+}
+
+func TestSubtitlingRunsBetweenDeliveryAndScan(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	for _, id := range []string{"ABCD-123", "ABCD-124"} {
+		if err := store.Create(ctx, &Task{ID: id, Code: id, Stage: TaskStagePendingIngest, StageStatus: TaskStageStatusPending, SavePath: "/downloads", ContentPath: "/downloads/" + id + ".mp4"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scanner := &fakeStashScanner{
+		jobID: "job-1",
+		config: stashsync.IntegrationConfig{
+			DeliveryMode: stashsync.DeliveryModePathMap,
+			Downloads:    stashsync.DownloadsPathConfig{QBRoot: "/downloads", MojiRoot: "/mnt/downloads"},
+			Library:      stashsync.LibraryPathConfig{StashRoot: "/library"},
+		},
+	}
+	subtitler := &fakeSubtitler{release: make(chan struct{}), errs: map[string]error{
+		"/mnt/downloads/ABCD-123.mp4": errors.New("whisper: model not found"),
+	}}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithSubtitler(subtitler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil {
+		t.Fatalf("TriggerStashScans: %v", err)
+	}
+	task, _ := service.FindTask(ctx, "ABCD-123")
+	if task.Stage != TaskStageSubtitling || task.StageStatus != TaskStageStatusPending || len(scanner.requests) != 0 {
+		t.Fatalf("expected delivered task to wait for subtitles, got %+v", task)
+	}
+	waitForTaskStage(t, service, scanner, "ABCD-123", TaskStageSubtitling, TaskStageStatusRunning)
+	if _, err := service.TriggerStashScans(ctx, scanner); err != nil || len(scanner.requests) != 0 {
+		t.Fatalf("scan must wait for the subtitle job, requests %+v err %v", scanner.requests, err)
+	}
+
+	close(subtitler.release)
+	task = waitForTaskStage(t, service, scanner, "ABCD-123", TaskStageSubtitling, TaskStageStatusBlocked)
+	if task.StageErrorCode != TaskStageErrorSubtitle {
+		t.Fatalf("expected subtitle failure, got %+v", task)
+	}
+
+	retried, err := service.RetryTask(ctx, "ABCD-123", scanner)
+	if err != nil || retried.Stage != TaskStageSubtitling || retried.StageStatus != TaskStageStatusPending {
+		t.Fatalf("retry: task %+v err %v", retried, err)
+	}
+	scanned, err := service.TriggerTaskStashScan(ctx, "ABCD-123", scanner)
+	if err != nil || scanned.Stage != TaskStageScanning || scanned.StageStatus != TaskStageStatusRunning {
+		t.Fatalf("manual scan should skip subtitles, got %+v err %v", scanned, err)
+	}
+
+	task = waitForTaskStage(t, service, scanner, "ABCD-124", TaskStageScanning, TaskStageStatusRunning)
+	if task.StashScanJobID != "job-1" || task.StashScanPath != "/library/ABCD-124.mp4" {
+		t.Fatalf("expected scan after subtitles, got %+v", task)
+	}
+}
+
+type blockingSubtitler struct {
+	stopped chan error
+}
+
+func (f *blockingSubtitler) Enabled() bool { return true }
+
+func (f *blockingSubtitler) Subtitle(ctx context.Context, _ string, _ string) (int, error) {
+	<-ctx.Done()
+	f.stopped <- ctx.Err()
+	return 0, ctx.Err()
+}
+
+func TestSubtitleJobStopsWhenTaskIsDeletedOrRuntimeCloses(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore()
+	for _, id := range []string{"ABCD-123", "ABCD-124"} {
+		if err := store.Create(ctx, &Task{ID: id, Code: id, Stage: TaskStagePendingIngest, StageStatus: TaskStageStatusPending, SavePath: "/downloads", ContentPath: "/downloads/" + id + ".mp4"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scanner := &fakeStashScanner{
+		jobID: "job-1",
+		config: stashsync.IntegrationConfig{
+			DeliveryMode: stashsync.DeliveryModePathMap,
+			Downloads:    stashsync.DownloadsPathConfig{QBRoot: "/downloads", MojiRoot: "/mnt/downloads"},
+			Library:      stashsync.LibraryPathConfig{StashRoot: "/library"},
+		},
+	}
+	subtitler := &blockingSubtitler{stopped: make(chan error, 2)}
+	service, err := NewService(fakeTracker{}, &fakeTorrentAdder{}, store, WithSubtitler(subtitler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first tick moves the tasks to SUBTITLING and the second starts
+	// their jobs. The tick's context ends right away; the jobs must not.
+	tickCtx, cancel := context.WithCancel(ctx)
+	for range 2 {
+		if _, err := service.TriggerStashScans(tickCtx, scanner); err != nil {
+			t.Fatalf("TriggerStashScans: %v", err)
+		}
+	}
+	cancel()
+	select {
+	case err := <-subtitler.stopped:
+		t.Fatalf("subtitle job stopped with the scan tick: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for _, stop := range []func(){
+		func() { _, _ = service.DeleteTask(ctx, "ABCD-123") },
+		service.Close,
+	} {
+		stop()
+		select {
+		case err := <-subtitler.stopped:
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected the subtitle job to be cancelled, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subtitle job kept running")
+		}
+	}
+}
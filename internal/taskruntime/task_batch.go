@@ -92,7 +92,7 @@ func (s *Service) ProcessTaskIngest(ctx context.Context, ids []string, scanner S
 		if err != nil || task == nil {
 			return missingOrFailedBatchResult(id, err, TaskBatchReasonIngestFailed)
 		}
-		if task.Stage == TaskStageScanning && task.StageStatus == TaskStageStatusRunning {
+		if (task.Stage == TaskStageScanning || task.Stage == TaskStageSubtitling) && task.StageStatus == TaskStageStatusRunning {
 			return TaskBatchResult{TaskID: id, Status: TaskBatchStatusSkipped, ReasonCode: TaskBatchReasonAlreadyRunning, Task: task}
 		}
 		if !shouldAllowManualStashScan(task) {
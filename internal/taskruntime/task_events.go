@@ -29,11 +29,14 @@ type TaskStats struct {
 	Failed       int
 }
 
+// TaskEvent is one committed task change. ProgressOnly marks an update whose
+// only change since the previous event is download progress.
 type TaskEvent struct {
 	Sequence       int
 	Type           TaskEventType
 	TaskID         string
 	Task           *Task
+	ProgressOnly   bool
 	DashboardStats TaskStats
 }
 
@@ -189,12 +192,35 @@ func cloneTaskEvent(event *TaskEvent) *TaskEvent {
 	return &next
 }
 
+// StageTransitionHook runs user hooks when an update changes a task's stage.
+// BeforeStageTransition runs before the update is persisted; an error names
+// the stage the failing hook guards, and the task is persisted blocked in that
+// stage instead of moving on. AfterStageTransition runs once the update is
+// persisted, in the background and in transition order per task. Both get
+// copies of the task. Enabled lets updates skip loading the previous task
+// when no hook is configured.
+type StageTransitionHook interface {
+	Enabled() bool
+	BeforeStageTransition(ctx context.Context, previous *Task, next *Task) (TaskStage, error)
+	AfterStageTransition(ctx context.Context, previous *Task, next *Task)
+}
+
 type EventingTaskStore struct {
 	store         TaskStore
 	publisher     TaskEventPublisher
+	hook          StageTransitionHook
 	now           func() time.Time
 	mu            sync.Mutex
 	lastPublished map[string]publishedTaskState
+	hookMu        sync.Mutex
+	hookQueues    map[string][]stageTransition
+	hookRuns      sync.WaitGroup
+}
+
+type stageTransition struct {
+	ctx      context.Context
+	previous *Task
+	next     *Task
 }
 
 type publishedTaskState struct {
@@ -212,6 +238,14 @@ func WithTaskEventClock(now func() time.Time) EventingTaskStoreOption {
 	}
 }
 
+func WithStageTransitionHook(hook StageTransitionHook) EventingTaskStoreOption {
+	return func(store *EventingTaskStore) {
+		if hook != nil {
+			store.hook = hook
+		}
+	}
+}
+
 func NewEventingTaskStore(store TaskStore, publisher TaskEventPublisher, options ...EventingTaskStoreOption) (*EventingTaskStore, error) {
 	if store == nil {
 		return nil, errors.New("taskruntime: task event store requires a task store")
@@ -224,6 +258,7 @@ func NewEventingTaskStore(store TaskStore, publisher TaskEventPublisher, options
 		publisher:     publisher,
 		now:           time.Now,
 		lastPublished: make(map[string]publishedTaskState),
+		hookQueues:    make(map[string][]stageTransition),
 	}
 	for _, option := range options {
 		option(next)
@@ -241,7 +276,20 @@ func (s *EventingTaskStore) Create(ctx context.Context, task *Task) error {
 	return nil
 }
 
+// Update persists task. When the update changes the task's stage, blocking
+// hooks run first, outside the store lock; if one fails task is blocked in
+// the stage that hook guards before it is persisted.
 func (s *EventingTaskStore) Update(ctx context.Context, task *Task) error {
+	previous := s.stageTransitionFrom(ctx, task)
+	if previous != nil {
+		if stage, err := s.hook.BeforeStageTransition(ctx, cloneTask(previous), cloneTask(task)); err != nil {
+			logging.Warnf("task events: stage hook blocked task_id=%s stage=%s: %v", task.ID, stage, err)
+			setTaskStage(task, stage, TaskStageStatusBlocked)
+			blockTask(task, TaskStageErrorHook, err.Error(), s.now().UTC())
+			previous = nil
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if err := s.store.Update(ctx, task); err != nil {
@@ -250,9 +298,33 @@ func (s *EventingTaskStore) Update(ctx context.Context, task *Task) error {
 	if s.shouldPublishUpdateLocked(task) {
 		s.publishLocked(ctx, TaskEventUpdated, task.ID, task)
 	}
+	if previous != nil {
+		s.enqueueStageTransition(ctx, previous, task)
+	}
 	return nil
 }
 
+// stageTransitionFrom returns the stored task when the update moves task to
+// another stage and hooks are configured.
+func (s *EventingTaskStore) stageTransitionFrom(ctx context.Context, task *Task) *Task {
+	if s.hook == nil || task == nil || task.StageStatus == TaskStageStatusBlocked || !s.hook.Enabled() {
+		return nil
+	}
+	previous, err := s.store.Find(ctx, task.ID)
+	if err != nil || previous == nil || previous.Stage == task.Stage {
+		return nil
+	}
+	return previous
+}
+
+// Close waits for background hooks that are still running.
+func (s *EventingTaskStore) Close() {
+	if s == nil {
+		return
+	}
+	s.hookRuns.Wait()
+}
+
 func (s *EventingTaskStore) Delete(ctx context.Context, id string) (*Task, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -281,6 +353,43 @@ func (s *EventingTaskStore) List(ctx context.Context) ([]*Task, error) {
 	return s.store.List(ctx)
 }
 
+// enqueueStageTransition hands a persisted transition to the background
+// hooks. They may run slow user scripts, so they run off the update path; one
+// goroutine per task drains its queue so hooks still see that task's
+// transitions in order.
+func (s *EventingTaskStore) enqueueStageTransition(ctx context.Context, previous *Task, next *Task) {
+	transition := stageTransition{ctx: context.WithoutCancel(ctx), previous: cloneTask(previous), next: cloneTask(next)}
+	s.hookMu.Lock()
+	queue, draining := s.hookQueues[next.ID]
+	s.hookQueues[next.ID] = append(queue, transition)
+	s.hookMu.Unlock()
+	if draining {
+		return
+	}
+	s.hookRuns.Add(1)
+	go func() {
+		defer s.hookRuns.Done()
+		s.drainStageTransitions(next.ID)
+	}()
+}
+
+func (s *EventingTaskStore) drainStageTransitions(taskID string) {
+	for {
+		s.hookMu.Lock()
+		queue := s.hookQueues[taskID]
+		if len(queue) == 0 {
+			delete(s.hookQueues, taskID)
+			s.hookMu.Unlock()
+			return
+		}
+		transition := queue[0]
+		s.hookQueues[taskID] = queue[1:]
+		s.hookMu.Unlock()
+
+		s.hook.AfterStageTransition(transition.ctx, transition.previous, transition.next)
+	}
+}
+
 func (s *EventingTaskStore) shouldPublishUpdateLocked(task *Task) bool {
 	if task == nil {
 		return false
@@ -316,6 +425,10 @@ func (s *EventingTaskStore) publishLocked(ctx context.Context, eventType TaskEve
 		return
 	}
 	now := s.now()
+	progressOnly := false
+	if previous, ok := s.lastPublished[taskID]; ok && eventType == TaskEventUpdated && previous.task != nil && task != nil {
+		progressOnly = !taskChangedBeyondProgress(previous.task, task)
+	}
 	if task != nil {
 		s.lastPublished[taskID] = publishedTaskState{task: cloneTask(task), at: now}
 	}
@@ -323,6 +436,7 @@ func (s *EventingTaskStore) publishLocked(ctx context.Context, eventType TaskEve
 		Type:           eventType,
 		TaskID:         taskID,
 		Task:           cloneTask(task),
+		ProgressOnly:   progressOnly,
 		DashboardStats: CalculateTaskStats(tasks),
 	})
 }
@@ -341,7 +455,7 @@ func CalculateTaskStats(tasks []*Task) TaskStats {
 		if task.Stage == TaskStageDownloading && task.StageStatus == TaskStageStatusRunning {
 			stats.Downloading++
 		}
-		if task.Stage == TaskStagePendingIngest || task.Stage == TaskStageTransferring || task.Stage == TaskStageScanning {
+		if task.Stage == TaskStagePendingIngest || task.Stage == TaskStageTransferring || task.Stage == TaskStageSubtitling || task.Stage == TaskStageScanning {
 			stats.PendingScans++
 		}
 		if task.StageStatus == TaskStageStatusBlocked {
@@ -122,7 +122,7 @@ func TestEventingTaskStorePublishesCommittedChangesAndThrottlesProgress(t *testi
 	if err := store.Update(context.Background(), task); err != nil {
 		t.Fatalf("Update significant progress: %v", err)
 	}
-	if len(publisher.events) != 2 || publisher.events[1].Type != TaskEventUpdated {
+	if len(publisher.events) != 2 || publisher.events[1].Type != TaskEventUpdated || !publisher.events[1].ProgressOnly {
 		t.Fatalf("expected significant progress event, got %#v", publisher.events)
 	}
 
@@ -131,7 +131,7 @@ func TestEventingTaskStorePublishesCommittedChangesAndThrottlesProgress(t *testi
 	if err := store.Update(context.Background(), task); err != nil {
 		t.Fatalf("Update critical state: %v", err)
 	}
-	if len(publisher.events) != 3 || publisher.events[2].DashboardStats.Failed != 1 {
+	if len(publisher.events) != 3 || publisher.events[2].DashboardStats.Failed != 1 || publisher.events[2].ProgressOnly {
 		t.Fatalf("expected immediate critical update with stats, got %#v", publisher.events)
 	}
 
@@ -177,3 +177,100 @@ func TestEventingTaskStoreDoesNotPublishFailedPersistence(t *testing.T) {
 		t.Fatalf("failed persistence published %d events", len(publisher.events))
 	}
 }
+
+type recordingStageTransitionHook struct {
+	enabled bool
+	before  [][2]TaskStage
+	after   [][2]TaskStage
+	err     error
+}
+
+func (h *recordingStageTransitionHook) Enabled() bool { return h.enabled }
+
+func (h *recordingStageTransitionHook) BeforeStageTransition(_ context.Context, previous *Task, next *Task) (TaskStage, error) {
+	h.before = append(h.before, [2]TaskStage{previous.Stage, next.Stage})
+	return previous.Stage, h.err
+}
+
+func (h *recordingStageTransitionHook) AfterStageTransition(_ context.Context, previous *Task, next *Task) {
+	h.after = append(h.after, [2]TaskStage{previous.Stage, next.Stage})
+}
+
+type countingFindTaskStore struct {
+	TaskStore
+	finds int
+}
+
+func (s *countingFindTaskStore) Find(ctx context.Context, id string) (*Task, error) {
+	s.finds++
+	return s.TaskStore.Find(ctx, id)
+}
+
+func TestEventingTaskStoreRunsHooksOnStageTransition(t *testing.T) {
+	now := time.Unix(100, 0)
+	hook := &recordingStageTransitionHook{enabled: true}
+	base := NewMemoryTaskStore()
+	store, err := NewEventingTaskStore(base, &collectingTaskEventPublisher{}, WithTaskEventClock(func() time.Time { return now }), WithStageTransitionHook(hook))
+	if err != nil {
+		t.Fatalf("NewEventingTaskStore: %v", err)
+	}
+	task := &Task{ID: "task-1", Stage: TaskStageDownloading, StageStatus: TaskStageStatusRunning, CreatedAt: now, UpdatedAt: now}
+	_ = store.Create(context.Background(), task)
+
+	task.Progress = 0.5
+	_ = store.Update(context.Background(), task)
+	if len(hook.before) != 0 {
+		t.Fatalf("progress update should not run hooks, got %v", hook.before)
+	}
+
+	setTaskStage(task, TaskStagePendingIngest, TaskStageStatusPending)
+	if err := store.Update(context.Background(), task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	store.Close()
+	want := [2]TaskStage{TaskStageDownloading, TaskStagePendingIngest}
+	if len(hook.before) != 1 || hook.before[0] != want || len(hook.after) != 1 || hook.after[0] != want {
+		t.Fatalf("unexpected hook transitions: before=%v after=%v", hook.before, hook.after)
+	}
+}
+
+func TestEventingTaskStoreBlocksGuardedStageWhenBlockingHookFails(t *testing.T) {
+	now := time.Unix(100, 0)
+	hook := &recordingStageTransitionHook{enabled: true, err: errors.New("disk check failed")}
+	base := NewMemoryTaskStore()
+	store, err := NewEventingTaskStore(base, &collectingTaskEventPublisher{}, WithTaskEventClock(func() time.Time { return now }), WithStageTransitionHook(hook))
+	if err != nil {
+		t.Fatalf("NewEventingTaskStore: %v", err)
+	}
+	task := &Task{ID: "task-1", Stage: TaskStageDownloading, StageStatus: TaskStageStatusRunning, CreatedAt: now, UpdatedAt: now}
+	_ = store.Create(context.Background(), task)
+
+	setTaskStage(task, TaskStagePendingIngest, TaskStageStatusPending)
+	if err := store.Update(context.Background(), task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	store.Close()
+	if len(hook.after) != 0 {
+		t.Fatalf("a blocked transition should not run background hooks, got %v", hook.after)
+	}
+	stored, _ := base.Find(context.Background(), task.ID)
+	if stored.Stage != TaskStageDownloading || stored.StageStatus != TaskStageStatusBlocked || stored.StageErrorCode != TaskStageErrorHook || stored.StageErrorMessage != "disk check failed" {
+		t.Fatalf("expected hook failure to block the guarded stage, got %#v", stored)
+	}
+}
+
+func TestEventingTaskStoreSkipsFindWithoutConfiguredHooks(t *testing.T) {
+	now := time.Unix(100, 0)
+	base := &countingFindTaskStore{TaskStore: NewMemoryTaskStore()}
+	store, err := NewEventingTaskStore(base, &collectingTaskEventPublisher{}, WithTaskEventClock(func() time.Time { return now }), WithStageTransitionHook(&recordingStageTransitionHook{}))
+	if err != nil {
+		t.Fatalf("NewEventingTaskStore: %v", err)
+	}
+	task := &Task{ID: "task-1", Stage: TaskStageDownloading, StageStatus: TaskStageStatusRunning, CreatedAt: now, UpdatedAt: now}
+	_ = store.Create(context.Background(), task)
+	setTaskStage(task, TaskStagePendingIngest, TaskStageStatusPending)
+	_ = store.Update(context.Background(), task)
+	if base.finds != 0 {
+		t.Fatalf("expected no lookups without configured hooks, got %d", base.finds)
+	}
+}
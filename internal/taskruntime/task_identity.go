@@ -9,11 +9,10 @@ import (
 	"net/http"
 	"net/url"
 	"path"
-	"regexp"
 	"strings"
-)
 
-var codePattern = regexp.MustCompile(`(?i)\b([a-z]{2,10})[-_\s]?(\d{2,6})\b`)
+	"github.com/leothevan2444/moji/internal/codeparser"
+)
 
 type torrentIdentity struct {
 	InfoHash  string
@@ -21,20 +20,11 @@ type torrentIdentity struct {
 }
 
 func normalizeCode(value string) string {
-	match := codePattern.FindStringSubmatch(strings.TrimSpace(value))
-	if len(match) != 3 {
-		return ""
-	}
-	return strings.ToUpper(match[1]) + "-" + match[2]
+	return codeparser.Normalize(value)
 }
 
 func extractCode(values ...string) string {
-	for _, value := range values {
-		if code := normalizeCode(value); code != "" {
-			return code
-		}
-	}
-	return ""
+	return codeparser.Extract(values...)
 }
 
 func normalizeInfoHash(value string) string {
@@ -9,6 +9,7 @@ const (
 	TaskStageDownloading   TaskStage = "DOWNLOADING"
 	TaskStagePendingIngest TaskStage = "PENDING_INGEST"
 	TaskStageTransferring  TaskStage = "TRANSFERRING"
+	TaskStageSubtitling    TaskStage = "SUBTITLING"
 	TaskStageScanning      TaskStage = "SCANNING"
 	TaskStageCompleted     TaskStage = "COMPLETED"
 )
@@ -34,6 +35,11 @@ const (
 	TaskStageErrorDuplicateCode       = "DUPLICATE_CODE"
 	TaskStageErrorDuplicateLibrary    = "DUPLICATE_LIBRARY_CODE"
 	TaskStageErrorCodeRequired        = "TASK_CODE_REQUIRED"
+	TaskStageErrorHook                = "HOOK_FAILED"
+	TaskStageErrorInsufficientDisk    = "INSUFFICIENT_DISK_SPACE"
+	TaskStageErrorTorrentMissing      = "TORRENT_MISSING"
+	TaskStageErrorSubtitle            = "SUBTITLE_FAILED"
+	TaskStageErrorIdentify            = "IDENTIFY_FAILED"
 )
 
 func normalizeTaskStage(value TaskStage) TaskStage {
@@ -42,6 +48,7 @@ func normalizeTaskStage(value TaskStage) TaskStage {
 		TaskStageDownloading,
 		TaskStagePendingIngest,
 		TaskStageTransferring,
+		TaskStageSubtitling,
 		TaskStageScanning,
 		TaskStageCompleted:
 		return value
@@ -72,6 +79,8 @@ func taskStageLabel(stage TaskStage) string {
 		return "待入库"
 	case TaskStageTransferring:
 		return "搬运中"
+	case TaskStageSubtitling:
+		return "字幕中"
 	case TaskStageScanning:
 		return "扫描中"
 	case TaskStageCompleted:
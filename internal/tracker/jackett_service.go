@@ -96,6 +96,16 @@ func (s *JackettService) ListIndexers() ([]jackett.Indexer, error) {
 	return client.GetIndexers()
 }
 
+// Feed reads the latest items of one indexer's Torznab feed. Returns no
+// results when Jackett is not configured, like Search.
+func (s *JackettService) Feed(indexer string, categories []int) ([]jackett.SearchResult, error) {
+	client := s.currentClient()
+	if client == nil {
+		return nil, nil
+	}
+	return client.Feed(jackett.FeedRequest{Indexer: indexer, Categories: categories})
+}
+
 func (s *JackettService) Search(query string, options ...SearchOption) ([]jackett.SearchResult, error) {
 	opts := &SearchOptions{}
 	for _, opt := range options {
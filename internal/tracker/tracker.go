@@ -13,6 +13,12 @@ type IndexerLister interface {
 	ListIndexers() ([]jackett.Indexer, error)
 }
 
+// FeedReader is optionally implemented by Tracker implementations that can
+// read an indexer's Torznab RSS feed. An empty indexer reads every indexer.
+type FeedReader interface {
+	Feed(indexer string, categories []int) ([]jackett.SearchResult, error)
+}
+
 type SearchOption func(*SearchOptions)
 
 type SearchOptions struct {
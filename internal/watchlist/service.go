@@ -0,0 +1,347 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/notify"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+// TaskCreator creates a task straight from a torrent link.
+type TaskCreator interface {
+	AddTorrentContext(ctx context.Context, req taskruntime.AddTorrentRequest) (*taskruntime.Task, error)
+}
+
+// Notifier delivers a chat notification.
+type Notifier interface {
+	Notify(event notify.Event)
+}
+
+// Service stores watchlists and re-runs their tracker searches. The first
+// successful run of a watchlist only records a baseline; every later run acts
+// on the results it has not seen before.
+type Service struct {
+	store    *sqliteStore
+	tracker  tracker.Tracker
+	tasks    TaskCreator
+	notifier Notifier
+	now      func() time.Time
+
+	// runMu serializes runs so the scheduler and a manual run never act on
+	// the same new result twice.
+	runMu sync.Mutex
+}
+
+type Option func(*Service)
+
+func WithTaskCreator(tasks TaskCreator) Option {
+	return func(service *Service) {
+		if tasks != nil {
+			service.tasks = tasks
+		}
+	}
+}
+
+func WithNotifier(notifier Notifier) Option {
+	return func(service *Service) {
+		if notifier != nil {
+			service.notifier = notifier
+		}
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(service *Service) {
+		if now != nil {
+			service.now = now
+		}
+	}
+}
+
+func New(path string, tr tracker.Tracker, options ...Option) (*Service, error) {
+	if tr == nil {
+		return nil, errors.New("watchlist: tracker is required")
+	}
+	store, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	service := &Service{store: store, tracker: tr, now: time.Now}
+	for _, option := range options {
+		option(service)
+	}
+	return service, nil
+}
+
+func (s *Service) Close() error {
+	if s == nil || s.store == nil || s.store.db == nil {
+		return nil
+	}
+	return s.store.db.Close()
+}
+
+// Start runs the due watchlists every minute until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) List(ctx context.Context) ([]Watchlist, error) {
+	return s.store.list(ctx)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (Watchlist, error) {
+	return s.store.find(ctx, strings.TrimSpace(id))
+}
+
+func (s *Service) Create(ctx context.Context, input Input) (Watchlist, error) {
+	input, err := input.normalize()
+	if err != nil {
+		return Watchlist{}, err
+	}
+	now := s.now().UTC()
+	item := Watchlist{ID: uuid.NewString(), CreatedAt: now}
+	applyInput(&item, input, now)
+	if err := s.store.save(ctx, item); err != nil {
+		return Watchlist{}, err
+	}
+	logging.Infof("watchlist: created %s (%q)", item.ID, item.Name)
+	return item, nil
+}
+
+// Update replaces the editable fields. Changing the query, indexers or
+// categories restarts the baseline.
+func (s *Service) Update(ctx context.Context, id string, input Input) (Watchlist, error) {
+	input, err := input.normalize()
+	if err != nil {
+		return Watchlist{}, err
+	}
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	item, err := s.store.find(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return Watchlist{}, err
+	}
+	if !item.sameSearch(input) {
+		if err := s.store.resetResults(ctx, item.ID); err != nil {
+			return Watchlist{}, err
+		}
+		item.LastSuccessAt = nil
+		item.LastRunAt = nil
+	}
+	applyInput(&item, input, s.now().UTC())
+	if err := s.store.save(ctx, item); err != nil {
+		return Watchlist{}, err
+	}
+	return item, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if err := s.store.delete(ctx, strings.TrimSpace(id)); err != nil {
+		return err
+	}
+	logging.Infof("watchlist: deleted %s", id)
+	return nil
+}
+
+// Results lists the results a watchlist has seen, newest first.
+func (s *Service) Results(ctx context.Context, id string, limit int) ([]Result, error) {
+	if limit <= 0 {
+		limit = defaultResultsLimit
+	}
+	return s.store.results(ctx, strings.TrimSpace(id), limit)
+}
+
+// RunDue runs every enabled watchlist whose interval has elapsed. A failing
+// watchlist records its error and does not stop the others.
+func (s *Service) RunDue(ctx context.Context) {
+	items, err := s.store.list(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.Warnf("watchlist: load watchlists: %v", err)
+		}
+		return
+	}
+	now := s.now().UTC()
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if !item.due(now) {
+			continue
+		}
+		if _, err := s.Run(ctx, item.ID); err != nil && ctx.Err() == nil {
+			logging.Warnf("watchlist: run %s (%q): %v", item.ID, item.Name, err)
+		}
+	}
+}
+
+// Run searches the trackers for one watchlist and acts on the new results.
+func (s *Service) Run(ctx context.Context, id string) (RunSummary, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	item, err := s.store.find(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return RunSummary{}, err
+	}
+	now := s.now().UTC()
+	item.LastRunAt = &now
+
+	found, err := s.tracker.Search(item.Query, tracker.WithTrackers(item.Indexers), tracker.WithCategories(item.Categories))
+	if err != nil {
+		item.LastError = err.Error()
+		if saveErr := s.store.save(ctx, item); saveErr != nil {
+			return RunSummary{}, saveErr
+		}
+		return RunSummary{Watchlist: item}, fmt.Errorf("watchlist: search %q: %w", item.Query, err)
+	}
+
+	seen, err := s.store.seenKeys(ctx, item.ID)
+	if err != nil {
+		return RunSummary{}, err
+	}
+	baseline := item.LastSuccessAt == nil
+	summary := RunSummary{Fetched: len(found)}
+	for _, candidate := range found {
+		result := newResult(item.ID, candidate, now)
+		if result.Key == "" || seen[result.Key] {
+			continue
+		}
+		seen[result.Key] = true
+		switch {
+		case baseline:
+			result.Status = ResultStatusBaseline
+		case item.excludes(candidate):
+			result.Status = ResultStatusExcluded
+		default:
+			s.act(ctx, item, candidate, &result)
+		}
+		summary.New = append(summary.New, result)
+	}
+	if err := s.store.insertResults(ctx, item.ID, summary.New, maxRetainedResults); err != nil {
+		return RunSummary{}, err
+	}
+
+	item.LastSuccessAt = &now
+	item.LastError = ""
+	if err := s.store.save(ctx, item); err != nil {
+		return RunSummary{}, err
+	}
+	summary.Watchlist = item
+	logging.Infof("watchlist: run %s (%q) fetched=%d new=%d baseline=%t", item.ID, item.Name, len(found), len(summary.New), baseline)
+	return summary, nil
+}
+
+func (s *Service) act(ctx context.Context, item Watchlist, candidate jackett.SearchResult, result *Result) {
+	if item.Action != ActionDownload {
+		if s.notifier != nil {
+			s.notifier.Notify(notify.Event{
+				Type:       notify.EventWatchlistMatch,
+				OccurredAt: result.SeenAt,
+				Watchlist:  item.Name,
+				Title:      result.Title,
+				Tracker:    result.Tracker,
+				Link:       firstNonEmpty(candidate.Details, result.Link),
+			})
+		}
+		result.Status = ResultStatusNotified
+		return
+	}
+	if s.tasks == nil {
+		result.Status = ResultStatusFailed
+		result.Error = "task runtime is not configured"
+		return
+	}
+	task, err := s.tasks.AddTorrentContext(ctx, taskruntime.AddTorrentRequest{Source: taskruntime.TaskSourceWatchlist, URL: result.Link})
+	switch {
+	case errors.Is(err, taskruntime.ErrDuplicateTorrentTask), errors.Is(err, taskruntime.ErrDuplicateCodeTask), errors.Is(err, taskruntime.ErrDuplicateLibraryCode):
+		result.Status = ResultStatusDuplicate
+		result.Error = err.Error()
+	case err != nil:
+		result.Status = ResultStatusFailed
+		result.Error = err.Error()
+		logging.Warnf("watchlist: create task for %q from %q: %v", result.Title, item.Name, err)
+	default:
+		result.Status = ResultStatusQueued
+		if task != nil {
+			result.TaskID = task.ID
+		}
+	}
+}
+
+// excludes reports whether a result fails the watchlist's exclusion filters.
+func (w Watchlist) excludes(candidate jackett.SearchResult) bool {
+	if candidate.Seeders < w.MinSeeders {
+		return true
+	}
+	title := strings.ToLower(candidate.Title)
+	for _, term := range w.Exclude {
+		if strings.Contains(title, strings.ToLower(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyInput(item *Watchlist, input Input, now time.Time) {
+	item.Name = input.Name
+	item.Query = input.Query
+	item.Indexers = input.Indexers
+	item.Categories = input.Categories
+	item.Exclude = input.Exclude
+	item.MinSeeders = input.MinSeeders
+	item.Action = input.Action
+	item.Enabled = input.Enabled
+	item.Interval = input.Interval
+	item.UpdatedAt = now
+}
+
+// newResult identifies a tracker result by info hash when the indexer
+// reports one, so the same torrent seen through two indexers counts once.
+func newResult(watchlistID string, candidate jackett.SearchResult, now time.Time) Result {
+	key := strings.ToLower(strings.TrimSpace(candidate.InfoHash))
+	if key == "" {
+		key = firstNonEmpty(candidate.GUID, candidate.Link, candidate.MagnetURI)
+	}
+	return Result{
+		WatchlistID: watchlistID,
+		Key:         key,
+		Title:       strings.TrimSpace(candidate.Title),
+		Tracker:     candidate.Tracker,
+		Link:        firstNonEmpty(candidate.MagnetURI, candidate.Link),
+		Size:        candidate.Size,
+		Seeders:     candidate.Seeders,
+		PublishDate: candidate.PublishDate,
+		SeenAt:      now,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/notify"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+	"github.com/leothevan2444/moji/internal/tracker"
+	"github.com/leothevan2444/moji/pkg/jackett"
+)
+
+type fakeTracker struct {
+	results []jackett.SearchResult
+	err     error
+	options tracker.SearchOptions
+}
+
+func (f *fakeTracker) Search(_ string, options ...tracker.SearchOption) ([]jackett.SearchResult, error) {
+	f.options = tracker.SearchOptions{}
+	for _, option := range options {
+		option(&f.options)
+	}
+	return f.results, f.err
+}
+
+type fakeTaskCreator struct {
+	requests []taskruntime.AddTorrentRequest
+	err      error
+}
+
+func (f *fakeTaskCreator) AddTorrentContext(_ context.Context, req taskruntime.AddTorrentRequest) (*taskruntime.Task, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &taskruntime.Task{ID: "task-" + req.URL}, nil
+}
+
+type fakeNotifier struct {
+	events []notify.Event
+}
+
+func (f *fakeNotifier) Notify(event notify.Event) { f.events = append(f.events, event) }
+
+func newTestService(t *testing.T, tr *fakeTracker, options ...Option) *Service {
+	t.Helper()
+	service, err := New(filepath.Join(t.TempDir(), "watchlists.db"), tr, options...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+	return service
+}
+
+func TestRunRecordsBaselineThenNotifiesNewMatches(t *testing.T) {
+	tr := &fakeTracker{results: []jackett.SearchResult{{Title: "Old Release", InfoHash: "AAA", Link: "https://t/old", Seeders: 5}}}
+	notifier := &fakeNotifier{}
+	service := newTestService(t, tr, WithNotifier(notifier))
+
+	item, err := service.Create(context.Background(), Input{Query: "keyword", Indexers: []string{"onejav", " onejav "}, Categories: []int{6000}, Exclude: []string{"sample"}, MinSeeders: 2, Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if item.Name != "keyword" || item.Action != ActionNotify || item.Interval != DefaultInterval || len(item.Indexers) != 1 {
+		t.Fatalf("unexpected normalized watchlist: %+v", item)
+	}
+
+	first, err := service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if len(first.New) != 1 || first.New[0].Status != ResultStatusBaseline || len(notifier.events) != 0 {
+		t.Fatalf("expected a silent baseline, got %+v events=%d", first.New, len(notifier.events))
+	}
+	if len(tr.options.Trackers) != 1 || tr.options.Trackers[0] != "onejav" || tr.options.Categories[0] != 6000 {
+		t.Fatalf("unexpected search options: %+v", tr.options)
+	}
+
+	tr.results = append(tr.results,
+		jackett.SearchResult{Title: "New Release", InfoHash: "BBB", Link: "https://t/new", Tracker: "onejav", Seeders: 3},
+		jackett.SearchResult{Title: "New Release SAMPLE", InfoHash: "CCC", Link: "https://t/sample", Seeders: 9},
+		jackett.SearchResult{Title: "Unseeded", InfoHash: "DDD", Link: "https://t/dead", Seeders: 0},
+	)
+	second, err := service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	statuses := map[string]ResultStatus{}
+	for _, result := range second.New {
+		statuses[result.Key] = result.Status
+	}
+	if len(second.New) != 3 || statuses["bbb"] != ResultStatusNotified || statuses["ccc"] != ResultStatusExcluded || statuses["ddd"] != ResultStatusExcluded {
+		t.Fatalf("unexpected second run results: %+v", second.New)
+	}
+	if len(notifier.events) != 1 || notifier.events[0].Type != notify.EventWatchlistMatch || notifier.events[0].Title != "New Release" || notifier.events[0].Watchlist != "keyword" {
+		t.Fatalf("unexpected notifications: %+v", notifier.events)
+	}
+
+	third, err := service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("third Run: %v", err)
+	}
+	if len(third.New) != 0 || len(notifier.events) != 1 {
+		t.Fatalf("expected seen results to be skipped, got %+v", third.New)
+	}
+	results, err := service.Results(context.Background(), item.ID, 0)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 recorded results, got %d", len(results))
+	}
+}
+
+func TestRunCreatesTasksForDownloadWatchlists(t *testing.T) {
+	tr := &fakeTracker{}
+	tasks := &fakeTaskCreator{}
+	service := newTestService(t, tr, WithTaskCreator(tasks))
+	item, err := service.Create(context.Background(), Input{Query: "keyword", Action: "download", Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := service.Run(context.Background(), item.ID); err != nil {
+		t.Fatalf("baseline Run: %v", err)
+	}
+
+	tr.results = []jackett.SearchResult{
+		{Title: "Magnet", GUID: "guid-1", Link: "https://t/1", MagnetURI: "magnet:?xt=urn:btih:111"},
+		{Title: "Link", GUID: "guid-2", Link: "https://t/2"},
+	}
+	summary, err := service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(tasks.requests) != 2 || tasks.requests[0].URL != "magnet:?xt=urn:btih:111" || tasks.requests[0].Source != taskruntime.TaskSourceWatchlist {
+		t.Fatalf("unexpected task requests: %+v", tasks.requests)
+	}
+	if summary.New[0].Status != ResultStatusQueued || summary.New[0].TaskID == "" {
+		t.Fatalf("unexpected queued result: %+v", summary.New[0])
+	}
+
+	tasks.err = taskruntime.ErrDuplicateTorrentTask
+	tr.results = append(tr.results, jackett.SearchResult{Title: "Again", GUID: "guid-3", Link: "https://t/3"})
+	summary, err = service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(summary.New) != 1 || summary.New[0].Status != ResultStatusDuplicate {
+		t.Fatalf("expected a duplicate result, got %+v", summary.New)
+	}
+}
+
+func TestRunRetriesFailedResults(t *testing.T) {
+	tr := &fakeTracker{}
+	tasks := &fakeTaskCreator{}
+	service := newTestService(t, tr, WithTaskCreator(tasks))
+	item, err := service.Create(context.Background(), Input{Query: "keyword", Action: "download", Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := service.Run(context.Background(), item.ID); err != nil {
+		t.Fatalf("baseline Run: %v", err)
+	}
+
+	tasks.err = errors.New("qbittorrent unavailable")
+	tr.results = []jackett.SearchResult{{Title: "Flaky", GUID: "guid-1", Link: "https://t/1"}}
+	summary, err := service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(summary.New) != 1 || summary.New[0].Status != ResultStatusFailed {
+		t.Fatalf("expected a failed result, got %+v", summary.New)
+	}
+
+	tasks.err = nil
+	summary, err = service.Run(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("retry Run: %v", err)
+	}
+	if len(summary.New) != 1 || summary.New[0].Status != ResultStatusQueued || len(tasks.requests) != 2 {
+		t.Fatalf("expected the failed result to be retried, got %+v after %d requests", summary.New, len(tasks.requests))
+	}
+	results, err := service.Results(context.Background(), item.ID, 10)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != ResultStatusQueued {
+		t.Fatalf("expected the retried row to replace the failed one, got %+v", results)
+	}
+}
+
+func TestRunRecordsSearchErrorAndUpdateResetsBaseline(t *testing.T) {
+	tr := &fakeTracker{err: errors.New("jackett down")}
+	service := newTestService(t, tr)
+	item, err := service.Create(context.Background(), Input{Query: "keyword", Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := service.Run(context.Background(), item.ID); err == nil {
+		t.Fatal("expected search error")
+	}
+	stored, err := service.Get(context.Background(), item.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if stored.LastError == "" || stored.LastRunAt == nil || stored.LastSuccessAt != nil {
+		t.Fatalf("expected recorded error without baseline, got %+v", stored)
+	}
+
+	tr.err = nil
+	tr.results = []jackett.SearchResult{{Title: "Result", GUID: "guid-1"}}
+	if _, err := service.Run(context.Background(), item.ID); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	updated, err := service.Update(context.Background(), item.ID, Input{Query: "other", Enabled: true})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.LastSuccessAt != nil {
+		t.Fatalf("expected search change to reset the baseline, got %+v", updated)
+	}
+	results, err := service.Results(context.Background(), item.ID, 10)
+	if err != nil {
+		t.Fatalf("Results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected results to be forgotten, got %+v", results)
+	}
+	if err := service.Delete(context.Background(), item.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := service.Get(context.Background(), item.ID); !errors.Is(err, ErrWatchlistNotFound) {
+		t.Fatalf("expected ErrWatchlistNotFound, got %v", err)
+	}
+}
+
+func TestRunDueSkipsDisabledAndRecentWatchlists(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := &fakeTracker{}
+	service := newTestService(t, tr, WithClock(func() time.Time { return now }))
+	enabled, _ := service.Create(context.Background(), Input{Query: "enabled", Enabled: true})
+	disabled, _ := service.Create(context.Background(), Input{Query: "disabled"})
+
+	service.RunDue(context.Background())
+	first, _ := service.Get(context.Background(), enabled.ID)
+	other, _ := service.Get(context.Background(), disabled.ID)
+	if first.LastRunAt == nil || other.LastRunAt != nil {
+		t.Fatalf("expected only the enabled watchlist to run, got %+v / %+v", first, other)
+	}
+
+	now = now.Add(30 * time.Minute)
+	service.RunDue(context.Background())
+	second, _ := service.Get(context.Background(), enabled.ID)
+	if !second.LastRunAt.Equal(*first.LastRunAt) {
+		t.Fatalf("expected watchlist to wait for its interval, last run moved to %v", second.LastRunAt)
+	}
+}
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS watchlist_meta (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS watchlists (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    indexers_json TEXT NOT NULL DEFAULT '[]',
+    categories_json TEXT NOT NULL DEFAULT '[]',
+    exclude_json TEXT NOT NULL DEFAULT '[]',
+    min_seeders INTEGER NOT NULL DEFAULT 0,
+    action TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    interval_seconds INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    last_run_at TEXT NOT NULL DEFAULT '',
+    last_success_at TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_results (
+    watchlist_id TEXT NOT NULL,
+    result_key TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    tracker TEXT NOT NULL DEFAULT '',
+    link TEXT NOT NULL DEFAULT '',
+    size INTEGER NOT NULL DEFAULT 0,
+    seeders INTEGER NOT NULL DEFAULT 0,
+    publish_date TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    task_id TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    seen_at TEXT NOT NULL,
+    PRIMARY KEY (watchlist_id, result_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_results_seen ON watchlist_results(watchlist_id, seen_at);
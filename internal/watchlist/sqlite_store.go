@@ -0,0 +1,302 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+const sqliteSchemaVersion = "1"
+
+const watchlistColumns = `id,name,query,indexers_json,categories_json,exclude_json,min_seeders,action,enabled,interval_seconds,created_at,updated_at,last_run_at,last_success_at,last_error`
+
+const resultColumns = `watchlist_id,result_key,title,tracker,link,size,seeders,publish_date,status,task_id,error,seen_at`
+
+type sqliteStore struct {
+	db *sqlx.DB
+}
+
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("watchlist: database path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("watchlist: create database directory: %w", err)
+		}
+	}
+	db, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("watchlist: open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("watchlist: configure database: %w", err)
+		}
+	}
+	store := &sqliteStore{db: db}
+	if err := store.initSchema(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *sqliteStore) initSchema() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS watchlist_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`); err != nil {
+		return err
+	}
+	var version string
+	err := s.db.Get(&version, `SELECT value FROM watchlist_meta WHERE key = 'schema_version'`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if version != "" && version != sqliteSchemaVersion {
+		for _, table := range []string{"watchlist_results", "watchlists", "watchlist_meta"} {
+			if _, err := s.db.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("watchlist: initialize schema: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO watchlist_meta(key,value) VALUES('schema_version',?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, sqliteSchemaVersion)
+	return err
+}
+
+type watchlistRow struct {
+	ID              string `db:"id"`
+	Name            string `db:"name"`
+	Query           string `db:"query"`
+	IndexersJSON    string `db:"indexers_json"`
+	CategoriesJSON  string `db:"categories_json"`
+	ExcludeJSON     string `db:"exclude_json"`
+	MinSeeders      int    `db:"min_seeders"`
+	Action          string `db:"action"`
+	Enabled         bool   `db:"enabled"`
+	IntervalSeconds int64  `db:"interval_seconds"`
+	CreatedAt       string `db:"created_at"`
+	UpdatedAt       string `db:"updated_at"`
+	LastRunAt       string `db:"last_run_at"`
+	LastSuccessAt   string `db:"last_success_at"`
+	LastError       string `db:"last_error"`
+}
+
+func (r watchlistRow) watchlist() Watchlist {
+	out := Watchlist{
+		ID:            r.ID,
+		Name:          r.Name,
+		Query:         r.Query,
+		MinSeeders:    r.MinSeeders,
+		Action:        Action(r.Action),
+		Enabled:       r.Enabled,
+		Interval:      time.Duration(r.IntervalSeconds) * time.Second,
+		LastRunAt:     parseOptionalTime(r.LastRunAt),
+		LastSuccessAt: parseOptionalTime(r.LastSuccessAt),
+		LastError:     r.LastError,
+	}
+	_ = json.Unmarshal([]byte(r.IndexersJSON), &out.Indexers)
+	_ = json.Unmarshal([]byte(r.CategoriesJSON), &out.Categories)
+	_ = json.Unmarshal([]byte(r.ExcludeJSON), &out.Exclude)
+	out.CreatedAt, _ = time.Parse(time.RFC3339Nano, r.CreatedAt)
+	out.UpdatedAt, _ = time.Parse(time.RFC3339Nano, r.UpdatedAt)
+	return out
+}
+
+func (s *sqliteStore) list(ctx context.Context) ([]Watchlist, error) {
+	var rows []watchlistRow
+	if err := s.db.SelectContext(ctx, &rows, `SELECT `+watchlistColumns+` FROM watchlists ORDER BY name COLLATE NOCASE ASC, id ASC`); err != nil {
+		return nil, err
+	}
+	out := make([]Watchlist, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.watchlist())
+	}
+	return out, nil
+}
+
+func (s *sqliteStore) find(ctx context.Context, id string) (Watchlist, error) {
+	var row watchlistRow
+	err := s.db.GetContext(ctx, &row, `SELECT `+watchlistColumns+` FROM watchlists WHERE id=?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Watchlist{}, ErrWatchlistNotFound
+	}
+	if err != nil {
+		return Watchlist{}, err
+	}
+	return row.watchlist(), nil
+}
+
+// save inserts or replaces the whole watchlist row.
+func (s *sqliteStore) save(ctx context.Context, item Watchlist) error {
+	indexers, _ := json.Marshal(nonNil(item.Indexers))
+	categories, _ := json.Marshal(nonNil(item.Categories))
+	exclude, _ := json.Marshal(nonNil(item.Exclude))
+	_, err := s.db.ExecContext(ctx, `INSERT INTO watchlists(`+watchlistColumns+`) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+		ON CONFLICT(id) DO UPDATE SET name=excluded.name,query=excluded.query,indexers_json=excluded.indexers_json,
+		categories_json=excluded.categories_json,exclude_json=excluded.exclude_json,min_seeders=excluded.min_seeders,
+		action=excluded.action,enabled=excluded.enabled,interval_seconds=excluded.interval_seconds,updated_at=excluded.updated_at,
+		last_run_at=excluded.last_run_at,last_success_at=excluded.last_success_at,last_error=excluded.last_error`,
+		item.ID, item.Name, item.Query, string(indexers), string(categories), string(exclude), item.MinSeeders,
+		string(item.Action), item.Enabled, int64(item.Interval/time.Second), formatTime(item.CreatedAt), formatTime(item.UpdatedAt),
+		formatOptionalTime(item.LastRunAt), formatOptionalTime(item.LastSuccessAt), item.LastError)
+	return err
+}
+
+func (s *sqliteStore) delete(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := tx.ExecContext(ctx, `DELETE FROM watchlists WHERE id=?`, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrWatchlistNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM watchlist_results WHERE watchlist_id=?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// resetResults forgets every result of a watchlist so its next run takes a
+// fresh baseline.
+func (s *sqliteStore) resetResults(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM watchlist_results WHERE watchlist_id=?`, id)
+	return err
+}
+
+// seenKeys returns the results a watchlist has already acted on. FAILED
+// results are left out so the next run retries them.
+func (s *sqliteStore) seenKeys(ctx context.Context, id string) (map[string]bool, error) {
+	var keys []string
+	if err := s.db.SelectContext(ctx, &keys, `SELECT result_key FROM watchlist_results WHERE watchlist_id=? AND status<>?`, id, string(ResultStatusFailed)); err != nil {
+		return nil, err
+	}
+	out := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		out[key] = true
+	}
+	return out, nil
+}
+
+// insertResults records newly seen results and trims the watchlist to the
+// newest maxRetained rows. Trimmed keys are forgotten, so a result that drops
+// out of the window and reappears counts as new again. A retried FAILED
+// result replaces its earlier row.
+func (s *sqliteStore) insertResults(ctx context.Context, id string, results []Result, maxRetained int) error {
+	if len(results) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, result := range results {
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO watchlist_results(`+resultColumns+`) VALUES(?,?,?,?,?,?,?,?,?,?,?,?)`,
+			id, result.Key, result.Title, result.Tracker, result.Link, result.Size, result.Seeders, result.PublishDate,
+			string(result.Status), result.TaskID, result.Error, formatTime(result.SeenAt)); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM watchlist_results WHERE watchlist_id=? AND result_key IN (
+		SELECT result_key FROM watchlist_results WHERE watchlist_id=? ORDER BY seen_at DESC, result_key DESC LIMIT -1 OFFSET ?
+	)`, id, id, maxRetained); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type resultRow struct {
+	WatchlistID string `db:"watchlist_id"`
+	Key         string `db:"result_key"`
+	Title       string `db:"title"`
+	Tracker     string `db:"tracker"`
+	Link        string `db:"link"`
+	Size        int64  `db:"size"`
+	Seeders     int    `db:"seeders"`
+	PublishDate string `db:"publish_date"`
+	Status      string `db:"status"`
+	TaskID      string `db:"task_id"`
+	Error       string `db:"error"`
+	SeenAt      string `db:"seen_at"`
+}
+
+func (s *sqliteStore) results(ctx context.Context, id string, limit int) ([]Result, error) {
+	var rows []resultRow
+	if err := s.db.SelectContext(ctx, &rows, `SELECT `+resultColumns+` FROM watchlist_results WHERE watchlist_id=?
+		ORDER BY seen_at DESC, result_key ASC LIMIT ?`, id, limit); err != nil {
+		return nil, err
+	}
+	out := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		seenAt, _ := time.Parse(time.RFC3339Nano, row.SeenAt)
+		out = append(out, Result{
+			WatchlistID: row.WatchlistID,
+			Key:         row.Key,
+			Title:       row.Title,
+			Tracker:     row.Tracker,
+			Link:        row.Link,
+			Size:        row.Size,
+			Seeders:     row.Seeders,
+			PublishDate: row.PublishDate,
+			Status:      ResultStatus(row.Status),
+			TaskID:      row.TaskID,
+			Error:       row.Error,
+			SeenAt:      seenAt,
+		})
+	}
+	return out, nil
+}
+
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// timeLayout is fixed width so stored timestamps compare correctly as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatTime(value time.Time) string { return value.UTC().Format(timeLayout) }
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return formatTime(*value)
+}
+
+func parseOptionalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,155 @@
+package watchlist
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Action string
+
+const (
+	// ActionNotify sends a WATCHLIST_MATCH notification for each new match.
+	ActionNotify Action = "NOTIFY"
+	// ActionDownload creates a task for each new match.
+	ActionDownload Action = "DOWNLOAD"
+)
+
+const (
+	DefaultInterval     = time.Hour
+	MinInterval         = 15 * time.Minute
+	schedulerInterval   = time.Minute
+	maxRetainedResults  = 2000
+	defaultResultsLimit = 50
+)
+
+var ErrWatchlistNotFound = errors.New("watchlist: watchlist not found")
+
+// Watchlist is a saved Jackett search the scheduler re-runs every Interval.
+// Exclude lists case-insensitive title terms that drop a result.
+type Watchlist struct {
+	ID            string
+	Name          string
+	Query         string
+	Indexers      []string
+	Categories    []int
+	Exclude       []string
+	MinSeeders    int
+	Action        Action
+	Enabled       bool
+	Interval      time.Duration
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	LastRunAt     *time.Time
+	LastSuccessAt *time.Time
+	LastError     string
+}
+
+// Input is the editable part of a Watchlist.
+type Input struct {
+	Name       string
+	Query      string
+	Indexers   []string
+	Categories []int
+	Exclude    []string
+	MinSeeders int
+	Action     Action
+	Enabled    bool
+	Interval   time.Duration
+}
+
+func (in Input) normalize() (Input, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Query = strings.TrimSpace(in.Query)
+	if in.Query == "" {
+		return Input{}, errors.New("watchlist: query is required")
+	}
+	if in.Name == "" {
+		in.Name = in.Query
+	}
+	in.Indexers = normalizeTerms(in.Indexers)
+	in.Exclude = normalizeTerms(in.Exclude)
+	categories := make([]int, 0, len(in.Categories))
+	for _, category := range in.Categories {
+		if category > 0 && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	in.Categories = categories
+	if in.MinSeeders < 0 {
+		in.MinSeeders = 0
+	}
+	switch Action(strings.ToUpper(strings.TrimSpace(string(in.Action)))) {
+	case ActionDownload:
+		in.Action = ActionDownload
+	default:
+		in.Action = ActionNotify
+	}
+	if in.Interval <= 0 {
+		in.Interval = DefaultInterval
+	}
+	if in.Interval < MinInterval {
+		in.Interval = MinInterval
+	}
+	return in, nil
+}
+
+// sameSearch reports whether in runs the same tracker search as w. Changing
+// the search restarts the baseline so existing results are not acted on.
+func (w Watchlist) sameSearch(in Input) bool {
+	return w.Query == in.Query && slices.Equal(w.Indexers, in.Indexers) && slices.Equal(w.Categories, in.Categories)
+}
+
+func (w Watchlist) due(now time.Time) bool {
+	return w.Enabled && (w.LastRunAt == nil || !now.Before(w.LastRunAt.Add(w.Interval)))
+}
+
+type ResultStatus string
+
+const (
+	// ResultStatusBaseline marks results present when the watchlist first ran.
+	ResultStatusBaseline  ResultStatus = "BASELINE"
+	ResultStatusExcluded  ResultStatus = "EXCLUDED"
+	ResultStatusNotified  ResultStatus = "NOTIFIED"
+	ResultStatusQueued    ResultStatus = "QUEUED"
+	ResultStatusDuplicate ResultStatus = "DUPLICATE"
+	ResultStatusFailed    ResultStatus = "FAILED"
+)
+
+// Result is one tracker result a watchlist has seen. Each result is acted on
+// at most once; later runs skip it unless the action FAILED, in which case the
+// next run tries it again.
+type Result struct {
+	WatchlistID string
+	Key         string
+	Title       string
+	Tracker     string
+	Link        string
+	Size        int64
+	Seeders     int
+	PublishDate string
+	Status      ResultStatus
+	TaskID      string
+	Error       string
+	SeenAt      time.Time
+}
+
+// RunSummary describes one watchlist run. New holds the results seen for the
+// first time in this run.
+type RunSummary struct {
+	Watchlist Watchlist
+	Fetched   int
+	New       []Result
+}
+
+func normalizeTerms(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(out, value) {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/stats"
+	"github.com/leothevan2444/moji/internal/subscription"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+// envelope is the JSON body POSTed to receivers. The delivery ID travels in
+// the X-Moji-Delivery header so a redelivery can reuse the stored body.
+type envelope struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type event struct {
+	Type       string
+	OccurredAt time.Time
+	Data       any
+}
+
+type taskPayload struct {
+	ID                string     `json:"id"`
+	Source            string     `json:"source"`
+	Code              string     `json:"code"`
+	Stage             string     `json:"stage"`
+	StageStatus       string     `json:"stage_status"`
+	StageErrorCode    string     `json:"stage_error_code,omitempty"`
+	StageErrorMessage string     `json:"stage_error_message,omitempty"`
+	Progress          float64    `json:"progress"`
+	TorrentName       string     `json:"torrent_name,omitempty"`
+	TorrentHash       string     `json:"torrent_hash,omitempty"`
+	ContentPath       string     `json:"content_path,omitempty"`
+	LibraryPath       string     `json:"library_path,omitempty"`
+	DownloadedAt      *time.Time `json:"downloaded_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type taskEventPayload struct {
+	TaskID string       `json:"task_id"`
+	Task   *taskPayload `json:"task,omitempty"`
+}
+
+type performerSubscriptionPayload struct {
+	PerformerID           string     `json:"performer_id"`
+	Name                  string     `json:"name,omitempty"`
+	LastCheckedAt         *time.Time `json:"last_checked_at,omitempty"`
+	LastError             string     `json:"last_error,omitempty"`
+	PendingReleaseCount   int        `json:"pending_release_count"`
+	ProcessedReleaseCount int        `json:"processed_release_count"`
+}
+
+type serviceStatusPayload struct {
+	Services []serviceState `json:"services"`
+}
+
+type serviceState struct {
+	Service   string     `json:"service"`
+	Healthy   bool       `json:"healthy"`
+	LastError string     `json:"last_error,omitempty"`
+	OKAt      *time.Time `json:"ok_at,omitempty"`
+}
+
+func taskEvent(source *taskruntime.TaskEvent, now time.Time) event {
+	payload := taskEventPayload{TaskID: source.TaskID}
+	if task := source.Task; task != nil {
+		libraryPath := task.StashScanPath
+		if libraryPath == "" {
+			libraryPath = task.MojiTransferPath
+		}
+		payload.Task = &taskPayload{
+			ID:                task.ID,
+			Source:            string(task.Source),
+			Code:              task.Code,
+			Stage:             string(task.Stage),
+			StageStatus:       string(task.StageStatus),
+			StageErrorCode:    task.StageErrorCode,
+			StageErrorMessage: task.StageErrorMessage,
+			Progress:          task.Progress,
+			TorrentName:       task.TorrentName,
+			TorrentHash:       task.TorrentHash,
+			ContentPath:       task.ContentPath,
+			LibraryPath:       libraryPath,
+			DownloadedAt:      task.DownloadCompletedAt,
+			CreatedAt:         task.CreatedAt,
+			UpdatedAt:         task.UpdatedAt,
+		}
+	}
+	eventType := "task." + strings.ToLower(string(source.Type))
+	if source.Type == taskruntime.TaskEventUpdated && source.ProgressOnly {
+		eventType = EventTaskProgress
+	}
+	return event{Type: eventType, OccurredAt: now, Data: payload}
+}
+
+func performerSubscriptionEvent(source *subscription.PerformerSubscriptionEvent, now time.Time) event {
+	payload := performerSubscriptionPayload{PerformerID: source.PerformerID}
+	if state := source.State; state != nil {
+		payload.Name = state.Performer.Name
+		payload.LastCheckedAt = state.LastCheckedAt
+		payload.LastError = state.LastError
+		payload.PendingReleaseCount = state.PendingReleaseCount
+		payload.ProcessedReleaseCount = state.ProcessedReleaseCount
+	}
+	return event{Type: "performer_subscription." + strings.ToLower(string(source.Type)), OccurredAt: now, Data: payload}
+}
+
+func serviceStatusEvent(source *stats.ServiceStatusEvent, provider StatsProvider) event {
+	payload := serviceStatusPayload{Services: make([]serviceState, 0, len(source.Services))}
+	var snapshot *stats.Snapshot
+	if provider != nil {
+		view := provider.SnapshotView()
+		snapshot = &view
+	}
+	for _, service := range source.Services {
+		state := serviceState{Service: string(service)}
+		if snapshot != nil {
+			var lastError string
+			var okAt time.Time
+			switch service {
+			case stats.ExternalServiceStash:
+				lastError, okAt = snapshot.Stash.LastError, snapshot.Stash.OKAt
+			case stats.ExternalServiceJackett:
+				lastError, okAt = snapshot.Jackett.LastError, snapshot.Jackett.OKAt
+			case stats.ExternalServiceQBittorrent:
+				lastError, okAt = snapshot.QBitt.LastError, snapshot.QBitt.OKAt
+			}
+			state.LastError = lastError
+			state.Healthy = lastError == ""
+			if !okAt.IsZero() {
+				state.OKAt = &okAt
+			}
+		}
+		payload.Services = append(payload.Services, state)
+	}
+	return event{Type: EventServiceStatusChanged, OccurredAt: source.ObservedAt, Data: payload}
+}
@@ -0,0 +1,379 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leothevan2444/moji/internal/logging"
+	"github.com/leothevan2444/moji/internal/stats"
+	"github.com/leothevan2444/moji/internal/subscription"
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+const (
+	HeaderEvent     = "X-Moji-Event"
+	HeaderDelivery  = "X-Moji-Delivery"
+	HeaderSignature = "X-Moji-Signature-256"
+)
+
+// StatsProvider supplies the current service health for service status
+// payloads; the bus event itself only names the services that changed.
+type StatsProvider interface {
+	SnapshotView() stats.Snapshot
+}
+
+// Service records every matching bus event as a pending delivery before any
+// network I/O happens, so the bus consumers never wait on a receiver and the
+// UI subscribers keep their own buffers to themselves.
+type Service struct {
+	store       *sqliteStore
+	webhooks    ConfigProvider
+	client      *http.Client
+	now         func() time.Time
+	taskEvents  taskruntime.TaskEventSource
+	performers  subscription.PerformerSubscriptionEventSource
+	services    stats.ServiceStatusEventSource
+	statsSource StatsProvider
+
+	wake chan struct{}
+}
+
+type Option func(*Service)
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(service *Service) {
+		if client != nil {
+			service.client = client
+		}
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(service *Service) {
+		if now != nil {
+			service.now = now
+		}
+	}
+}
+
+func WithTaskEvents(source taskruntime.TaskEventSource) Option {
+	return func(service *Service) {
+		if source != nil {
+			service.taskEvents = source
+		}
+	}
+}
+
+func WithPerformerSubscriptionEvents(source subscription.PerformerSubscriptionEventSource) Option {
+	return func(service *Service) {
+		if source != nil {
+			service.performers = source
+		}
+	}
+}
+
+func WithServiceStatusEvents(source stats.ServiceStatusEventSource, provider StatsProvider) Option {
+	return func(service *Service) {
+		if source != nil {
+			service.services = source
+			service.statsSource = provider
+		}
+	}
+}
+
+func New(path string, provider ConfigProvider, options ...Option) (*Service, error) {
+	store, err := openSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		provider = func() []Webhook { return nil }
+	}
+	service := &Service{
+		store:    store,
+		webhooks: provider,
+		client:   &http.Client{},
+		now:      time.Now,
+		wake:     make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(service)
+	}
+	return service, nil
+}
+
+func (s *Service) Close() error {
+	if s == nil || s.store == nil || s.store.db == nil {
+		return nil
+	}
+	return s.store.db.Close()
+}
+
+// Start subscribes to the configured event buses and runs the delivery loop
+// until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	if s.taskEvents != nil {
+		events := s.taskEvents.Subscribe(ctx)
+		go func() {
+			for source := range events {
+				s.enqueue(ctx, taskEvent(source, s.now().UTC()))
+			}
+		}()
+	}
+	if s.performers != nil {
+		events := s.performers.Subscribe(ctx)
+		go func() {
+			for source := range events {
+				s.enqueue(ctx, performerSubscriptionEvent(source, s.now().UTC()))
+			}
+		}()
+	}
+	if s.services != nil {
+		events := s.services.Subscribe(ctx)
+		go func() {
+			for source := range events {
+				s.enqueue(ctx, serviceStatusEvent(source, s.statsSource))
+			}
+		}()
+	}
+	go s.run(ctx)
+}
+
+func (s *Service) enqueue(ctx context.Context, evt event) {
+	if err := s.record(ctx, evt); err != nil {
+		logging.Errorf("webhook: record %s event: %v", evt.Type, err)
+	}
+}
+
+func (s *Service) record(ctx context.Context, evt event) error {
+	now := s.now().UTC()
+	if evt.OccurredAt.IsZero() {
+		evt.OccurredAt = now
+	}
+	deliveries := make([]Delivery, 0, 1)
+	for _, hook := range s.webhooks() {
+		if strings.TrimSpace(hook.URL) == "" || !hook.Accepts(evt.Type) {
+			continue
+		}
+		payload, err := json.Marshal(envelope{Event: evt.Type, OccurredAt: evt.OccurredAt, Data: evt.Data})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, Delivery{
+			ID:            uuid.NewString(),
+			Webhook:       hook.Name,
+			URL:           hook.URL,
+			Event:         evt.Type,
+			Payload:       payload,
+			Status:        DeliveryStatusPending,
+			CreatedAt:     now,
+			NextAttemptAt: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := s.store.insert(ctx, deliveries); err != nil {
+		return err
+	}
+	s.signal()
+	return nil
+}
+
+func (s *Service) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) run(ctx context.Context) {
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Time{}
+	for {
+		if s.now().Sub(lastCleanup) >= cleanupInterval {
+			if err := s.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				logging.Warnf("webhook: cleanup delivery log: %v", err)
+			}
+			lastCleanup = s.now()
+		}
+		s.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// dispatchDue sends the due deliveries, bounded by maxConcurrentSends so one
+// slow receiver cannot hold up the others, and returns once the batch is done.
+func (s *Service) dispatchDue(ctx context.Context) {
+	due, err := s.store.due(ctx, s.now().UTC(), dispatchBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.Warnf("webhook: load due deliveries: %v", err)
+		}
+		return
+	}
+	slots := make(chan struct{}, maxConcurrentSends)
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(delivery Delivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.attempt(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	if len(due) == dispatchBatchSize {
+		s.signal()
+	}
+}
+
+func (s *Service) attempt(ctx context.Context, delivery Delivery) {
+	hook, ok := s.webhook(delivery.Webhook)
+	now := s.now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	if !ok {
+		delivery.Status = DeliveryStatusFailed
+		delivery.LastError = "webhook is no longer configured"
+		delivery.NextAttemptAt = nil
+	} else {
+		status, err := s.send(ctx, hook, delivery)
+		delivery.ResponseStatus = status
+		switch {
+		case err == nil:
+			delivery.Status = DeliveryStatusDelivered
+			delivery.LastError = ""
+			delivery.NextAttemptAt = nil
+			delivery.DeliveredAt = &now
+		case delivery.Attempts >= hook.maxAttempts():
+			delivery.Status = DeliveryStatusFailed
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = nil
+		default:
+			next := now.Add(retryBackoff(delivery.Attempts))
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = &next
+		}
+	}
+	if err := s.store.recordAttempt(context.WithoutCancel(ctx), delivery); err != nil {
+		logging.Errorf("webhook: record attempt delivery_id=%s: %v", delivery.ID, err)
+	}
+	if delivery.Status == DeliveryStatusFailed {
+		logging.Warnf("webhook: delivery failed webhook=%s event=%s delivery_id=%s attempts=%d: %s", delivery.Webhook, delivery.Event, delivery.ID, delivery.Attempts, delivery.LastError)
+	}
+}
+
+func (s *Service) webhook(name string) (Webhook, bool) {
+	for _, hook := range s.webhooks() {
+		if hook.Name == name && strings.TrimSpace(hook.URL) != "" {
+			return hook, true
+		}
+	}
+	return Webhook{}, false
+}
+
+func (s *Service) send(ctx context.Context, hook Webhook, delivery Delivery) (int, error) {
+	sendCtx, cancel := context.WithTimeout(ctx, hook.timeout())
+	defer cancel()
+	request, err := http.NewRequestWithContext(sendCtx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Moji-Webhook")
+	request.Header.Set(HeaderEvent, delivery.Event)
+	request.Header.Set(HeaderDelivery, delivery.ID)
+	if hook.Secret != "" {
+		request.Header.Set(HeaderSignature, Sign(hook.Secret, delivery.Payload))
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("receiver responded with HTTP %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// Sign returns the value of the signature header: "sha256=" followed by the
+// hex HMAC-SHA256 of the raw request body keyed with the webhook secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func retryBackoff(attempts int) time.Duration {
+	backoff := minRetryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+	return backoff
+}
+
+func (s *Service) Deliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, error) {
+	filter.Webhook = strings.TrimSpace(filter.Webhook)
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	return s.store.list(ctx, filter)
+}
+
+// Redeliver queues a fresh copy of a logged delivery with the same payload.
+func (s *Service) Redeliver(ctx context.Context, id string) (Delivery, error) {
+	original, err := s.store.find(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return Delivery{}, err
+	}
+	now := s.now().UTC()
+	next := Delivery{
+		ID:            uuid.NewString(),
+		Webhook:       original.Webhook,
+		URL:           original.URL,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        DeliveryStatusPending,
+		CreatedAt:     now,
+		NextAttemptAt: &now,
+		RedeliveryOf:  original.ID,
+	}
+	if hook, ok := s.webhook(original.Webhook); ok {
+		next.URL = hook.URL
+	}
+	if err := s.store.insert(ctx, []Delivery{next}); err != nil {
+		return Delivery{}, err
+	}
+	s.signal()
+	return next, nil
+}
+
+func (s *Service) Cleanup(ctx context.Context) error {
+	return s.store.cleanup(ctx, s.now().UTC().Add(-retentionPeriod), maxRetainedRows)
+}
@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leothevan2444/moji/internal/taskruntime"
+)
+
+func newTestService(t *testing.T, webhooks []Webhook, options ...Option) *Service {
+	t.Helper()
+	service, err := New(filepath.Join(t.TempDir(), "webhooks.db"), func() []Webhook { return webhooks }, options...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+	return service
+}
+
+func TestWebhookAcceptsEventFilters(t *testing.T) {
+	for _, test := range []struct {
+		events []string
+		event  string
+		want   bool
+	}{
+		{nil, EventTaskUpdated, true},
+		{[]string{"task.*"}, EventTaskUpdated, true},
+		{[]string{"task.*"}, EventServiceStatusChanged, false},
+		{[]string{EventTaskCreated}, EventTaskUpdated, false},
+		{[]string{"*"}, EventPerformerSubscriptionDeleted, true},
+		{nil, EventTaskProgress, false},
+		{[]string{"*", "task.*"}, EventTaskProgress, false},
+		{[]string{" task.progress "}, EventTaskProgress, true},
+	} {
+		if got := (Webhook{Events: test.events}).Accepts(test.event); got != test.want {
+			t.Errorf("Accepts(%v, %q) = %v, want %v", test.events, test.event, got, test.want)
+		}
+	}
+}
+
+func TestServiceDeliversSignedTaskEvent(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer server.Close()
+
+	bus := taskruntime.NewTaskEventBus(4)
+	defer bus.Close()
+	service := newTestService(t, []Webhook{
+		{Name: "tasks", URL: server.URL, Secret: "s3cret", Events: []string{"task.*"}},
+		{Name: "status-only", URL: server.URL, Events: []string{EventServiceStatusChanged}},
+	}, WithTaskEvents(bus))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Start(ctx)
+	waitForSubscriber(t, bus)
+
+	bus.Publish(&taskruntime.TaskEvent{Type: taskruntime.TaskEventUpdated, TaskID: "task-1", Task: &taskruntime.Task{ID: "task-1", Code: "ABC-123", Stage: taskruntime.TaskStageScanning}})
+
+	var got received
+	select {
+	case got = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if got.header.Get(HeaderEvent) != EventTaskUpdated || got.header.Get(HeaderDelivery) == "" {
+		t.Fatalf("unexpected headers: %v", got.header)
+	}
+	if signature := got.header.Get(HeaderSignature); signature != Sign("s3cret", got.body) {
+		t.Fatalf("signature %q does not match body", signature)
+	}
+	var payload struct {
+		Event string `json:"event"`
+		Data  struct {
+			Task struct {
+				Code  string `json:"code"`
+				Stage string `json:"stage"`
+			} `json:"task"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Event != EventTaskUpdated || payload.Data.Task.Code != "ABC-123" || payload.Data.Task.Stage != "SCANNING" {
+		t.Fatalf("unexpected payload: %s", got.body)
+	}
+
+	deliveries := waitForDeliveries(t, service, DeliveryStatusDelivered, 1)
+	if deliveries[0].Webhook != "tasks" || deliveries[0].ResponseStatus != http.StatusOK || deliveries[0].Attempts != 1 {
+		t.Fatalf("unexpected delivery log: %+v", deliveries[0])
+	}
+}
+
+func TestServiceRetriesWithBackoffThenFailsAndCanRedeliver(t *testing.T) {
+	var mu sync.Mutex
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	var clockMu sync.Mutex
+	clock := func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return now
+	}
+	service := newTestService(t, []Webhook{{Name: "flaky", URL: server.URL, MaxAttempts: 2}}, WithClock(clock))
+	ctx := context.Background()
+	if err := service.record(ctx, event{Type: EventTaskCreated, Data: map[string]string{"task_id": "task-1"}}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	service.dispatchDue(ctx)
+	pending := waitForDeliveries(t, service, DeliveryStatusPending, 1)
+	if pending[0].Attempts != 1 || pending[0].ResponseStatus != http.StatusInternalServerError || pending[0].NextAttemptAt == nil || !pending[0].NextAttemptAt.Equal(now.Add(minRetryBackoff)) {
+		t.Fatalf("expected scheduled retry, got %+v", pending[0])
+	}
+
+	service.dispatchDue(ctx)
+	if again, _ := service.Deliveries(ctx, DeliveryFilter{Status: DeliveryStatusPending}); len(again) != 1 || again[0].Attempts != 1 {
+		t.Fatalf("retry should wait for its backoff, got %+v", again)
+	}
+
+	clockMu.Lock()
+	now = now.Add(minRetryBackoff)
+	clockMu.Unlock()
+	service.dispatchDue(ctx)
+	failed := waitForDeliveries(t, service, DeliveryStatusFailed, 1)
+	if failed[0].Attempts != 2 || failed[0].LastError == "" {
+		t.Fatalf("expected failed delivery after max attempts, got %+v", failed[0])
+	}
+
+	mu.Lock()
+	status = http.StatusNoContent
+	mu.Unlock()
+	redelivery, err := service.Redeliver(ctx, failed[0].ID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if redelivery.RedeliveryOf != failed[0].ID || string(redelivery.Payload) != string(failed[0].Payload) {
+		t.Fatalf("unexpected redelivery: %+v", redelivery)
+	}
+	service.dispatchDue(ctx)
+	delivered := waitForDeliveries(t, service, DeliveryStatusDelivered, 1)
+	if delivered[0].ID != redelivery.ID {
+		t.Fatalf("expected redelivery to be delivered, got %+v", delivered[0])
+	}
+	if _, err := service.Redeliver(ctx, "missing"); err != ErrDeliveryNotFound {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestRetryBackoffIsCapped(t *testing.T) {
+	if got := retryBackoff(1); got != minRetryBackoff {
+		t.Fatalf("retryBackoff(1) = %s", got)
+	}
+	if got := retryBackoff(3); got != 4*minRetryBackoff {
+		t.Fatalf("retryBackoff(3) = %s", got)
+	}
+	if got := retryBackoff(50); got != maxRetryBackoff {
+		t.Fatalf("retryBackoff(50) = %s", got)
+	}
+}
+
+func waitForSubscriber(t *testing.T, bus *taskruntime.TaskEventBus) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for bus.SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook service did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func waitForDeliveries(t *testing.T, service *Service, status DeliveryStatus, count int) []Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := service.Deliveries(context.Background(), DeliveryFilter{Status: status})
+		if err != nil {
+			t.Fatalf("Deliveries: %v", err)
+		}
+		if len(deliveries) == count {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d %s deliveries, got %+v", count, status, deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
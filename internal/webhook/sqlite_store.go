@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+const sqliteSchemaVersion = "1"
+
+const deliveryColumns = `id,webhook,url,event,payload_json,status,attempts,response_status,last_error,created_at,last_attempt_at,next_attempt_at,delivered_at,redelivery_of`
+
+type sqliteStore struct {
+	db *sqlx.DB
+}
+
+func openSQLiteStore(path string) (*sqliteStore, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("webhook: database path is required")
+	}
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("webhook: create database directory: %w", err)
+		}
+	}
+	db, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("webhook: configure database: %w", err)
+		}
+	}
+	store := &sqliteStore{db: db}
+	if err := store.initSchema(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *sqliteStore) initSchema() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS webhook_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`); err != nil {
+		return err
+	}
+	var version string
+	err := s.db.Get(&version, `SELECT value FROM webhook_meta WHERE key = 'schema_version'`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if version != "" && version != sqliteSchemaVersion {
+		for _, table := range []string{"webhook_deliveries", "webhook_meta"} {
+			if _, err := s.db.Exec(`DROP TABLE IF EXISTS ` + table); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("webhook: initialize schema: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO webhook_meta(key,value) VALUES('schema_version',?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, sqliteSchemaVersion)
+	return err
+}
+
+type deliveryRow struct {
+	ID             string `db:"id"`
+	Webhook        string `db:"webhook"`
+	URL            string `db:"url"`
+	Event          string `db:"event"`
+	Payload        []byte `db:"payload_json"`
+	Status         string `db:"status"`
+	Attempts       int    `db:"attempts"`
+	ResponseStatus int    `db:"response_status"`
+	LastError      string `db:"last_error"`
+	CreatedAt      string `db:"created_at"`
+	LastAttemptAt  string `db:"last_attempt_at"`
+	NextAttemptAt  string `db:"next_attempt_at"`
+	DeliveredAt    string `db:"delivered_at"`
+	RedeliveryOf   string `db:"redelivery_of"`
+}
+
+func (r deliveryRow) delivery() Delivery {
+	createdAt, _ := time.Parse(time.RFC3339Nano, r.CreatedAt)
+	return Delivery{
+		ID:             r.ID,
+		Webhook:        r.Webhook,
+		URL:            r.URL,
+		Event:          r.Event,
+		Payload:        r.Payload,
+		Status:         DeliveryStatus(r.Status),
+		Attempts:       r.Attempts,
+		ResponseStatus: r.ResponseStatus,
+		LastError:      r.LastError,
+		CreatedAt:      createdAt,
+		LastAttemptAt:  parseOptionalTime(r.LastAttemptAt),
+		NextAttemptAt:  parseOptionalTime(r.NextAttemptAt),
+		DeliveredAt:    parseOptionalTime(r.DeliveredAt),
+		RedeliveryOf:   r.RedeliveryOf,
+	}
+}
+
+func (s *sqliteStore) insert(ctx context.Context, deliveries []Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, delivery := range deliveries {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO webhook_deliveries(`+deliveryColumns+`) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			delivery.ID, delivery.Webhook, delivery.URL, delivery.Event, delivery.Payload, string(delivery.Status),
+			delivery.Attempts, delivery.ResponseStatus, delivery.LastError, formatTime(delivery.CreatedAt),
+			formatOptionalTime(delivery.LastAttemptAt), formatOptionalTime(delivery.NextAttemptAt),
+			formatOptionalTime(delivery.DeliveredAt), delivery.RedeliveryOf); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStore) find(ctx context.Context, id string) (Delivery, error) {
+	var row deliveryRow
+	err := s.db.GetContext(ctx, &row, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id=?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Delivery{}, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return Delivery{}, err
+	}
+	return row.delivery(), nil
+}
+
+func (s *sqliteStore) due(ctx context.Context, now time.Time, limit int) ([]Delivery, error) {
+	var rows []deliveryRow
+	if err := s.db.SelectContext(ctx, &rows, `SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE status=? AND next_attempt_at<=? ORDER BY next_attempt_at ASC, created_at ASC LIMIT ?`,
+		string(DeliveryStatusPending), formatTime(now), limit); err != nil {
+		return nil, err
+	}
+	return deliveriesFromRows(rows), nil
+}
+
+func (s *sqliteStore) list(ctx context.Context, filter DeliveryFilter) ([]Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE 1=1`
+	args := make([]any, 0, 3)
+	if filter.Webhook != "" {
+		query += ` AND webhook=?`
+		args = append(args, filter.Webhook)
+	}
+	if filter.Status != "" {
+		query += ` AND status=?`
+		args = append(args, string(filter.Status))
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+	var rows []deliveryRow
+	if err := s.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return deliveriesFromRows(rows), nil
+}
+
+func (s *sqliteStore) recordAttempt(ctx context.Context, delivery Delivery) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status=?,attempts=?,response_status=?,last_error=?,last_attempt_at=?,next_attempt_at=?,delivered_at=? WHERE id=?`,
+		string(delivery.Status), delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		formatOptionalTime(delivery.LastAttemptAt), formatOptionalTime(delivery.NextAttemptAt),
+		formatOptionalTime(delivery.DeliveredAt), delivery.ID)
+	return err
+}
+
+func (s *sqliteStore) cleanup(ctx context.Context, createdBefore time.Time, maxRows int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE status<>? AND created_at<?`, string(DeliveryStatusPending), formatTime(createdBefore)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE id IN (
+		SELECT id FROM webhook_deliveries WHERE status<>? ORDER BY created_at DESC, id DESC LIMIT -1 OFFSET ?
+	)`, string(DeliveryStatusPending), maxRows); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func deliveriesFromRows(rows []deliveryRow) []Delivery {
+	out := make([]Delivery, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.delivery())
+	}
+	return out
+}
+
+// timeLayout is fixed width so stored timestamps compare correctly as text.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatTime(value time.Time) string { return value.UTC().Format(timeLayout) }
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return formatTime(*value)
+}
+
+func parseOptionalTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	EventTaskCreated                  = "task.created"
+	EventTaskUpdated                  = "task.updated"
+	EventTaskProgress                 = "task.progress"
+	EventTaskDeleted                  = "task.deleted"
+	EventPerformerSubscriptionCreated = "performer_subscription.created"
+	EventPerformerSubscriptionUpdated = "performer_subscription.updated"
+	EventPerformerSubscriptionDeleted = "performer_subscription.deleted"
+	EventServiceStatusChanged         = "service_status.changed"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultTimeout     = 10 * time.Second
+	retentionPeriod    = 14 * 24 * time.Hour
+	maxRetainedRows    = 5000
+	cleanupInterval    = time.Hour
+	dispatchInterval   = 5 * time.Second
+	dispatchBatchSize  = 32
+	maxConcurrentSends = 4
+	minRetryBackoff    = 10 * time.Second
+	maxRetryBackoff    = 30 * time.Minute
+)
+
+var ErrDeliveryNotFound = errors.New("webhook: delivery not found")
+
+// Webhook is one configured receiver. Events lists the event types it wants;
+// an entry ending in ".*" matches a whole event family and an empty list
+// matches everything. Download progress ticks arrive as task.progress, which
+// is only delivered when listed by name.
+type Webhook struct {
+	Name        string
+	URL         string
+	Secret      string
+	Events      []string
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+type ConfigProvider func() []Webhook
+
+func (w Webhook) Accepts(eventType string) bool {
+	if eventType == EventTaskProgress {
+		return slices.ContainsFunc(w.Events, func(filter string) bool { return strings.TrimSpace(filter) == eventType })
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, filter := range w.Events {
+		filter = strings.TrimSpace(filter)
+		switch {
+		case filter == "*" || filter == eventType:
+			return true
+		case strings.HasSuffix(filter, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+func (w Webhook) maxAttempts() int {
+	if w.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return w.MaxAttempts
+}
+
+func (w Webhook) timeout() time.Duration {
+	if w.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return w.Timeout
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	DeliveryStatusFailed    DeliveryStatus = "FAILED"
+)
+
+type Delivery struct {
+	ID             string
+	Webhook        string
+	URL            string
+	Event          string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+	LastAttemptAt  *time.Time
+	NextAttemptAt  *time.Time
+	DeliveredAt    *time.Time
+	RedeliveryOf   string
+}
+
+type DeliveryFilter struct {
+	Webhook string
+	Status  DeliveryStatus
+	Limit   int
+}
@@ -0,0 +1,142 @@
+package jackett
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FeedRequest selects one Torznab feed. An empty Indexer reads the
+// aggregate "all" feed.
+type FeedRequest struct {
+	Indexer    string
+	Categories []int
+}
+
+type torznabFeed struct {
+	Channel struct {
+		Items []torznabItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type torznabItem struct {
+	Title    string `xml:"title"`
+	GUID     string `xml:"guid"`
+	Link     string `xml:"link"`
+	Comments string `xml:"comments"`
+	PubDate  string `xml:"pubDate"`
+	Size     int64  `xml:"size"`
+	Indexer  struct {
+		ID   string `xml:"id,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"jackettindexer"`
+	Categories []int `xml:"category"`
+	Enclosure  struct {
+		URL    string `xml:"url,attr"`
+		Length int64  `xml:"length,attr"`
+	} `xml:"enclosure"`
+	Attributes []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+// Feed reads the latest items of a Torznab feed. Torznab returns the newest
+// releases when a search has no query, which is what an RSS poll wants.
+func (c *Client) Feed(req FeedRequest) ([]SearchResult, error) {
+	indexer := strings.TrimSpace(req.Indexer)
+	if indexer == "" {
+		indexer = "all"
+	}
+	u, err := url.Parse(fmt.Sprintf("%s/api/v2.0/indexers/%s/results/torznab/api", c.baseURL, url.PathEscape(indexer)))
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("apikey", c.apiKey)
+	q.Set("t", "search")
+	if len(req.Categories) > 0 {
+		categories := make([]string, 0, len(req.Categories))
+		for _, category := range req.Categories {
+			categories = append(categories, strconv.Itoa(category))
+		}
+		q.Set("cat", strings.Join(categories, ","))
+	}
+	u.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("jackett torznab error: %s, body: %s", resp.Status, string(body))
+	}
+
+	var feed torznabFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to decode torznab feed: %w", err)
+	}
+	results := make([]SearchResult, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		results = append(results, item.searchResult(indexer))
+	}
+	return results, nil
+}
+
+func (item torznabItem) searchResult(indexer string) SearchResult {
+	result := SearchResult{
+		Title:       strings.TrimSpace(item.Title),
+		GUID:        strings.TrimSpace(item.GUID),
+		Link:        strings.TrimSpace(item.Link),
+		Details:     strings.TrimSpace(item.Comments),
+		PublishDate: item.PubDate,
+		Category:    item.Categories,
+		Size:        item.Size,
+		Tracker:     strings.TrimSpace(item.Indexer.Name),
+		TrackerID:   strings.TrimSpace(item.Indexer.ID),
+	}
+	if result.Link == "" {
+		result.Link = strings.TrimSpace(item.Enclosure.URL)
+	}
+	if result.Size == 0 {
+		result.Size = item.Enclosure.Length
+	}
+	if result.TrackerID == "" && indexer != "all" {
+		result.TrackerID = indexer
+	}
+	if published, err := time.Parse(time.RFC1123Z, strings.TrimSpace(item.PubDate)); err == nil {
+		result.PublishDate = published.UTC().Format(time.RFC3339)
+	}
+	for _, attribute := range item.Attributes {
+		value := strings.TrimSpace(attribute.Value)
+		switch strings.ToLower(attribute.Name) {
+		case "seeders":
+			result.Seeders, _ = strconv.Atoi(value)
+		case "peers":
+			result.Peers, _ = strconv.Atoi(value)
+		case "infohash":
+			result.InfoHash = value
+		case "magneturl":
+			result.MagnetURI = value
+		case "grabs":
+			result.Grabs, _ = strconv.Atoi(value)
+		case "size":
+			if result.Size == 0 {
+				result.Size, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+	}
+	if strings.HasPrefix(result.Link, "magnet:") && result.MagnetURI == "" {
+		result.MagnetURI = result.Link
+	}
+	return result
+}
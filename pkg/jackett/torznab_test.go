@@ -0,0 +1,58 @@
+package jackett
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const torznabFeedFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <item>
+      <title>SONE-786 New Release</title>
+      <guid>https://tracker.example/t/1</guid>
+      <jackettindexer id="onejav">OneJAV</jackettindexer>
+      <comments>https://tracker.example/t/1</comments>
+      <pubDate>Sat, 17 Oct 2026 21:00:00 +0000</pubDate>
+      <size>123456</size>
+      <link>https://jackett.example/dl/onejav/1.torrent</link>
+      <category>6000</category>
+      <torznab:attr name="seeders" value="12" />
+      <torznab:attr name="peers" value="20" />
+      <torznab:attr name="infohash" value="ABCDEF" />
+      <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:ABCDEF" />
+    </item>
+  </channel>
+</rss>`
+
+func TestClientFeedParsesTorznabItems(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2.0/indexers/onejav/results/torznab/api" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(torznabFeedFixture))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL, "key", "").Feed(FeedRequest{Indexer: "onejav", Categories: []int{6000, 6010}})
+	if err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if query != "apikey=key&cat=6000%2C6010&t=search" {
+		t.Fatalf("unexpected query %q", query)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+	result := results[0]
+	if result.Title != "SONE-786 New Release" || result.Tracker != "OneJAV" || result.TrackerID != "onejav" || result.Seeders != 12 || result.Peers != 20 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.InfoHash != "ABCDEF" || result.MagnetURI != "magnet:?xt=urn:btih:ABCDEF" || result.Size != 123456 || result.PublishDate != "2026-10-17T21:00:00Z" {
+		t.Fatalf("unexpected torrent fields: %+v", result)
+	}
+}
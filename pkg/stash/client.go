@@ -121,6 +121,17 @@ func (c *Client) resolve() *graphql.Client {
 	return c.graphql
 }
 
+// currentConfig returns the provider's latest config, for connections that
+// do not go through the graphql client.
+func (c *Client) currentConfig() Config {
+	if c.configProvider == nil {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.lastConfig
+	}
+	return c.configProvider()
+}
+
 // rebuildLocked rebuilds the http and graphql clients for cfg. Caller must
 // hold c.mu for writing.
 func (c *Client) rebuildLocked(cfg Config) {
@@ -14,12 +14,15 @@ type StashGraphQLClient interface {
 	FindPerformers(ctx context.Context, performerFilter *PerformerFilterType, filter *FindFilterType, performerIds []int, ids []string, interceptors ...clientv2.RequestInterceptor) (*FindPerformers, error)
 	AllPerformers(ctx context.Context, interceptors ...clientv2.RequestInterceptor) (*AllPerformers, error)
 	FindScenes(ctx context.Context, sceneFilter *SceneFilterType, filter *FindFilterType, interceptors ...clientv2.RequestInterceptor) (*FindScenes, error)
+	SceneUpdate(ctx context.Context, input SceneUpdateInput, interceptors ...clientv2.RequestInterceptor) (*SceneUpdate, error)
 	UpdatePerformerCustomFields(ctx context.Context, input PerformerUpdateInput, interceptors ...clientv2.RequestInterceptor) (*UpdatePerformerCustomFields, error)
 	GetVersion(ctx context.Context, interceptors ...clientv2.RequestInterceptor) (*GetVersion, error)
 	MetadataScan(ctx context.Context, input ScanMetadataInput, interceptors ...clientv2.RequestInterceptor) (*MetadataScan, error)
+	MetadataIdentify(ctx context.Context, input IdentifyMetadataInput, interceptors ...clientv2.RequestInterceptor) (*MetadataIdentify, error)
 	FindJob(ctx context.Context, input FindJobInput, interceptors ...clientv2.RequestInterceptor) (*FindJob, error)
 	Configuration(ctx context.Context, interceptors ...clientv2.RequestInterceptor) (*Configuration, error)
 	FindSceneCount(ctx context.Context, interceptors ...clientv2.RequestInterceptor) (*FindSceneCount, error)
+	FindLibraryScenes(ctx context.Context, sceneFilter *SceneFilterType, filter *FindFilterType, interceptors ...clientv2.RequestInterceptor) (*FindLibraryScenes, error)
 }
 
 type Client struct {
@@ -302,6 +305,52 @@ func (t *StashBoxFragment) GetMaxRequestsPerMinute() int {
 	return t.MaxRequestsPerMinute
 }
 
+type LibrarySceneFragment struct {
+	ID        string                           "json:\"id\" graphql:\"id\""
+	Title     *string                          "json:\"title,omitempty\" graphql:\"title\""
+	Code      *string                          "json:\"code,omitempty\" graphql:\"code\""
+	UpdatedAt time.Time                        "json:\"updated_at\" graphql:\"updated_at\""
+	StashIds  []*LibrarySceneFragment_StashIds "json:\"stash_ids\" graphql:\"stash_ids\""
+	Files     []*LibrarySceneFragment_Files    "json:\"files\" graphql:\"files\""
+}
+
+func (t *LibrarySceneFragment) GetID() string {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return t.ID
+}
+func (t *LibrarySceneFragment) GetTitle() *string {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return t.Title
+}
+func (t *LibrarySceneFragment) GetCode() *string {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return t.Code
+}
+func (t *LibrarySceneFragment) GetUpdatedAt() *time.Time {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return &t.UpdatedAt
+}
+func (t *LibrarySceneFragment) GetStashIds() []*LibrarySceneFragment_StashIds {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return t.StashIds
+}
+func (t *LibrarySceneFragment) GetFiles() []*LibrarySceneFragment_Files {
+	if t == nil {
+		t = &LibrarySceneFragment{}
+	}
+	return t.Files
+}
+
 type SceneFragment_Paths struct {
 	Screenshot *string "json:\"screenshot,omitempty\" graphql:\"screenshot\""
 }
@@ -349,6 +398,67 @@ func (t *SceneFragment_Tags) GetName() string {
 	return t.Name
 }
 
+type LibrarySceneFragment_StashIds struct {
+	Endpoint string "json:\"endpoint\" graphql:\"endpoint\""
+	StashID  string "json:\"stash_id\" graphql:\"stash_id\""
+}
+
+func (t *LibrarySceneFragment_StashIds) GetEndpoint() string {
+	if t == nil {
+		t = &LibrarySceneFragment_StashIds{}
+	}
+	return t.Endpoint
+}
+func (t *LibrarySceneFragment_StashIds) GetStashID() string {
+	if t == nil {
+		t = &LibrarySceneFragment_StashIds{}
+	}
+	return t.StashID
+}
+
+type LibrarySceneFragment_Files_Fingerprints struct {
+	Type  string "json:\"type\" graphql:\"type\""
+	Value string "json:\"value\" graphql:\"value\""
+}
+
+func (t *LibrarySceneFragment_Files_Fingerprints) GetType() string {
+	if t == nil {
+		t = &LibrarySceneFragment_Files_Fingerprints{}
+	}
+	return t.Type
+}
+func (t *LibrarySceneFragment_Files_Fingerprints) GetValue() string {
+	if t == nil {
+		t = &LibrarySceneFragment_Files_Fingerprints{}
+	}
+	return t.Value
+}
+
+type LibrarySceneFragment_Files struct {
+	Basename     string                                     "json:\"basename\" graphql:\"basename\""
+	Fingerprints []*LibrarySceneFragment_Files_Fingerprints "json:\"fingerprints\" graphql:\"fingerprints\""
+	Path         string                                     "json:\"path\" graphql:\"path\""
+}
+
+func (t *LibrarySceneFragment_Files) GetBasename() string {
+	if t == nil {
+		t = &LibrarySceneFragment_Files{}
+	}
+	return t.Basename
+}
+func (t *LibrarySceneFragment_Files) GetFingerprints() []*LibrarySceneFragment_Files_Fingerprints {
+	if t == nil {
+		t = &LibrarySceneFragment_Files{}
+	}
+	return t.Fingerprints
+}
+func (t *LibrarySceneFragment_Files) GetPath() string {
+	if t == nil {
+		t = &LibrarySceneFragment_Files{}
+	}
+	return t.Path
+}
+
 type FindPerformers_FindPerformers struct {
 	Count      int                  "json:\"count\" graphql:\"count\""
 	Performers []*PerformerFragment "json:\"performers\" graphql:\"performers\""
@@ -432,6 +542,53 @@ func (t *FindScenes_FindScenes) GetScenes() []*SceneFragment {
 	return t.Scenes
 }
 
+type SceneUpdate_SceneUpdate_SceneFragment_Paths struct {
+	Screenshot *string "json:\"screenshot,omitempty\" graphql:\"screenshot\""
+}
+
+func (t *SceneUpdate_SceneUpdate_SceneFragment_Paths) GetScreenshot() *string {
+	if t == nil {
+		t = &SceneUpdate_SceneUpdate_SceneFragment_Paths{}
+	}
+	return t.Screenshot
+}
+
+type SceneUpdate_SceneUpdate_SceneFragment_Performers struct {
+	ID   string "json:\"id\" graphql:\"id\""
+	Name string "json:\"name\" graphql:\"name\""
+}
+
+func (t *SceneUpdate_SceneUpdate_SceneFragment_Performers) GetID() string {
+	if t == nil {
+		t = &SceneUpdate_SceneUpdate_SceneFragment_Performers{}
+	}
+	return t.ID
+}
+func (t *SceneUpdate_SceneUpdate_SceneFragment_Performers) GetName() string {
+	if t == nil {
+		t = &SceneUpdate_SceneUpdate_SceneFragment_Performers{}
+	}
+	return t.Name
+}
+
+type SceneUpdate_SceneUpdate_SceneFragment_Tags struct {
+	ID   string "json:\"id\" graphql:\"id\""
+	Name string "json:\"name\" graphql:\"name\""
+}
+
+func (t *SceneUpdate_SceneUpdate_SceneFragment_Tags) GetID() string {
+	if t == nil {
+		t = &SceneUpdate_SceneUpdate_SceneFragment_Tags{}
+	}
+	return t.ID
+}
+func (t *SceneUpdate_SceneUpdate_SceneFragment_Tags) GetName() string {
+	if t == nil {
+		t = &SceneUpdate_SceneUpdate_SceneFragment_Tags{}
+	}
+	return t.Name
+}
+
 type GetVersion_Version struct {
 	BuildTime string  "json:\"build_time\" graphql:\"build_time\""
 	Hash      string  "json:\"hash\" graphql:\"hash\""
@@ -575,6 +732,85 @@ func (t *FindSceneCount_FindScenes) GetCount() int {
 	return t.Count
 }
 
+type FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_StashIds struct {
+	Endpoint string "json:\"endpoint\" graphql:\"endpoint\""
+	StashID  string "json:\"stash_id\" graphql:\"stash_id\""
+}
+
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_StashIds) GetEndpoint() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_StashIds{}
+	}
+	return t.Endpoint
+}
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_StashIds) GetStashID() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_StashIds{}
+	}
+	return t.StashID
+}
+
+type FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints struct {
+	Type  string "json:\"type\" graphql:\"type\""
+	Value string "json:\"value\" graphql:\"value\""
+}
+
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints) GetType() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints{}
+	}
+	return t.Type
+}
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints) GetValue() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints{}
+	}
+	return t.Value
+}
+
+type FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files struct {
+	Basename     string                                                                         "json:\"basename\" graphql:\"basename\""
+	Fingerprints []*FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints "json:\"fingerprints\" graphql:\"fingerprints\""
+	Path         string                                                                         "json:\"path\" graphql:\"path\""
+}
+
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files) GetBasename() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files{}
+	}
+	return t.Basename
+}
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files) GetFingerprints() []*FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files_Fingerprints {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files{}
+	}
+	return t.Fingerprints
+}
+func (t *FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files) GetPath() string {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes_Scenes_LibrarySceneFragment_Files{}
+	}
+	return t.Path
+}
+
+type FindLibraryScenes_FindScenes struct {
+	Count  int                     "json:\"count\" graphql:\"count\""
+	Scenes []*LibrarySceneFragment "json:\"scenes\" graphql:\"scenes\""
+}
+
+func (t *FindLibraryScenes_FindScenes) GetCount() int {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes{}
+	}
+	return t.Count
+}
+func (t *FindLibraryScenes_FindScenes) GetScenes() []*LibrarySceneFragment {
+	if t == nil {
+		t = &FindLibraryScenes_FindScenes{}
+	}
+	return t.Scenes
+}
+
 type FindPerformerByID struct {
 	FindPerformer *PerformerFragment "json:\"findPerformer,omitempty\" graphql:\"findPerformer\""
 }
@@ -619,6 +855,17 @@ func (t *FindScenes) GetFindScenes() *FindScenes_FindScenes {
 	return &t.FindScenes
 }
 
+type SceneUpdate struct {
+	SceneUpdate *SceneFragment "json:\"sceneUpdate,omitempty\" graphql:\"sceneUpdate\""
+}
+
+func (t *SceneUpdate) GetSceneUpdate() *SceneFragment {
+	if t == nil {
+		t = &SceneUpdate{}
+	}
+	return t.SceneUpdate
+}
+
 type UpdatePerformerCustomFields struct {
 	PerformerUpdate *PerformerFragment "json:\"performerUpdate,omitempty\" graphql:\"performerUpdate\""
 }
@@ -652,6 +899,17 @@ func (t *MetadataScan) GetMetadataScan() string {
 	return t.MetadataScan
 }
 
+type MetadataIdentify struct {
+	MetadataIdentify string "json:\"metadataIdentify\" graphql:\"metadataIdentify\""
+}
+
+func (t *MetadataIdentify) GetMetadataIdentify() string {
+	if t == nil {
+		t = &MetadataIdentify{}
+	}
+	return t.MetadataIdentify
+}
+
 type FindJob struct {
 	FindJob *FindJob_FindJob "json:\"findJob,omitempty\" graphql:\"findJob\""
 }
@@ -685,6 +943,17 @@ func (t *FindSceneCount) GetFindScenes() *FindSceneCount_FindScenes {
 	return &t.FindScenes
 }
 
+type FindLibraryScenes struct {
+	FindScenes FindLibraryScenes_FindScenes "json:\"findScenes\" graphql:\"findScenes\""
+}
+
+func (t *FindLibraryScenes) GetFindScenes() *FindLibraryScenes_FindScenes {
+	if t == nil {
+		t = &FindLibraryScenes{}
+	}
+	return &t.FindScenes
+}
+
 const FindPerformerByIDDocument = `query FindPerformerByID ($id: ID!) {
 	findPerformer(id: $id) {
 		... PerformerFragment
@@ -900,6 +1169,63 @@ func (c *Client) FindScenes(ctx context.Context, sceneFilter *SceneFilterType, f
 	return &res, nil
 }
 
+const SceneUpdateDocument = `mutation SceneUpdate ($input: SceneUpdateInput!) {
+	sceneUpdate(input: $input) {
+		... SceneFragment
+	}
+}
+fragment SceneFragment on Scene {
+	id
+	title
+	code
+	date
+	urls
+	studio {
+		... StudioNameFragment
+	}
+	paths {
+		screenshot
+	}
+	stash_ids {
+		... StashIdFragment
+	}
+	performers {
+		id
+		name
+	}
+	tags {
+		id
+		name
+	}
+}
+fragment StudioNameFragment on Studio {
+	id
+	name
+}
+fragment StashIdFragment on StashID {
+	endpoint
+	stash_id
+	updated_at
+}
+`
+
+func (c *Client) SceneUpdate(ctx context.Context, input SceneUpdateInput, interceptors ...clientv2.RequestInterceptor) (*SceneUpdate, error) {
+	vars := map[string]any{
+		"input": input,
+	}
+
+	var res SceneUpdate
+	if err := c.Client.Post(ctx, "SceneUpdate", SceneUpdateDocument, &res, vars, interceptors...); err != nil {
+		if c.Client.ParseDataWhenErrors {
+			return &res, err
+		}
+
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 const UpdatePerformerCustomFieldsDocument = `mutation UpdatePerformerCustomFields ($input: PerformerUpdateInput!) {
 	performerUpdate(input: $input) {
 		... PerformerFragment
@@ -996,6 +1322,28 @@ func (c *Client) MetadataScan(ctx context.Context, input ScanMetadataInput, inte
 	return &res, nil
 }
 
+const MetadataIdentifyDocument = `mutation MetadataIdentify ($input: IdentifyMetadataInput!) {
+	metadataIdentify(input: $input)
+}
+`
+
+func (c *Client) MetadataIdentify(ctx context.Context, input IdentifyMetadataInput, interceptors ...clientv2.RequestInterceptor) (*MetadataIdentify, error) {
+	vars := map[string]any{
+		"input": input,
+	}
+
+	var res MetadataIdentify
+	if err := c.Client.Post(ctx, "MetadataIdentify", MetadataIdentifyDocument, &res, vars, interceptors...); err != nil {
+		if c.Client.ParseDataWhenErrors {
+			return &res, err
+		}
+
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 const FindJobDocument = `query FindJob ($input: FindJobInput!) {
 	findJob(input: $input) {
 		id
@@ -1085,15 +1433,64 @@ func (c *Client) FindSceneCount(ctx context.Context, interceptors ...clientv2.Re
 	return &res, nil
 }
 
+const FindLibraryScenesDocument = `query FindLibraryScenes ($scene_filter: SceneFilterType, $filter: FindFilterType) {
+	findScenes(scene_filter: $scene_filter, filter: $filter) {
+		count
+		scenes {
+			... LibrarySceneFragment
+		}
+	}
+}
+fragment LibrarySceneFragment on Scene {
+	id
+	title
+	code
+	updated_at
+	stash_ids {
+		endpoint
+		stash_id
+	}
+	files {
+		path
+		basename
+		fingerprints {
+			type
+			value
+		}
+	}
+}
+`
+
+func (c *Client) FindLibraryScenes(ctx context.Context, sceneFilter *SceneFilterType, filter *FindFilterType, interceptors ...clientv2.RequestInterceptor) (*FindLibraryScenes, error) {
+	vars := map[string]any{
+		"scene_filter": sceneFilter,
+		"filter":       filter,
+	}
+
+	var res FindLibraryScenes
+	if err := c.Client.Post(ctx, "FindLibraryScenes", FindLibraryScenesDocument, &res, vars, interceptors...); err != nil {
+		if c.Client.ParseDataWhenErrors {
+			return &res, err
+		}
+
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 var DocumentOperationNames = map[string]string{
 	FindPerformerByIDDocument:           "FindPerformerByID",
 	FindPerformersDocument:              "FindPerformers",
 	AllPerformersDocument:               "allPerformers",
 	FindScenesDocument:                  "FindScenes",
+	SceneUpdateDocument:                 "SceneUpdate",
 	UpdatePerformerCustomFieldsDocument: "UpdatePerformerCustomFields",
 	GetVersionDocument:                  "GetVersion",
 	MetadataScanDocument:                "MetadataScan",
+	MetadataIdentifyDocument:            "MetadataIdentify",
 	FindJobDocument:                     "FindJob",
 	ConfigurationDocument:               "Configuration",
 	FindSceneCountDocument:              "FindSceneCount",
+	FindLibraryScenesDocument:           "FindLibraryScenes",
 }
@@ -0,0 +1,225 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+// jobsSubscribeDocument is sent by hand because gqlgenc does not generate
+// subscriptions.
+const jobsSubscribeDocument = `subscription JobsSubscribe {
+  jobsSubscribe {
+    type
+    job {
+      id
+      status
+      subTasks
+      description
+      progress
+      startTime
+      endTime
+      addTime
+      error
+    }
+  }
+}`
+
+// Stash serves both GraphQL websocket subprotocols; the newer one is
+// preferred.
+const (
+	protocolGraphQLTransportWS = "graphql-transport-ws"
+	protocolGraphQLWS          = "graphql-ws"
+)
+
+const (
+	jobsSubscriptionID      = "jobs"
+	websocketHandshakeLimit = 10 * time.Second
+	websocketPingInterval   = 30 * time.Second
+	// websocketReadTimeout drops a connection that stopped answering pings,
+	// so callers notice and fall back to polling.
+	websocketReadTimeout = 75 * time.Second
+)
+
+type websocketMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// SubscribeJobs streams Stash job status updates to handle until ctx is
+// cancelled or the connection drops, and returns why it stopped. ready is
+// called once the subscription is established, before the first update.
+func (c *Client) SubscribeJobs(ctx context.Context, ready func(), handle func(*graphql.JobStatusUpdate)) error {
+	cfg := c.currentConfig()
+	endpoint, err := websocketURL(cfg.URL)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if cfg.APIKey != "" {
+		header.Set("ApiKey", cfg.APIKey)
+	}
+	dialer := websocket.Dialer{
+		HandshakeTimeout: websocketHandshakeLimit,
+		Subprotocols:     []string{protocolGraphQLTransportWS, protocolGraphQLWS},
+	}
+	conn, _, err := dialer.DialContext(ctx, endpoint, header)
+	if err != nil {
+		return fmt.Errorf("stash: dial job subscription: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(websocketReadTimeout))
+	})
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go pingWebsocket(conn, pingDone)
+
+	legacy := conn.Subprotocol() == protocolGraphQLWS
+	if err := initJobSubscription(conn, cfg.APIKey, legacy); err != nil {
+		return subscriptionError(ctx, err)
+	}
+	if ready != nil {
+		ready()
+	}
+
+	for {
+		var msg websocketMessage
+		if err := readWebsocketMessage(conn, &msg); err != nil {
+			return subscriptionError(ctx, fmt.Errorf("stash: read job subscription: %w", err))
+		}
+		switch msg.Type {
+		case "next", "data":
+			update, err := decodeJobStatusUpdate(msg.Payload)
+			if err != nil {
+				return err
+			}
+			if update != nil && update.Job != nil && handle != nil {
+				handle(update)
+			}
+		case "ping":
+			if err := conn.WriteJSON(websocketMessage{Type: "pong"}); err != nil {
+				return subscriptionError(ctx, fmt.Errorf("stash: answer job subscription ping: %w", err))
+			}
+		case "error", "connection_error":
+			return fmt.Errorf("stash: job subscription error: %s", msg.Payload)
+		case "complete":
+			return errors.New("stash: job subscription completed by server")
+		}
+	}
+}
+
+// initJobSubscription performs the connection_init handshake and starts the
+// jobsSubscribe operation.
+func initJobSubscription(conn *websocket.Conn, apiKey string, legacy bool) error {
+	initPayload, err := json.Marshal(map[string]string{"ApiKey": apiKey})
+	if err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(websocketMessage{Type: "connection_init", Payload: initPayload}); err != nil {
+		return fmt.Errorf("stash: init job subscription: %w", err)
+	}
+	for {
+		var msg websocketMessage
+		if err := readWebsocketMessage(conn, &msg); err != nil {
+			return fmt.Errorf("stash: await job subscription ack: %w", err)
+		}
+		if msg.Type == "connection_ack" {
+			break
+		}
+		if msg.Type == "connection_error" || msg.Type == "error" {
+			return fmt.Errorf("stash: job subscription rejected: %s", msg.Payload)
+		}
+	}
+
+	query, err := json.Marshal(map[string]string{"query": jobsSubscribeDocument})
+	if err != nil {
+		return err
+	}
+	start := "subscribe"
+	if legacy {
+		start = "start"
+	}
+	if err := conn.WriteJSON(websocketMessage{ID: jobsSubscriptionID, Type: start, Payload: query}); err != nil {
+		return fmt.Errorf("stash: start job subscription: %w", err)
+	}
+	return nil
+}
+
+func readWebsocketMessage(conn *websocket.Conn, msg *websocketMessage) error {
+	if err := conn.SetReadDeadline(time.Now().Add(websocketReadTimeout)); err != nil {
+		return err
+	}
+	return conn.ReadJSON(msg)
+}
+
+func pingWebsocket(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(websocketPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketHandshakeLimit)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func decodeJobStatusUpdate(payload json.RawMessage) (*graphql.JobStatusUpdate, error) {
+	var result struct {
+		Data struct {
+			JobsSubscribe *graphql.JobStatusUpdate `json:"jobsSubscribe"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("stash: decode job update: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("stash: job subscription error: %s", result.Errors[0].Message)
+	}
+	return result.Data.JobsSubscribe, nil
+}
+
+// subscriptionError reports cancellation as such rather than as the read
+// error caused by closing the connection.
+func subscriptionError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// websocketURL maps the GraphQL endpoint onto its websocket scheme.
+func websocketURL(endpoint string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("stash: invalid graphql endpoint %q", endpoint)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "ws":
+		parsed.Scheme = "ws"
+	case "https", "wss":
+		parsed.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("stash: unsupported graphql endpoint scheme %q", parsed.Scheme)
+	}
+	return parsed.String(), nil
+}
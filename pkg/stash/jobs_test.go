@@ -0,0 +1,79 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/leothevan2444/moji/pkg/stash/graphql"
+)
+
+func TestSubscribeJobsStreamsUpdates(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{protocolGraphQLTransportWS}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("ApiKey") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var msg websocketMessage
+		if conn.ReadJSON(&msg) != nil || msg.Type != "connection_init" {
+			return
+		}
+		conn.WriteJSON(websocketMessage{Type: "connection_ack"})
+		if conn.ReadJSON(&msg) != nil || msg.Type != "subscribe" || !strings.Contains(string(msg.Payload), "jobsSubscribe") {
+			return
+		}
+		conn.WriteJSON(websocketMessage{Type: "ping"})
+		if conn.ReadJSON(&msg) != nil || msg.Type != "pong" {
+			return
+		}
+		payload, _ := json.Marshal(map[string]any{"data": map[string]any{"jobsSubscribe": map[string]any{
+			"type": "UPDATE",
+			"job":  map[string]any{"id": "7", "status": "FINISHED", "description": "Scanning...", "addTime": "2026-05-01T08:00:00Z"},
+		}}})
+		conn.WriteJSON(websocketMessage{ID: msg.ID, Type: "next", Payload: payload})
+		conn.WriteJSON(websocketMessage{ID: msg.ID, Type: "complete"})
+	}))
+	defer server.Close()
+
+	client := NewClient(func() Config { return Config{URL: server.URL + "/graphql", APIKey: "secret"} })
+	ready := false
+	var updates []*graphql.JobStatusUpdate
+	err := client.SubscribeJobs(context.Background(), func() { ready = true }, func(update *graphql.JobStatusUpdate) {
+		updates = append(updates, update)
+	})
+	if err == nil || !strings.Contains(err.Error(), "completed by server") {
+		t.Fatalf("expected the subscription to end with the server's complete, got %v", err)
+	}
+	if !ready || len(updates) != 1 {
+		t.Fatalf("expected ready and one update, got ready=%v updates=%+v", ready, updates)
+	}
+	if updates[0].Type != graphql.JobStatusUpdateTypeUpdate || updates[0].Job.ID != "7" || updates[0].Job.Status != graphql.JobStatusFinished {
+		t.Fatalf("unexpected update %+v job %+v", updates[0], updates[0].Job)
+	}
+}
+
+func TestWebsocketURL(t *testing.T) {
+	for input, want := range map[string]string{
+		"http://stash:9999/graphql": "ws://stash:9999/graphql",
+		"https://stash.example/gql": "wss://stash.example/gql",
+		" ws://stash:9999/graphql ": "ws://stash:9999/graphql",
+	} {
+		got, err := websocketURL(input)
+		if err != nil || got != want {
+			t.Fatalf("websocketURL(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := websocketURL("stash:9999"); err == nil {
+		t.Fatal("expected an error for an endpoint without a scheme")
+	}
+}
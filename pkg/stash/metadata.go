@@ -15,6 +15,15 @@ func (c *Client) MetadataScan(ctx context.Context, input graphql.ScanMetadataInp
 	return resp.MetadataScan, nil
 }
 
+func (c *Client) MetadataIdentify(ctx context.Context, input graphql.IdentifyMetadataInput) (string, error) {
+	resp, err := c.graphql.MetadataIdentify(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.MetadataIdentify, nil
+}
+
 func (c *Client) FindJob(ctx context.Context, id string) (*graphql.FindJob_FindJob, error) {
 	resp, err := c.graphql.FindJob(ctx, graphql.FindJobInput{ID: id})
 	if err != nil {
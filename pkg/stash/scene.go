@@ -13,3 +13,46 @@ func (c *Client) FindScenes(ctx context.Context, sceneFilter *graphql.SceneFilte
 	}
 	return scenes.FindScenes.Scenes, nil
 }
+
+// FindLibraryScenes returns one page of scenes with their files and
+// fingerprints, together with the total count matching sceneFilter.
+func (c *Client) FindLibraryScenes(ctx context.Context, sceneFilter *graphql.SceneFilterType, filter *graphql.FindFilterType) (int, []*graphql.LibrarySceneFragment, error) {
+	resp, err := c.resolve().FindLibraryScenes(ctx, sceneFilter, filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.FindScenes.Count, resp.FindScenes.Scenes, nil
+}
+
+func (c *Client) UpdateScene(ctx context.Context, input graphql.SceneUpdateInput) (*graphql.SceneFragment, error) {
+	resp, err := c.resolve().SceneUpdate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return resp.SceneUpdate, nil
+}
+
+// sceneCustomFieldsDocument is written by hand because scene custom fields
+// are newer than the Stash schema the client is generated from. Older Stash
+// servers reject it.
+const sceneCustomFieldsDocument = `mutation SceneUpdateCustomFields($input: SceneUpdateInput!) {
+	sceneUpdate(input: $input) {
+		id
+	}
+}
+`
+
+func (c *Client) UpdateSceneCustomFields(ctx context.Context, id string, partial map[string]any) error {
+	vars := map[string]any{
+		"input": map[string]any{
+			"id":            id,
+			"custom_fields": graphql.CustomFieldsInput{Partial: partial},
+		},
+	}
+	var res struct {
+		SceneUpdate *struct {
+			ID string `json:"id"`
+		} `json:"sceneUpdate"`
+	}
+	return c.resolve().Client.Post(ctx, "SceneUpdateCustomFields", sceneCustomFieldsDocument, &res, vars)
+}
@@ -0,0 +1,15 @@
+package stashbox
+
+import (
+	"context"
+
+	"github.com/leothevan2444/moji/pkg/stashbox/graphql"
+)
+
+func (c *Client) FindStudioByID(ctx context.Context, id string) (*graphql.StudioFragment, error) {
+	studio, err := c.graphql.FindStudio(ctx, &id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return studio.FindStudio, nil
+}
@@ -1243,6 +1243,7 @@ export enum TaskStage {
   PendingIngest = 'PENDING_INGEST',
   Scanning = 'SCANNING',
   Sourcing = 'SOURCING',
+  Subtitling = 'SUBTITLING',
   Transferring = 'TRANSFERRING'
 }
 
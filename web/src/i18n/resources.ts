@@ -50,7 +50,7 @@ export const resources = {
     taskRoute: { retryNoResult: "任务重试失败，后端没有返回任务记录。", retried: "已重试任务：{{task}}。", noneBlocked: "当前没有受阻任务需要重试。", retrySummary: "受阻任务重试完成：成功 {{succeeded}} 个，失败 {{failed}} 个。", deleteNoResult: "任务删除失败，后端没有返回已删除的任务记录。", deleted: "已删除任务：{{task}}。", loadFailed: "任务加载失败", resolutionTitle: "人工处理：{{task}}", task: "任务", details: "任务详情", loading: "正在加载任务...", notFound: "任务不存在", deleteTitle: "删除确认", confirmDelete: "确认删除任务", cancel: "取消", deleting: "删除中...", confirm: "确认删除" },
     taskBatch: { more: "更多任务操作", moreActions: "{{task}} 的更多操作", multiSelect: "进入多选模式", exitMultiSelect: "退出多选模式", deselectTask: "取消选择任务：{{task}}", syncNow: "立即同步 qBittorrent", syncing: "正在同步 qBittorrent…", syncComplete: "qBittorrent 任务进度已同步。", autoSyncOn: "自动同步：每 {{seconds}} 秒", autoSyncOff: "自动同步已关闭", updatedAt: "列表更新于 {{time}}", selectionActions: "所选任务操作", selectTask: "选择任务：{{task}}", selected: "已选择 {{count}} 项", hidden: "其中 {{count}} 项不在当前筛选结果中", selectVisible: "选择当前结果（{{count}}）", retry: "重试（{{count}}）", ingest: "处理入库（{{count}}）", delete: "删除（{{count}}）", clear: "清除选择", limit: "一次最多选择 {{count}} 个任务。", groupLimit: "待入库任务超过 {{count}} 项，请先通过筛选和多选分批处理。", batchComplete: "已成功处理 {{count}} 个任务。", confirmDeleteCount: "将删除所选的 {{count}} 个任务。", deletePolicies: { KEEP_ONLY: "仅删除 Moji 任务记录。", REMOVE_TORRENT: "同时删除 qBittorrent 下载任务，此操作不可撤销。", REMOVE_TORRENT_AND_FILES: "同时删除 qBittorrent 下载任务和已下载文件，此操作不可撤销。" }, resultTitle: "批量操作结果", batchId: "批次 {{id}}", requested: "请求", succeeded: "成功", skipped: "跳过", failed: "失败", reasons: { RETRIED: "任务已重新执行", INGEST_STARTED: "已开始处理入库", DELETED: "任务已删除", TASK_NOT_FOUND: "任务已不存在", NOT_RETRYABLE: "任务当前不可重试", NOT_READY_FOR_INGEST: "任务尚未进入可处理的入库阶段", ALREADY_RUNNING: "任务已经在执行", RETRY_FAILED: "重试失败", INGEST_FAILED: "入库处理失败", DELETE_FAILED: "删除失败", CANCELLED: "操作已取消", UNKNOWN: "未识别的操作结果" } },
     taskUi: { noneSelected: "还没有选中任务", noneSelectedDetail: "点击任务卡片后，这里会显示详细信息和操作。", cannotResolve: "任务当前无法人工处理", cannotResolveDetail: "任务可能已经离开选种受阻阶段，请关闭后刷新任务列表。", resolution: { noResult: "后端没有返回已恢复的任务记录。", title: "人工解决选种受阻", detail: "为 {{code}} 重新搜索候选，或直接提供磁力链接以继续原任务。", magnetPlaceholder: "粘贴 magnet:?xt=urn:btih:...", magnetLabel: "磁力链接", submitting: "提交中...", useMagnet: "使用磁力链接", searching: "正在搜索...", searchAgain: "重新搜索候选", search: "搜索候选", found: "找到 {{count}} 个候选", none: "没有找到候选，可以修改 Jackett 配置后重搜，或直接粘贴磁力链接。", selecting: "选用中...", select: "选用" }, card: { aria: "{{task}}，状态：{{status}}，点击查看详情", resolveLabel: "人工处理任务：{{task}}", resolve: "人工处理", retryLabel: "重试任务：{{task}}", retrying: "重试中...", retry: "重试", scanLabel: "重扫任务：{{task}}", scanning: "扫描中...", scan: "重扫", deleteLabel: "删除任务：{{task}}", deleting: "删除中...", delete: "删除" } },
-    taskModel: { manualMagnet: "手动磁链任务", sources: { search: "搜索", subscription: "订阅", manual: "手动" }, source: "来源 {{source}}", states: { blocked: "受阻", transferring: "搬运中", scanning: "扫描中", pendingIngest: "待入库", completed: "已完成", downloading: "下载中", queued: "待下载", closed: "已闭环" }, failure: { blocked: "当前阶段受阻", blockedDetail: "任务被阻塞，但没有更多错误上下文。", scanPending: "等待扫描收口", scanPendingDetail: "下载已完成，等待 Stash 扫描继续推进。", downloading: "下载进行中", downloadingDetail: "任务仍在等待下载状态变化。", healthy: "状态正常", healthyDetail: "当前任务没有显式错误，等待下一次同步。", qbPath: "qB 路径映射失败", sourcePath: "Moji 源路径构建失败", targetPath: "Moji 目标路径构建失败", transfer: "文件搬运失败", scanPath: "Stash 扫描路径构建失败", missingScanPath: "缺少扫描路径", missingScanPathDetail: "任务没有可用于 Stash 扫描的内容路径或保存路径。", stashMissing: "Stash 未配置", stashMissingDetail: "当前任务需要触发 Stash 扫描，但后端未启用对应连接。", scan: "Stash 扫描失败", noCandidate: "没有可下载候选", noCandidateDetail: "搜索返回了结果，但没有可直接提交的 magnet 或种子链接。", trackerMissing: "索引器未配置", trackerMissingDetail: "当前下载链路无法访问 Jackett 或其他搜索后端。", torrentMissing: "缺少种子地址", torrentMissingDetail: "手动添加任务时没有提供有效的磁链或下载地址。", duplicateTorrent: "重复种子任务", duplicateTorrentDetail: "同一个 torrent 或 magnet 已存在对应的 Moji 任务。", duplicateCode: "重复番号任务", duplicateCodeDetail: "同一个番号已经存在 Moji 任务，当前请求被严格去重拦截。", codeMissing: "无法提取番号", codeMissingDetail: "任务创建前必须解析出影片番号，但当前输入无法稳定提取 code。", downloaderMissing: "下载器未启用", downloaderMissingDetail: "任务无法提交到 qBittorrent，需先补齐下载器配置。", submit: "提交下载失败", generic: "任务执行失败" }, lifecycle: { sourcing: "待选种", downloading: "下载中", pending_ingest: "待入库", transferring: "搬运中", scanning: "扫描中", completed: "已完成", sourcingCurrent: "Moji 已创建正式任务，正在搜索并筛选可用资源。", sourcingUpcoming: "等待创建正式任务后开始搜索资源。", downloadUpcoming: "选种完成并提交到 qBittorrent 后进入此阶段。", downloadProgress: "已提交到 qBittorrent，当前进度 {{progress}}%。", landed: "内容已落地：{{path}}", downloadDone: "下载已完成。", ingestUpcoming: "qB 下载完成后，任务会进入待入库阶段。", ingestCurrent: "下载已完成，等待开始入库处理。", ingestDone: "下载已完成，已进入入库链路。", noTransferCurrent: "当前入库方式无需搬运文件，Moji 会直接进入扫描阶段。", noTransfer: "当前入库方式无需搬运文件。", transferUpcoming: "需要搬运时，Moji 会在这里执行复制、移动或符号链接。", delivery: "交付", preparingTarget: "Moji 正在准备搬运目标路径。", transferDonePath: "搬运已完成：{{path}}", transferDone: "搬运已完成。", scanUpcoming: "入库准备完成后，Moji 会触发 Stash 扫描。", jobRunning: "Stash job {{job}} 正在执行。", scanWaiting: "等待 Stash 接手当前扫描。", scanPath: "扫描路径：{{path}}", scanDone: "扫描已完成。", closed: "任务已完成闭环。", completeUpcoming: "扫描完成并收口后，任务会进入最终完成态。" }, presentation: { transfer: "下载已完成，Moji 正在准备文件搬运。", target: "正在准备交付目标路径。", scan: "已完成下载，正在等待 Stash 收口。", stashAccepted: "Stash 已接手当前任务。", pending: "下载已结束，等待触发或完成 Stash 扫描。", noScan: "当前尚未有扫描结果。", completed: "下载与入库链路已完成。", closed: "任务已闭环。", downloaderWaiting: "任务正在等待下载器推进。", progress: "当前进度 {{progress}}%", queued: "任务已创建，正在搜寻资源。", stageWaiting: "当前阶段等待继续推进。" } },
+    taskModel: { manualMagnet: "手动磁链任务", sources: { search: "搜索", subscription: "订阅", manual: "手动" }, source: "来源 {{source}}", states: { blocked: "受阻", transferring: "搬运中", subtitling: "字幕中", scanning: "扫描中", pendingIngest: "待入库", completed: "已完成", downloading: "下载中", queued: "待下载", closed: "已闭环" }, failure: { blocked: "当前阶段受阻", blockedDetail: "任务被阻塞，但没有更多错误上下文。", scanPending: "等待扫描收口", scanPendingDetail: "下载已完成，等待 Stash 扫描继续推进。", downloading: "下载进行中", downloadingDetail: "任务仍在等待下载状态变化。", healthy: "状态正常", healthyDetail: "当前任务没有显式错误，等待下一次同步。", qbPath: "qB 路径映射失败", sourcePath: "Moji 源路径构建失败", targetPath: "Moji 目标路径构建失败", transfer: "文件搬运失败", scanPath: "Stash 扫描路径构建失败", missingScanPath: "缺少扫描路径", missingScanPathDetail: "任务没有可用于 Stash 扫描的内容路径或保存路径。", stashMissing: "Stash 未配置", stashMissingDetail: "当前任务需要触发 Stash 扫描，但后端未启用对应连接。", scan: "Stash 扫描失败", noCandidate: "没有可下载候选", noCandidateDetail: "搜索返回了结果，但没有可直接提交的 magnet 或种子链接。", trackerMissing: "索引器未配置", trackerMissingDetail: "当前下载链路无法访问 Jackett 或其他搜索后端。", torrentMissing: "缺少种子地址", torrentMissingDetail: "手动添加任务时没有提供有效的磁链或下载地址。", duplicateTorrent: "重复种子任务", duplicateTorrentDetail: "同一个 torrent 或 magnet 已存在对应的 Moji 任务。", duplicateCode: "重复番号任务", duplicateCodeDetail: "同一个番号已经存在 Moji 任务，当前请求被严格去重拦截。", codeMissing: "无法提取番号", codeMissingDetail: "任务创建前必须解析出影片番号，但当前输入无法稳定提取 code。", downloaderMissing: "下载器未启用", downloaderMissingDetail: "任务无法提交到 qBittorrent，需先补齐下载器配置。", submit: "提交下载失败", generic: "任务执行失败" }, lifecycle: { sourcing: "待选种", downloading: "下载中", pending_ingest: "待入库", transferring: "搬运中", subtitling: "字幕中", scanning: "扫描中", completed: "已完成", sourcingCurrent: "Moji 已创建正式任务，正在搜索并筛选可用资源。", sourcingUpcoming: "等待创建正式任务后开始搜索资源。", downloadUpcoming: "选种完成并提交到 qBittorrent 后进入此阶段。", downloadProgress: "已提交到 qBittorrent，当前进度 {{progress}}%。", landed: "内容已落地：{{path}}", downloadDone: "下载已完成。", ingestUpcoming: "qB 下载完成后，任务会进入待入库阶段。", ingestCurrent: "下载已完成，等待开始入库处理。", ingestDone: "下载已完成，已进入入库链路。", noTransferCurrent: "当前入库方式无需搬运文件，Moji 会直接进入扫描阶段。", noTransfer: "当前入库方式无需搬运文件。", transferUpcoming: "需要搬运时，Moji 会在这里执行复制、移动或符号链接。", delivery: "交付", preparingTarget: "Moji 正在准备搬运目标路径。", transferDonePath: "搬运已完成：{{path}}", transferDone: "搬运已完成。", subtitlingQueued: "交付已完成，等待生成字幕。", subtitlingCurrent: "Moji 正在匹配或生成字幕。", subtitlingUpcoming: "交付完成后，Moji 会为视频匹配或生成字幕。", subtitlingDone: "字幕处理已完成。", scanUpcoming: "入库准备完成后，Moji 会触发 Stash 扫描。", jobRunning: "Stash job {{job}} 正在执行。", scanWaiting: "等待 Stash 接手当前扫描。", scanPath: "扫描路径：{{path}}", scanDone: "扫描已完成。", closed: "任务已完成闭环。", completeUpcoming: "扫描完成并收口后，任务会进入最终完成态。" }, presentation: { transfer: "下载已完成，Moji 正在准备文件搬运。", subtitle: "文件已交付，Moji 正在处理字幕。", target: "正在准备交付目标路径。", scan: "已完成下载，正在等待 Stash 收口。", stashAccepted: "Stash 已接手当前任务。", pending: "下载已结束，等待触发或完成 Stash 扫描。", noScan: "当前尚未有扫描结果。", completed: "下载与入库链路已完成。", closed: "任务已闭环。", downloaderWaiting: "任务正在等待下载器推进。", progress: "当前进度 {{progress}}%", queued: "任务已创建，正在搜寻资源。", stageWaiting: "当前阶段等待继续推进。" } },
     taskDetail: { created: "创建时间", updated: "最近更新", completedAt: "完成时间", currentProgress: "当前进度", lifecycle: "生命周期", data: "任务数据", magnet: "磁力链接", taskCode: "任务番号", copyMagnet: "复制磁力链接", copiedMagnet: "磁力链接已复制", code: "番号", source: "任务来源", savePath: "qB 保存目录", category: "分类", tags: "标签", contentPath: "qB 内容路径", torrentName: "Torrent 名称", copyName: "复制 Torrent 名称", copiedName: "Torrent 名称已复制", torrentHash: "Torrent Hash", copyHash: "复制 Torrent Hash", copiedHash: "Torrent Hash 已复制", waitingSync: "待同步", progress: "进度", ingestMode: "入库方式", sourcePath: "Moji 搬运源路径", action: "交付动作", targetPath: "Moji 交付目标路径", result: "交付结果", resultDone: "已完成", notTriggered: "未触发", error: "交付错误", scanPath: "Stash 扫描路径", scanStage: "扫描阶段", notStarted: "未开始", scanHint: "扫描提示", actions: "操作", retrying: "正在重试当前任务", retryBlocked: "重试受阻任务", syncAll: "同步全部任务进度", triggering: "正在触发当前任务扫描", trigger: "触发当前任务扫描", scanPending: "触发待入库任务扫描", deleting: "正在删除当前任务", delete: "删除当前任务" },
     discoverUi: { search: "搜索", searchMode: "搜索模式", keyword: "搜索关键词", searchStashBox: "搜索 StashBox", searchJackett: "搜索 Jackett", queued: "已将 {{title}} 加入任务队列。", stashResults: "StashBox 搜索结果", jackettResults: "Jackett 搜索结果", query: "搜索词：{{query}}", fastPreview: "快速规则预览", filePreview: "文件结构规则预览", inspecting: "正在检查文件结构...", inspected: "已检查 {{inspected}} / {{total}} 条可检查候选", recommendation: "推荐系统占位区", recommendationNote: "后续可接入推荐、通知和批量操作。", recommendationDisabled: "推荐系统未启用", recommendationDetail: "先把健康、任务和扫描闭环跑顺，再把推荐位接进来。", viewHelp: "看帮助", placeholders: { first: "输入番号、标题、演员或关键词", second: "试试 STARS-001 / IPX-789", third: "按 / 聚焦搜索框", fourth: "已订阅演员会自动出现在推荐里" }, sorts: { relevance: "默认（相关度）", dateDesc: "日期从新到旧", dateAsc: "日期从旧到新", durationDesc: "时长从长到短", titleAsc: "标题 A → Z", seedersDesc: "种子数从多到少", sizeDesc: "体积从大到小", publishDesc: "发布日期从新到旧" }, toolbar: { sort: "排序", sortAria: "排序方式", results: "结果", summary: "{{label}} · 第 {{page}} / {{pages}} 页 · 共 {{total}} 条", previous: "上一页", next: "下一页" }, filters: { title: "索引器筛选", disconnected: "未连接 Jackett", disconnectedDetail: "当前没有可用的 Jackett 索引器。请到设置里检查 Jackett URL / API Key。", selected: "已选 {{count}} 个", loading: "正在加载索引器列表…", disabled: "Jackett 端未配置此索引器", clear: "清空筛选" }, history: { aria: "搜索历史", recent: "最近搜索", clear: "清空", remove: "删除历史 {{entry}}" }, result: { source: "数据来源", code: "番号 · {{code}}", studio: "片商 · {{studio}}", performers: "演员 · {{performers}}", noDate: "无日期", original: "原始页面", queueing: "加入中", queue: "加入任务队列", magnet: "磁力链接", originalDownload: "原始下载", adding: "添加中", create: "创建任务", enter: "输入关键词开始搜索", enterDetail: "支持番号、标题、演员名或任意关键词。按 / 可快速聚焦搜索框。", noStash: "StashBox 没有匹配结果", tryJackettDetail: "试试切到 Jackett 备用搜索，索引更广。", tryJackett: "试试 Jackett →", noFiltered: "当前筛选下没有结果", noFilteredDetail: "取消部分索引器限制后再试一次。", noResults: "没有结果", noJackett: "Jackett 没有返回可用候选。" } },
     performerRoute: { unsubscribed: "已取消订阅 {{name}}。", subscribed: "已订阅 {{name}}，Moji 会通过 custom_fields 记录状态。", checked: "已检查 {{name}} 的最新发行信息。", checkedAll: "已触发全部已订阅演员的更新检查。", reloaded: "已从 Stash 重新加载演员列表。", noSelection: "当前没有可提交的已选作品，请刷新列表后重试。", batchNoResult: "批量下载失败，后端没有返回结果。", batchSummary: "已创建 {{queued}} 个任务，跳过 {{skipped}} 个，失败 {{failed}} 个。", createNoResult: "创建任务失败，后端没有返回结果。", created: "已为 {{scene}} 创建下载任务。", scene: "影片" },
@@ -110,7 +110,7 @@ export const resources = {
     taskRoute: { retryNoResult: "The retry failed because the server returned no task record.", retried: "Retried task: {{task}}.", noneBlocked: "There are no blocked tasks to retry.", retrySummary: "Blocked-task retry complete: {{succeeded}} succeeded and {{failed}} failed.", deleteNoResult: "Deletion failed because the server returned no deleted task record.", deleted: "Deleted task: {{task}}.", loadFailed: "Tasks failed to load", resolutionTitle: "Manual resolution: {{task}}", task: "Task", details: "Task details", loading: "Loading task...", notFound: "Task not found", deleteTitle: "Confirm deletion", confirmDelete: "Confirm task deletion", cancel: "Cancel", deleting: "Deleting...", confirm: "Confirm deletion" },
     taskBatch: { more: "More task actions", moreActions: "More actions for {{task}}", multiSelect: "Enter multi-select mode", exitMultiSelect: "Exit multi-select mode", deselectTask: "Deselect task: {{task}}", syncNow: "Sync qBittorrent now", syncing: "Syncing qBittorrent…", syncComplete: "qBittorrent task progress synchronized.", autoSyncOn: "Auto sync every {{seconds}} seconds", autoSyncOff: "Auto sync disabled", updatedAt: "List updated at {{time}}", selectionActions: "Selected task actions", selectTask: "Select task: {{task}}", selected: "{{count}} selected", hidden: "{{count}} selected outside the current filter", selectVisible: "Select visible ({{count}})", retry: "Retry ({{count}})", ingest: "Process ingest ({{count}})", delete: "Delete ({{count}})", clear: "Clear selection", limit: "You can select up to {{count}} tasks at once.", groupLimit: "There are more than {{count}} ingest tasks. Filter and select them in batches.", batchComplete: "Successfully processed {{count}} tasks.", confirmDeleteCount: "Delete the {{count}} selected tasks.", deletePolicies: { KEEP_ONLY: "Only the Moji task records will be deleted.", REMOVE_TORRENT: "The qBittorrent downloads will also be removed. This cannot be undone.", REMOVE_TORRENT_AND_FILES: "The qBittorrent downloads and downloaded files will also be removed. This cannot be undone." }, resultTitle: "Batch operation results", batchId: "Batch {{id}}", requested: "Requested", succeeded: "Succeeded", skipped: "Skipped", failed: "Failed", reasons: { RETRIED: "Task retried", INGEST_STARTED: "Ingest processing started", DELETED: "Task deleted", TASK_NOT_FOUND: "Task no longer exists", NOT_RETRYABLE: "Task is not retryable", NOT_READY_FOR_INGEST: "Task is not ready for ingest", ALREADY_RUNNING: "Task is already running", RETRY_FAILED: "Retry failed", INGEST_FAILED: "Ingest processing failed", DELETE_FAILED: "Deletion failed", CANCELLED: "Operation cancelled", UNKNOWN: "Unknown operation result" } },
     taskUi: { noneSelected: "No task selected", noneSelectedDetail: "Select a task card to see its details and actions here.", cannotResolve: "This task cannot be resolved manually", cannotResolveDetail: "The task may have left the blocked sourcing stage. Close this panel and refresh the list.", resolution: { noResult: "The server returned no resumed task record.", title: "Resolve blocked sourcing manually", detail: "Search again for {{code}}, or provide a magnet link to continue the existing task.", magnetPlaceholder: "Paste magnet:?xt=urn:btih:...", magnetLabel: "Magnet link", submitting: "Submitting...", useMagnet: "Use magnet link", searching: "Searching...", searchAgain: "Search again", search: "Search candidates", found: "Found {{count}} candidate", found_other: "Found {{count}} candidates", none: "No candidates found. Update Jackett and search again, or paste a magnet link.", selecting: "Selecting...", select: "Select" }, card: { aria: "{{task}}, status: {{status}}. Open details.", resolveLabel: "Resolve task manually: {{task}}", resolve: "Resolve manually", retryLabel: "Retry task: {{task}}", retrying: "Retrying...", retry: "Retry", scanLabel: "Rescan task: {{task}}", scanning: "Scanning...", scan: "Rescan", deleteLabel: "Delete task: {{task}}", deleting: "Deleting...", delete: "Delete" } },
-    taskModel: { manualMagnet: "Manual magnet task", sources: { search: "Search", subscription: "Subscription", manual: "Manual" }, source: "Source: {{source}}", states: { blocked: "Blocked", transferring: "Transferring", scanning: "Scanning", pendingIngest: "Pending ingest", completed: "Completed", downloading: "Downloading", queued: "Queued", closed: "Closed" }, failure: { blocked: "Current stage blocked", blockedDetail: "The task is blocked without additional error context.", scanPending: "Waiting for scan completion", scanPendingDetail: "The download is complete and waiting for Stash to finish scanning.", downloading: "Download in progress", downloadingDetail: "The task is waiting for its download state to change.", healthy: "Healthy", healthyDetail: "The task has no explicit error and is waiting for the next sync.", qbPath: "qB path mapping failed", sourcePath: "Moji source path failed", targetPath: "Moji target path failed", transfer: "File delivery failed", scanPath: "Stash scan path failed", missingScanPath: "Missing scan path", missingScanPathDetail: "The task has no content or save path available for a Stash scan.", stashMissing: "Stash not configured", stashMissingDetail: "This task requires a Stash scan, but the connection is disabled.", scan: "Stash scan failed", noCandidate: "No downloadable candidate", noCandidateDetail: "Search returned results, but none had a usable magnet or torrent link.", trackerMissing: "Indexer not configured", trackerMissingDetail: "The download workflow cannot reach Jackett or another search provider.", torrentMissing: "Missing torrent URL", torrentMissingDetail: "The manually created task has no valid magnet or download URL.", duplicateTorrent: "Duplicate torrent task", duplicateTorrentDetail: "Another Moji task already uses the same torrent or magnet.", duplicateCode: "Duplicate code task", duplicateCodeDetail: "A Moji task already uses this code, so strict deduplication rejected the request.", codeMissing: "Unable to extract code", codeMissingDetail: "A code is required before task creation, but the input could not be parsed reliably.", downloaderMissing: "Downloader disabled", downloaderMissingDetail: "Configure qBittorrent before submitting this task.", submit: "Download submission failed", generic: "Task execution failed" }, lifecycle: { sourcing: "Sourcing", downloading: "Downloading", pending_ingest: "Pending ingest", transferring: "Transferring", scanning: "Scanning", completed: "Completed", sourcingCurrent: "Moji created the task and is searching and ranking resources.", sourcingUpcoming: "Waiting for the task before searching for resources.", downloadUpcoming: "This stage starts after selecting a torrent and submitting it to qBittorrent.", downloadProgress: "Submitted to qBittorrent; progress is {{progress}}%.", landed: "Content path: {{path}}", downloadDone: "Download completed.", ingestUpcoming: "The task enters pending ingest after qB finishes downloading.", ingestCurrent: "Download completed; waiting to begin ingest.", ingestDone: "Download completed and entered the ingest workflow.", noTransferCurrent: "This ingest mode does not move files; Moji will proceed directly to scanning.", noTransfer: "This ingest mode does not move files.", transferUpcoming: "When needed, Moji copies, moves, or links files at this stage.", delivery: "Delivery", preparingTarget: "Moji is preparing the delivery target.", transferDonePath: "Delivery completed: {{path}}", transferDone: "Delivery completed.", scanUpcoming: "Moji triggers a Stash scan after ingest preparation.", jobRunning: "Stash job {{job}} is running.", scanWaiting: "Waiting for Stash to take over the scan.", scanPath: "Scan path: {{path}}", scanDone: "Scan completed.", closed: "Task workflow completed.", completeUpcoming: "The task becomes complete after the scan finishes." }, presentation: { transfer: "Download complete; Moji is preparing file delivery.", target: "Preparing the delivery target.", scan: "Download complete; waiting for Stash to finish.", stashAccepted: "Stash accepted this task.", pending: "Download complete; waiting to trigger or finish the Stash scan.", noScan: "No scan result is available yet.", completed: "Download and ingest completed.", closed: "Task workflow closed.", downloaderWaiting: "Waiting for the downloader to advance the task.", progress: "Current progress: {{progress}}%", queued: "Task created; searching for resources.", stageWaiting: "Waiting for the current stage to advance." } },
+    taskModel: { manualMagnet: "Manual magnet task", sources: { search: "Search", subscription: "Subscription", manual: "Manual" }, source: "Source: {{source}}", states: { blocked: "Blocked", transferring: "Transferring", subtitling: "Subtitling", scanning: "Scanning", pendingIngest: "Pending ingest", completed: "Completed", downloading: "Downloading", queued: "Queued", closed: "Closed" }, failure: { blocked: "Current stage blocked", blockedDetail: "The task is blocked without additional error context.", scanPending: "Waiting for scan completion", scanPendingDetail: "The download is complete and waiting for Stash to finish scanning.", downloading: "Download in progress", downloadingDetail: "The task is waiting for its download state to change.", healthy: "Healthy", healthyDetail: "The task has no explicit error and is waiting for the next sync.", qbPath: "qB path mapping failed", sourcePath: "Moji source path failed", targetPath: "Moji target path failed", transfer: "File delivery failed", scanPath: "Stash scan path failed", missingScanPath: "Missing scan path", missingScanPathDetail: "The task has no content or save path available for a Stash scan.", stashMissing: "Stash not configured", stashMissingDetail: "This task requires a Stash scan, but the connection is disabled.", scan: "Stash scan failed", noCandidate: "No downloadable candidate", noCandidateDetail: "Search returned results, but none had a usable magnet or torrent link.", trackerMissing: "Indexer not configured", trackerMissingDetail: "The download workflow cannot reach Jackett or another search provider.", torrentMissing: "Missing torrent URL", torrentMissingDetail: "The manually created task has no valid magnet or download URL.", duplicateTorrent: "Duplicate torrent task", duplicateTorrentDetail: "Another Moji task already uses the same torrent or magnet.", duplicateCode: "Duplicate code task", duplicateCodeDetail: "A Moji task already uses this code, so strict deduplication rejected the request.", codeMissing: "Unable to extract code", codeMissingDetail: "A code is required before task creation, but the input could not be parsed reliably.", downloaderMissing: "Downloader disabled", downloaderMissingDetail: "Configure qBittorrent before submitting this task.", submit: "Download submission failed", generic: "Task execution failed" }, lifecycle: { sourcing: "Sourcing", downloading: "Downloading", pending_ingest: "Pending ingest", transferring: "Transferring", subtitling: "Subtitling", scanning: "Scanning", completed: "Completed", sourcingCurrent: "Moji created the task and is searching and ranking resources.", sourcingUpcoming: "Waiting for the task before searching for resources.", downloadUpcoming: "This stage starts after selecting a torrent and submitting it to qBittorrent.", downloadProgress: "Submitted to qBittorrent; progress is {{progress}}%.", landed: "Content path: {{path}}", downloadDone: "Download completed.", ingestUpcoming: "The task enters pending ingest after qB finishes downloading.", ingestCurrent: "Download completed; waiting to begin ingest.", ingestDone: "Download completed and entered the ingest workflow.", noTransferCurrent: "This ingest mode does not move files; Moji will proceed directly to scanning.", noTransfer: "This ingest mode does not move files.", transferUpcoming: "When needed, Moji copies, moves, or links files at this stage.", delivery: "Delivery", preparingTarget: "Moji is preparing the delivery target.", transferDonePath: "Delivery completed: {{path}}", transferDone: "Delivery completed.", subtitlingQueued: "Delivery complete; waiting to fetch subtitles.", subtitlingCurrent: "Moji is matching or generating subtitles.", subtitlingUpcoming: "After delivery, Moji matches or generates subtitles for the videos.", subtitlingDone: "Subtitles handled.", scanUpcoming: "Moji triggers a Stash scan after ingest preparation.", jobRunning: "Stash job {{job}} is running.", scanWaiting: "Waiting for Stash to take over the scan.", scanPath: "Scan path: {{path}}", scanDone: "Scan completed.", closed: "Task workflow completed.", completeUpcoming: "The task becomes complete after the scan finishes." }, presentation: { transfer: "Download complete; Moji is preparing file delivery.", subtitle: "Files delivered; Moji is handling subtitles.", target: "Preparing the delivery target.", scan: "Download complete; waiting for Stash to finish.", stashAccepted: "Stash accepted this task.", pending: "Download complete; waiting to trigger or finish the Stash scan.", noScan: "No scan result is available yet.", completed: "Download and ingest completed.", closed: "Task workflow closed.", downloaderWaiting: "Waiting for the downloader to advance the task.", progress: "Current progress: {{progress}}%", queued: "Task created; searching for resources.", stageWaiting: "Waiting for the current stage to advance." } },
     taskDetail: { created: "Created", updated: "Last updated", completedAt: "Completed", currentProgress: "Current progress", lifecycle: "Lifecycle", data: "Task data", magnet: "Magnet link", taskCode: "Task code", copyMagnet: "Copy magnet link", copiedMagnet: "Magnet link copied", code: "Code", source: "Task source", savePath: "qB save directory", category: "Category", tags: "Tags", contentPath: "qB content path", torrentName: "Torrent name", copyName: "Copy torrent name", copiedName: "Torrent name copied", torrentHash: "Torrent hash", copyHash: "Copy torrent hash", copiedHash: "Torrent hash copied", waitingSync: "Waiting to sync", progress: "Progress", ingestMode: "Ingest mode", sourcePath: "Moji delivery source", action: "Delivery action", targetPath: "Moji delivery target", result: "Delivery result", resultDone: "Completed", notTriggered: "Not triggered", error: "Delivery error", scanPath: "Stash scan path", scanStage: "Scan stage", notStarted: "Not started", scanHint: "Scan hint", actions: "Actions", retrying: "Retrying current task", retryBlocked: "Retry blocked task", syncAll: "Sync all task progress", triggering: "Triggering scan for this task", trigger: "Trigger scan for this task", scanPending: "Scan pending-ingest tasks", deleting: "Deleting current task", delete: "Delete current task" },
     discoverUi: { search: "Search", searchMode: "Search mode", keyword: "Search keywords", searchStashBox: "Search StashBox", searchJackett: "Search Jackett", queued: "Added {{title}} to the task queue.", stashResults: "StashBox search results", jackettResults: "Jackett search results", query: "Query: {{query}}", fastPreview: "Preview fast rules", filePreview: "Preview structure rules", inspecting: "Inspecting torrent structures...", inspected: "Inspected {{inspected}} / {{total}} eligible candidates", recommendation: "Recommendation placeholder", recommendationNote: "Recommendations, notifications, and bulk actions can be added here later.", recommendationDisabled: "Recommendations are disabled", recommendationDetail: "Complete the health, task, and scan workflows before enabling recommendations.", viewHelp: "View help", placeholders: { first: "Enter a code, title, performer, or keyword", second: "Try STARS-001 or IPX-789", third: "Press / to focus search", fourth: "Subscribed performers will appear in recommendations" }, sorts: { relevance: "Default (relevance)", dateDesc: "Newest date first", dateAsc: "Oldest date first", durationDesc: "Longest duration first", titleAsc: "Title A → Z", seedersDesc: "Most seeders first", sizeDesc: "Largest size first", publishDesc: "Newest publish date first" }, toolbar: { sort: "Sort", sortAria: "Sort order", results: "Results", summary: "{{label}} · Page {{page}} / {{pages}} · {{total}} total", previous: "Previous page", next: "Next page" }, filters: { title: "Indexer filters", disconnected: "Jackett disconnected", disconnectedDetail: "No Jackett indexers are available. Check the Jackett URL and API key in Settings.", selected: "{{count}} selected", loading: "Loading indexers…", disabled: "This indexer is not configured in Jackett", clear: "Clear filters" }, history: { aria: "Search history", recent: "Recent searches", clear: "Clear", remove: "Remove {{entry}} from history" }, result: { source: "Data source", code: "Code · {{code}}", studio: "Studio · {{studio}}", performers: "Performers · {{performers}}", noDate: "No date", original: "Original page", queueing: "Queuing", queue: "Add to task queue", magnet: "Magnet link", originalDownload: "Original download", adding: "Adding", create: "Create task", enter: "Enter keywords to search", enterDetail: "Search by code, title, performer, or any keyword. Press / to focus the field.", noStash: "No matching StashBox results", tryJackettDetail: "Try the broader Jackett fallback search.", tryJackett: "Try Jackett →", noFiltered: "No results with these filters", noFilteredDetail: "Remove some indexer restrictions and try again.", noResults: "No results", noJackett: "Jackett returned no usable candidates." } },
     performerRoute: { unsubscribed: "Unsubscribed from {{name}}.", subscribed: "Subscribed to {{name}}; Moji records the state in custom_fields.", checked: "Checked {{name}} for new releases.", checkedAll: "Started an update check for all subscribed performers.", reloaded: "Reloaded the performer list from Stash.", noSelection: "No selected scenes can be submitted. Refresh the list and try again.", batchNoResult: "Bulk download failed because the server returned no result.", batchSummary: "Created {{queued}} tasks, skipped {{skipped}}, and failed {{failed}}.", createNoResult: "Task creation failed because the server returned no result.", created: "Created a download task for {{scene}}.", scene: "scene" },
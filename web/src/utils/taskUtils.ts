@@ -32,7 +32,7 @@ export type TaskLifecycleStep = {
 };
 
 export type TaskPresentation = {
-  phase: "queued" | "downloading" | "transferRunning" | "scanPending" | "scanRunning" | "completed" | "failed";
+  phase: "queued" | "downloading" | "transferRunning" | "subtitleRunning" | "scanPending" | "scanRunning" | "completed" | "failed";
   label: string;
   tone: "tone-success" | "tone-danger" | "tone-info" | "tone-warn" | "tone-neutral";
   summary: string;
@@ -55,6 +55,7 @@ const TASK_STAGE_SEQUENCE = [
   "downloading",
   "pending_ingest",
   "transferring",
+  "subtitling",
   "scanning",
   "completed"
 ] as const;
@@ -144,7 +145,7 @@ export function canTriggerTaskStashScan(task: DashboardTask) {
 export function taskBatchEligibility(tasks: DashboardTask[]) {
   return {
     retryIds: tasks.filter((task) => task.stageStatus === "BLOCKED" && task.stage !== "COMPLETED").map((task) => task.id),
-    ingestIds: tasks.filter((task) => ["PENDING_INGEST", "TRANSFERRING", "SCANNING"].includes(task.stage) && !(task.stage === "SCANNING" && task.stageStatus === "RUNNING")).map((task) => task.id)
+    ingestIds: tasks.filter((task) => ["PENDING_INGEST", "TRANSFERRING", "SUBTITLING", "SCANNING"].includes(task.stage) && !((task.stage === "SCANNING" || task.stage === "SUBTITLING") && task.stageStatus === "RUNNING")).map((task) => task.id)
   };
 }
 
@@ -343,6 +344,10 @@ export function taskPrimaryState(task: DashboardTask): Pick<TaskPresentation, "p
     return { phase: "transferRunning", label: tr("taskModel.states.transferring"), tone: "tone-info" as const };
   }
 
+  if (stageValue(task) === "subtitling") {
+    return { phase: "subtitleRunning", label: tr("taskModel.states.subtitling"), tone: "tone-info" as const };
+  }
+
   if (stageValue(task) === "scanning" && stageStatusValue(task) === "running") {
     return { phase: "scanRunning", label: tr("taskModel.states.scanning"), tone: "tone-info" as const };
   }
@@ -396,7 +401,7 @@ function lifecycleTime(task: DashboardTask, stage: TaskStageKey) {
   if (stage === "pending_ingest") {
     return currentIndex >= stageIndex ? (task.downloadCompletedAt || task.updatedAt) : null;
   }
-  if (stage === "transferring") {
+  if (stage === "transferring" || stage === "subtitling") {
     return currentIndex >= stageIndex ? task.updatedAt : null;
   }
   if (stage === "scanning") {
@@ -453,6 +458,13 @@ function lifecycleDetail(task: DashboardTask, stage: TaskStageKey, failure: Task
     return tr("taskModel.lifecycle.transferDone");
   }
 
+  if (stage === "subtitling") {
+    if (isBlocked) return failure.detail;
+    if (isCurrent) return tr(currentStatus === "running" ? "taskModel.lifecycle.subtitlingCurrent" : "taskModel.lifecycle.subtitlingQueued");
+    if (taskStageIndex(task) < TASK_STAGE_SEQUENCE.indexOf("subtitling")) return tr("taskModel.lifecycle.subtitlingUpcoming");
+    return tr("taskModel.lifecycle.subtitlingDone");
+  }
+
   if (stage === "scanning") {
     if (isBlocked) return failure.detail;
     if (currentStage === "sourcing" || currentStage === "downloading" || currentStage === "pending_ingest") {
@@ -526,6 +538,9 @@ export function taskPresentation(task: DashboardTask): TaskPresentation {
   } else if (primary.phase === "transferRunning") {
     summary = tr("taskModel.presentation.transfer");
     detail = task.mojiTransferPath ? `${transferActionLabel(task.transferAction ?? "") || tr("taskModel.lifecycle.delivery")} -> ${task.mojiTransferPath}` : tr("taskModel.presentation.target");
+  } else if (primary.phase === "subtitleRunning") {
+    summary = tr("taskModel.presentation.subtitle");
+    detail = task.mojiTransferPath || task.contentPath || tr("taskModel.presentation.stageWaiting");
   } else if (primary.phase === "scanRunning") {
     summary = tr("taskModel.presentation.scan");
     detail = task.stashScanJobId ? tr("taskModel.lifecycle.jobRunning", { job: task.stashScanJobId }) : tr("taskModel.presentation.stashAccepted");
@@ -571,7 +586,7 @@ export function taskCardState(
   if (presentation.phase === "completed") {
     return "completed";
   }
-  if (presentation.phase === "downloading" || presentation.phase === "scanRunning") {
+  if (presentation.phase === "downloading" || presentation.phase === "subtitleRunning" || presentation.phase === "scanRunning") {
     return "progress";
   }
   return "pending";